@@ -0,0 +1,52 @@
+package main
+
+import (
+	"MikoNews/internal/database"
+	"context"
+	"fmt"
+	"os"
+)
+
+// runCheckConfig handles `miko_news check-config`. The configuration has already been loaded and validated by
+// config.LoadConfig; this prints a summary and checks that the database is reachable and fully migrated.
+func runCheckConfig(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) > 0 {
+		return errUsage
+	}
+	cfg := env.cfg
+	fmt.Println("configuration: ok")
+	for _, app := range cfg.FeishuAppConfigs() {
+		policy := cfg.AppSubmissionPolicy(app)
+		fmt.Printf("feishu app: tenant %q, app id %s, %s mode, %d group chats, %d admins\n",
+			app.TenantKey, app.AppID, app.EventMode, len(app.GroupChats), len(cfg.AppAdmin(app).OpenIDs))
+		fmt.Printf("submission policy: tenant %q, default %s, %d rules\n", app.TenantKey, policy.DefaultEffect, len(policy.Rules))
+	}
+	if cfg.Telegram.Enabled {
+		fmt.Printf("telegram: %d chats, tenant %q\n", len(cfg.Telegram.Chats), cfg.Telegram.TenantKey)
+	}
+
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("database (%s): %w", cfg.Database.Driver, err)
+	}
+	defer db.Close()
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("database (%s): %w", cfg.Database.Driver, err)
+	}
+	pending := 0
+	for _, status := range statuses {
+		if !status.Applied {
+			pending++
+		}
+	}
+	fmt.Printf("database: %s, connected, %d pending migrations\n", cfg.Database.Driver, pending)
+	if pending > 0 {
+		fmt.Fprintln(os.Stderr, "run `miko_news migrate up` to apply pending migrations")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"MikoNews/internal/model"
+	repositoryImpl "MikoNews/internal/repository/impl"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// exportBatchSize is the number of articles read from the database per query
+const exportBatchSize = 200
+
+// articleRecord is one line of an export file. Anonymous articles carry their real author only as the ciphertext,
+// which can be revealed after importing into an instance configured with the same author ID secret.
+type articleRecord struct {
+	ID           int64                  `json:"id"` // article ID in the exporting database, not used on import
+	Title        string                 `json:"title"`
+	Content      string                 `json:"content"`
+	RawContent   string                 `json:"raw_content,omitempty"`
+	AuthorID     string                 `json:"author_id,omitempty"`
+	AuthorName   string                 `json:"author_name"`
+	CuratorID    string                 `json:"curator_id,omitempty"`
+	Anonymous    bool                   `json:"anonymous,omitempty"`
+	Source       string                 `json:"source"`
+	MessageID    string                 `json:"message_id,omitempty"` // source message ID, used to skip records imported before
+	Status       string                 `json:"status"`
+	DocToken     string                 `json:"doc_token,omitempty"`
+	ForwardCount int                    `json:"forward_count,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	Authors      []*model.ArticleAuthor `json:"authors,omitempty"`
+
+	AuthorIDEncrypted string `json:"author_id_encrypted,omitempty"` // encrypted real author of an anonymous article
+}
+
+func newArticleRecord(article *model.Article) *articleRecord {
+	return &articleRecord{
+		ID:           article.ID,
+		Title:        article.Title,
+		Content:      article.Content,
+		RawContent:   article.RawContent,
+		AuthorID:     article.AuthorID,
+		AuthorName:   article.AuthorName,
+		CuratorID:    article.CuratorID,
+		Anonymous:    article.Anonymous,
+		Source:       article.Source,
+		MessageID:    string(article.SourceMessageID),
+		Status:       article.Status,
+		DocToken:     article.DocToken,
+		ForwardCount: article.ForwardCount,
+		CreatedAt:    article.CreatedAt,
+		Authors:      article.Authors,
+
+		AuthorIDEncrypted: article.AuthorIDEncrypted,
+	}
+}
+
+// article converts the record back to a new, unsaved article
+func (r *articleRecord) article() *model.Article {
+	return &model.Article{
+		Title:           r.Title,
+		Content:         r.Content,
+		RawContent:      r.RawContent,
+		AuthorID:        r.AuthorID,
+		AuthorName:      r.AuthorName,
+		CuratorID:       r.CuratorID,
+		Anonymous:       r.Anonymous,
+		Source:          r.Source,
+		SourceMessageID: model.NullString(r.MessageID),
+		Status:          r.Status,
+		DocToken:        r.DocToken,
+		ForwardCount:    r.ForwardCount,
+		CreatedAt:       r.CreatedAt,
+		Authors:         r.Authors,
+
+		AuthorIDEncrypted: r.AuthorIDEncrypted,
+	}
+}
+
+// runExport handles `miko_news export`: it writes the tenant's articles created since --since as JSON Lines
+func runExport(ctx context.Context, env *commandEnv, args []string) error {
+	fs := newFlagSet("export")
+	since := fs.String("since", "", "only export articles created at or after this time (2006-01-02 or RFC 3339)")
+	tenant := fs.String("tenant", "", "tenant key of the Feishu app, required when several apps are configured")
+	output := fs.String("output", "", "write to this file instead of stdout")
+	if positional, err := parseFlags(fs, args); err != nil {
+		return err
+	} else if len(positional) > 0 {
+		return errUsage
+	}
+
+	var sinceTime time.Time
+	if *since != "" {
+		var err error
+		if sinceTime, err = parseTime(*since); err != nil {
+			return fmt.Errorf("invalid --since %q: %w", *since, errUsage)
+		}
+	}
+	app, err := findApp(env.cfg, *tenant, flagSet(fs, "tenant"))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	n, err := exportArticles(ctx, env, app.TenantKey, sinceTime, w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d articles\n", n)
+	return nil
+}
+
+// exportArticles writes the articles of tenantKey created at or after since to w, one JSON object per line
+func exportArticles(ctx context.Context, env *commandEnv, tenantKey string, since time.Time, w io.Writer) (int, error) {
+	repo := repositoryImpl.NewArticleRepository(env.db, tenantKey)
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	n := 0
+	var afterID int64
+	for {
+		articles, err := repo.FindAfter(ctx, afterID, exportBatchSize)
+		if err != nil {
+			return n, fmt.Errorf("failed to read articles: %w", err)
+		}
+		for _, article := range articles {
+			afterID = article.ID
+			if article.CreatedAt.Before(since) {
+				continue
+			}
+			if err := encoder.Encode(newArticleRecord(article)); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(articles) < exportBatchSize {
+			return n, buf.Flush()
+		}
+	}
+}
+
+// parseTime parses a date or a date and time in the local time zone, or an RFC 3339 timestamp
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.DateOnly, time.DateTime} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/service"
+	mh "MikoNews/internal/service/impl/messagehandler"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Import file formats
+const (
+	importFormatExport = "export" // JSON Lines written by `miko_news export`
+	importFormatJSON   = "json"   // a JSON array, or JSON Lines, of service.ImportRecord
+	importFormatCSV    = "csv"    // CSV with a header row naming service.ImportRecord fields
+)
+
+// runImport handles `miko_news import`: it imports historical articles from a file or from the history of a
+// Feishu chat. Imported articles keep their authors and timestamps, are deduplicated by source message ID
+// and are not forwarded.
+func runImport(ctx context.Context, env *commandEnv, args []string) error {
+	fs := newFlagSet("import")
+	tenant := fs.String("tenant", "", "tenant key of the Feishu app, required when several apps are configured")
+	format := fs.String("format", "", "file format: export, json or csv, defaults by file extension (.jsonl: export)")
+	chat := fs.String("chat", "", "import the history of this Feishu chat instead of a file")
+	since := fs.String("since", "", "with --chat, only import messages sent at or after this time")
+	until := fs.String("until", "", "with --chat, only import messages sent before this time")
+	includeText := fs.Bool("include-text", false, "with --chat, also import plain text messages, not only rich text posts")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if (*chat == "") == (len(positional) == 0) || len(positional) > 1 {
+		return errUsage
+	}
+	app, err := findApp(env.cfg, *tenant, flagSet(fs, "tenant"))
+	if err != nil {
+		return err
+	}
+	feishuBots, err := newFeishuBots(env, []*config.FeishuConfig{app})
+	if err != nil {
+		return err
+	}
+	feishuBot := feishuBots[0]
+	importService := mh.NewArticleImportService(feishuBot.GetArticleService(), feishuBot.GetMessageService(), feishuBot.GetUserDirectory())
+
+	var result *service.ImportResult
+	if *chat != "" {
+		query := &service.ChatHistoryQuery{ChatID: *chat, IncludeText: *includeText}
+		if *since != "" {
+			if query.Start, err = parseTime(*since); err != nil {
+				return fmt.Errorf("invalid --since %q: %w", *since, errUsage)
+			}
+		}
+		if *until != "" {
+			if query.End, err = parseTime(*until); err != nil {
+				return fmt.Errorf("invalid --until %q: %w", *until, errUsage)
+			}
+		}
+		result, err = importService.ImportChatHistory(ctx, query)
+	} else {
+		result, err = importFile(ctx, feishuBot.GetArticleService(), importService, positional[0], *format)
+	}
+	if result != nil {
+		fmt.Fprintf(os.Stderr, "imported %d articles, skipped %d, failed %d\n", result.Imported, result.Skipped, result.Failed)
+	}
+	return err
+}
+
+// importFile imports the articles of a file in the given format, detected from the file extension when empty
+func importFile(ctx context.Context, articleService service.ArticleService, importService service.ArticleImportService, path, format string) (*service.ImportResult, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".jsonl":
+			format = importFormatExport
+		case ".json":
+			format = importFormatJSON
+		case ".csv":
+			format = importFormatCSV
+		default:
+			return nil, fmt.Errorf("cannot detect the format of %s, pass --format: %w", path, errUsage)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []*service.ImportRecord
+	switch format {
+	case importFormatExport:
+		return importArticles(ctx, articleService, f)
+	case importFormatJSON:
+		records, err = readJSONRecords(f)
+	case importFormatCSV:
+		records, err = readCSVRecords(f)
+	default:
+		return nil, fmt.Errorf("unknown format %q: %w", format, errUsage)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return importService.ImportRecords(ctx, records)
+}
+
+// importArticles saves every record of an export read from r. Records are saved as exported, including their
+// status and authors; records whose source message was already imported are skipped.
+func importArticles(ctx context.Context, articleService service.ArticleService, r io.Reader) (*service.ImportResult, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	result := &service.ImportResult{}
+	for line := 1; ; line++ {
+		var record articleRecord
+		if err := decoder.Decode(&record); errors.Is(err, io.EOF) {
+			return result, nil
+		} else if err != nil {
+			return result, fmt.Errorf("failed to read record %d: %w", line, err)
+		}
+		imported, err := articleService.ImportArticle(ctx, record.article())
+		if err != nil {
+			return result, fmt.Errorf("failed to import record %d (exported id %d): %w", line, record.ID, err)
+		}
+		if imported {
+			result.Imported++
+		} else {
+			result.Skipped++
+		}
+	}
+}
+
+// readJSONRecords reads a JSON array of records, or one record per line
+func readJSONRecords(r io.Reader) ([]*service.ImportRecord, error) {
+	reader := bufio.NewReader(r)
+	decoder := json.NewDecoder(reader)
+	if first, err := peekNonSpace(reader); err == nil && first == '[' {
+		var records []*service.ImportRecord
+		if err := decoder.Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to read records: %w", err)
+		}
+		return records, nil
+	}
+
+	var records []*service.ImportRecord
+	for {
+		var record service.ImportRecord
+		if err := decoder.Decode(&record); errors.Is(err, io.EOF) {
+			return records, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read record %d: %w", len(records)+1, err)
+		}
+		records = append(records, &record)
+	}
+}
+
+// peekNonSpace returns the first byte of r that is not white space without consuming it
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b[0])) {
+			return b[0], nil
+		}
+		_, _ = r.ReadByte()
+	}
+}
+
+// readCSVRecords reads records from a CSV file whose header row names the columns, using the JSON field names
+// of service.ImportRecord. Unknown columns are ignored.
+func readCSVRecords(r io.Reader) ([]*service.ImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet applications often start CSV files with a byte order mark
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["content"]; !ok {
+		if _, ok := columns["raw_content"]; !ok {
+			return nil, fmt.Errorf("CSV header must contain a content or raw_content column")
+		}
+	}
+
+	var records []*service.ImportRecord
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %w", line, err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		record := &service.ImportRecord{
+			MessageID:  field("message_id"),
+			AuthorID:   field("author_id"),
+			AuthorName: field("author_name"),
+			Title:      field("title"),
+			Content:    field("content"),
+			RawContent: field("raw_content"),
+		}
+		if createdAt := field("created_at"); createdAt != "" {
+			if record.CreatedAt, err = parseTime(createdAt); err != nil {
+				return nil, fmt.Errorf("invalid created_at %q on CSV line %d", createdAt, line)
+			}
+		}
+		records = append(records, record)
+	}
+}
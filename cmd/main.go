@@ -1,107 +1,195 @@
 package main
 
 import (
-	"MikoNews/internal/api"
-	"MikoNews/internal/bot"
 	"MikoNews/internal/config"
 	"MikoNews/internal/database"
 	"MikoNews/internal/pkg/logger"
 	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	stdlog "log"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
+
 	"go.uber.org/zap"
 )
 
+// command is a miko_news subcommand. All commands share the configuration loaded by config.LoadConfig.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	needsDB bool // open the database before running the command
+	run     func(ctx context.Context, env *commandEnv, args []string) error
+}
+
+// commandEnv holds the dependencies shared by all commands
+type commandEnv struct {
+	cfg *config.Config
+	db  *database.DB // nil for commands that do not need the database
+}
+
+// errUsage is returned by commands when the arguments are invalid; the command usage is printed instead of the error
+var errUsage = errors.New("invalid arguments")
+
+var commands = []*command{
+	{name: "serve", usage: "serve", summary: "run the bots, API server and background workers (default)", needsDB: true, run: runServe},
+	{name: "migrate", usage: "migrate up | down [steps] | status", summary: "apply, revert or list database migrations", needsDB: true, run: runMigrate},
+	{name: "export", usage: "export [--since DATE] [--tenant KEY] [--output FILE]", summary: "export articles as JSON Lines", needsDB: true, run: runExport},
+	{name: "import", usage: "import [--tenant KEY] <file>", summary: "import articles from a JSON Lines export without forwarding them", needsDB: true, run: runImport},
+	{name: "reforward", usage: "reforward <article-id> [--chat CHAT_ID,...] [--tenant KEY]", summary: "forward a published article card to group chats again", needsDB: true, run: runReforward},
+	{name: "resync-users", usage: "resync-users [--tenant KEY]", summary: "run a full sync of the user directory from the Feishu contact API", needsDB: true, run: runResyncUsers},
+	{name: "telegram-login", usage: "telegram-login", summary: "log in to Telegram interactively and save the session used by serve", run: runTelegramLogin},
+	{name: "check-config", usage: "check-config", summary: "validate the configuration and check the database connection", run: runCheckConfig},
+}
+
 func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run dispatches to the subcommand named by the first argument, `serve` when there is none, and returns the exit code
+func run(args []string) int {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage(os.Stderr)
+		return 2
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		stdlog.Fatalf("Failed to load configuration: %v", err)
+		stdlog.Printf("Failed to load configuration: %v", err)
+		return 1
 	}
 
-	// Initialize Logger
-	logger.InitLogger(cfg.Logger.Path, cfg.Logger.Level) // Call directly with correct arguments
-	log := zap.L()                                       // Get the global logger instance
+	// Initialize Logger; only the server logs to stdout, other commands keep stdout for their output
+	if cmd.name != "serve" {
+		logger.Console = os.Stderr
+	}
+	logger.InitLogger(cfg.Logger.Path, cfg.Logger.Level)
+	log := zap.L()
 	defer func() { _ = log.Sync() }()
 
-	log.Info("Logger initialized successfully")
-
-	// --- Initialize Database ---
-	// *** ASSUMPTION: database.New now returns *gorm.DB ***
-	gormDB, err := database.New(&cfg.Database)
-	if err != nil {
-		log.Fatal("Failed to connect to database", zap.Error(err))
+	// Cancel the command on SIGINT / SIGTERM. After the first signal the default handling is restored,
+	// so a second signal exits immediately instead of waiting for the graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	env := &commandEnv{cfg: cfg}
+	if cmd.needsDB {
+		db, err := database.New(&cfg.Database)
+		if err != nil {
+			log.Error("Failed to connect to database", zap.Error(err))
+			fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+			return 1
+		}
+		defer func() {
+			if err := db.Close(); err != nil {
+				log.Error("Failed to close database connection", zap.Error(err))
+			}
+		}()
+		log.Info("Database connection successful", zap.String("driver", db.Driver))
+		env.db = db
 	}
-	log.Info("Database connection successful")
-
-	// Create context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// --- Initialize Feishu Bot ---
-	feishuBot := bot.NewFeishuBot(&cfg.Feishu, gormDB)
 
-	// --- Create API Server ---
-	apiServer := api.New(cfg, gormDB)
-
-	// --- Start Feishu Bot (WebSocket) in background ---
-	go func() {
-		log.Info("Starting Feishu Bot (WebSocket)...")
-		if err := feishuBot.Start(ctx); err != nil {
-			log.Error("Feishu Bot (WebSocket) failed", zap.Error(err))
-			// Decide if bot failure should stop the main application
-			// cancel() // Optionally cancel context to stop server too
+	if err := cmd.run(ctx, env, args); err != nil {
+		if errors.Is(err, errUsage) {
+			if err != errUsage {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+			}
+			fmt.Fprintf(os.Stderr, "usage: miko_news %s\n", cmd.usage)
+			return 2
 		}
-	}()
+		log.Error("Command failed", zap.String("command", cmd.name), zap.Error(err))
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
 
-	// --- Start API Server (HTTP) in background ---
-	go func() {
-		log.Info("Starting API Server...")
-		if err := apiServer.Start(); err != nil && err != http.ErrServerClosed {
-			log.Fatal("Failed to start API server", zap.Error(err))
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
 		}
-	}()
-
-	log.Info("API server started", zap.Int("port", cfg.Server.Port))
-
-	// Wait for interrupt signal to gracefully shut down the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info("Shutting down server...")
+	}
+	return nil
+}
 
-	// Create a deadline context for shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer shutdownCancel()
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: miko_news <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "configuration is read from configs/config.yaml and environment variables")
+}
 
-	// Attempt graceful shutdown of the API server
-	// if err := apiServer.Shutdown(shutdownCtx); err != nil { // Commented out: Shutdown undefined
-	// 	log.Error("API server shutdown failed", zap.Error(err))
-	// }
-	_ = shutdownCtx // Temp use
+// newFlagSet creates a flag set for cmd that returns errors instead of printing them and exiting
+func newFlagSet(cmd string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
 
-	// Close database connection using *gorm.DB
-	// Access the underlying *gorm.DB (gormDB.DB) then call its DB() method
-	if gormInstance := gormDB.DB; gormInstance != nil {
-		if dbSQL, err := gormInstance.DB(); err == nil {
-			if err := dbSQL.Close(); err != nil {
-				log.Error("Failed to close database connection", zap.Error(err))
+// parseFlags parses args with fs, allowing flags after positional arguments, and returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, errUsage
 			}
-		} else {
-			log.Error("Failed to get underlying sql.DB for closing", zap.Error(err))
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
 		}
-	} else {
-		log.Warn("Database wrapper or underlying gorm DB instance is nil, cannot close")
+		positional = append(positional, args[0])
+		args = args[1:]
 	}
+}
 
-	// Cancel the main context
-	cancel()
-	_ = ctx // Use ctx to avoid unused error
+// findApp returns the Feishu app of tenantKey; tenantKey may be omitted when only one app is configured
+func findApp(cfg *config.Config, tenantKey string, tenantSet bool) (*config.FeishuConfig, error) {
+	apps := cfg.FeishuAppConfigs()
+	if !tenantSet {
+		if len(apps) > 1 {
+			return nil, fmt.Errorf("%d Feishu apps are configured, select one with --tenant", len(apps))
+		}
+		return apps[0], nil
+	}
+	for _, app := range apps {
+		if app.TenantKey == tenantKey {
+			return app, nil
+		}
+	}
+	return nil, fmt.Errorf("no Feishu app with tenant key %q", tenantKey)
+}
 
-	logger.Info("Server gracefully stopped") // Corrected to use logger.Info
+// flagSet reports whether the flag name was given on the command line
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
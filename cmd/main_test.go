@@ -0,0 +1,199 @@
+package main
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/database"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/crypto"
+	"MikoNews/internal/pkg/fingerprint"
+	"MikoNews/internal/pkg/logger"
+	repositoryImpl "MikoNews/internal/repository/impl"
+	"MikoNews/internal/service/impl"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	logDir, err := os.MkdirTemp("", "miko-cmd-test")
+	if err != nil {
+		panic(err)
+	}
+	logger.InitLogger(filepath.Join(logDir, "test.log"), "warn")
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+// openEnv 在临时的 SQLite 数据库上执行迁移并返回命令环境
+func openEnv(t *testing.T) *commandEnv {
+	t.Helper()
+	db, err := database.New(&config.DatabaseConfig{Driver: config.DatabaseDriverSQLite, Path: filepath.Join(t.TempDir(), "miko_news.db")})
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("加载迁移失败: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	return &commandEnv{cfg: &config.Config{}, db: db}
+}
+
+func TestParseFlags(t *testing.T) {
+	fs := newFlagSet("reforward")
+	chat := fs.String("chat", "", "")
+	positional, err := parseFlags(fs, []string{"42", "--chat", "oc_1", "extra"})
+	if err != nil {
+		t.Fatalf("解析参数失败: %v", err)
+	}
+	if !reflect.DeepEqual(positional, []string{"42", "extra"}) || *chat != "oc_1" {
+		t.Errorf("解析结果不符: %q, chat=%q", positional, *chat)
+	}
+
+	if _, err := parseFlags(newFlagSet("export"), []string{"--unknown"}); !errors.Is(err, errUsage) {
+		t.Errorf("未知参数应返回 errUsage，实际: %v", err)
+	}
+}
+
+func TestFindApp(t *testing.T) {
+	cfg := &config.Config{FeishuApps: []config.FeishuConfig{{TenantKey: "a"}, {TenantKey: "b"}}}
+	if _, err := findApp(cfg, "", false); err == nil {
+		t.Error("多应用部署未指定租户时应返回错误")
+	}
+	if app, err := findApp(cfg, "b", true); err != nil || app.TenantKey != "b" {
+		t.Errorf("应找到租户 b 的应用: %v, %v", app, err)
+	}
+	if _, err := findApp(cfg, "c", true); err == nil {
+		t.Error("不存在的租户应返回错误")
+	}
+	if app, err := findApp(&config.Config{}, "", false); err != nil || app == nil {
+		t.Errorf("单应用部署无需指定租户: %v", err)
+	}
+}
+
+// TestExportImportRoundTrip 导出的文章导入到另一个租户后，作者、状态和时间保持不变
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	env := openEnv(t)
+	repo := repositoryImpl.NewArticleRepository(env.db, "source")
+	createdAt := time.Date(2024, 5, 1, 8, 30, 0, 0, time.Local)
+	cipher, _ := crypto.NewCipher("migration-secret")
+	encrypted, err := cipher.Encrypt("ou_anon")
+	if err != nil {
+		t.Fatalf("加密作者失败: %v", err)
+	}
+	articles := []*model.Article{
+		{Title: "旧文章", Content: "早于 since", AuthorID: "ou_old", AuthorName: "老用户", Source: model.ArticleSourceFeishu,
+			Status: model.ArticleStatusPublished, CreatedAt: createdAt.AddDate(0, -1, 0), UpdatedAt: createdAt.AddDate(0, -1, 0)},
+		{Title: "标题", Content: "正文 https://example.com/a", RawContent: `{"content":[]}`, AuthorID: "ou_a", AuthorName: "作者",
+			Source: model.ArticleSourceTelegram, SourceMessageID: "om_1", Status: model.ArticleStatusWithdrawn, ForwardCount: 2, CreatedAt: createdAt, UpdatedAt: createdAt,
+			Authors: []*model.ArticleAuthor{
+				{OpenID: "ou_a", Name: "作者", Role: model.AuthorRoleAuthor},
+				{OpenID: "ou_b", Name: "合著者", Role: model.AuthorRoleCoAuthor},
+			}},
+		{Title: "匿名", Content: "匿名正文", AuthorName: model.AnonymousAuthorName, Anonymous: true, AuthorIDEncrypted: encrypted,
+			Source: model.ArticleSourceFeishu, Status: model.ArticleStatusPublished, CreatedAt: createdAt.Add(time.Hour), UpdatedAt: createdAt.Add(time.Hour)},
+	}
+	for _, article := range articles {
+		if err := repo.Create(ctx, article); err != nil {
+			t.Fatalf("创建文章失败: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := exportArticles(ctx, env, "source", createdAt, &buf)
+	if err != nil || n != 2 {
+		t.Fatalf("期望导出 2 篇文章: %d, %v", n, err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("ou_anon")) {
+		t.Error("导出文件不应包含匿名作者的明文")
+	}
+
+	target := repositoryImpl.NewArticleRepository(env.db, "target")
+	exported := buf.String()
+	if result, err := importArticles(ctx, impl.NewArticleService(target, cipher), strings.NewReader(exported)); err != nil || result.Imported != 2 {
+		t.Fatalf("期望导入 2 篇文章: %+v, %v", result, err)
+	}
+	// 带来源消息ID的文章重复导入时跳过
+	if result, err := importArticles(ctx, impl.NewArticleService(target, cipher), strings.NewReader(exported)); err != nil || result.Imported != 1 || result.Skipped != 1 {
+		t.Fatalf("重复导入应跳过已导入的消息: %+v, %v", result, err)
+	}
+	imported, err := target.FindAfter(ctx, 0, 10)
+	if err != nil || len(imported) != 3 {
+		t.Fatalf("读取导入的文章失败: %v, %v", imported, err)
+	}
+
+	got := imported[0]
+	if got.Title != "标题" || got.RawContent != `{"content":[]}` || got.AuthorID != "ou_a" || got.Source != model.ArticleSourceTelegram || got.SourceMessageID != "om_1" ||
+		got.Status != model.ArticleStatusWithdrawn || got.ForwardCount != 2 || !got.CreatedAt.Equal(createdAt) {
+		t.Errorf("导入的文章不符: %+v", got)
+	}
+	if len(got.Authors) != 2 {
+		t.Errorf("期望保留 2 个署名，实际: %d", len(got.Authors))
+	}
+	url, _ := fingerprint.NormalizeURL("https://example.com/a")
+	if duplicates, _ := target.FindByFingerprints(ctx, model.FingerprintKindURL, []string{url}, 1, 10); len(duplicates) != 1 {
+		t.Errorf("导入的文章应生成查重指纹，实际命中: %d", len(duplicates))
+	}
+	anonymous := imported[1]
+	if !anonymous.Anonymous || anonymous.AuthorID != "" || len(anonymous.Authors) != 0 {
+		t.Errorf("匿名文章应保持匿名: %+v", anonymous)
+	}
+	// 使用相同密钥的实例可以查看导入的匿名文章的真实作者
+	if authorID, err := impl.NewArticleService(target, cipher).RevealAuthor(ctx, anonymous.ID); err != nil || authorID != "ou_anon" {
+		t.Errorf("导入的匿名文章应保留加密的作者: %q, %v", authorID, err)
+	}
+}
+
+func TestReadJSONRecords(t *testing.T) {
+	for name, input := range map[string]string{
+		"数组":         ` [{"message_id": "om_1", "content": "第一篇"}, {"message_id": "om_2", "content": "第二篇", "created_at": "2024-05-01T08:30:00+08:00"}]`,
+		"JSON Lines": `{"message_id": "om_1", "content": "第一篇"}` + "\n" + `{"message_id": "om_2", "content": "第二篇", "created_at": "2024-05-01T08:30:00+08:00"}` + "\n",
+	} {
+		records, err := readJSONRecords(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: 读取失败: %v", name, err)
+		}
+		if len(records) != 2 || records[0].MessageID != "om_1" || records[1].Content != "第二篇" || records[1].CreatedAt.IsZero() {
+			t.Errorf("%s: 读取结果不符: %+v", name, records)
+		}
+	}
+}
+
+func TestReadCSVRecords(t *testing.T) {
+	input := "\ufeffmessage_id,Author_ID,author_name,created_at,content,extra\n" +
+		"om_1,ou_a,作者,2024-05-01 08:30:00,\"多行\n正文\",忽略\n" +
+		"om_2,ou_b,,2024-05-02,第二篇\n"
+	records, err := readCSVRecords(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("期望 2 条记录，实际: %d", len(records))
+	}
+	first := records[0]
+	if first.MessageID != "om_1" || first.AuthorID != "ou_a" || first.AuthorName != "作者" || first.Content != "多行\n正文" ||
+		!first.CreatedAt.Equal(time.Date(2024, 5, 1, 8, 30, 0, 0, time.Local)) {
+		t.Errorf("第一条记录不符: %+v", first)
+	}
+	if records[1].AuthorName != "" || !records[1].CreatedAt.Equal(time.Date(2024, 5, 2, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("第二条记录不符: %+v", records[1])
+	}
+
+	if _, err := readCSVRecords(strings.NewReader("title,author_id\na,b\n")); err == nil {
+		t.Error("缺少内容列时应返回错误")
+	}
+	if _, err := readCSVRecords(strings.NewReader("content,created_at\na,yesterday\n")); err == nil {
+		t.Error("无效的时间应返回错误")
+	}
+}
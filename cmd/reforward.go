@@ -0,0 +1,69 @@
+package main
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	mh "MikoNews/internal/service/impl/messagehandler"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// runReforward handles `miko_news reforward <article-id>`: it sends the card of a published article to the
+// given group chats, or to all group chats of the app when --chat is omitted
+func runReforward(ctx context.Context, env *commandEnv, args []string) error {
+	fs := newFlagSet("reforward")
+	chats := fs.String("chat", "", "comma separated group chat IDs, defaults to the group chats of the app")
+	tenant := fs.String("tenant", "", "tenant key of the Feishu app, required when several apps are configured")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+	id, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid article id %q: %w", positional[0], errUsage)
+	}
+	app, err := findApp(env.cfg, *tenant, flagSet(fs, "tenant"))
+	if err != nil {
+		return err
+	}
+
+	groupChats := app.GroupChats
+	if *chats != "" {
+		groupChats = nil
+		for _, chat := range strings.Split(*chats, ",") {
+			if chat = strings.TrimSpace(chat); chat != "" {
+				groupChats = append(groupChats, chat)
+			}
+		}
+	}
+	if len(groupChats) == 0 {
+		return fmt.Errorf("no group chats to forward to, pass --chat or configure group_chats")
+	}
+
+	// The webhook service receives the forwarded event, so subscribers see the new forward count
+	feishuBots, err := newFeishuBots(env, []*config.FeishuConfig{app}, newWebhookService(env))
+	if err != nil {
+		return err
+	}
+	feishuBot := feishuBots[0]
+	article, err := feishuBot.GetArticleService().FindArticleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if article.Status != model.ArticleStatusPublished {
+		return fmt.Errorf("article %d is %s, only published articles can be forwarded", id, article.Status)
+	}
+
+	publisher := mh.NewFeishuPublisher(feishuBot.GetMessageService(), feishuBot.GetArticleService(), groupChats)
+	if err := publisher.Publish(ctx, article); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "forwarded article %d to %d group chats\n", id, len(groupChats))
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"MikoNews/internal/api"
+	"MikoNews/internal/api/handler"
+	"MikoNews/internal/bot"
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/crypto"
+	"MikoNews/internal/pkg/health"
+	"MikoNews/internal/pkg/lifecycle"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/metrics"
+	"MikoNews/internal/pkg/tracing"
+	repositoryImpl "MikoNews/internal/repository/impl"
+	"MikoNews/internal/service"
+	"MikoNews/internal/service/impl"
+	"MikoNews/internal/source/telegram"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// runServe handles `miko_news serve`: it runs the bots, API server and background workers until ctx is cancelled
+func runServe(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) > 0 {
+		return errUsage
+	}
+	cfg, gormDB := env.cfg, env.db
+	log := zap.L()
+
+	if cfg.Database.AutoMigrate {
+		if err := migrateUp(ctx, gormDB); err != nil {
+			return fmt.Errorf("failed to run database migrations: %w", err)
+		}
+	}
+
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing.Endpoint, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+		if err != nil {
+			return fmt.Errorf("failed to set up tracing: %w", err)
+		}
+		// Export the remaining spans after the components have stopped; ctx is already cancelled by then
+		defer func() {
+			flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+			defer cancel()
+			if err := shutdownTracing(flushCtx); err != nil {
+				log.Warn("Failed to export remaining spans", zap.Error(err))
+			}
+		}()
+		log.Info("Tracing enabled", zap.String("endpoint", cfg.Tracing.Endpoint), zap.Float64("sampleRatio", cfg.Tracing.SampleRatio))
+	}
+
+	// Expose the connection pool statistics on /metrics
+	sqlDB, err := gormDB.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection pool: %w", err)
+	}
+	if err := metrics.RegisterDBStats(sqlDB, gormDB.Driver); err != nil {
+		return fmt.Errorf("failed to register database metrics: %w", err)
+	}
+
+	// --- Initialize outbound webhooks for article lifecycle events ---
+	webhookService := newWebhookService(env)
+	if err := webhookService.SyncConfigEndpoints(ctx); err != nil {
+		return fmt.Errorf("failed to sync webhook endpoints: %w", err)
+	}
+
+	// --- Initialize Feishu Bots (one per configured app) ---
+	apps := cfg.FeishuAppConfigs()
+	feishuBots, err := newFeishuBots(env, apps, webhookService)
+	if err != nil {
+		return err
+	}
+
+	// --- Create API Server ---
+	// Share the bots' per-tenant article services, so admin API actions notify webhooks and Bitable sync too
+	articleServices := make(handler.TenantArticleServices, len(feishuBots))
+	for _, feishuBot := range feishuBots {
+		articleServices[feishuBot.TenantKey()] = feishuBot.GetArticleService()
+	}
+	// Readiness covers the database and every bot; liveness only fails when a bot's WebSocket stays down
+	checker := health.NewChecker(cfg.Health.Timeout)
+	checker.AddReadiness("database", gormDB.HealthCheck(cfg.Health.DBMaxPoolUsage))
+	for _, feishuBot := range feishuBots {
+		feishuBot.RegisterHealthChecks(checker, &cfg.Health)
+	}
+	apiServer := api.New(cfg, gormDB, articleServices, webhookService, checker)
+	for i, app := range apps {
+		if app.IsWebhookMode() {
+			apiServer.RegisterWebhook(app.EventPath, feishuBots[i].WebhookHandler())
+		}
+	}
+
+	// --- Register components ---
+	// Components stop in reverse order: the API server stops accepting requests first, then the Telegram source
+	// and the bots drain their in-flight messages, followed by each bot's event queue, Bitable sync and user sync,
+	// and the webhook worker stops last so that it still queues the deliveries of articles published while draining
+	manager := lifecycle.NewManager(cfg.Server.ShutdownTimeout)
+	manager.Add(&lifecycle.Component{Name: "webhook-worker", Run: webhookService.Run})
+	for i, app := range apps {
+		log.Info("Feishu Bot configured", zap.String("tenantKey", app.TenantKey), zap.String("eventMode", app.EventMode))
+		for _, component := range feishuBots[i].Components() {
+			manager.Add(component)
+		}
+	}
+
+	if cfg.Telegram.Enabled {
+		var target *bot.FeishuBot
+		for _, feishuBot := range feishuBots {
+			if feishuBot.TenantKey() == cfg.Telegram.TenantKey {
+				target = feishuBot
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("no Feishu app matches telegram.tenant_key %q", cfg.Telegram.TenantKey)
+		}
+		log.Info("Telegram source configured", zap.Int64s("chats", cfg.Telegram.Chats))
+		manager.Add(&lifecycle.Component{Name: "telegram-source", Run: telegram.New(&cfg.Telegram, target.ExternalSubmissions()).Run})
+	}
+
+	manager.Add(&lifecycle.Component{
+		Name: "api-server",
+		Run: func(context.Context) error {
+			if err := apiServer.Start(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+		Stop:     apiServer.Shutdown,
+		Critical: true,
+	})
+
+	// Run until an interrupt signal, then stop the components gracefully
+	log.Info("Starting server...", zap.Int("port", cfg.Server.Port))
+	if err := manager.Run(ctx); err != nil {
+		return err
+	}
+	logger.Info("Server gracefully stopped")
+	return nil
+}
+
+// newWebhookService creates the outbound webhook service; commands that change articles pass it to the bots
+// so that webhook deliveries are queued for their events too
+func newWebhookService(env *commandEnv) service.WebhookService {
+	return impl.NewWebhookService(repositoryImpl.NewWebhookRepository(env.db), &env.cfg.Webhooks, &env.cfg.Server)
+}
+
+// newFeishuBots creates one Feishu bot per app, in the order of apps
+func newFeishuBots(env *commandEnv, apps []*config.FeishuConfig, listeners ...service.ArticleEventListener) ([]*bot.FeishuBot, error) {
+	// --- Initialize Cipher for sensitive fields ---
+	cipher, err := crypto.NewCipher(env.cfg.Admin.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cipher: %w", err)
+	}
+	if !cipher.Enabled() {
+		zap.L().Warn("admin.secret_key is not configured, anonymous submissions are disabled")
+	}
+
+	feishuBots := make([]*bot.FeishuBot, 0, len(apps))
+	for _, app := range apps {
+		feishuBot, err := bot.NewFeishuBot(env.cfg, app, env.db, cipher, listeners...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Feishu bot for tenant %q: %w", app.TenantKey, err)
+		}
+		feishuBots = append(feishuBots, feishuBot)
+	}
+	return feishuBots, nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"MikoNews/internal/source/telegram"
+	"context"
+	"fmt"
+	"os"
+)
+
+// runTelegramLogin handles `miko_news telegram-login`: it logs in to Telegram in the terminal and saves the session
+// to telegram.session_file, so that serve can start without prompting for a login code
+func runTelegramLogin(ctx context.Context, env *commandEnv, args []string) error {
+	if positional, err := parseFlags(newFlagSet("telegram-login"), args); err != nil {
+		return err
+	} else if len(positional) > 0 {
+		return errUsage
+	}
+	if err := telegram.New(&env.cfg.Telegram, nil).Login(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "telegram session saved to %s\n", env.cfg.Telegram.SessionFile)
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"MikoNews/internal/config"
+	"context"
+	"fmt"
+	"os"
+)
+
+// runResyncUsers handles `miko_news resync-users`: it runs a full user directory sync for one app, or for all apps
+// when --tenant is omitted
+func runResyncUsers(ctx context.Context, env *commandEnv, args []string) error {
+	fs := newFlagSet("resync-users")
+	tenant := fs.String("tenant", "", "tenant key of the Feishu app, defaults to all apps")
+	if positional, err := parseFlags(fs, args); err != nil {
+		return err
+	} else if len(positional) > 0 {
+		return errUsage
+	}
+
+	apps := env.cfg.FeishuAppConfigs()
+	if flagSet(fs, "tenant") {
+		app, err := findApp(env.cfg, *tenant, true)
+		if err != nil {
+			return err
+		}
+		apps = []*config.FeishuConfig{app}
+	}
+	feishuBots, err := newFeishuBots(env, apps)
+	if err != nil {
+		return err
+	}
+	for _, feishuBot := range feishuBots {
+		synced, err := feishuBot.GetUserDirectory().SyncAll(ctx)
+		if err != nil {
+			return fmt.Errorf("tenant %q: %w", feishuBot.TenantKey(), err)
+		}
+		fmt.Fprintf(os.Stderr, "tenant %q: synced %d users\n", feishuBot.TenantKey(), synced)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminHandler 处理需要管理员权限的HTTP请求
+type AdminHandler struct {
+	articleServices TenantArticleServices
+}
+
+// NewAdminHandler 创建管理员处理器
+func NewAdminHandler(articleServices TenantArticleServices) *AdminHandler {
+	return &AdminHandler{
+		articleServices: articleServices,
+	}
+}
+
+// AdminArticle 是管理员视角下的文章，包含匿名投稿的真实作者
+type AdminArticle struct {
+	*model.Article
+	RealAuthorID string `json:"real_author_id"` // 真实作者飞书OpenID
+}
+
+// GetArticle godoc
+// @Summary      管理员获取指定ID的文章
+// @Description  返回文章详情，匿名投稿会额外返回解密后的真实作者OpenID
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int     true   "文章ID"
+// @Param        tenant  query     string  false  "租户标识，多应用部署时必填"
+// @Success      200  {object}  response.Response{data=AdminArticle} "成功响应"
+// @Failure      400  {object}  response.Response "无效的文章ID"
+// @Failure      401  {object}  response.Response "未授权"
+// @Failure      500  {object}  response.Response "服务器内部错误"
+// @Router       /admin/articles/{id} [get]
+func (h *AdminHandler) GetArticle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	articleService, ok := h.articleServices.resolve(c)
+	if !ok {
+		return
+	}
+
+	article, err := articleService.FindArticleByID(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("管理员获取文章失败", zap.Error(err), zap.Int64("id", id))
+		handleError(c, err)
+		return
+	}
+
+	realAuthorID, err := articleService.RevealAuthor(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("管理员获取真实作者失败", zap.Error(err), zap.Int64("id", id))
+		handleError(c, err)
+		return
+	}
+
+	logger.Info("管理员查看文章作者", zap.Int64("id", id), zap.Bool("anonymous", article.Anonymous))
+	response.Success(c, &AdminArticle{Article: article, RealAuthorID: realAuthorID})
+}
+
+// WithdrawArticle godoc
+// @Summary      撤回已发布的文章
+// @Description  将已发布的文章置为撤回状态，并触发 article.withdrawn webhook 事件
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int     true   "文章ID"
+// @Param        tenant  query     string  false  "租户标识，多应用部署时必填"
+// @Success      200  {object}  response.Response{data=model.Article} "成功响应"
+// @Failure      400  {object}  response.Response "无效的文章ID"
+// @Failure      401  {object}  response.Response "未授权"
+// @Failure      500  {object}  response.Response "服务器内部错误"
+// @Router       /admin/articles/{id}/withdraw [post]
+func (h *AdminHandler) WithdrawArticle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	articleService, ok := h.articleServices.resolve(c)
+	if !ok {
+		return
+	}
+
+	article, err := articleService.WithdrawArticle(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("管理员撤回文章失败", zap.Error(err), zap.Int64("id", id))
+		handleError(c, err)
+		return
+	}
+
+	logger.Info("管理员撤回文章", zap.Int64("id", id))
+	response.Success(c, article)
+}
@@ -4,7 +4,6 @@ import (
 	"MikoNews/internal/pkg/errors"
 	"MikoNews/internal/pkg/logger"
 	"MikoNews/internal/pkg/response"
-	"MikoNews/internal/service"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -13,23 +12,24 @@ import (
 
 // ArticleHandler 处理文章相关的HTTP请求
 type ArticleHandler struct {
-	articleService service.ArticleService
+	articleServices TenantArticleServices
 }
 
 // NewArticleHandler 创建文章处理器
-func NewArticleHandler(articleService service.ArticleService) *ArticleHandler {
+func NewArticleHandler(articleServices TenantArticleServices) *ArticleHandler {
 	return &ArticleHandler{
-		articleService: articleService,
+		articleServices: articleServices,
 	}
 }
 
 // GetArticle godoc
 // @Summary      获取指定ID的文章
-// @Description  根据提供的文章ID获取详细信息
+// @Description  根据提供的文章ID获取已发布文章的详细信息，待审核、已驳回和已撤回的文章返回 404
 // @Tags         Articles
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "文章ID"
+// @Param        id      path      int     true   "文章ID"
+// @Param        tenant  query     string  false  "租户标识，多应用部署时必填"
 // @Success      200  {object}  response.Response{data=model.Article} "成功响应"
 // @Failure      400  {object}  response.Response "无效的文章ID"
 // @Failure      404  {object}  response.Response "文章未找到"
@@ -44,8 +44,13 @@ func (h *ArticleHandler) GetArticle(c *gin.Context) {
 		return
 	}
 
-	// 获取文章
-	article, err := h.articleService.FindArticleByID(c.Request.Context(), id)
+	articleService, ok := h.articleServices.resolve(c)
+	if !ok {
+		return
+	}
+
+	// 获取文章，公开接口只返回已发布的文章
+	article, err := articleService.FindPublishedArticle(c.Request.Context(), id)
 	if err != nil {
 		logger.Error("获取文章失败", zap.Error(err), zap.Int64("id", id))
 		handleError(c, err)
@@ -55,6 +60,31 @@ func (h *ArticleHandler) GetArticle(c *gin.Context) {
 	response.Success(c, article)
 }
 
+// GetStats godoc
+// @Summary      获取投稿统计
+// @Description  返回文章总数、作者数、最近 7 天投稿数，以及投稿榜和合著榜
+// @Tags         Articles
+// @Produce      json
+// @Param        tenant  query     string  false  "租户标识，多应用部署时必填"
+// @Success      200  {object}  response.Response{data=model.ArticleStats} "成功响应"
+// @Failure      500  {object}  response.Response "服务器内部错误"
+// @Router       /stats [get]
+func (h *ArticleHandler) GetStats(c *gin.Context) {
+	articleService, ok := h.articleServices.resolve(c)
+	if !ok {
+		return
+	}
+
+	stats, err := articleService.GetArticleStats(c.Request.Context())
+	if err != nil {
+		logger.Error("获取投稿统计失败", zap.Error(err))
+		handleError(c, err)
+		return
+	}
+
+	response.Success(c, stats)
+}
+
 // 处理错误
 func handleError(c *gin.Context, err error) {
 	// 尝试转换为应用错误
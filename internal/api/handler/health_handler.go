@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"MikoNews/internal/pkg/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 处理容器编排系统的存活与就绪探针
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler 创建健康检查处理器
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Live godoc
+// @Summary      存活检查
+// @Description  只包含需要重启进程才能恢复的检查，如 WebSocket 长连接长时间失效；失败时编排系统应重启容器
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  health.Report "存活"
+// @Failure      503  {object}  health.Report "需要重启"
+// @Router       /healthz/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	writeReport(c, h.checker.Live(c.Request.Context()))
+}
+
+// Ready godoc
+// @Summary      就绪检查
+// @Description  检查数据库、飞书 tenant_access_token、WebSocket 长连接和事件队列积压，返回各组件的状态与检查耗时
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  health.Report "就绪"
+// @Failure      503  {object}  health.Report "未就绪"
+// @Router       /healthz/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	writeReport(c, h.checker.Ready(c.Request.Context()))
+}
+
+// writeReport 输出检查结果，任一组件不可用时返回 503
+func writeReport(c *gin.Context, report *health.Report) {
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"MikoNews/internal/pkg/response"
+	"MikoNews/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantArticleServices 按飞书租户标识 (tenant_key) 索引的文章服务，每个服务只能访问所属租户的文章
+type TenantArticleServices map[string]service.ArticleService
+
+// resolve 按请求的 tenant 查询参数选择文章服务，只有一个租户时可省略该参数
+// 找不到对应租户时直接写入错误响应并返回 false
+func (s TenantArticleServices) resolve(c *gin.Context) (service.ArticleService, bool) {
+	tenantKey, ok := c.GetQuery("tenant")
+	if !ok && len(s) == 1 {
+		for _, articleService := range s {
+			return articleService, true
+		}
+	}
+	if !ok {
+		response.BadRequest(c, "缺少 tenant 参数")
+		return nil, false
+	}
+	articleService, ok := s[tenantKey]
+	if !ok {
+		response.NotFound(c, "租户不存在")
+		return nil, false
+	}
+	return articleService, true
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/response"
+	"MikoNews/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// defaultDeliveryLimit 是查询投递记录时的默认条数
+const defaultDeliveryLimit = 50
+
+// WebhookHandler 处理 webhook 端点管理相关的HTTP请求，需要管理员权限
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler 创建 webhook 处理器
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreatedWebhookEndpoint 是新建端点的响应，签名密钥只在创建时返回一次
+type CreatedWebhookEndpoint struct {
+	*model.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// ListEndpoints godoc
+// @Summary      列出 webhook 端点
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]model.WebhookEndpoint} "成功响应"
+// @Failure      401  {object}  response.Response "未授权"
+// @Failure      500  {object}  response.Response "服务器内部错误"
+// @Router       /admin/webhooks [get]
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	endpoints, err := h.webhookService.ListEndpoints(c.Request.Context())
+	if err != nil {
+		logger.Error("获取 webhook 端点失败", zap.Error(err))
+		handleError(c, err)
+		return
+	}
+	response.Success(c, endpoints)
+}
+
+// CreateEndpoint godoc
+// @Summary      注册 webhook 端点
+// @Description  未指定 secret 时自动生成；events 为空表示订阅全部事件
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        endpoint  body      service.WebhookEndpointInput  true  "端点信息"
+// @Success      201  {object}  response.Response{data=CreatedWebhookEndpoint} "成功响应"
+// @Failure      400  {object}  response.Response "参数无效"
+// @Failure      401  {object}  response.Response "未授权"
+// @Failure      500  {object}  response.Response "服务器内部错误"
+// @Router       /admin/webhooks [post]
+func (h *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	var input service.WebhookEndpointInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "无效的请求参数")
+		return
+	}
+
+	endpoint, secret, err := h.webhookService.CreateEndpoint(c.Request.Context(), &input)
+	if err != nil {
+		logger.Error("注册 webhook 端点失败", zap.Error(err), zap.String("name", input.Name))
+		handleError(c, err)
+		return
+	}
+	response.Created(c, &CreatedWebhookEndpoint{WebhookEndpoint: endpoint, Secret: secret})
+}
+
+// DeleteEndpoint godoc
+// @Summary      删除 webhook 端点
+// @Description  只能删除通过接口注册的端点，配置文件中的端点需修改配置
+// @Tags         Webhooks
+// @Security     BearerAuth
+// @Param        id   path      int  true  "端点ID"
+// @Success      204  "删除成功"
+// @Failure      400  {object}  response.Response "无效的端点ID或端点来自配置文件"
+// @Failure      401  {object}  response.Response "未授权"
+// @Failure      404  {object}  response.Response "端点不存在"
+// @Router       /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的端点ID")
+		return
+	}
+
+	if err := h.webhookService.DeleteEndpoint(c.Request.Context(), id); err != nil {
+		logger.Error("删除 webhook 端点失败", zap.Error(err), zap.Int64("id", id))
+		handleError(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// ListDeliveries godoc
+// @Summary      查询 webhook 投递记录
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int  true   "端点ID"
+// @Param        limit  query     int  false  "返回条数，默认 50"
+// @Success      200  {object}  response.Response{data=[]model.WebhookDelivery} "成功响应"
+// @Failure      400  {object}  response.Response "无效的端点ID"
+// @Failure      401  {object}  response.Response "未授权"
+// @Failure      404  {object}  response.Response "端点不存在"
+// @Router       /admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的端点ID")
+		return
+	}
+	limit := defaultDeliveryLimit
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			response.BadRequest(c, "无效的 limit 参数")
+			return
+		}
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), id, limit)
+	if err != nil {
+		logger.Error("查询 webhook 投递记录失败", zap.Error(err), zap.Int64("id", id))
+		handleError(c, err)
+		return
+	}
+	response.Success(c, deliveries)
+}
+
+// Redeliver godoc
+// @Summary      重新投递
+// @Description  将投递记录重新加入投递队列，重置尝试次数
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "投递记录ID"
+// @Success      200  {object}  response.Response{data=model.WebhookDelivery} "成功响应"
+// @Failure      400  {object}  response.Response "无效的投递记录ID"
+// @Failure      401  {object}  response.Response "未授权"
+// @Failure      404  {object}  response.Response "投递记录不存在"
+// @Router       /admin/webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的投递记录ID")
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("重新投递 webhook 失败", zap.Error(err), zap.Int64("id", id))
+		handleError(c, err)
+		return
+	}
+	logger.Info("管理员重新投递 webhook", zap.Int64("deliveryID", id))
+	response.Success(c, delivery)
+}
@@ -2,13 +2,20 @@ package middleware
 
 import (
 	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/metrics"
 	"MikoNews/internal/pkg/response"
+	"MikoNews/internal/pkg/tracing"
 	"bytes"
+	"crypto/subtle"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +36,31 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
+// Tracing 链路追踪中间件，为每个请求创建 server span，并延续请求头中传入的追踪上下文
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Request.Method
+		if route := c.FullPath(); route != "" {
+			name += " " + route
+		}
+		ctx, span := tracing.StartKind(tracing.FromHeader(c.Request.Context(), c.Request.Header), trace.SpanKindServer, name,
+			semconv.HTTPRequestMethodKey.String(c.Request.Method),
+			semconv.HTTPRoute(c.FullPath()),
+			semconv.URLPath(c.Request.URL.Path),
+		)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
 // Logger 日志中间件，记录请求信息
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -54,9 +86,10 @@ func Logger() gin.HandlerFunc {
 
 		// 计算耗时
 		latency := time.Since(start)
+		metrics.ObserveHTTPRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), latency)
 
 		// 使用 zap 记录请求信息
-		logger.Info("Request processed",
+		logger.Ctx(c.Request.Context()).Info("Request processed",
 			zap.String("requestID", requestID.(string)),
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("latency", latency),
@@ -110,6 +143,29 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
+// AdminAuth 管理员鉴权中间件，要求请求携带 Authorization: Bearer <token>
+func AdminAuth(tokens []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !containsToken(tokens, token) {
+			response.Unauthorized(c, "未授权的访问")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// containsToken 以常量时间比较令牌，避免时序攻击
+func containsToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
 // generateRequestID 生成请求ID
 func generateRequestID() string {
 	// 简单实现，实际项目中可以使用UUID或其他更复杂的方法
@@ -4,6 +4,7 @@ import (
 	"MikoNews/internal/api/handler"
 	"MikoNews/internal/api/middleware"
 	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/metrics"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
@@ -14,10 +15,14 @@ import (
 func Setup(
 	engine *gin.Engine,
 	articleHandler *handler.ArticleHandler,
+	adminHandler *handler.AdminHandler,
+	webhookHandler *handler.WebhookHandler,
+	healthHandler *handler.HealthHandler,
 	config *config.Config,
 ) {
 	// 使用中间件
 	engine.Use(middleware.RequestID())
+	engine.Use(middleware.Tracing())
 	engine.Use(middleware.Logger())
 	engine.Use(middleware.Recovery())
 	engine.Use(middleware.CORS())
@@ -25,12 +30,22 @@ func Setup(
 	// 健康检查路由
 	setupHealthRoutes(engine)
 
+	// 存活与就绪探针，供容器编排系统使用
+	engine.GET("/healthz/live", healthHandler.Live)
+	engine.GET("/healthz/ready", healthHandler.Ready)
+
+	// Prometheus 指标
+	engine.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// API v1 路由组
 	v1 := engine.Group("/api/v1")
 	{
 		// 文章相关路由
 		setupArticleRoutes(v1, articleHandler, config)
 
+		// 管理员路由
+		setupAdminRoutes(v1, adminHandler, webhookHandler, config)
+
 		// 其他路由...
 		// setupUserRoutes(v1, userHandler)
 		// setupCommentRoutes(v1, commentHandler)
@@ -52,4 +67,30 @@ func setupArticleRoutes(
 		// 获取特定文章
 		articles.GET("/:id", handler.GetArticle)
 	}
+
+	// 投稿统计（含合著榜）
+	router.GET("/stats", handler.GetStats)
+}
+
+// setupAdminRoutes 配置管理员路由，需携带管理员令牌访问
+func setupAdminRoutes(
+	router *gin.RouterGroup,
+	handler *handler.AdminHandler,
+	webhookHandler *handler.WebhookHandler,
+	config *config.Config,
+) {
+	admin := router.Group("/admin", middleware.AdminAuth(config.Admin.APITokens))
+	{
+		// 获取文章（含匿名投稿的真实作者）
+		admin.GET("/articles/:id", handler.GetArticle)
+		// 撤回已发布的文章
+		admin.POST("/articles/:id/withdraw", handler.WithdrawArticle)
+
+		// webhook 端点与投递记录
+		admin.GET("/webhooks", webhookHandler.ListEndpoints)
+		admin.POST("/webhooks", webhookHandler.CreateEndpoint)
+		admin.DELETE("/webhooks/:id", webhookHandler.DeleteEndpoint)
+		admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+		admin.POST("/webhooks/deliveries/:id/redeliver", webhookHandler.Redeliver)
+	}
 }
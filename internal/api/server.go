@@ -5,32 +5,45 @@ import (
 	"MikoNews/internal/api/router"
 	"MikoNews/internal/config"
 	"MikoNews/internal/database"
-	"MikoNews/internal/repository/impl/mysql"
-	"MikoNews/internal/service/impl"
+	"MikoNews/internal/pkg/health"
+	"MikoNews/internal/service"
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // Server 是API服务器结构体
 type Server struct {
-	config  *config.Config // 配置对象
-	db      *database.DB   // 数据库连接
-	engine  *gin.Engine    // Gin引擎
-	started bool           // 是否已启动
+	config          *config.Config                // 配置对象
+	db              *database.DB                  // 数据库连接
+	articleServices handler.TenantArticleServices // 各租户的文章服务，与机器人共用，管理操作同样会触发文章事件
+	webhooks        service.WebhookService        // webhook 端点管理
+	checker         *health.Checker               // 存活与就绪检查
+	engine          *gin.Engine                   // Gin引擎
+	httpServer      *http.Server                  // 承载 Gin 引擎的 HTTP 服务器
+	started         bool                          // 是否已启动
 }
 
 // New 创建新的API服务器
-func New(config *config.Config, db *database.DB) *Server {
+func New(config *config.Config, db *database.DB, articleServices handler.TenantArticleServices, webhooks service.WebhookService, checker *health.Checker) *Server {
 	// 创建Gin引擎
 	gin.SetMode(gin.ReleaseMode) // 生产模式
 	engine := gin.New()
 
 	s := &Server{
-		config: config,
-		db:     db,
-		engine: engine,
+		config:          config,
+		db:              db,
+		articleServices: articleServices,
+		webhooks:        webhooks,
+		checker:         checker,
+		engine:          engine,
+		httpServer: &http.Server{
+			Addr:    fmt.Sprintf(":%d", config.Server.Port),
+			Handler: engine,
+		},
 	}
 
 	// 初始化服务器
@@ -41,27 +54,35 @@ func New(config *config.Config, db *database.DB) *Server {
 
 // init 初始化服务器
 func (s *Server) init() {
-	// 在这里创建 Repository 和 Service
-	articleRepo := mysql.NewArticleRepository(s.db.DB)
-	articleService := impl.NewArticleService(articleRepo)
-
 	// 创建处理器
-	articleHandler := handler.NewArticleHandler(articleService)
+	articleHandler := handler.NewArticleHandler(s.articleServices)
+	adminHandler := handler.NewAdminHandler(s.articleServices)
+	webhookHandler := handler.NewWebhookHandler(s.webhooks)
+	healthHandler := handler.NewHealthHandler(s.checker)
 
 	// 配置路由
-	router.Setup(s.engine, articleHandler, s.config)
+	router.Setup(s.engine, articleHandler, adminHandler, webhookHandler, healthHandler, s.config)
+}
+
+// RegisterWebhook 在指定路径挂载处理 POST 回调的 handler，如飞书事件回调
+func (s *Server) RegisterWebhook(path string, handler http.Handler) {
+	s.engine.POST(path, gin.WrapH(handler))
 }
 
-// Start 启动HTTP服务器
+// Start 启动HTTP服务器，阻塞到服务器关闭；调用 Shutdown 后返回 http.ErrServerClosed
 func (s *Server) Start() error {
 	if s.started {
 		return fmt.Errorf("服务器已经启动")
 	}
 
 	s.started = true
-	addr := fmt.Sprintf(":%d", s.config.Server.Port)
-	log.Printf("API服务器开始监听 %s", addr)
-	return s.engine.Run(addr)
+	log.Printf("API服务器开始监听 %s", s.httpServer.Addr)
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown 停止接受新的连接，并等待进行中的请求处理完成或 ctx 结束
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
 }
 
 // GetEngine 获取Gin引擎，方便测试
@@ -3,13 +3,21 @@ package bot
 import (
 	"MikoNews/internal/config"
 	"MikoNews/internal/database"
+	"MikoNews/internal/pkg/crypto"
+	"MikoNews/internal/pkg/lifecycle"
 	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/tracing"
 	"MikoNews/internal/repository"
-	"MikoNews/internal/repository/impl/mysql"
+	repositoryImpl "MikoNews/internal/repository/impl"
+	"MikoNews/internal/repository/impl/memory"
 	"MikoNews/internal/service"
 	articleServiceImpl "MikoNews/internal/service/impl"
+	"MikoNews/internal/service/impl/contentfilter"
 	mh "MikoNews/internal/service/impl/messagehandler"
+	"MikoNews/internal/service/impl/publisher"
 	"context"
+	"fmt"
+	"net/http"
 
 	lark "github.com/larksuite/oapi-sdk-go/v3"
 	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
@@ -27,31 +35,101 @@ type FeishuBot struct {
 	messageHandlingService service.MessageHandlingService
 	dispatcher             *FeishuEventDispatcher
 	msgService             service.FeishuMessageService
+	userDirectory          service.UserDirectoryService
+	externalSubmissions    service.ExternalSubmissionService
+	bitableSync            service.BitableSyncService // 未配置多维表格时为 nil
+	eventQueue             service.EventQueueService  // 同步处理消息事件时为 nil
+	contactConf            *config.ContactConfig
+	wsMonitor              *wsMonitor // WebSocket 长连接的状态
 }
 
-// NewFeishuBot 创建一个新的 FeishuBot 实例
-func NewFeishuBot(conf *config.FeishuConfig, db *database.DB) *FeishuBot {
-
+// NewFeishuBot 为 conf 指定的飞书应用创建一个 FeishuBot 实例，文章和用户数据限定在该应用的租户内
+func NewFeishuBot(cfg *config.Config, conf *config.FeishuConfig, db *database.DB, cipher *crypto.Cipher, listeners ...service.ArticleEventListener) (*FeishuBot, error) {
 	// Create API client
 	apiClient := lark.NewClient(conf.AppID, conf.AppSecret,
+		lark.WithOpenBaseUrl(conf.BaseURL()),
 		lark.WithLogLevel(larkcore.LogLevelDebug),
 		lark.WithLogReqAtDebug(true),
+		// 每次 HTTP 调用（含获取 tenant_access_token）都在调用方的链路中创建 span
+		lark.WithHttpClient(&http.Client{Transport: tracing.Transport(nil)}),
 	)
 
 	// --- Create Dependencies ---
 	// Repository
-	articleRepo := mysql.NewArticleRepository(db.DB)
+	articleRepo := repositoryImpl.NewArticleRepository(db, conf.TenantKey)
+	userRepo := repositoryImpl.NewUserRepository(db, conf.TenantKey)
+	// open_id 和群聊ID只在所属应用内有效，管理员、投稿策略和限流使用本应用的配置
+	adminConf := cfg.AppAdmin(conf)
+	rateLimitConf := cfg.AppRateLimit(conf)
+	rateLimitRepo := repositoryImpl.NewRateLimitRepository(db)
+	if rateLimitConf.Backend == config.RateLimitBackendMemory {
+		rateLimitRepo = memory.NewRateLimitRepository()
+	}
 
 	// Services
-	articleService := articleServiceImpl.NewArticleService(articleRepo)
+	// Bitable sync reads articles from the repository, so it can listen to the article service it is registered with
+	var bitableSync service.BitableSyncService
+	if conf.Bitable.Enabled() {
+		bitableService := articleServiceImpl.NewFeishuBitableService(apiClient)
+		bitableSync = articleServiceImpl.NewBitableSyncService(articleRepo, bitableService, &conf.Bitable, &cfg.Server, conf.TenantKey)
+		listeners = append(listeners, bitableSync)
+	}
+	articleService := articleServiceImpl.NewArticleService(articleRepo, cipher, listeners...)
 	msgService := articleServiceImpl.NewFeishuMessageService(apiClient)
 	feishuContactService := articleServiceImpl.NewFeishuContactService(apiClient)
+	botIdentityService := articleServiceImpl.NewFeishuBotIdentityService(apiClient, conf)
+	userDirectory := articleServiceImpl.NewUserDirectoryService(feishuContactService, userRepo, articleRepo, &cfg.Contact)
+	policyService := articleServiceImpl.NewSubmissionPolicyService(cfg.AppSubmissionPolicy(conf), userDirectory, msgService)
+	rateLimiter := articleServiceImpl.NewSubmissionRateLimiter(rateLimitRepo, rateLimitConf, adminConf)
+	contentFilters, err := contentfilter.NewFiltersFromConfig(&cfg.Moderation)
+	if err != nil {
+		return nil, fmt.Errorf("初始化内容审核失败: %w", err)
+	}
+	moderationService := contentfilter.NewContentModerationService(contentFilters...)
+	// Publishers: Feishu group chats, Feishu Docs / Wiki archive when configured, plus Telegram channels for the tenant bridged to Telegram
+	publishers := []service.Publisher{mh.NewFeishuPublisher(msgService, articleService, conf.GroupChats)}
+	if conf.Docs.Mode != "" {
+		docService := articleServiceImpl.NewFeishuDocService(apiClient)
+		publishers = append(publishers, publisher.NewFeishuDocPublisher(&conf.Docs, docService, msgService, articleService))
+	}
+	if len(cfg.Telegram.Publish.Channels) > 0 && cfg.Telegram.TenantKey == conf.TenantKey {
+		publishers = append(publishers, publisher.NewTelegramPublisher(&cfg.Telegram.Publish, msgService))
+	}
+	articlePublisher := publisher.NewMultiPublisher(publishers...)
+
 	// Message Handling Strategies (Use alias 'mh')
-	submissionStrategy := mh.NewSubmissionHandlerStrategy(articleService, msgService, feishuContactService, conf)
+	submissionStrategy := mh.NewSubmissionHandlerStrategy(articleService, msgService, articlePublisher, userDirectory, policyService, rateLimiter, moderationService, adminConf, &cfg.Server)
+	revealAuthorStrategy := mh.NewAdminRevealAuthorHandlerStrategy(articleService, msgService, adminConf)
+	reviewStrategy := mh.NewAdminReviewHandlerStrategy(articleService, msgService, articlePublisher, adminConf)
+	archiveService := mh.NewMessageArchiveService(articleService, msgService, articlePublisher, userDirectory, policyService, moderationService, adminConf)
+	externalSubmissions := mh.NewExternalSubmissionService(articleService, msgService, articlePublisher, moderationService, adminConf)
+	groupArchiveStrategy := mh.NewGroupArchiveHandlerStrategy(archiveService, msgService, botIdentityService)
+	groupLatestStrategy := mh.NewGroupLatestHandlerStrategy(articleService, msgService, botIdentityService)
+	groupSearchStrategy := mh.NewGroupSearchHandlerStrategy(articleService, msgService, botIdentityService)
+	groupStatsStrategy := mh.NewGroupStatsHandlerStrategy(articleService, msgService, botIdentityService)
+	groupHelpStrategy := mh.NewGroupHelpHandlerStrategy(msgService, botIdentityService)
 	defaultStrategy := mh.NewDefaultMessageHandlerStrategy()
 
 	// Message Handling Service (Use alias 'mh')
-	messageHandlingService := mh.NewMessageHandlingService(submissionStrategy, defaultStrategy)
+	// Order matters: the group help strategy is the fallback for @-mentions in group chats,
+	// and the default strategy is the fallback for P2P messages.
+	strategies := []service.MessageHandlerStrategy{
+		submissionStrategy,
+		revealAuthorStrategy,
+		reviewStrategy,
+	}
+	if bitableSync != nil {
+		strategies = append(strategies, mh.NewAdminBitableResyncHandlerStrategy(bitableSync, msgService, adminConf))
+	}
+	strategies = append(strategies,
+		groupArchiveStrategy,
+		groupLatestStrategy,
+		groupSearchStrategy,
+		groupStatsStrategy,
+		groupHelpStrategy,
+		defaultStrategy,
+	)
+	messageHandlingService := mh.NewMessageHandlingService(strategies...)
 
 	// --- Create Bot and Dispatcher ---
 	bot := &FeishuBot{
@@ -62,24 +140,115 @@ func NewFeishuBot(conf *config.FeishuConfig, db *database.DB) *FeishuBot {
 		articleService:         articleService,
 		messageHandlingService: messageHandlingService,
 		msgService:             msgService,
+		userDirectory:          userDirectory,
+		externalSubmissions:    externalSubmissions,
+		bitableSync:            bitableSync,
+		contactConf:            &cfg.Contact,
+	}
+
+	// Event queue: message events are stored and acknowledged at once, then processed by a pool of workers
+	var eventQueue service.EventQueueService
+	if cfg.EventQueue.IsAsync() {
+		eventQueue = articleServiceImpl.NewEventQueueService(repositoryImpl.NewEventJobRepository(db, conf.TenantKey), &cfg.EventQueue, conf.TenantKey)
 	}
+	bot.eventQueue = eventQueue
 
 	// Event Dispatcher (injects the handling service)
-	bot.dispatcher = NewFeishuEventDispatcher(conf, bot, messageHandlingService)
+	bot.dispatcher = NewFeishuEventDispatcher(conf, bot, messageHandlingService, archiveService, msgService, userDirectory, eventQueue)
 
-	// WebSocket Client
+	// WebSocket Client (unused in webhook mode); its logs are watched to track the connection state
+	bot.wsMonitor = newWSMonitor(conf.TenantKey)
 	bot.client = larkws.NewClient(conf.AppID, conf.AppSecret,
 		larkws.WithEventHandler(bot.dispatcher.GetEventDispatcher()),
+		larkws.WithDomain(conf.BaseURL()),
 		larkws.WithLogLevel(larkcore.LogLevelDebug),
+		larkws.WithLogger(bot.wsMonitor),
 	)
 
-	logger.Info("FeishuBot initialized")
-	return bot
+	logger.Info("FeishuBot initialized", "tenantKey", conf.TenantKey, "appID", conf.AppID)
+	return bot, nil
+}
+
+// Components 返回机器人的生命周期组件，按启动顺序排列：通讯录定期同步、多维表格同步、消息事件队列、机器人本身。
+// 关闭时按相反顺序停止：机器人先停止接收事件，事件队列处理完进行中的事件，多维表格同步再写入这些事件产生的变更
+func (b *FeishuBot) Components() []*lifecycle.Component {
+	tenantKey := b.conf.TenantKey
+	components := []*lifecycle.Component{{
+		// 定期全量同步通讯录，事件之间的增量变更由通讯录事件处理
+		Name: "user-sync:" + tenantKey,
+		Run: func(ctx context.Context) error {
+			b.userDirectory.RunPeriodicSync(ctx, b.contactConf.SyncInterval)
+			return nil
+		},
+		Stop: b.userDirectory.StopPeriodicSync,
+	}}
+	if b.bitableSync != nil {
+		// 按文章生命周期事件增量同步多维表格
+		components = append(components, &lifecycle.Component{
+			Name: "bitable-sync:" + tenantKey,
+			Run:  b.bitableSync.Run,
+			Stop: b.bitableSync.Drain,
+		})
+	}
+	if b.eventQueue != nil {
+		// 异步处理入队的消息事件
+		components = append(components, &lifecycle.Component{
+			Name: "event-queue:" + tenantKey,
+			Run:  b.eventQueue.Run,
+			Stop: b.eventQueue.Drain,
+		})
+	}
+	return append(components, &lifecycle.Component{
+		Name: "feishu-bot:" + tenantKey,
+		Run:  b.Start,
+		Stop: b.Stop,
+	})
 }
 
-// Start 启动飞书机器人，建立 WebSocket 长连接
+// Start 启动飞书机器人，WebSocket 模式下建立长连接，运行到 ctx 被取消为止；后台任务由 Components 中的其他组件运行
 func (b *FeishuBot) Start(ctx context.Context) error {
-	return b.client.Start(ctx) // 启动客户端
+	// webhook 模式下事件由 HTTP 服务器上挂载的 WebhookHandler 接收，无需建立长连接
+	if b.conf.IsWebhookMode() {
+		logger.Info("FeishuBot running in webhook mode", "tenantKey", b.conf.TenantKey, "path", b.conf.EventPath)
+		if b.conf.EncryptKey == "" {
+			logger.Warn("Feishu encrypt key is empty, webhook requests will not be signature-verified")
+		}
+		<-ctx.Done()
+		return nil
+	}
+
+	// SDK 的长连接客户端连接成功后永不返回，也没有关闭方法，因此在后台运行并在 ctx 取消时返回。
+	// 此前 Stop 已让分发器拒绝新的事件，飞书会将其推送给重启后的连接
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- b.client.Start(ctx)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// Stop 停止接收新的飞书事件，并等待进行中的事件处理完成或 ctx 结束
+func (b *FeishuBot) Stop(ctx context.Context) error {
+	return b.dispatcher.Drain(ctx)
+}
+
+// WebhookHandler 返回 webhook 模式下接收飞书事件回调的 http.Handler
+func (b *FeishuBot) WebhookHandler() http.Handler {
+	return NewWebhookHandler(b.dispatcher.GetEventDispatcher())
+}
+
+// TenantKey 返回机器人所属应用的租户标识
+func (b *FeishuBot) TenantKey() string {
+	return b.conf.TenantKey
+}
+
+// ExternalSubmissions 返回收录外部来源（如 Telegram）投稿的服务，文章保存在本应用的租户内并转发到本应用的群聊
+func (b *FeishuBot) ExternalSubmissions() service.ExternalSubmissionService {
+	return b.externalSubmissions
 }
 
 // GetClient 获取API客户端
@@ -107,6 +276,11 @@ func (b *FeishuBot) GetMessageService() service.FeishuMessageService {
 	return b.msgService
 }
 
+// GetUserDirectory 获取用户目录服务
+func (b *FeishuBot) GetUserDirectory() service.UserDirectoryService {
+	return b.userDirectory
+}
+
 // GetConfig 返回配置
 func (b *FeishuBot) GetConfig() *config.FeishuConfig {
 	return b.conf
@@ -121,3 +295,8 @@ func (b *FeishuBot) GetDB() *database.DB {
 func (b *FeishuBot) GetEventDispatcher() *FeishuEventDispatcher {
 	return b.dispatcher
 }
+
+// GetEventQueue 返回消息事件队列，同步处理消息事件时为 nil
+func (b *FeishuBot) GetEventQueue() service.EventQueueService {
+	return b.eventQueue
+}
@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/health"
+	"context"
+	"fmt"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+)
+
+// RegisterHealthChecks 注册机器人的健康检查，组件名以租户标识结尾：
+//   - feishu_token: 能否获取 tenant_access_token，即应用凭证有效且飞书开放平台可以访问
+//   - feishu_ws: WebSocket 长连接的状态，仅 WebSocket 模式；连接长时间失效时存活检查失败，由编排系统重启进程
+//   - event_queue: 事件队列的积压情况，仅异步处理消息事件时
+func (b *FeishuBot) RegisterHealthChecks(checker *health.Checker, conf *config.HealthConfig) {
+	suffix := ":" + b.conf.TenantKey
+	checker.AddReadiness("feishu_token"+suffix, health.Cached(conf.TokenCheckInterval, b.checkTenantToken))
+	if !b.conf.IsWebhookMode() {
+		checker.AddReadiness("feishu_ws"+suffix, b.wsMonitor.Check(conf.WSMaxSilence, 0))
+		checker.AddLiveness("feishu_ws_alive"+suffix, b.wsMonitor.Check(conf.WSMaxSilence, conf.WSMaxSilence))
+	}
+	if b.eventQueue != nil {
+		checker.AddReadiness("event_queue"+suffix, b.checkEventQueue(conf.QueueMaxPending, conf.QueueMaxAge))
+	}
+}
+
+// checkTenantToken 获取一次 tenant_access_token
+func (b *FeishuBot) checkTenantToken(ctx context.Context) (map[string]any, error) {
+	resp, err := b.apiClient.GetTenantAccessTokenBySelfBuiltApp(ctx, &larkcore.SelfBuiltTenantAccessTokenReq{
+		AppID:     b.conf.AppID,
+		AppSecret: b.conf.AppSecret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取 tenant_access_token 失败: %w", err)
+	}
+	if !resp.Success() {
+		return nil, fmt.Errorf("获取 tenant_access_token 失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+	return map[string]any{"expires_in_s": resp.Expire}, nil
+}
+
+// checkEventQueue 返回事件队列的检查：待处理事件数超过 maxPending，或最早的待处理事件等待超过 maxAge 时不可用
+func (b *FeishuBot) checkEventQueue(maxPending int64, maxAge time.Duration) health.Check {
+	return func(ctx context.Context) (map[string]any, error) {
+		stats, err := b.eventQueue.Stats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		details := map[string]any{
+			"pending":           stats.Pending,
+			"oldest_pending_ms": stats.OldestPending.Milliseconds(),
+			"buffered":          stats.Buffered,
+			"active":            stats.Active,
+			"failed":            stats.Failed,
+		}
+		if stats.Pending > maxPending {
+			return details, fmt.Errorf("待处理事件过多: %d > %d", stats.Pending, maxPending)
+		}
+		if stats.OldestPending > maxAge {
+			return details, fmt.Errorf("事件积压: 最早的待处理事件已等待 %s", stats.OldestPending.Round(time.Second))
+		}
+		return details, nil
+	}
+}
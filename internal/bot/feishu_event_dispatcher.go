@@ -2,14 +2,23 @@ package bot
 
 import (
 	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/lifecycle"
 	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/metrics"
+	"MikoNews/internal/pkg/tracing"
 	"MikoNews/internal/service"
+	mh "MikoNews/internal/service/impl/messagehandler"
 	"context"
+	"encoding/json"
+	"fmt"
 
 	larkevent "github.com/larksuite/oapi-sdk-go/v3/event"
 	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
 	larkapplication "github.com/larksuite/oapi-sdk-go/v3/service/application/v6"
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // FeishuEventDispatcher 负责分发和处理飞书事件
@@ -17,33 +26,268 @@ type FeishuEventDispatcher struct {
 	conf                   *config.FeishuConfig
 	bot                    *FeishuBot
 	messageHandlingService service.MessageHandlingService
+	archiveService         service.MessageArchiveService
+	msgService             service.FeishuMessageService
+	userDirectory          service.UserDirectoryService
+	queue                  service.EventQueueService // 异步处理消息事件的队列，同步处理时为 nil
+	inflight               lifecycle.Group           // 进行中的事件处理，关闭时等待其完成
 }
 
+// 入队的事件类型
+const (
+	eventTypeMessageReceive = "im.message.receive_v1"
+)
+
 // NewFeishuEventDispatcher 创建一个新的事件分发器
-func NewFeishuEventDispatcher(conf *config.FeishuConfig, bot *FeishuBot, msgHandler service.MessageHandlingService) *FeishuEventDispatcher {
-	return &FeishuEventDispatcher{
+func NewFeishuEventDispatcher(
+	conf *config.FeishuConfig,
+	bot *FeishuBot,
+	msgHandler service.MessageHandlingService,
+	archiveService service.MessageArchiveService,
+	msgService service.FeishuMessageService,
+	userDirectory service.UserDirectoryService,
+	queue service.EventQueueService,
+) *FeishuEventDispatcher {
+	d := &FeishuEventDispatcher{
 		conf:                   conf,
 		bot:                    bot,
 		messageHandlingService: msgHandler,
+		archiveService:         archiveService,
+		msgService:             msgService,
+		userDirectory:          userDirectory,
+		queue:                  queue,
+	}
+	if queue != nil {
+		queue.Handle(eventTypeMessageReceive, d.handleQueuedMessage)
+		if conf.ArchiveShortcutEvent != "" {
+			queue.Handle(conf.ArchiveShortcutEvent, func(ctx context.Context, payload []byte) error {
+				return d.handleArchiveShortcut(ctx, &larkevent.EventReq{Body: payload})
+			})
+		}
+	}
+	return d
+}
+
+// archiveShortcutEvent 是消息快捷操作回调中本项目关心的字段
+type archiveShortcutEvent struct {
+	Header struct {
+		EventID string `json:"event_id"`
+	} `json:"header"`
+	Event struct {
+		Operator struct {
+			OpenID     string `json:"open_id"`
+			OperatorID struct {
+				OpenID string `json:"open_id"`
+			} `json:"operator_id"`
+		} `json:"operator"`
+		MessageID string `json:"message_id"`
+		Context   struct {
+			OpenMessageID string `json:"open_message_id"`
+		} `json:"context"`
+	} `json:"event"`
+}
+
+// handleArchiveShortcut 处理“收录”消息快捷操作，将被操作的消息代为投稿
+func (d *FeishuEventDispatcher) handleArchiveShortcut(ctx context.Context, event *larkevent.EventReq) error {
+	var payload archiveShortcutEvent
+	if err := json.Unmarshal(event.Body, &payload); err != nil {
+		logger.Ctx(ctx).Error("Failed to unmarshal archive shortcut event", "error", err)
+		return nil
+	}
+
+	messageID := payload.Event.MessageID
+	if messageID == "" {
+		messageID = payload.Event.Context.OpenMessageID
+	}
+	curatorID := payload.Event.Operator.OpenID
+	if curatorID == "" {
+		curatorID = payload.Event.Operator.OperatorID.OpenID
+	}
+	if messageID == "" || curatorID == "" {
+		logger.Ctx(ctx).Warn("Archive shortcut event missing message_id or operator open_id", "body", string(event.Body))
+		return nil
+	}
+
+	replyText := ""
+	result, err := d.archiveService.ArchiveMessage(ctx, messageID, curatorID)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to archive message from shortcut", "messageID", messageID, "error", err)
+		replyText = fmt.Sprintf("收录失败：%s", err)
+	} else {
+		replyText = mh.ArchiveResultText(result)
+	}
+	if _, replyErr := d.msgService.ReplyTextMessage(ctx, messageID, replyText); replyErr != nil {
+		logger.Ctx(ctx).Error("Failed to reply archive shortcut result", "messageID", messageID, "error", replyErr)
+	}
+	return nil
+}
+
+// receive 记录收到一个事件并为其开始 span。事件的后续处理，包括入队后由工作协程进行的处理，都在这条链路中
+func (d *FeishuEventDispatcher) receive(ctx context.Context, eventType string) (context.Context, trace.Span) {
+	metrics.EventReceived(d.conf.TenantKey, eventType)
+	return tracing.StartKind(ctx, trace.SpanKindConsumer, "feishu.event "+eventType,
+		attribute.String("event.type", eventType),
+		attribute.String("feishu.tenant_key", d.conf.TenantKey),
+	)
+}
+
+// process 在未启用事件队列时处理一个事件。WebSocket 模式下同步执行；webhook 模式下飞书要求 3 秒内响应，超时会重试推送，
+// 因此在后台执行。两种模式都使用与接收方解绑的 context，关闭时由 Drain 等待处理完成，而不是中途取消。
+// Drain 之后收到的事件返回错误，飞书会稍后重新推送
+func (d *FeishuEventDispatcher) process(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	ctx = context.WithoutCancel(ctx)
+	run := func() {
+		if err := fn(ctx); err != nil {
+			logger.Ctx(ctx).Error("Error processing event", "event", name, "error", err)
+		}
+	}
+	if d.conf.IsWebhookMode() {
+		if err := d.inflight.Go(run); err != nil {
+			logger.Ctx(ctx).Warn("Rejected event during shutdown", "event", name)
+			return err
+		}
+		return nil
+	}
+	if err := d.inflight.Add(); err != nil {
+		logger.Ctx(ctx).Warn("Rejected event during shutdown", "event", name)
+		return err
+	}
+	defer d.inflight.Done()
+	run()
+	return nil
+}
+
+// queuedMessageEvent 是入队的消息事件。SDK 的事件结构同时内嵌了请求原文，两者的 Header 字段冲突，无法直接序列化
+type queuedMessageEvent struct {
+	Schema string                         `json:"schema"`
+	Header *larkevent.EventHeader         `json:"header"`
+	Event  *larkim.P2MessageReceiveV1Data `json:"event"`
+}
+
+// enqueue 将事件写入队列后立即返回，入队失败时返回错误，飞书会稍后重新推送
+func (d *FeishuEventDispatcher) enqueue(ctx context.Context, eventType, eventID, chatID string, payload any) error {
+	if err := d.inflight.Add(); err != nil {
+		logger.Ctx(ctx).Warn("Rejected event during shutdown", "event", eventType)
+		return err
+	}
+	defer d.inflight.Done()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化事件失败: %w", err)
+	}
+	if err := d.queue.Enqueue(ctx, &service.QueuedEvent{Type: eventType, ID: eventID, ChatID: chatID, Payload: body}); err != nil {
+		logger.Ctx(ctx).Error("Failed to enqueue event", "event", eventType, "eventID", eventID, "error", err)
+		return err
 	}
+	return nil
+}
+
+// enqueueMessage 将消息事件入队，同一会话的消息按顺序处理
+func (d *FeishuEventDispatcher) enqueueMessage(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	queued := &queuedMessageEvent{Event: event.Event}
+	if event.EventV2Base != nil {
+		queued.Schema, queued.Header = event.EventV2Base.Schema, event.EventV2Base.Header
+	}
+	eventID, chatID := "", ""
+	if queued.Header != nil {
+		eventID = queued.Header.EventID
+	}
+	if event.Event != nil && event.Event.Message != nil && event.Event.Message.ChatId != nil {
+		chatID = *event.Event.Message.ChatId
+	}
+	return d.enqueue(ctx, eventTypeMessageReceive, eventID, chatID, queued)
+}
+
+// handleQueuedMessage 处理从队列中取出的消息事件
+func (d *FeishuEventDispatcher) handleQueuedMessage(ctx context.Context, payload []byte) error {
+	var queued queuedMessageEvent
+	if err := json.Unmarshal(payload, &queued); err != nil {
+		return fmt.Errorf("解析入队的消息事件失败: %w", err)
+	}
+	return d.messageHandlingService.ProcessReceivedMessage(ctx, &larkim.P2MessageReceiveV1{
+		EventV2Base: &larkevent.EventV2Base{Schema: queued.Schema, Header: queued.Header},
+		Event:       queued.Event,
+	})
+}
+
+// Drain 停止接收新的事件，并等待进行中的事件处理（异步处理时为入队）完成或 ctx 结束
+// 队列中的事件由事件队列组件在之后停止时处理
+func (d *FeishuEventDispatcher) Drain(ctx context.Context) error {
+	return d.inflight.Close(ctx)
 }
 
 // GetEventDispatcher 返回事件处理函数
 func (d *FeishuEventDispatcher) GetEventDispatcher() *dispatcher.EventDispatcher {
-	return dispatcher.NewEventDispatcher(d.conf.VerificationToken, d.conf.EncryptKey).
+	eventDispatcher := dispatcher.NewEventDispatcher(d.conf.VerificationToken, d.conf.EncryptKey)
+	if d.conf.ArchiveShortcutEvent != "" {
+		eventDispatcher.OnCustomizedEvent(d.conf.ArchiveShortcutEvent, func(ctx context.Context, event *larkevent.EventReq) error {
+			ctx, span := d.receive(ctx, d.conf.ArchiveShortcutEvent)
+			defer span.End()
+			if d.queue != nil {
+				var payload archiveShortcutEvent
+				_ = json.Unmarshal(event.Body, &payload)
+				return d.enqueue(ctx, d.conf.ArchiveShortcutEvent, payload.Header.EventID, "", json.RawMessage(event.Body))
+			}
+			return d.process(ctx, d.conf.ArchiveShortcutEvent, func(ctx context.Context) error {
+				return d.handleArchiveShortcut(ctx, event)
+			})
+		})
+	}
+	return eventDispatcher.
 		OnP2MessageReceiveV1(func(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
-			if err := d.messageHandlingService.ProcessReceivedMessage(ctx, event); err != nil {
-				logger.Error("Error processing received message event", "error", err)
-				return nil
+			ctx, span := d.receive(ctx, eventTypeMessageReceive)
+			defer span.End()
+			if d.queue != nil {
+				return d.enqueueMessage(ctx, event)
 			}
-			return nil
+			return d.process(ctx, eventTypeMessageReceive, func(ctx context.Context) error {
+				return d.messageHandlingService.ProcessReceivedMessage(ctx, event)
+			})
 		}).
 		OnCustomizedEvent("create_post", func(ctx context.Context, event *larkevent.EventReq) error {
+			_, span := d.receive(ctx, "create_post")
+			defer span.End()
 			logger.Infof("收到自定义事件: %v", event)
 			return nil
 		}).
 		OnP2BotMenuV6(func(ctx context.Context, event *larkapplication.P2BotMenuV6) error {
+			_, span := d.receive(ctx, "application.bot.menu_v6")
+			defer span.End()
 			logger.Infof("收到机器人菜单事件: %v", event)
 			return nil
+		}).
+		OnP2UserCreatedV3(func(ctx context.Context, event *larkcontact.P2UserCreatedV3) error {
+			ctx, span := d.receive(ctx, "contact.user.created_v3")
+			defer span.End()
+			if event.Event == nil {
+				return nil
+			}
+			if err := d.userDirectory.SyncUser(ctx, event.Event.Object); err != nil {
+				logger.Ctx(ctx).Error("Error syncing created user", "error", err)
+			}
+			return nil
+		}).
+		OnP2UserUpdatedV3(func(ctx context.Context, event *larkcontact.P2UserUpdatedV3) error {
+			ctx, span := d.receive(ctx, "contact.user.updated_v3")
+			defer span.End()
+			if event.Event == nil {
+				return nil
+			}
+			if err := d.userDirectory.SyncUser(ctx, event.Event.Object); err != nil {
+				logger.Ctx(ctx).Error("Error syncing updated user", "error", err)
+			}
+			return nil
+		}).
+		OnP2UserDeletedV3(func(ctx context.Context, event *larkcontact.P2UserDeletedV3) error {
+			ctx, span := d.receive(ctx, "contact.user.deleted_v3")
+			defer span.End()
+			if event.Event == nil || event.Event.Object == nil || event.Event.Object.OpenId == nil {
+				return nil
+			}
+			if err := d.userDirectory.RemoveUser(ctx, *event.Event.Object.OpenId); err != nil {
+				logger.Ctx(ctx).Error("Error removing deleted user", "error", err)
+			}
+			return nil
 		})
 }
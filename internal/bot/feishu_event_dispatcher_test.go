@@ -0,0 +1,152 @@
+package bot
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	larkevent "github.com/larksuite/oapi-sdk-go/v3/event"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// blockingHandler 阻塞处理消息，直到 release 被关闭
+type blockingHandler struct {
+	started  chan struct{}
+	release  chan struct{}
+	finished chan struct{}
+}
+
+func (h *blockingHandler) ProcessReceivedMessage(ctx context.Context, _ *larkim.P2MessageReceiveV1) error {
+	close(h.started)
+	<-h.release
+	close(h.finished)
+	return ctx.Err()
+}
+
+func TestDispatcherDrain(t *testing.T) {
+	for _, mode := range []string{config.EventModeWebhook, config.EventModeWebSocket} {
+		t.Run(mode, func(t *testing.T) {
+			handler := &blockingHandler{started: make(chan struct{}), release: make(chan struct{}), finished: make(chan struct{})}
+			d := NewFeishuEventDispatcher(&config.FeishuConfig{EventMode: mode}, nil, handler, nil, nil, nil, nil)
+			handle := func(ctx context.Context) error {
+				return d.process(ctx, eventTypeMessageReceive, func(ctx context.Context) error {
+					return handler.ProcessReceivedMessage(ctx, nil)
+				})
+			}
+
+			// 接收方的 ctx 被取消不影响进行中的处理
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() { _ = handle(ctx) }()
+			<-handler.started
+			cancel()
+
+			drained := make(chan error, 1)
+			go func() { drained <- d.Drain(context.Background()) }()
+			select {
+			case <-drained:
+				t.Fatal("Drain 应等待进行中的处理完成")
+			case <-time.After(20 * time.Millisecond):
+			}
+			if err := handle(context.Background()); err == nil {
+				t.Error("Drain 之后收到的事件应返回错误")
+			}
+
+			close(handler.release)
+			if err := <-drained; err != nil {
+				t.Fatalf("Drain 返回错误: %v", err)
+			}
+			select {
+			case <-handler.finished:
+			default:
+				t.Error("Drain 返回时处理应已完成")
+			}
+		})
+	}
+}
+
+// fakeArchiveService 记录被收录的消息
+type fakeArchiveService struct {
+	messageID, curatorID string
+	err                  error
+}
+
+func (s *fakeArchiveService) ArchiveMessage(_ context.Context, messageID, curatorID string) (*service.ArchiveResult, error) {
+	s.messageID, s.curatorID = messageID, curatorID
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &service.ArchiveResult{Article: &model.Article{ID: 3, Title: "好文推荐", AuthorName: "张三", Status: model.ArticleStatusPublished}}, nil
+}
+
+// replyRecorder 记录回复的消息
+type replyRecorder struct {
+	service.FeishuMessageService
+	msgID, text string
+}
+
+func (r *replyRecorder) ReplyTextMessage(_ context.Context, msgID, text string) (*larkim.ReplyMessageResp, error) {
+	r.msgID, r.text = msgID, text
+	return &larkim.ReplyMessageResp{}, nil
+}
+
+func TestHandleArchiveShortcut(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		archiveErr  error
+		wantMessage string
+		wantCurator string
+		wantReply   string
+	}{
+		{
+			name:        "消息 ID 与操作者",
+			body:        `{"header":{"event_id":"ev_1"},"event":{"operator":{"open_id":"ou_curator"},"message_id":"om_1"}}`,
+			wantMessage: "om_1",
+			wantCurator: "ou_curator",
+			wantReply:   "已收录 '好文推荐'，作者：张三 (ID: 3)，感谢推荐！",
+		},
+		{
+			name:        "从 context 与 operator_id 中读取",
+			body:        `{"event":{"operator":{"operator_id":{"open_id":"ou_curator"}},"context":{"open_message_id":"om_2"}}}`,
+			wantMessage: "om_2",
+			wantCurator: "ou_curator",
+			wantReply:   "已收录 '好文推荐'，作者：张三 (ID: 3)，感谢推荐！",
+		},
+		{
+			name:        "收录失败时回复原因",
+			body:        `{"event":{"operator":{"open_id":"ou_curator"},"message_id":"om_1"}}`,
+			archiveErr:  errors.New("内容未通过审核：包含敏感词"),
+			wantMessage: "om_1",
+			wantCurator: "ou_curator",
+			wantReply:   "收录失败：内容未通过审核：包含敏感词",
+		},
+		{
+			name: "缺少操作者时忽略",
+			body: `{"event":{"message_id":"om_1"}}`,
+		},
+		{
+			name: "无法解析的事件被忽略",
+			body: `not json`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := &fakeArchiveService{err: tt.archiveErr}
+			replies := &replyRecorder{}
+			d := NewFeishuEventDispatcher(&config.FeishuConfig{}, nil, nil, archive, replies, nil, nil)
+			if err := d.handleArchiveShortcut(context.Background(), &larkevent.EventReq{Body: []byte(tt.body)}); err != nil {
+				t.Fatalf("handleArchiveShortcut 返回错误: %v", err)
+			}
+			if archive.messageID != tt.wantMessage || archive.curatorID != tt.wantCurator {
+				t.Errorf("收录 %q（操作者 %q），期望 %q（操作者 %q）", archive.messageID, archive.curatorID, tt.wantMessage, tt.wantCurator)
+			}
+			if replies.text != tt.wantReply || (tt.wantReply != "" && replies.msgID != tt.wantMessage) {
+				t.Errorf("回复 %q: %q，期望回复 %q: %q", replies.msgID, replies.text, tt.wantMessage, tt.wantReply)
+			}
+		})
+	}
+}
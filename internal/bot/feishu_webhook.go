@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"io"
+	"net/http"
+
+	larkevent "github.com/larksuite/oapi-sdk-go/v3/event"
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
+)
+
+// maxWebhookBodySize 限制事件回调请求体的大小
+const maxWebhookBodySize = 1 << 20
+
+// NewWebhookHandler 返回处理飞书 HTTP 事件回调的 http.Handler
+// URL 校验 (challenge)、基于 EncryptKey 的签名校验和解密均由 eventDispatcher 完成，与 WebSocket 模式共用同一套事件处理
+func NewWebhookHandler(eventDispatcher *dispatcher.EventDispatcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+		if err != nil {
+			logger.Ctx(r.Context()).Warn("Failed to read Feishu webhook request body", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		eventResp := eventDispatcher.Handle(r.Context(), &larkevent.EventReq{
+			Header:     r.Header,
+			Body:       body,
+			RequestURI: r.RequestURI,
+		})
+
+		// 先写响应头再写状态码，否则响应头不会生效
+		for key, values := range eventResp.Header {
+			for _, value := range values {
+				w.Header().Add(key, value)
+			}
+		}
+		w.WriteHeader(eventResp.StatusCode)
+		if len(eventResp.Body) > 0 {
+			if _, err := w.Write(eventResp.Body); err != nil {
+				logger.Ctx(r.Context()).Warn("Failed to write Feishu webhook response", "error", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	larkevent "github.com/larksuite/oapi-sdk-go/v3/event"
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+const (
+	testVerificationToken = "test_verification_token"
+	testEncryptKey        = "test_encrypt_key"
+)
+
+// newTestWebhook returns a webhook handler whose dispatcher records the received message IDs.
+func newTestWebhook(encryptKey string) (http.Handler, *[]string) {
+	var received []string
+	eventDispatcher := dispatcher.NewEventDispatcher(testVerificationToken, encryptKey).
+		OnP2MessageReceiveV1(func(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+			received = append(received, *event.Event.Message.MessageId)
+			return nil
+		})
+	return NewWebhookHandler(eventDispatcher), &received
+}
+
+// readPayload loads a recorded event payload from testdata.
+func readPayload(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("read payload %s: %v", name, err)
+	}
+	return data
+}
+
+// encryptPayload encrypts a payload the way Feishu does: AES-256-CBC with sha256(key), IV prepended, base64 encoded.
+func encryptPayload(t *testing.T, plain []byte, key string) []byte {
+	t.Helper()
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		t.Fatalf("new cipher: %v", err)
+	}
+	padding := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := append(append([]byte(nil), plain...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	buf := make([]byte, aes.BlockSize+len(padded))
+	copy(buf, "0123456789abcdef") // fixed IV keeps the test deterministic
+	cipher.NewCBCEncrypter(block, buf[:aes.BlockSize]).CryptBlocks(buf[aes.BlockSize:], padded)
+
+	body, err := json.Marshal(map[string]string{"encrypt": base64.StdEncoding.EncodeToString(buf)})
+	if err != nil {
+		t.Fatalf("marshal encrypted payload: %v", err)
+	}
+	return body
+}
+
+// post sends the body to the handler, signing it with the encrypt key when sign is true.
+func post(handler http.Handler, body []byte, sign bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhook/feishu/event", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if sign {
+		timestamp, nonce := "1608725989", "d0b8a9f2"
+		req.Header.Set(larkevent.EventRequestTimestamp, timestamp)
+		req.Header.Set(larkevent.EventRequestNonce, nonce)
+		req.Header.Set(larkevent.EventSignature, larkevent.Signature(timestamp, nonce, testEncryptKey, string(body)))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookURLVerification(t *testing.T) {
+	handler, _ := newTestWebhook("")
+
+	rec := post(handler, readPayload(t, "url_verification.json"), false)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Challenge string `json:"challenge"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if resp.Challenge != "ajls384kdjx98XX" {
+		t.Errorf("challenge = %q, want %q", resp.Challenge, "ajls384kdjx98XX")
+	}
+	if ct := rec.Header().Get("Content-Type"); ct == "" {
+		t.Error("Content-Type header not set")
+	}
+}
+
+func TestWebhookEncryptedURLVerification(t *testing.T) {
+	handler, _ := newTestWebhook(testEncryptKey)
+
+	// The challenge request is encrypted but not signed
+	rec := post(handler, encryptPayload(t, readPayload(t, "url_verification.json"), testEncryptKey), false)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("ajls384kdjx98XX")) {
+		t.Errorf("response %s does not contain the challenge", rec.Body.String())
+	}
+}
+
+func TestWebhookEncryptedSignedEvent(t *testing.T) {
+	handler, received := newTestWebhook(testEncryptKey)
+
+	rec := post(handler, encryptPayload(t, readPayload(t, "im_message_receive_v1.json"), testEncryptKey), true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if len(*received) != 1 || (*received)[0] != "om_5ce6d572455d361153b7cb51da133945" {
+		t.Errorf("received = %v, want the recorded message", *received)
+	}
+}
+
+func TestWebhookRejectsInvalidSignature(t *testing.T) {
+	handler, received := newTestWebhook(testEncryptKey)
+
+	body := encryptPayload(t, readPayload(t, "im_message_receive_v1.json"), testEncryptKey)
+	req := httptest.NewRequest(http.MethodPost, "/webhook/feishu/event", bytes.NewReader(body))
+	req.Header.Set(larkevent.EventRequestTimestamp, "1608725989")
+	req.Header.Set(larkevent.EventRequestNonce, "d0b8a9f2")
+	req.Header.Set(larkevent.EventSignature, "forged")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code == http.StatusOK {
+		t.Fatalf("status = %d, want an error for a forged signature", rec.Code)
+	}
+	if len(*received) != 0 {
+		t.Errorf("handler was called for a forged event: %v", *received)
+	}
+}
+
+func TestWebhookRejectsPlainEventWhenEncryptKeyIsSet(t *testing.T) {
+	handler, received := newTestWebhook(testEncryptKey)
+
+	rec := post(handler, readPayload(t, "im_message_receive_v1.json"), true)
+	if rec.Code == http.StatusOK {
+		t.Fatalf("status = %d, want an error for an unencrypted event", rec.Code)
+	}
+	if len(*received) != 0 {
+		t.Errorf("handler was called for an unencrypted event: %v", *received)
+	}
+}
+
+func TestWebhookRejectsNonPost(t *testing.T) {
+	handler, _ := newTestWebhook("")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook/feishu/event", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"MikoNews/internal/pkg/health"
+	"MikoNews/internal/pkg/logger"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// wsMonitor 通过 SDK 的日志跟踪 WebSocket 长连接的状态。SDK 没有提供查询连接状态的接口，但会在建立、断开连接
+// 以及收到 pong 时输出固定格式的日志。wsMonitor 同时实现 larkcore.Logger，将 SDK 日志转发到项目日志
+type wsMonitor struct {
+	tenantKey string
+	now       func() time.Time // 便于测试替换
+
+	mu          sync.Mutex
+	connected   bool
+	changedAt   time.Time // 连接状态最近一次变化的时间，未连接过时为创建时间
+	lastPongAt  time.Time // 最近一次收到 pong 的时间，连接建立时视为收到
+	lastFailure string    // 最近一次连接失败的原因
+}
+
+// newWSMonitor 创建一个连接状态为未连接的 wsMonitor
+func newWSMonitor(tenantKey string) *wsMonitor {
+	m := &wsMonitor{tenantKey: tenantKey, now: time.Now}
+	m.changedAt = m.now()
+	return m
+}
+
+// observe 根据 SDK 日志更新连接状态
+func (m *wsMonitor) observe(msg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	switch {
+	case strings.HasPrefix(msg, "connected to "):
+		m.connected, m.changedAt, m.lastPongAt, m.lastFailure = true, now, now, ""
+	case strings.HasPrefix(msg, "disconnected to "):
+		m.connected, m.changedAt = false, now
+	case strings.HasPrefix(msg, "receive pong"):
+		m.lastPongAt = now
+	case strings.HasPrefix(msg, "connect failed"), strings.HasPrefix(msg, "receive message failed"):
+		m.lastFailure = msg
+	}
+}
+
+// Check 返回长连接的健康检查：未连接超过 downFor，或已连接但超过 maxSilence 未收到 pong 时不可用。
+// 就绪检查的 downFor 为 0，重连期间即视为未就绪；存活检查给重连留出时间，避免进程被过早重启
+func (m *wsMonitor) Check(maxSilence, downFor time.Duration) health.Check {
+	return func(context.Context) (map[string]any, error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		now := m.now()
+		details := map[string]any{"connected": m.connected, "since": m.changedAt}
+		if !m.lastPongAt.IsZero() {
+			details["last_pong_at"] = m.lastPongAt
+		}
+		if m.lastFailure != "" {
+			details["last_failure"] = m.lastFailure
+		}
+		if !m.connected {
+			if down := now.Sub(m.changedAt); down >= downFor {
+				return details, fmt.Errorf("WebSocket 未连接，已持续 %s", down.Round(time.Second))
+			}
+			return details, nil
+		}
+		if silence := now.Sub(m.lastPongAt); silence > maxSilence {
+			return details, fmt.Errorf("WebSocket 已 %s 未收到 pong，连接可能已失效", silence.Round(time.Second))
+		}
+		return details, nil
+	}
+}
+
+// log 更新连接状态并输出 SDK 日志
+func (m *wsMonitor) log(level string, args []interface{}) {
+	msg := strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+	if len(args) > 0 {
+		if first, ok := args[0].(string); ok {
+			m.observe(first)
+		}
+	}
+	switch level {
+	case "debug":
+		logger.Debug("Feishu WebSocket", "tenantKey", m.tenantKey, "msg", msg)
+	case "info":
+		logger.Info("Feishu WebSocket", "tenantKey", m.tenantKey, "msg", msg)
+	case "warn":
+		logger.Warn("Feishu WebSocket", "tenantKey", m.tenantKey, "msg", msg)
+	default:
+		logger.Error("Feishu WebSocket", "tenantKey", m.tenantKey, "msg", msg)
+	}
+}
+
+// Debug 实现 larkcore.Logger
+func (m *wsMonitor) Debug(_ context.Context, args ...interface{}) { m.log("debug", args) }
+
+// Info 实现 larkcore.Logger
+func (m *wsMonitor) Info(_ context.Context, args ...interface{}) { m.log("info", args) }
+
+// Warn 实现 larkcore.Logger
+func (m *wsMonitor) Warn(_ context.Context, args ...interface{}) { m.log("warn", args) }
+
+// Error 实现 larkcore.Logger
+func (m *wsMonitor) Error(_ context.Context, args ...interface{}) { m.log("error", args) }
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWSMonitorCheck(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := newWSMonitor("tenant")
+	m.now = func() time.Time { return now }
+	m.changedAt = now
+
+	ready := m.Check(time.Minute, 0)
+	live := m.Check(time.Minute, time.Minute)
+	check := func(name string, c func(context.Context) (map[string]any, error), wantUp bool) {
+		t.Helper()
+		if _, err := c(context.Background()); (err == nil) != wantUp {
+			t.Errorf("%s: 期望可用 %v，实际错误 %v", name, wantUp, err)
+		}
+	}
+
+	// 启动后尚未连接：未就绪，但存活检查给连接留出时间
+	check("未连接 ready", ready, false)
+	check("未连接 live", live, true)
+
+	m.Info(context.Background(), "connected to wss://example.com/ws [conn_id=1]")
+	check("已连接 ready", ready, true)
+	check("已连接 live", live, true)
+
+	// 收到 pong 后重新计算静默时长
+	now = now.Add(50 * time.Second)
+	m.Debug(context.Background(), "receive pong")
+	now = now.Add(50 * time.Second)
+	check("收到 pong 后 ready", ready, true)
+
+	// 连接未断开，但长时间未收到 pong
+	now = now.Add(time.Minute)
+	check("静默 ready", ready, false)
+	check("静默 live", live, false)
+
+	// 断开后重连期间
+	m.Error(context.Background(), "disconnected to wss://example.com/ws")
+	m.Error(context.Background(), "connect failed, err: dial timeout")
+	now = now.Add(30 * time.Second)
+	check("重连中 ready", ready, false)
+	check("重连中 live", live, true)
+	if details, _ := ready(context.Background()); details["last_failure"] != "connect failed, err: dial timeout" {
+		t.Errorf("应记录最近一次连接失败的原因: %v", details)
+	}
+
+	// 长时间未能重连
+	now = now.Add(time.Minute)
+	check("断开过久 live", live, false)
+}
@@ -1,32 +1,212 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
 // Config 结构体表示整个配置文件
 type Config struct {
-	Feishu   FeishuConfig   `yaml:"feishu"`   // 飞书相关配置
+	Feishu   FeishuConfig   `yaml:"feishu"`   // 飞书相关配置（单应用部署）
 	Database DatabaseConfig `yaml:"database"` // 数据库相关配置
 	Server   ServerConfig   `yaml:"server"`   // 服务器相关配置
 	Logger   LoggerConfig   `yaml:"logger"`   // 日志相关配置
+	Admin    AdminConfig    `yaml:"admin"`    // 管理员相关配置
+	Contact  ContactConfig  `yaml:"contact"`  // 通讯录同步相关配置
+
+	SubmissionPolicy SubmissionPolicyConfig `yaml:"submission_policy"` // 投稿权限策略
+	RateLimit        RateLimitConfig        `yaml:"rate_limit"`        // 投稿限流配置
+	Moderation       ModerationConfig       `yaml:"moderation"`        // 内容审核配置
+	Telegram         TelegramConfig         `yaml:"telegram"`          // Telegram 投稿来源配置
+	Webhooks         WebhooksConfig         `yaml:"webhooks"`          // 文章生命周期事件的 webhook 配置
+	EventQueue       EventQueueConfig       `yaml:"event_queue"`       // 飞书消息事件的异步处理队列配置
+	Tracing          TracingConfig          `yaml:"tracing"`           // OpenTelemetry 链路追踪配置
+	Health           HealthConfig           `yaml:"health"`            // 健康检查配置
+
+	// FeishuApps 多应用部署时的飞书应用列表，配置后忽略 feishu 段，每个应用的文章按 tenant_key 隔离
+	FeishuApps []FeishuConfig `yaml:"feishu_apps"`
+}
+
+// FeishuAppConfigs 返回需要启动的飞书应用配置，未配置 feishu_apps 时只有 feishu 段一个应用
+func (c *Config) FeishuAppConfigs() []*FeishuConfig {
+	if len(c.FeishuApps) == 0 {
+		return []*FeishuConfig{&c.Feishu}
+	}
+	apps := make([]*FeishuConfig, len(c.FeishuApps))
+	for i := range c.FeishuApps {
+		apps[i] = &c.FeishuApps[i]
+	}
+	return apps
+}
+
+// AppAdmin 返回飞书应用的管理员配置：应用配置了 admin_open_ids 时使用应用的管理员，访问令牌和加密密钥始终使用全局配置
+func (c *Config) AppAdmin(app *FeishuConfig) *AdminConfig {
+	admin := c.Admin
+	if app.AdminOpenIDs != nil {
+		admin.OpenIDs = app.AdminOpenIDs
+	}
+	return &admin
+}
+
+// AppSubmissionPolicy 返回飞书应用的投稿权限策略，应用未单独配置时使用全局策略
+func (c *Config) AppSubmissionPolicy(app *FeishuConfig) *SubmissionPolicyConfig {
+	if app.SubmissionPolicy != nil {
+		return app.SubmissionPolicy
+	}
+	return &c.SubmissionPolicy
+}
+
+// AppRateLimit 返回飞书应用的投稿限流配置，应用未单独配置时使用全局配置
+func (c *Config) AppRateLimit(app *FeishuConfig) *RateLimitConfig {
+	if app.RateLimit != nil {
+		return app.RateLimit
+	}
+	return &c.RateLimit
+}
+
+// TenantKeys 返回所有飞书应用的租户标识
+func (c *Config) TenantKeys() []string {
+	apps := c.FeishuAppConfigs()
+	keys := make([]string, len(apps))
+	for i, app := range apps {
+		keys[i] = app.TenantKey
+	}
+	return keys
 }
 
 // FeishuConfig 结构体表示飞书机器人的配置
 type FeishuConfig struct {
-	AppID             string `yaml:"app_id"`             // 飞书应用的 App ID
-	AppSecret         string `yaml:"app_secret"`         // 飞书应用的 App Secret
-	VerificationToken string `yaml:"verification_token"` // 事件订阅的验证令牌
-	EncryptKey        string `yaml:"encrypt_key"`        // 事件订阅的加密密钥
+	// TenantKey 应用所属租户的标识，用于隔离各租户的文章和用户，多应用部署时必填且不能重复
+	TenantKey string `yaml:"tenant_key"`
+	// Domain 开放平台域名: feishu (默认，open.feishu.cn) / lark (open.larksuite.com)，也可填写完整地址
+	Domain            string   `yaml:"domain"`
+	AppID             string   `yaml:"app_id"`             // 飞书应用的 App ID
+	AppSecret         string   `yaml:"app_secret"`         // 飞书应用的 App Secret
+	VerificationToken string   `yaml:"verification_token"` // 事件订阅的验证令牌
+	EncryptKey        string   `yaml:"encrypt_key"`        // 事件订阅的加密密钥
 	GroupChats        []string `yaml:"group_chats"`        // 群聊ID列表
+	BotOpenID         string   `yaml:"bot_open_id"`        // 机器人自身的 OpenID，留空时通过 API 自动获取
+	// ArchiveShortcutEvent 为“收录”消息快捷操作配置的事件类型，留空则不启用
+	ArchiveShortcutEvent string `yaml:"archive_shortcut_event"`
+	// EventMode 接收事件的方式: websocket (默认，长连接) / webhook (HTTP 回调)
+	EventMode string `yaml:"event_mode"`
+	// EventPath webhook 模式下事件回调的路径，默认 /webhook/feishu/event
+	EventPath string `yaml:"event_path"`
+	// Docs 将发布的文章归档到飞书云文档或知识库
+	Docs FeishuDocsConfig `yaml:"docs"`
+	// Bitable 将文章同步到飞书多维表格
+	Bitable FeishuBitableConfig `yaml:"bitable"`
+
+	// 以下选项只对本应用生效，留空时使用全局配置。open_id 和群聊ID只在所属应用内有效，多应用部署时需要为每个应用分别配置
+	AdminOpenIDs     []string                `yaml:"admin_open_ids"`    // 本应用的管理员 OpenID 列表，留空使用 admin.open_ids
+	SubmissionPolicy *SubmissionPolicyConfig `yaml:"submission_policy"` // 本应用的投稿权限策略，留空使用全局 submission_policy
+	RateLimit        *RateLimitConfig        `yaml:"rate_limit"`        // 本应用的投稿限流配置，留空使用全局 rate_limit
+}
+
+// FeishuDocsConfig 结构体表示文章归档到飞书云文档 / 知识库的配置
+type FeishuDocsConfig struct {
+	// Mode 归档方式: 留空不归档 / docx (每篇文章一个文档) / wiki (按月追加到知识库节点)
+	Mode           string `yaml:"mode"`
+	FolderToken    string `yaml:"folder_token"`     // docx 模式下文档所在的文件夹，留空为应用的云空间根目录
+	WikiSpaceID    string `yaml:"wiki_space_id"`    // wiki 模式下的知识空间ID，应用需为该空间的成员
+	WikiParentNode string `yaml:"wiki_parent_node"` // wiki 模式下月度节点的父节点，留空为知识空间根节点
+}
+
+// FeishuBitableConfig 结构体表示文章同步到飞书多维表格的配置
+type FeishuBitableConfig struct {
+	AppToken string `yaml:"app_token"` // 多维表格的 app_token，留空不同步
+	TableID  string `yaml:"table_id"`  // 数据表ID
+	// Fields 文章字段到数据表列名的映射，未配置的字段使用默认列名，映射为空字符串时不同步该字段
+	Fields map[string]string `yaml:"fields"`
+}
+
+// 可同步到多维表格的文章字段
+const (
+	BitableFieldID           = "id"            // 文章ID（数字），用于匹配已有记录，不能为空
+	BitableFieldTitle        = "title"         // 标题（文本）
+	BitableFieldAuthor       = "author"        // 作者（文本），匿名投稿为“匿名用户”
+	BitableFieldStatus       = "status"        // 状态（单选）
+	BitableFieldSource       = "source"        // 投稿来源（单选）
+	BitableFieldCreatedAt    = "created_at"    // 投稿时间（日期）
+	BitableFieldTags         = "tags"          // 正文中的 #话题 标签（多选）
+	BitableFieldForwardCount = "forward_count" // 转发次数（数字）
+	BitableFieldLink         = "link"          // 文章链接（超链接），未配置 server.public_url 时不同步
+)
+
+// defaultBitableFields 是各字段默认对应的列名
+var defaultBitableFields = map[string]string{
+	BitableFieldID:           "文章ID",
+	BitableFieldTitle:        "标题",
+	BitableFieldAuthor:       "作者",
+	BitableFieldStatus:       "状态",
+	BitableFieldSource:       "来源",
+	BitableFieldCreatedAt:    "投稿时间",
+	BitableFieldTags:         "标签",
+	BitableFieldForwardCount: "转发次数",
+	BitableFieldLink:         "链接",
+}
+
+// Enabled 返回是否配置了多维表格同步
+func (c *FeishuBitableConfig) Enabled() bool {
+	return c.AppToken != "" && c.TableID != ""
+}
+
+// FieldName 返回文章字段对应的列名，为空表示不同步该字段
+func (c *FeishuBitableConfig) FieldName(field string) string {
+	if name, ok := c.Fields[field]; ok {
+		return name
+	}
+	return defaultBitableFields[field]
+}
+
+// 文章归档方式
+const (
+	DocsModeDocx = "docx" // 每篇文章创建一个云文档
+	DocsModeWiki = "wiki" // 按月在知识库中创建节点，文章追加到当月节点
+)
+
+// 飞书事件接收方式
+const (
+	EventModeWebSocket = "websocket" // 通过 WebSocket 长连接接收事件
+	EventModeWebhook   = "webhook"   // 通过 HTTP 回调接收事件
+)
+
+// 开放平台域名
+const (
+	DomainFeishu = "feishu" // 飞书，open.feishu.cn
+	DomainLark   = "lark"   // Lark 国际版，open.larksuite.com
+)
+
+// BaseURL 返回开放平台的接口地址
+func (c *FeishuConfig) BaseURL() string {
+	switch c.Domain {
+	case "", DomainFeishu:
+		return "https://open.feishu.cn"
+	case DomainLark:
+		return "https://open.larksuite.com"
+	default:
+		return strings.TrimRight(c.Domain, "/")
+	}
+}
+
+// IsWebhookMode 返回是否通过 HTTP 回调接收事件
+func (c *FeishuConfig) IsWebhookMode() bool {
+	return c.EventMode == EventModeWebhook
 }
 
 // DatabaseConfig 结构体表示数据库配置
 type DatabaseConfig struct {
+	Driver       string `yaml:"driver"`         // 数据库驱动: mysql（默认）/sqlite/postgres
+	Path         string `yaml:"path"`           // SQLite 数据库文件路径，仅 sqlite 驱动使用
+	SSLMode      string `yaml:"sslmode"`        // PostgreSQL 的 sslmode，默认 disable
+	DSN          string `yaml:"dsn"`            // 完整的连接串，配置后忽略 host/port/user/password/dbname/path
+	AutoMigrate  bool   `yaml:"auto_migrate"`   // 启动时自动执行尚未执行的数据库迁移
 	Host         string `yaml:"host"`           // 数据库主机
 	Port         int    `yaml:"port"`           // 数据库端口
 	User         string `yaml:"user"`           // 数据库用户名
@@ -36,9 +216,33 @@ type DatabaseConfig struct {
 	MaxIdleConns int    `yaml:"max_idle_conns"` // 最大空闲连接数
 }
 
+// 数据库驱动
+const (
+	DatabaseDriverMySQL    = "mysql"    // MySQL 5.7+
+	DatabaseDriverSQLite   = "sqlite"   // SQLite，适用于小规模部署和测试
+	DatabaseDriverPostgres = "postgres" // PostgreSQL 12+
+)
+
 // ServerConfig 结构体表示服务器配置
 type ServerConfig struct {
-	Port int `yaml:"port"` // 服务器监听端口
+	Port      int    `yaml:"port"`       // 服务器监听端口
+	PublicURL string `yaml:"public_url"` // 服务对外访问的地址，如 https://news.example.com，用于生成文章链接
+
+	// ShutdownTimeout 收到退出信号后等待进行中的请求、事件处理和后台任务完成的总时长，默认 20s
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// ArticleURL 返回文章的对外访问链接，未配置 public_url 时返回空字符串
+// tenantKey 非空时附带 tenant 参数，以便 API 在对应租户内查找文章
+func (c *ServerConfig) ArticleURL(tenantKey string, id int64) string {
+	if c.PublicURL == "" {
+		return ""
+	}
+	link := fmt.Sprintf("%s/api/v1/articles/%d", strings.TrimRight(c.PublicURL, "/"), id)
+	if tenantKey != "" {
+		link += "?tenant=" + url.QueryEscape(tenantKey)
+	}
+	return link
 }
 
 // LoggerConfig 结构体表示日志配置
@@ -47,6 +251,171 @@ type LoggerConfig struct {
 	Path  string `yaml:"path"`  // 日志文件路径
 }
 
+// AdminConfig 结构体表示管理员配置
+type AdminConfig struct {
+	OpenIDs   []string `yaml:"open_ids"`   // 管理员的飞书 OpenID 列表
+	APITokens []string `yaml:"api_tokens"` // 管理接口的访问令牌列表 (Authorization: Bearer <token>)
+	SecretKey string   `yaml:"secret_key"` // 敏感字段（如匿名作者身份）的加密密钥
+}
+
+// IsAdmin 判断给定的 OpenID 是否为管理员
+func (c *AdminConfig) IsAdmin(openID string) bool {
+	for _, id := range c.OpenIDs {
+		if id == openID {
+			return true
+		}
+	}
+	return false
+}
+
+// ContactConfig 结构体表示本地用户目录的同步与缓存配置
+type ContactConfig struct {
+	SyncInterval time.Duration `yaml:"sync_interval"` // 全量同步间隔，默认 24h
+	CacheSize    int           `yaml:"cache_size"`    // 内存缓存的用户数，默认 1000
+	CacheTTL     time.Duration `yaml:"cache_ttl"`     // 内存缓存的过期时间，默认 10m
+}
+
+// 投稿策略的效果
+const (
+	PolicyEffectAllow = "allow" // 允许投稿
+	PolicyEffectDeny  = "deny"  // 禁止投稿
+)
+
+// SubmissionPolicyConfig 结构体表示投稿权限策略
+// 规则按顺序匹配，第一条命中的规则生效；没有规则命中时使用默认效果
+type SubmissionPolicyConfig struct {
+	DefaultEffect string                 `yaml:"default_effect"` // 默认效果: allow (默认) / deny
+	DefaultReview bool                   `yaml:"default_review"` // 默认是否需要管理员审核后再转发
+	DenyMessage   string                 `yaml:"deny_message"`   // 拒绝投稿时回复给用户的说明
+	Rules         []SubmissionPolicyRule `yaml:"rules"`          // 策略规则
+}
+
+// SubmissionPolicyRule 结构体表示一条投稿策略规则
+// 用户满足 open_ids、departments、chats 中任意一项即命中该规则
+type SubmissionPolicyRule struct {
+	Name        string   `yaml:"name"`        // 规则名称，用于日志
+	Effect      string   `yaml:"effect"`      // allow / deny，必填
+	OpenIDs     []string `yaml:"open_ids"`    // 用户 OpenID 列表
+	Departments []string `yaml:"departments"` // 部门 open_department_id 列表（仅匹配用户直属部门）
+	Chats       []string `yaml:"chats"`       // 群聊 ID 列表，用户是其中任一群成员即命中
+	Review      bool     `yaml:"review"`      // allow 规则命中后是否需要管理员审核
+	Message     string   `yaml:"message"`     // deny 规则命中时回复给用户的说明，留空使用 deny_message
+}
+
+// 限流计数的存储后端
+const (
+	RateLimitBackendDB     = "db"     // 数据库，多实例部署时共享计数
+	RateLimitBackendMemory = "memory" // 进程内存，仅适用于单机部署
+)
+
+// RateLimitConfig 结构体表示投稿限流配置，管理员使用 admin 配额，其他成员使用 default 配额
+type RateLimitConfig struct {
+	Backend string         `yaml:"backend"` // 计数存储后端: db (默认) / memory
+	Default RateLimitQuota `yaml:"default"` // 普通成员的配额
+	Admin   RateLimitQuota `yaml:"admin"`   // 管理员的配额
+}
+
+// RateLimitQuota 结构体表示投稿配额，0 表示不限制
+type RateLimitQuota struct {
+	PerHour int `yaml:"per_hour"` // 每小时最多投稿数
+	PerDay  int `yaml:"per_day"`  // 每天最多投稿数
+}
+
+// ModerationConfig 结构体表示投稿内容审核配置，投稿在保存和转发前依次经过各项过滤器
+type ModerationConfig struct {
+	SensitiveWords      []string                 `yaml:"sensitive_words"`       // 敏感词列表
+	SensitiveWordsFile  string                   `yaml:"sensitive_words_file"`  // 敏感词文件，每行一个，# 开头为注释
+	SensitiveWordAction string                   `yaml:"sensitive_word_action"` // 命中敏感词的处理: reject (默认) / flag (转人工审核)
+	BlockedDomains      []string                 `yaml:"blocked_domains"`       // 禁止分享的链接域名，包含其子域名
+	MaxLength           int                      `yaml:"max_length"`            // 正文最大字数，0 表示不限制
+	MaxImages           int                      `yaml:"max_images"`            // 最多图片数，0 表示不限制
+	External            ExternalModerationConfig `yaml:"external"`              // 外部审核服务
+}
+
+// ExternalModerationConfig 结构体表示外部内容审核服务配置
+// 服务接收 POST {"author_id","title","content"}，返回 {"verdict":"allow|flag|reject","reason":"..."}
+type ExternalModerationConfig struct {
+	URL     string        `yaml:"url"`     // 服务地址，留空则不启用
+	Token   string        `yaml:"token"`   // 访问令牌，以 Authorization: Bearer 发送
+	Timeout time.Duration `yaml:"timeout"` // 请求超时，默认 5s
+}
+
+// TelegramConfig 结构体表示 Telegram 投稿来源配置，监听的群组或频道中的消息会作为投稿收录并转发到飞书群
+type TelegramConfig struct {
+	Enabled     bool    `yaml:"enabled"`      // 是否启用
+	AppID       int     `yaml:"app_id"`       // Telegram API ID，从 https://my.telegram.org 获取
+	AppHash     string  `yaml:"app_hash"`     // Telegram API Hash
+	BotToken    string  `yaml:"bot_token"`    // 以机器人身份登录时的令牌，与 phone 二选一
+	Phone       string  `yaml:"phone"`        // 以用户身份登录时的手机号，需先执行 telegram-login 命令输入验证码
+	Password    string  `yaml:"password"`     // 用户账号的两步验证密码
+	SessionFile string  `yaml:"session_file"` // 登录会话的保存路径，默认 data/telegram_session.json
+	Chats       []int64 `yaml:"chats"`        // 监听的群组或频道 Chat ID，如 -1001234567890
+	TenantKey   string  `yaml:"tenant_key"`   // 对接哪个飞书应用的租户：收录到该租户并转发到其群聊，该租户的文章也会发布到 Telegram，单应用部署留空
+
+	Publish TelegramPublishConfig `yaml:"publish"` // 将已发布文章同步发布到 Telegram 频道
+}
+
+// TelegramPublishConfig 结构体表示将文章发布到 Telegram 频道的配置，channels 为空时不发布
+type TelegramPublishConfig struct {
+	BotToken string   `yaml:"bot_token"` // 发布所用机器人的令牌，留空使用 telegram.bot_token；机器人需是频道管理员
+	Channels []string `yaml:"channels"`  // 目标频道，@username 或 Chat ID
+	APIURL   string   `yaml:"api_url"`   // Bot API 地址，默认 https://api.telegram.org
+}
+
+// WebhooksConfig 结构体表示 webhook 投递配置，端点也可以通过管理接口注册
+type WebhooksConfig struct {
+	MaxAttempts int                     `yaml:"max_attempts"` // 单次投递的最多尝试次数，默认 6
+	Timeout     time.Duration           `yaml:"timeout"`      // 单次请求超时，默认 10s
+	Endpoints   []WebhookEndpointConfig `yaml:"endpoints"`    // 配置文件中的端点，启动时按名称同步到数据库
+}
+
+// WebhookEndpointConfig 结构体表示一个 webhook 端点
+type WebhookEndpointConfig struct {
+	Name      string   `yaml:"name"`       // 端点名称，唯一
+	URL       string   `yaml:"url"`        // 接收事件的地址
+	Secret    string   `yaml:"secret"`     // HMAC-SHA256 签名密钥，必填
+	Events    []string `yaml:"events"`     // 订阅的事件，为空表示全部
+	TenantKey string   `yaml:"tenant_key"` // 只接收该租户的事件，为空表示全部租户
+}
+
+// 飞书消息事件的处理方式
+const (
+	EventQueueModeAsync = "async" // 写入队列后立即确认，由工作协程异步处理 (默认)
+	EventQueueModeSync  = "sync"  // 在事件回调中直接处理
+)
+
+// EventQueueConfig 结构体表示飞书消息事件的异步处理队列配置。事件先写入数据库再确认，进程崩溃后重启时继续处理
+type EventQueueConfig struct {
+	Mode        string        `yaml:"mode"`         // 处理方式: async (默认) / sync
+	Workers     int           `yaml:"workers"`      // 每个飞书应用的工作协程数，同一群聊（或私聊）的事件由同一协程按顺序处理，默认 4
+	Buffer      int           `yaml:"buffer"`       // 每个工作协程的待处理事件缓冲区大小，缓冲区满时事件留在数据库中稍后处理，默认 100
+	MaxAttempts int           `yaml:"max_attempts"` // 事件因进程退出等原因未处理完成时的最多尝试次数，默认 3
+	Retention   time.Duration `yaml:"retention"`    // 已处理事件的保留时长，默认 168h
+}
+
+// IsAsync 判断是否异步处理飞书消息事件
+func (c *EventQueueConfig) IsAsync() bool {
+	return c.Mode == EventQueueModeAsync
+}
+
+// TracingConfig 结构体表示 OpenTelemetry 链路追踪配置，追踪数据通过 OTLP/HTTP 导出
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`      // 是否启用，默认关闭
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/HTTP 接收地址，如 http://otel-collector:4318，为空时使用 OTEL_EXPORTER_OTLP_* 环境变量
+	ServiceName string  `yaml:"service_name"` // 上报的服务名，默认 miko-news
+	SampleRatio float64 `yaml:"sample_ratio"` // 新链路的采样比例，(0, 1]，默认 1；上游已采样的请求总是被采样
+}
+
+// HealthConfig 结构体表示 /healthz/live 和 /healthz/ready 的检查阈值
+type HealthConfig struct {
+	Timeout            time.Duration `yaml:"timeout"`              // 每项检查的超时时间，默认 3s
+	DBMaxPoolUsage     float64       `yaml:"db_max_pool_usage"`    // 使用中的连接数占连接池上限的比例达到该值时未就绪，默认 0.9
+	TokenCheckInterval time.Duration `yaml:"token_check_interval"` // 获取飞书 tenant_access_token 的检查间隔，期间复用上次结果，默认 1m
+	WSMaxSilence       time.Duration `yaml:"ws_max_silence"`       // WebSocket 超过该时长未连接或未收到 pong 时视为连接已失效，默认 5m
+	QueueMaxPending    int64         `yaml:"queue_max_pending"`    // 事件队列待处理事件数上限，默认 1000
+	QueueMaxAge        time.Duration `yaml:"queue_max_age"`        // 事件队列最早待处理事件的等待时长上限，默认 5m
+}
+
 // LoadConfig 加载配置文件并解析为 Config 结构体
 func LoadConfig() (*Config, error) {
 	// 打开配置文件
@@ -66,12 +435,292 @@ func LoadConfig() (*Config, error) {
 	// 从环境变量覆盖配置
 	overrideFromEnv(&cfg)
 
+	// 填充未配置项的默认值
+	applyDefaults(&cfg)
+
+	if err := validateDatabase(&cfg.Database); err != nil {
+		return nil, err
+	}
+	for _, app := range cfg.FeishuAppConfigs() {
+		if err := validateFeishuEventMode(app); err != nil {
+			return nil, err
+		}
+		if err := validateFeishuDocs(&app.Docs); err != nil {
+			return nil, err
+		}
+		if err := validateFeishuBitable(&app.Bitable); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateFeishuApps(&cfg); err != nil {
+		return nil, err
+	}
+	if err := validateSubmissionPolicy(&cfg.SubmissionPolicy); err != nil {
+		return nil, err
+	}
+	for _, app := range cfg.FeishuAppConfigs() {
+		if app.SubmissionPolicy == nil {
+			continue
+		}
+		if err := validateSubmissionPolicy(app.SubmissionPolicy); err != nil {
+			return nil, fmt.Errorf("租户 %q: %w", app.TenantKey, err)
+		}
+	}
+	if cfg.EventQueue.Mode != EventQueueModeAsync && cfg.EventQueue.Mode != EventQueueModeSync {
+		return nil, fmt.Errorf("不支持的 event_queue.mode: %s", cfg.EventQueue.Mode)
+	}
+
 	return &cfg, nil
 }
 
+// validateFeishuApps 校验多应用配置：租户标识必填且唯一，webhook 回调路径不能重复。
+// 有多个应用时，全局配置的管理员和按 open_id、部门、群聊匹配的策略规则只在其中一个应用内有效，必须按应用分别配置
+func validateFeishuApps(cfg *Config) error {
+	if len(cfg.FeishuApps) == 0 {
+		return nil
+	}
+	multiApp := len(cfg.FeishuApps) > 1
+	tenants := make(map[string]bool)
+	paths := make(map[string]bool)
+	for i, app := range cfg.FeishuApps {
+		if app.TenantKey == "" {
+			return fmt.Errorf("feishu_apps[%d] 缺少 tenant_key", i)
+		}
+		if tenants[app.TenantKey] {
+			return fmt.Errorf("feishu_apps 中的 tenant_key 重复: %s", app.TenantKey)
+		}
+		tenants[app.TenantKey] = true
+		if app.IsWebhookMode() {
+			if paths[app.EventPath] {
+				return fmt.Errorf("feishu_apps 中的 event_path 重复: %s", app.EventPath)
+			}
+			paths[app.EventPath] = true
+		}
+		if multiApp && app.AdminOpenIDs == nil && len(cfg.Admin.OpenIDs) > 0 {
+			return fmt.Errorf("feishu_apps[%d] (%s) 缺少 admin_open_ids：open_id 只在所属应用内有效，多应用部署时不能使用全局 admin.open_ids", i, app.TenantKey)
+		}
+		if multiApp && app.SubmissionPolicy == nil && policyMatchesIDs(&cfg.SubmissionPolicy) {
+			return fmt.Errorf("feishu_apps[%d] (%s) 缺少 submission_policy：全局策略规则中的 open_id、部门和群聊只在所属应用内有效", i, app.TenantKey)
+		}
+	}
+	return nil
+}
+
+// policyMatchesIDs 判断策略是否有按 open_id、部门或群聊匹配的规则
+func policyMatchesIDs(conf *SubmissionPolicyConfig) bool {
+	for _, rule := range conf.Rules {
+		if len(rule.OpenIDs) > 0 || len(rule.Departments) > 0 || len(rule.Chats) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSubmissionPolicy 校验投稿策略的效果只能是 allow 或 deny，避免拼写错误的 deny 规则被当作允许
+func validateSubmissionPolicy(conf *SubmissionPolicyConfig) error {
+	if !isPolicyEffect(conf.DefaultEffect) {
+		return fmt.Errorf("不支持的 submission_policy.default_effect: %q，只能是 allow 或 deny", conf.DefaultEffect)
+	}
+	for i, rule := range conf.Rules {
+		if !isPolicyEffect(rule.Effect) {
+			return fmt.Errorf("不支持的 submission_policy.rules[%d] (%s) effect: %q，只能是 allow 或 deny", i, rule.Name, rule.Effect)
+		}
+	}
+	return nil
+}
+
+// isPolicyEffect 判断是否为已知的策略效果
+func isPolicyEffect(effect string) bool {
+	return effect == PolicyEffectAllow || effect == PolicyEffectDeny
+}
+
+// validateFeishuEventMode 校验接收事件的方式只能是 websocket 或 webhook，避免拼写错误时静默使用长连接
+func validateFeishuEventMode(conf *FeishuConfig) error {
+	switch conf.EventMode {
+	case EventModeWebSocket, EventModeWebhook:
+		return nil
+	default:
+		return fmt.Errorf("不支持的 event_mode: %q，只能是 websocket 或 webhook", conf.EventMode)
+	}
+}
+
+// validateFeishuBitable 校验多维表格同步配置：字段必须是已知字段，且文章ID列不能为空
+func validateFeishuBitable(conf *FeishuBitableConfig) error {
+	if !conf.Enabled() {
+		return nil
+	}
+	for field := range conf.Fields {
+		if _, ok := defaultBitableFields[field]; !ok {
+			return fmt.Errorf("bitable.fields 中的字段未知: %s", field)
+		}
+	}
+	if conf.FieldName(BitableFieldID) == "" {
+		return fmt.Errorf("bitable.fields.id 不能为空，需用于匹配已有记录")
+	}
+	return nil
+}
+
+// validateDatabase 校验数据库驱动配置
+func validateDatabase(conf *DatabaseConfig) error {
+	switch conf.Driver {
+	case DatabaseDriverMySQL, DatabaseDriverPostgres:
+		return nil
+	case DatabaseDriverSQLite:
+		if conf.Path == "" && conf.DSN == "" {
+			return fmt.Errorf("database.driver 为 sqlite 时必须填写 database.path")
+		}
+		return nil
+	default:
+		return fmt.Errorf("不支持的 database.driver: %s", conf.Driver)
+	}
+}
+
+// validateFeishuDocs 校验文章归档配置
+func validateFeishuDocs(conf *FeishuDocsConfig) error {
+	switch conf.Mode {
+	case "", DocsModeDocx:
+		return nil
+	case DocsModeWiki:
+		if conf.WikiSpaceID == "" {
+			return fmt.Errorf("docs.mode 为 wiki 时必须填写 docs.wiki_space_id")
+		}
+		return nil
+	default:
+		return fmt.Errorf("不支持的 docs.mode: %s", conf.Mode)
+	}
+}
+
+// applyDefaults 为未配置的选项填充默认值
+func applyDefaults(cfg *Config) {
+	applyFeishuDefaults(&cfg.Feishu, "/webhook/feishu/event")
+	for i := range cfg.FeishuApps {
+		// 多应用时默认按租户区分回调路径
+		applyFeishuDefaults(&cfg.FeishuApps[i], "/webhook/feishu/event/"+cfg.FeishuApps[i].TenantKey)
+	}
+	if cfg.Database.Driver == "" {
+		cfg.Database.Driver = DatabaseDriverMySQL
+	}
+	if cfg.Database.Driver == DatabaseDriverPostgres && cfg.Database.SSLMode == "" {
+		cfg.Database.SSLMode = "disable"
+	}
+	if cfg.Server.ShutdownTimeout == 0 {
+		cfg.Server.ShutdownTimeout = 20 * time.Second
+	}
+	if cfg.Contact.SyncInterval == 0 {
+		cfg.Contact.SyncInterval = 24 * time.Hour
+	}
+	if cfg.Contact.CacheSize == 0 {
+		cfg.Contact.CacheSize = 1000
+	}
+	if cfg.Contact.CacheTTL == 0 {
+		cfg.Contact.CacheTTL = 10 * time.Minute
+	}
+	if cfg.Moderation.External.Timeout == 0 {
+		cfg.Moderation.External.Timeout = 5 * time.Second
+	}
+	if cfg.Telegram.Publish.BotToken == "" {
+		cfg.Telegram.Publish.BotToken = cfg.Telegram.BotToken
+	}
+	if cfg.Telegram.Publish.APIURL == "" {
+		cfg.Telegram.Publish.APIURL = "https://api.telegram.org"
+	}
+	if cfg.Webhooks.MaxAttempts == 0 {
+		cfg.Webhooks.MaxAttempts = 6
+	}
+	if cfg.Webhooks.Timeout == 0 {
+		cfg.Webhooks.Timeout = 10 * time.Second
+	}
+	if cfg.EventQueue.Mode == "" {
+		cfg.EventQueue.Mode = EventQueueModeAsync
+	}
+	if cfg.EventQueue.Workers <= 0 {
+		cfg.EventQueue.Workers = 4
+	}
+	if cfg.EventQueue.Buffer <= 0 {
+		cfg.EventQueue.Buffer = 100
+	}
+	if cfg.EventQueue.MaxAttempts <= 0 {
+		cfg.EventQueue.MaxAttempts = 3
+	}
+	if cfg.EventQueue.Retention <= 0 {
+		cfg.EventQueue.Retention = 7 * 24 * time.Hour
+	}
+	if cfg.Health.Timeout <= 0 {
+		cfg.Health.Timeout = 3 * time.Second
+	}
+	if cfg.Health.DBMaxPoolUsage <= 0 {
+		cfg.Health.DBMaxPoolUsage = 0.9
+	}
+	if cfg.Health.TokenCheckInterval <= 0 {
+		cfg.Health.TokenCheckInterval = time.Minute
+	}
+	if cfg.Health.WSMaxSilence <= 0 {
+		cfg.Health.WSMaxSilence = 5 * time.Minute
+	}
+	if cfg.Health.QueueMaxPending <= 0 {
+		cfg.Health.QueueMaxPending = 1000
+	}
+	if cfg.Health.QueueMaxAge <= 0 {
+		cfg.Health.QueueMaxAge = 5 * time.Minute
+	}
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = "miko-news"
+	}
+	if cfg.Tracing.SampleRatio <= 0 || cfg.Tracing.SampleRatio > 1 {
+		cfg.Tracing.SampleRatio = 1
+	}
+	if cfg.Telegram.SessionFile == "" {
+		cfg.Telegram.SessionFile = "data/telegram_session.json"
+	}
+	if cfg.RateLimit.Backend == "" {
+		cfg.RateLimit.Backend = RateLimitBackendDB
+	}
+	applySubmissionPolicyDefaults(&cfg.SubmissionPolicy)
+	for _, app := range cfg.FeishuAppConfigs() {
+		if app.SubmissionPolicy != nil {
+			applySubmissionPolicyDefaults(app.SubmissionPolicy)
+		}
+		if app.RateLimit != nil && app.RateLimit.Backend == "" {
+			app.RateLimit.Backend = cfg.RateLimit.Backend
+		}
+	}
+}
+
+// applySubmissionPolicyDefaults 为投稿权限策略填充默认值
+func applySubmissionPolicyDefaults(conf *SubmissionPolicyConfig) {
+	if conf.DefaultEffect == "" {
+		conf.DefaultEffect = PolicyEffectAllow
+	}
+	if conf.DenyMessage == "" {
+		conf.DenyMessage = "抱歉，您暂时没有投稿权限，如有疑问请联系管理员。"
+	}
+}
+
+// applyFeishuDefaults 为飞书应用配置填充默认值
+func applyFeishuDefaults(conf *FeishuConfig, eventPath string) {
+	if conf.EventMode == "" {
+		conf.EventMode = EventModeWebSocket
+	}
+	if conf.EventPath == "" {
+		conf.EventPath = eventPath
+	}
+}
+
 // overrideFromEnv 从环境变量覆盖配置
 func overrideFromEnv(cfg *Config) {
 	// 数据库配置
+	if driver := os.Getenv("DB_DRIVER"); driver != "" {
+		cfg.Database.Driver = driver
+	}
+	if path := os.Getenv("DB_PATH"); path != "" {
+		cfg.Database.Path = path
+	}
+	if dsn := os.Getenv("DB_DSN"); dsn != "" {
+		cfg.Database.DSN = dsn
+	}
+	if autoMigrate, err := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE")); err == nil {
+		cfg.Database.AutoMigrate = autoMigrate
+	}
 	if host := os.Getenv("DB_HOST"); host != "" {
 		cfg.Database.Host = host
 	}
@@ -103,6 +752,39 @@ func overrideFromEnv(cfg *Config) {
 	if key := os.Getenv("FEISHU_ENCRYPT_KEY"); key != "" {
 		cfg.Feishu.EncryptKey = key
 	}
+	if botOpenID := os.Getenv("FEISHU_BOT_OPEN_ID"); botOpenID != "" {
+		cfg.Feishu.BotOpenID = botOpenID
+	}
+	if mode := os.Getenv("FEISHU_EVENT_MODE"); mode != "" {
+		cfg.Feishu.EventMode = mode
+	}
+	if path := os.Getenv("FEISHU_EVENT_PATH"); path != "" {
+		cfg.Feishu.EventPath = path
+	}
+	if tenantKey := os.Getenv("FEISHU_TENANT_KEY"); tenantKey != "" {
+		cfg.Feishu.TenantKey = tenantKey
+	}
+	if domain := os.Getenv("FEISHU_DOMAIN"); domain != "" {
+		cfg.Feishu.Domain = domain
+	}
+	if mode := os.Getenv("FEISHU_DOCS_MODE"); mode != "" {
+		cfg.Feishu.Docs.Mode = mode
+	}
+	if folder := os.Getenv("FEISHU_DOCS_FOLDER_TOKEN"); folder != "" {
+		cfg.Feishu.Docs.FolderToken = folder
+	}
+	if spaceID := os.Getenv("FEISHU_WIKI_SPACE_ID"); spaceID != "" {
+		cfg.Feishu.Docs.WikiSpaceID = spaceID
+	}
+	if parent := os.Getenv("FEISHU_WIKI_PARENT_NODE"); parent != "" {
+		cfg.Feishu.Docs.WikiParentNode = parent
+	}
+	if appToken := os.Getenv("FEISHU_BITABLE_APP_TOKEN"); appToken != "" {
+		cfg.Feishu.Bitable.AppToken = appToken
+	}
+	if tableID := os.Getenv("FEISHU_BITABLE_TABLE_ID"); tableID != "" {
+		cfg.Feishu.Bitable.TableID = tableID
+	}
 
 	// 服务器配置
 	if portStr := os.Getenv("PORT"); portStr != "" {
@@ -111,11 +793,112 @@ func overrideFromEnv(cfg *Config) {
 		}
 	}
 
+	if publicURL := os.Getenv("SERVER_PUBLIC_URL"); publicURL != "" {
+		cfg.Server.PublicURL = publicURL
+	}
+	if timeout := os.Getenv("SERVER_SHUTDOWN_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			cfg.Server.ShutdownTimeout = d
+		}
+	}
+
 	// 群聊ID列表
 	if groupChats := os.Getenv("FEISHU_GROUP_CHATS"); groupChats != "" {
 		cfg.Feishu.GroupChats = strings.Split(groupChats, ",")
 	}
 
+	// 管理员配置
+	if openIDs := os.Getenv("ADMIN_OPEN_IDS"); openIDs != "" {
+		cfg.Admin.OpenIDs = strings.Split(openIDs, ",")
+	}
+	if tokens := os.Getenv("ADMIN_API_TOKENS"); tokens != "" {
+		cfg.Admin.APITokens = strings.Split(tokens, ",")
+	}
+	if key := os.Getenv("ADMIN_SECRET_KEY"); key != "" {
+		cfg.Admin.SecretKey = key
+	}
+
+	// 通讯录同步配置
+	if interval := os.Getenv("CONTACT_SYNC_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			cfg.Contact.SyncInterval = d
+		}
+	}
+
+	// 事件队列配置
+	if mode := os.Getenv("EVENT_QUEUE_MODE"); mode != "" {
+		cfg.EventQueue.Mode = mode
+	}
+	if workers := os.Getenv("EVENT_QUEUE_WORKERS"); workers != "" {
+		if n, err := strconv.Atoi(workers); err == nil {
+			cfg.EventQueue.Workers = n
+		}
+	}
+
+	// 链路追踪配置
+	if enabled, err := strconv.ParseBool(os.Getenv("TRACING_ENABLED")); err == nil {
+		cfg.Tracing.Enabled = enabled
+	}
+	if endpoint := os.Getenv("TRACING_ENDPOINT"); endpoint != "" {
+		cfg.Tracing.Endpoint = endpoint
+	}
+
+	// 投稿限流配置
+	if backend := os.Getenv("RATE_LIMIT_BACKEND"); backend != "" {
+		cfg.RateLimit.Backend = backend
+	}
+	if perHour := os.Getenv("RATE_LIMIT_PER_HOUR"); perHour != "" {
+		if n, err := strconv.Atoi(perHour); err == nil {
+			cfg.RateLimit.Default.PerHour = n
+		}
+	}
+	if perDay := os.Getenv("RATE_LIMIT_PER_DAY"); perDay != "" {
+		if n, err := strconv.Atoi(perDay); err == nil {
+			cfg.RateLimit.Default.PerDay = n
+		}
+	}
+
+	// 内容审核配置
+	if words := os.Getenv("MODERATION_SENSITIVE_WORDS_FILE"); words != "" {
+		cfg.Moderation.SensitiveWordsFile = words
+	}
+	if externalURL := os.Getenv("MODERATION_EXTERNAL_URL"); externalURL != "" {
+		cfg.Moderation.External.URL = externalURL
+	}
+	if token := os.Getenv("MODERATION_EXTERNAL_TOKEN"); token != "" {
+		cfg.Moderation.External.Token = token
+	}
+
+	// Telegram 配置
+	if appID := os.Getenv("TELEGRAM_APP_ID"); appID != "" {
+		if id, err := strconv.Atoi(appID); err == nil {
+			cfg.Telegram.AppID = id
+		}
+	}
+	if appHash := os.Getenv("TELEGRAM_APP_HASH"); appHash != "" {
+		cfg.Telegram.AppHash = appHash
+	}
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		cfg.Telegram.BotToken = token
+	}
+	if phone := os.Getenv("TELEGRAM_PHONE"); phone != "" {
+		cfg.Telegram.Phone = phone
+	}
+	if password := os.Getenv("TELEGRAM_PASSWORD"); password != "" {
+		cfg.Telegram.Password = password
+	}
+	if channels := os.Getenv("TELEGRAM_PUBLISH_CHANNELS"); channels != "" {
+		cfg.Telegram.Publish.Channels = strings.Split(channels, ",")
+	}
+	if chats := os.Getenv("TELEGRAM_CHATS"); chats != "" {
+		cfg.Telegram.Chats = nil
+		for _, chat := range strings.Split(chats, ",") {
+			if id, err := strconv.ParseInt(strings.TrimSpace(chat), 10, 64); err == nil {
+				cfg.Telegram.Chats = append(cfg.Telegram.Chats, id)
+			}
+		}
+	}
+
 	// 日志配置
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
 		cfg.Logger.Level = level
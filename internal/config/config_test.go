@@ -0,0 +1,147 @@
+package config
+
+import "testing"
+
+func TestValidateSubmissionPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    SubmissionPolicyConfig
+		wantErr bool
+	}{
+		{"默认允许", SubmissionPolicyConfig{DefaultEffect: PolicyEffectAllow}, false},
+		{"规则", SubmissionPolicyConfig{DefaultEffect: PolicyEffectDeny, Rules: []SubmissionPolicyRule{{Effect: PolicyEffectAllow}, {Effect: PolicyEffectDeny}}}, false},
+		{"默认效果大小写错误", SubmissionPolicyConfig{DefaultEffect: "Deny"}, true},
+		{"规则效果未知", SubmissionPolicyConfig{DefaultEffect: PolicyEffectAllow, Rules: []SubmissionPolicyRule{{Name: "外部成员", Effect: "block"}}}, true},
+		{"规则缺少效果", SubmissionPolicyConfig{DefaultEffect: PolicyEffectAllow, Rules: []SubmissionPolicyRule{{Name: "外部成员"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSubmissionPolicy(&tt.conf); (err != nil) != tt.wantErr {
+				t.Errorf("validateSubmissionPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFeishuEventMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{EventModeWebSocket, false},
+		{EventModeWebhook, false},
+		{"Webhook", true},
+		{"http", true},
+		{"", true}, // 未配置时由 applyFeishuDefaults 填充为 websocket
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if err := validateFeishuEventMode(&FeishuConfig{EventMode: tt.mode}); (err != nil) != tt.wantErr {
+				t.Errorf("validateFeishuEventMode(%q) error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeishuAppOverrides(t *testing.T) {
+	cfg := &Config{
+		Admin:            AdminConfig{OpenIDs: []string{"ou_global"}, SecretKey: "secret"},
+		SubmissionPolicy: SubmissionPolicyConfig{DefaultEffect: PolicyEffectAllow},
+		RateLimit:        RateLimitConfig{Default: RateLimitQuota{PerDay: 10}},
+		FeishuApps: []FeishuConfig{
+			{
+				TenantKey:    "cn",
+				AdminOpenIDs: []string{"ou_cn_admin"},
+				SubmissionPolicy: &SubmissionPolicyConfig{Rules: []SubmissionPolicyRule{
+					{Effect: PolicyEffectAllow, Chats: []string{"oc_cn_editors"}},
+				}},
+			},
+			{
+				TenantKey:    "intl",
+				AdminOpenIDs: []string{"ou_intl_admin"},
+				SubmissionPolicy: &SubmissionPolicyConfig{DefaultEffect: PolicyEffectDeny, Rules: []SubmissionPolicyRule{
+					{Effect: PolicyEffectAllow, OpenIDs: []string{"ou_intl_user"}},
+				}},
+				RateLimit: &RateLimitConfig{Default: RateLimitQuota{PerDay: 3}},
+			},
+		},
+	}
+	applyDefaults(cfg)
+	if err := validateFeishuApps(cfg); err != nil {
+		t.Fatalf("validateFeishuApps() error = %v", err)
+	}
+
+	apps := cfg.FeishuAppConfigs()
+	cn, intl := apps[0], apps[1]
+	if got := cfg.AppAdmin(cn); !got.IsAdmin("ou_cn_admin") || got.IsAdmin("ou_intl_admin") || got.IsAdmin("ou_global") {
+		t.Errorf("cn 的管理员应只有 ou_cn_admin，实际 %v", got.OpenIDs)
+	}
+	if got := cfg.AppAdmin(intl); !got.IsAdmin("ou_intl_admin") || got.IsAdmin("ou_cn_admin") {
+		t.Errorf("intl 的管理员应只有 ou_intl_admin，实际 %v", got.OpenIDs)
+	}
+	if got := cfg.AppAdmin(intl).SecretKey; got != "secret" {
+		t.Errorf("加密密钥应使用全局配置，实际 %q", got)
+	}
+	if len(cfg.Admin.OpenIDs) != 1 || cfg.Admin.OpenIDs[0] != "ou_global" {
+		t.Errorf("全局管理员不应被修改，实际 %v", cfg.Admin.OpenIDs)
+	}
+
+	if got := cfg.AppSubmissionPolicy(cn); got.Rules[0].Chats[0] != "oc_cn_editors" || got.DefaultEffect != PolicyEffectAllow || got.DenyMessage == "" {
+		t.Errorf("cn 的投稿策略应使用本应用配置并填充默认值，实际 %+v", got)
+	}
+	if got := cfg.AppSubmissionPolicy(intl); got.DefaultEffect != PolicyEffectDeny || got.Rules[0].OpenIDs[0] != "ou_intl_user" {
+		t.Errorf("intl 的投稿策略应使用本应用配置，实际 %+v", got)
+	}
+
+	if got := cfg.AppRateLimit(cn); got != &cfg.RateLimit {
+		t.Errorf("cn 未配置限流时应使用全局配置，实际 %+v", got)
+	}
+	if got := cfg.AppRateLimit(intl); got.Default.PerDay != 3 || got.Backend != RateLimitBackendDB {
+		t.Errorf("intl 的限流应使用本应用配置并继承全局存储后端，实际 %+v", got)
+	}
+}
+
+func TestValidateFeishuApps(t *testing.T) {
+	chatRule := SubmissionPolicyConfig{DefaultEffect: PolicyEffectAllow, Rules: []SubmissionPolicyRule{{Effect: PolicyEffectDeny, Chats: []string{"oc_a"}}}}
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"单应用使用全局管理员和策略", Config{
+			Admin:            AdminConfig{OpenIDs: []string{"ou_admin"}},
+			SubmissionPolicy: chatRule,
+			FeishuApps:       []FeishuConfig{{TenantKey: "cn"}},
+		}, false},
+		{"多应用分别配置", Config{
+			Admin:            AdminConfig{OpenIDs: []string{"ou_admin"}},
+			SubmissionPolicy: chatRule,
+			FeishuApps: []FeishuConfig{
+				{TenantKey: "cn", AdminOpenIDs: []string{"ou_a"}, SubmissionPolicy: &chatRule},
+				{TenantKey: "intl", AdminOpenIDs: []string{}, SubmissionPolicy: &SubmissionPolicyConfig{DefaultEffect: PolicyEffectAllow}},
+			},
+		}, false},
+		{"多应用共用不含ID的全局策略", Config{
+			SubmissionPolicy: SubmissionPolicyConfig{DefaultEffect: PolicyEffectAllow, DefaultReview: true},
+			FeishuApps:       []FeishuConfig{{TenantKey: "cn"}, {TenantKey: "intl"}},
+		}, false},
+		{"多应用使用全局管理员", Config{
+			Admin:      AdminConfig{OpenIDs: []string{"ou_admin"}},
+			FeishuApps: []FeishuConfig{{TenantKey: "cn", AdminOpenIDs: []string{"ou_a"}}, {TenantKey: "intl"}},
+		}, true},
+		{"多应用使用按群聊匹配的全局策略", Config{
+			SubmissionPolicy: chatRule,
+			FeishuApps:       []FeishuConfig{{TenantKey: "cn", SubmissionPolicy: &chatRule}, {TenantKey: "intl"}},
+		}, true},
+		{"租户标识重复", Config{
+			FeishuApps: []FeishuConfig{{TenantKey: "cn"}, {TenantKey: "cn"}},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateFeishuApps(&tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateFeishuApps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"MikoNews/internal/config"
 	"MikoNews/internal/pkg/logger"
 	"fmt"
+	"net/url"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
 )
@@ -15,13 +18,19 @@ import (
 // DB 是数据库连接的封装
 type DB struct {
 	*gorm.DB
+	Driver string // 数据库驱动，见 config.DatabaseDriver*
 }
 
-// New 创建一个新的数据库连接
+// New 按 conf.Driver 创建一个新的数据库连接，未配置驱动时使用 MySQL
 func New(conf *config.DatabaseConfig) (*DB, error) {
-	// 构建DSN连接字符串
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		conf.User, conf.Password, conf.Host, conf.Port, conf.DBName)
+	driver := conf.Driver
+	if driver == "" {
+		driver = config.DatabaseDriverMySQL
+	}
+	dialector, err := openDialector(driver, conf)
+	if err != nil {
+		return nil, err
+	}
 
 	// 配置GORM日志
 	gormLogLevel := gormlogger.Info
@@ -45,13 +54,19 @@ func New(conf *config.DatabaseConfig) (*DB, error) {
 	)
 
 	// 打开数据库连接
-	gormDB, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: gormLogger,
+	gormDB, err := gorm.Open(dialector, &gorm.Config{
+		Logger:         gormLogger,
+		TranslateError: true, // 将各驱动的唯一键冲突统一转换为 gorm.ErrDuplicatedKey
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// 为每条 SQL 创建 span，未启用链路追踪时开销可以忽略
+	if err := gormDB.Use(&TracingPlugin{Driver: driver}); err != nil {
+		return nil, fmt.Errorf("注册链路追踪插件失败: %w", err)
+	}
+
 	// 获取底层SQL连接以设置连接池
 	sqlDB, err := gormDB.DB()
 	if err != nil {
@@ -62,8 +77,51 @@ func New(conf *config.DatabaseConfig) (*DB, error) {
 	sqlDB.SetMaxOpenConns(conf.MaxOpenConns)
 	sqlDB.SetMaxIdleConns(conf.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(time.Hour)
+	if driver == config.DatabaseDriverSQLite {
+		// SQLite 同一时间只允许一个写入者，串行化连接以避免 database is locked
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	return &DB{DB: gormDB, Driver: driver}, nil
+}
 
-	return &DB{DB: gormDB}, nil
+// openDialector 根据驱动构建 GORM 方言，配置了 conf.DSN 时直接使用
+func openDialector(driver string, conf *config.DatabaseConfig) (gorm.Dialector, error) {
+	switch driver {
+	case config.DatabaseDriverMySQL:
+		dsn := conf.DSN
+		if dsn == "" {
+			dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+				conf.User, conf.Password, conf.Host, conf.Port, conf.DBName)
+		}
+		return mysql.Open(dsn), nil
+	case config.DatabaseDriverPostgres:
+		dsn := conf.DSN
+		if dsn == "" {
+			sslMode := conf.SSLMode
+			if sslMode == "" {
+				sslMode = "disable"
+			}
+			u := url.URL{
+				Scheme:   "postgres",
+				User:     url.UserPassword(conf.User, conf.Password),
+				Host:     fmt.Sprintf("%s:%d", conf.Host, conf.Port),
+				Path:     "/" + conf.DBName,
+				RawQuery: "sslmode=" + url.QueryEscape(sslMode),
+			}
+			dsn = u.String()
+		}
+		return postgres.Open(dsn), nil
+	case config.DatabaseDriverSQLite:
+		dsn := conf.DSN
+		if dsn == "" {
+			// 时间统一按 "2006-01-02 15:04:05.999999999-07:00" 保存，保证按字符串比较时顺序正确
+			dsn = conf.Path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_time_format=sqlite"
+		}
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
 }
 
 // Close 关闭数据库连接
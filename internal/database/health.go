@@ -0,0 +1,40 @@
+package database
+
+import (
+	"MikoNews/internal/pkg/health"
+	"context"
+	"fmt"
+)
+
+// HealthCheck 返回数据库的健康检查：Ping 失败，或使用中的连接数占连接池上限的比例达到 maxPoolUsage 时不可用。
+// 连接池上限为 1（SQLite）或不限时只检查 Ping
+func (db *DB) HealthCheck(maxPoolUsage float64) health.Check {
+	return func(ctx context.Context) (map[string]any, error) {
+		sqlDB, err := db.DB.DB()
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return nil, fmt.Errorf("数据库连接失败: %w", err)
+		}
+
+		stats := sqlDB.Stats()
+		details := map[string]any{
+			"driver":           db.Driver,
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"idle":             stats.Idle,
+			"max_open":         stats.MaxOpenConnections,
+			"wait_count":       stats.WaitCount,
+			"wait_duration_ms": stats.WaitDuration.Milliseconds(),
+		}
+		if stats.MaxOpenConnections > 1 {
+			usage := float64(stats.InUse) / float64(stats.MaxOpenConnections)
+			details["pool_usage"] = usage
+			if usage >= maxPoolUsage {
+				return details, fmt.Errorf("连接池接近耗尽: 使用中 %d / 上限 %d", stats.InUse, stats.MaxOpenConnections)
+			}
+		}
+		return details, nil
+	}
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	db := openSQLite(t)
+	details, err := db.HealthCheck(0.9)(context.Background())
+	if err != nil {
+		t.Fatalf("数据库应可用: %v", err)
+	}
+	if details["driver"] != db.Driver {
+		t.Errorf("details 应包含驱动: %v", details)
+	}
+
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 占用一半连接时超过阈值 0.5
+	sqlDB.SetMaxOpenConns(2)
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := db.HealthCheck(0.5)(context.Background()); err == nil {
+		t.Error("连接池使用率达到阈值时应不可用")
+	}
+
+	_ = db.Close()
+	if _, err := db.HealthCheck(0.9)(context.Background()); err == nil {
+		t.Error("数据库关闭后应不可用")
+	}
+}
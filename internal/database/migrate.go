@@ -0,0 +1,395 @@
+package database
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/migrations"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schemaMigrationsTable 记录已执行的迁移版本
+const schemaMigrationsTable = "schema_migrations"
+
+// postgresMigrationLockKey 是 PostgreSQL 迁移使用的 advisory lock 键，任意固定值即可
+const postgresMigrationLockKey int64 = 0x4d696b6f4e657773 // "MikoNews"
+
+// defaultMigrationLockTimeout 是等待其他实例释放迁移锁的最长时间
+const defaultMigrationLockTimeout = time.Minute
+
+// legacySchemaRequirements 是 legacy/013_article_forward_count.sql 之后的表结构中，各旧版升级脚本引入的表和列
+// 初始迁移使用 CREATE TABLE IF NOT EXISTS，数据库中已有旧表时不会补齐缺少的列，需要先执行对应的旧版脚本
+var legacySchemaRequirements = []struct {
+	table  string
+	column string // 为空表示检查表是否存在
+	script string
+}{
+	{"articles", "curator_id", "002_article_curator.sql"},
+	{"articles", "is_anonymous", "003_article_anonymous.sql"},
+	{"article_authors", "", "004_article_authors.sql"},
+	{"users", "", "005_users.sql"},
+	{"articles", "status", "006_article_status.sql"},
+	{"rate_limit_counters", "", "007_rate_limit_counters.sql"},
+	{"article_fingerprints", "", "008_article_fingerprints.sql"},
+	{"articles", "tenant_key", "009_tenant_key.sql"},
+	{"articles", "source", "010_article_source.sql"},
+	{"webhook_endpoints", "", "011_webhooks.sql"},
+	{"articles", "doc_token", "012_article_doc_token.sql"},
+	{"articles", "forward_count", "013_article_forward_count.sql"},
+}
+
+// Migration 是一个版本的迁移脚本
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string // 为空表示该版本不支持回滚
+}
+
+// MigrationStatus 是一个版本的执行状态
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Missing   bool // 数据库中已执行，但当前程序中没有对应的迁移脚本（通常是数据库比程序新）
+}
+
+// Migrator 执行内嵌在程序中的版本化迁移
+// MySQL 使用 GET_LOCK、PostgreSQL 使用 advisory lock 保证多个实例同时启动时只有一个执行迁移；
+// SQLite 只支持单实例部署，不加锁
+type Migrator struct {
+	db          *DB
+	migrations  []Migration
+	lockTimeout time.Duration
+}
+
+// NewMigrator 为 db 创建迁移执行器，使用 db 驱动对应的迁移脚本
+func NewMigrator(db *DB) (*Migrator, error) {
+	fsys, err := migrations.FS(db.Driver)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: loaded, lockTimeout: defaultMigrationLockTimeout}, nil
+}
+
+// LoadMigrations 读取 fsys 根目录下的 <版本号>_<名称>.up.sql / .down.sql，按版本号正序返回
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录失败: %w", err)
+	}
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		direction := path.Ext(base)
+		if direction != ".up" && direction != ".down" {
+			return nil, fmt.Errorf("迁移文件名需以 .up.sql 或 .down.sql 结尾: %s", entry.Name())
+		}
+		versionStr, name, ok := strings.Cut(strings.TrimSuffix(base, direction), "_")
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("迁移文件名格式应为 <版本号>_<名称>: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件 %s 失败: %w", entry.Name(), err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("迁移版本 %d 重复: %s 与 %s", version, migration.Name, name)
+		}
+		if direction == ".up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("迁移版本 %d 缺少 up 脚本", migration.Version)
+		}
+		result = append(result, *migration)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Up 按版本号依次执行所有尚未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			if err := checkLegacySchema(ctx, conn); err != nil {
+				return err
+			}
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down 按版本号倒序回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("迁移版本 %d 已执行，但程序中没有对应的脚本，无法回滚", version)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("迁移版本 %d (%s) 不支持回滚", version, migration.Name)
+			}
+			if err := m.run(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status 返回所有迁移的执行状态，按版本号正序
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var rows []struct {
+		Version   int64
+		Name      string
+		AppliedAt time.Time
+	}
+	if m.db.Migrator().HasTable(schemaMigrationsTable) {
+		if err := m.db.WithContext(ctx).Table(schemaMigrationsTable).Order("version").Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("读取迁移记录失败: %w", err)
+		}
+	}
+
+	applied := make(map[int64]MigrationStatus, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = MigrationStatus{Version: row.Version, Name: row.Name, Applied: true, AppliedAt: row.AppliedAt, Missing: true}
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations)+len(rows))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied, status.AppliedAt = true, row.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, status := range applied {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// find 按版本号查找迁移脚本
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// withLock 在一个独占的连接上持有迁移锁并执行 fn
+// 锁是会话级的，加锁、迁移和解锁必须使用同一个连接
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	sqlDB, err := m.db.DB.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+	defer conn.Close()
+
+	switch m.db.Driver {
+	case config.DatabaseDriverMySQL:
+		// GET_LOCK 在整个 MySQL 实例内生效，带上库名避免不同库之间互相等待
+		var acquired sql.NullInt64
+		err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(DATABASE(), '.schema_migrations'), ?)", int(m.lockTimeout.Seconds())).Scan(&acquired)
+		if err != nil {
+			return fmt.Errorf("获取迁移锁失败: %w", err)
+		}
+		if acquired.Int64 != 1 {
+			return fmt.Errorf("等待迁移锁超时，可能有其他实例正在执行迁移")
+		}
+		defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.schema_migrations'))")
+	case config.DatabaseDriverPostgres:
+		lockCtx, cancel := context.WithTimeout(ctx, m.lockTimeout)
+		defer cancel()
+		if _, err := conn.ExecContext(lockCtx, "SELECT pg_advisory_lock($1)", postgresMigrationLockKey); err != nil {
+			return fmt.Errorf("获取迁移锁失败，可能有其他实例正在执行迁移: %w", err)
+		}
+		defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", postgresMigrationLockKey)
+	}
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`); err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+	return fn(conn)
+}
+
+// checkLegacySchema 在首次迁移前检查数据库中是否有引入迁移前、且未升级到最新的旧表结构
+// 空数据库和已是最新结构的旧数据库可以直接迁移，否则返回需要先执行的旧版脚本
+func checkLegacySchema(ctx context.Context, conn *sql.Conn) error {
+	if !hasColumn(ctx, conn, "articles", "*") {
+		return nil
+	}
+	var missing []string
+	for _, req := range legacySchemaRequirements {
+		column := req.column
+		if column == "" {
+			column = "*"
+		}
+		if !hasColumn(ctx, conn, req.table, column) {
+			missing = append(missing, req.script)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("数据库中已有引入迁移前的旧表结构，且尚未升级到最新: 请先按顺序执行 migrations/legacy/ 下的 %s，再执行 migrate up", strings.Join(missing, "、"))
+	}
+	return nil
+}
+
+// hasColumn 通过查询空结果集判断表和列是否存在，column 为 * 时只检查表
+// 使用持有迁移锁的连接查询，SQLite 只有一个连接，不能再通过 GORM 的 Migrator 查询
+func hasColumn(ctx context.Context, conn *sql.Conn, table, column string) bool {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE 1 = 0", column, table))
+	if err != nil {
+		return false
+	}
+	_ = rows.Close()
+	return true
+}
+
+// appliedVersions 返回已执行的迁移版本
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]struct{}, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移记录失败: %w", err)
+	}
+	defer rows.Close()
+	applied := make(map[int64]struct{})
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = struct{}{}
+	}
+	return applied, rows.Err()
+}
+
+// run 在事务中执行一个迁移的 up 或 down 脚本并更新迁移记录
+// MySQL 的 DDL 会隐式提交事务，脚本中途失败时需要根据错误手动处理已执行的语句
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, migration Migration, up bool) error {
+	script, record, args := migration.Down, "DELETE FROM schema_migrations WHERE version = ?", []any{migration.Version}
+	if up {
+		script, record, args = migration.Up, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", []any{migration.Version, migration.Name, time.Now()}
+	}
+	if m.db.Driver == config.DatabaseDriverPostgres {
+		record = rebindPostgres(record)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, stmt := range SplitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("执行迁移 %d_%s 失败: %w\n%s", migration.Version, migration.Name, err, stmt)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("更新迁移记录失败: %w", err)
+	}
+	return tx.Commit()
+}
+
+// rebindPostgres 将 ? 占位符替换为 PostgreSQL 的 $n
+func rebindPostgres(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SplitStatements 将迁移脚本拆分为单条语句：语句以行尾的分号结束，整行注释会被忽略
+func SplitStatements(script string) []string {
+	var statements []string
+	var current []string
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current = append(current, line)
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(strings.Join(current, "\n")), ";"))
+			current = nil
+		}
+	}
+	if rest := strings.TrimSpace(strings.Join(current, "\n")); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,357 @@
+package database
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/migrations"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	logDir, err := os.MkdirTemp("", "miko-database-test")
+	if err != nil {
+		panic(err)
+	}
+	logger.InitLogger(filepath.Join(logDir, "test.log"), "warn")
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+// openSQLite 在临时目录中创建一个 SQLite 数据库
+func openSQLite(t *testing.T) *DB {
+	t.Helper()
+	db, err := New(&config.DatabaseConfig{Driver: config.DatabaseDriverSQLite, Path: filepath.Join(t.TempDir(), "miko_news.db")})
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_column.up.sql":   {Data: []byte("ALTER TABLE t ADD COLUMN c INT;")},
+		"0001_init.up.sql":         {Data: []byte("CREATE TABLE t (id INT);")},
+		"0001_init.down.sql":       {Data: []byte("DROP TABLE t;")},
+		"README.md":                {Data: []byte("ignored")},
+		"0002_add_column.down.sql": {Data: []byte("ALTER TABLE t DROP COLUMN c;")},
+	}
+	loaded, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("加载迁移失败: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Version != 1 || loaded[0].Name != "init" || loaded[1].Name != "add_column" {
+		t.Fatalf("迁移列表不符: %+v", loaded)
+	}
+	if loaded[0].Down != "DROP TABLE t;" {
+		t.Errorf("down 脚本不符: %q", loaded[0].Down)
+	}
+
+	invalid := map[string]fstest.MapFS{
+		"缺少 up": {"0001_init.down.sql": {Data: []byte("x")}},
+		"没有版本号": {"init.up.sql": {Data: []byte("x")}},
+		"没有方向":  {"0001_init.sql": {Data: []byte("x")}},
+		"版本号重复": {"0001_a.up.sql": {Data: []byte("x")}, "0001_b.up.sql": {Data: []byte("x")}},
+	}
+	for name, fsys := range invalid {
+		if _, err := LoadMigrations(fsys); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
+
+// TestMigrationsConsistentAcrossDrivers 各驱动的迁移版本和名称必须一一对应
+func TestMigrationsConsistentAcrossDrivers(t *testing.T) {
+	versions := make(map[string]string)
+	for _, driver := range []string{config.DatabaseDriverMySQL, config.DatabaseDriverPostgres, config.DatabaseDriverSQLite} {
+		fsys, err := migrations.FS(driver)
+		if err != nil {
+			t.Fatalf("读取 %s 迁移失败: %v", driver, err)
+		}
+		loaded, err := LoadMigrations(fsys)
+		if err != nil {
+			t.Fatalf("加载 %s 迁移失败: %v", driver, err)
+		}
+		var list []string
+		for _, migration := range loaded {
+			if migration.Down == "" {
+				t.Errorf("%s 的迁移 %d_%s 缺少 down 脚本", driver, migration.Version, migration.Name)
+			}
+			list = append(list, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+		versions[driver] = fmt.Sprint(list)
+	}
+	if versions[config.DatabaseDriverMySQL] != versions[config.DatabaseDriverPostgres] || versions[config.DatabaseDriverMySQL] != versions[config.DatabaseDriverSQLite] {
+		t.Errorf("各驱动的迁移不一致: %v", versions)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- 注释
+CREATE TABLE a (
+    id INT -- 行尾注释保留
+);
+
+CREATE INDEX idx ON a (id);
+INSERT INTO a VALUES (1)`
+	statements := SplitStatements(script)
+	if len(statements) != 3 {
+		t.Fatalf("期望 3 条语句，实际: %q", statements)
+	}
+	if statements[1] != "CREATE INDEX idx ON a (id)" || statements[2] != "INSERT INTO a VALUES (1)" {
+		t.Errorf("语句拆分不符: %q", statements)
+	}
+}
+
+func TestMigratorUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("创建迁移执行器失败: %v", err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("读取迁移状态失败: %v", err)
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			t.Errorf("新数据库的迁移不应已执行: %+v", status)
+		}
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	if len(applied) != len(statuses) {
+		t.Errorf("期望执行 %d 个迁移，实际: %d", len(statuses), len(applied))
+	}
+	if !db.Migrator().HasTable(&model.Article{}) {
+		t.Fatal("执行迁移后应存在 articles 表")
+	}
+	if applied, err = migrator.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("重复执行不应再有迁移: %v, %v", applied, err)
+	}
+
+	statuses, _ = migrator.Status(ctx)
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt.IsZero() {
+			t.Errorf("迁移应已执行: %+v", status)
+		}
+	}
+
+	reverted, err := migrator.Down(ctx, 1)
+	if err != nil || len(reverted) != 1 || reverted[0].Version != statuses[len(statuses)-1].Version {
+		t.Fatalf("回滚最近的迁移失败: %v, %v", reverted, err)
+	}
+	if _, err := migrator.Down(ctx, 100); err != nil {
+		t.Fatalf("回滚全部迁移失败: %v", err)
+	}
+	if db.Migrator().HasTable(&model.Article{}) {
+		t.Error("回滚全部迁移后不应存在 articles 表")
+	}
+}
+
+func TestMigratorStatusReportsMissing(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("创建迁移执行器失败: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	// 模拟数据库已由更新版本的程序迁移
+	if err := db.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (9999, 'future', CURRENT_TIMESTAMP)").Error; err != nil {
+		t.Fatalf("写入迁移记录失败: %v", err)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("读取迁移状态失败: %v", err)
+	}
+	last := statuses[len(statuses)-1]
+	if last.Version != 9999 || !last.Missing {
+		t.Errorf("期望标记缺失的迁移，实际: %+v", last)
+	}
+	if _, err := migrator.Down(ctx, 1); err == nil {
+		t.Error("回滚缺少脚本的迁移应返回错误")
+	}
+}
+
+// TestModelsMatchMigrations 执行全部迁移后，每个模型字段都应有对应的列
+func TestModelsMatchMigrations(t *testing.T) {
+	db := openSQLite(t)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("创建迁移执行器失败: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+
+	models := []any{
+		&model.Article{}, &model.ArticleAuthor{}, &model.ArticleFingerprint{}, &model.User{},
+		&model.RateLimitCounter{}, &model.WebhookEndpoint{}, &model.WebhookDelivery{},
+	}
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db.DB}
+		if err := stmt.Parse(m); err != nil {
+			t.Fatalf("解析模型失败: %v", err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			if !db.Migrator().HasColumn(m, field.DBName) {
+				t.Errorf("表 %s 缺少模型字段 %s 对应的列 %s", stmt.Schema.Table, field.Name, field.DBName)
+			}
+		}
+	}
+}
+
+// TestMigratorRejectsLegacySchema 引入迁移前的旧数据库未升级到最新结构时，首次迁移应拒绝执行
+func TestMigratorRejectsLegacySchema(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("创建迁移执行器失败: %v", err)
+	}
+	// 只执行初始迁移并删除迁移记录，模拟已执行到 legacy/013 的旧数据库
+	initial := &Migrator{db: db, migrations: migrator.migrations[:1], lockTimeout: defaultMigrationLockTimeout}
+	if _, err := initial.Up(ctx); err != nil {
+		t.Fatalf("执行初始迁移失败: %v", err)
+	}
+	if err := db.Exec("DELETE FROM schema_migrations").Error; err != nil {
+		t.Fatalf("删除迁移记录失败: %v", err)
+	}
+
+	// 模拟停留在 legacy/010 的旧数据库
+	for _, stmt := range []string{"ALTER TABLE articles DROP COLUMN forward_count", "ALTER TABLE articles DROP COLUMN doc_token", "DROP TABLE webhook_deliveries", "DROP TABLE webhook_endpoints"} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	_, err = migrator.Up(ctx)
+	if err == nil {
+		t.Fatal("旧表结构未升级时迁移应返回错误")
+	}
+	for _, script := range []string{"011_webhooks.sql", "012_article_doc_token.sql", "013_article_forward_count.sql"} {
+		if !strings.Contains(err.Error(), script) {
+			t.Errorf("错误信息应提示执行 %s: %v", script, err)
+		}
+	}
+	if strings.Contains(err.Error(), "010_article_source.sql") {
+		t.Errorf("已执行的旧版脚本不应出现在错误信息中: %v", err)
+	}
+	if statuses, _ := migrator.Status(ctx); statuses[0].Applied {
+		t.Error("拒绝执行时不应写入迁移记录")
+	}
+}
+
+// TestMigratorBaselinesLegacySchema 已升级到 legacy/013 的旧数据库可以直接迁移
+func TestMigratorBaselinesLegacySchema(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("创建迁移执行器失败: %v", err)
+	}
+	initial := &Migrator{db: db, migrations: migrator.migrations[:1], lockTimeout: defaultMigrationLockTimeout}
+	if _, err := initial.Up(ctx); err != nil {
+		t.Fatalf("执行初始迁移失败: %v", err)
+	}
+	if err := db.Exec("DELETE FROM schema_migrations").Error; err != nil {
+		t.Fatalf("删除迁移记录失败: %v", err)
+	}
+	if applied, err := migrator.Up(ctx); err != nil || len(applied) != len(migrator.migrations) {
+		t.Fatalf("最新的旧表结构应可直接迁移: %v, %v", applied, err)
+	}
+}
+
+// TestMigrationSourceMessageUnique 来源消息ID改为唯一索引时，空 ID 转为 NULL，重复导入的文章只保留最早一篇的 ID
+func TestMigrationSourceMessageUnique(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("创建迁移执行器失败: %v", err)
+	}
+	var before []Migration
+	for _, migration := range migrator.migrations {
+		if migration.Name != "article_source_message_unique" {
+			before = append(before, migration)
+		}
+	}
+	if len(before) != len(migrator.migrations)-1 {
+		t.Fatal("未找到 article_source_message_unique 迁移")
+	}
+	if _, err := (&Migrator{db: db, migrations: before, lockTimeout: defaultMigrationLockTimeout}).Up(ctx); err != nil {
+		t.Fatalf("执行之前的迁移失败: %v", err)
+	}
+	for _, stmt := range []string{
+		"INSERT INTO articles (id, tenant_key, title, content, source_message_id) VALUES (1, '', 'a', 'a', 'om_1')",
+		"INSERT INTO articles (id, tenant_key, title, content, source_message_id) VALUES (2, '', 'b', 'b', 'om_1')",
+		"INSERT INTO articles (id, tenant_key, title, content, source_message_id) VALUES (3, 'other', 'c', 'c', 'om_1')",
+		"INSERT INTO articles (id, tenant_key, title, content, source_message_id) VALUES (4, '', 'd', 'd', '')",
+		"DELETE FROM articles WHERE id = 4",
+		"INSERT INTO articles (id, tenant_key, title, content, source_message_id) VALUES (4, '', 'd', 'd', '')",
+		"INSERT INTO articles (id, tenant_key, title, content, source_message_id) VALUES (9, '', 'e', 'e', '')",
+		"DELETE FROM articles WHERE id = 9",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	var rows []struct {
+		ID              int64
+		SourceMessageID *string
+	}
+	if err := db.Table("articles").Select("id", "source_message_id").Order("id").Find(&rows).Error; err != nil {
+		t.Fatalf("读取文章失败: %v", err)
+	}
+	got := make(map[int64]string)
+	for _, row := range rows {
+		got[row.ID] = "NULL"
+		if row.SourceMessageID != nil {
+			got[row.ID] = *row.SourceMessageID
+		}
+	}
+	if want := map[int64]string{1: "om_1", 2: "NULL", 3: "om_1", 4: "NULL"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("迁移后的来源消息ID = %v，期望 %v", got, want)
+	}
+
+	// 重建表后自增序列不应回退
+	article := &model.Article{Title: "new", Content: "new"}
+	if err := db.Create(article).Error; err != nil || article.ID != 10 {
+		t.Errorf("新文章的ID = %d, %v，期望 10", article.ID, err)
+	}
+	if err := db.Create(&model.Article{Title: "dup", Content: "dup", SourceMessageID: "om_1"}).Error; err == nil {
+		t.Error("同一租户内重复的来源消息ID应违反唯一索引")
+	}
+
+	if _, err := migrator.Down(ctx, 1); err != nil {
+		t.Fatalf("回滚迁移失败: %v", err)
+	}
+	var empty int64
+	if err := db.Table("articles").Where("source_message_id = ''").Count(&empty).Error; err != nil || empty != 3 {
+		t.Errorf("回滚后空的来源消息ID数 = %d, %v，期望 3", empty, err)
+	}
+}
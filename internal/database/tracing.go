@@ -0,0 +1,86 @@
+package database
+
+import (
+	"MikoNews/internal/pkg/tracing"
+	"errors"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// tracingSpanKey 是 span 在 gorm.Statement 中的存放位置
+const tracingSpanKey = "tracing:span"
+
+// TracingPlugin 是为每条 SQL 创建 span 的 GORM 插件，span 的父级为仓库方法通过 WithContext 传入的 ctx
+type TracingPlugin struct {
+	Driver string // 数据库驱动，记录为 db.system.name
+}
+
+// Name 实现 gorm.Plugin
+func (p *TracingPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize 实现 gorm.Plugin，在各类操作的回调前后开始和结束 span
+func (p *TracingPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	for _, processor := range processors {
+		if err := processor.before("tracing:before_"+processor.operation, p.before(processor.operation)); err != nil {
+			return err
+		}
+		if err := processor.after("tracing:after_"+processor.operation, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before 开始一个 span 并将其 ctx 设置为语句的 ctx
+func (p *TracingPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+		ctx, span := tracing.StartKind(db.Statement.Context, trace.SpanKindClient, "gorm."+operation,
+			semconv.DBSystemNameKey.String(p.Driver),
+			semconv.DBOperationName(operation),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(tracingSpanKey, span)
+	}
+}
+
+// after 记录执行的 SQL、影响行数和错误后结束 span
+func (p *TracingPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		semconv.DBResponseReturnedRows(int(db.Statement.RowsAffected)),
+	)
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 未找到记录是正常的查询结果，不标记为失败
+		err = nil
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"MikoNews/internal/pkg/tracing"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+func TestTracingPlugin(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), "miko-news-test", 1)
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	db := openSQLite(t)
+	if err := db.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT)").Error; err != nil {
+		t.Fatal(err)
+	}
+	exporter.Reset()
+
+	ctx, parent := tracing.Start(context.Background(), "repository")
+	if err := db.WithContext(ctx).Table("notes").Create(map[string]any{"id": 1, "body": "hello"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var body string
+	err := db.WithContext(ctx).Table("notes").Where("id = ?", 2).Select("body").Take(&body).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("应未找到记录，实际为 %v", err)
+	}
+	// 没有传入 ctx 的语句不属于调用方的链路
+	db.Table("notes").Count(new(int64))
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 4 {
+		t.Fatalf("应导出 4 个 span，实际为 %d", len(spans))
+	}
+	create, query := spans[0], spans[1]
+	for _, span := range []tracetest.SpanStub{create, query} {
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("%s 应为仓库调用的子 span", span.Name)
+		}
+	}
+	if create.Name != "gorm.create" || !hasAttribute(create.Attributes, "db.query.text", "INSERT INTO `notes`") {
+		t.Errorf("create 的 span 应记录 SQL: %+v", create.Attributes)
+	}
+	if !hasAttribute(create.Attributes, "db.system.name", "sqlite") {
+		t.Errorf("span 应记录数据库驱动: %+v", create.Attributes)
+	}
+	if query.Name != "gorm.query" || query.Status.Code == codes.Error {
+		t.Errorf("未找到记录不应标记为失败: %+v", query)
+	}
+	if spans[2].Parent.IsValid() {
+		t.Error("没有传入 ctx 的语句不应有父 span")
+	}
+}
+
+// hasAttribute 判断 attrs 中 key 的值是否以 prefix 开头
+func hasAttribute(attrs []attribute.KeyValue, key, prefix string) bool {
+	for _, attr := range attrs {
+		if string(attr.Key) == key && strings.HasPrefix(attr.Value.Emit(), prefix) {
+			return true
+		}
+	}
+	return false
+}
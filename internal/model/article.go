@@ -4,17 +4,54 @@ import (
 	"time"
 )
 
-// Article 代表存储的文章投稿信息 (与 init.sql 同步)
+// Article 代表存储的文章投稿信息 (表结构由 migrations/ 下的迁移维护)
 type Article struct {
-	ID         int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	Title      string    `gorm:"column:title;type:varchar(255);not null;default:''" json:"title"`                         // 文章标题
-	Content    string    `gorm:"column:content;type:text;not null;" json:"content"`                                       // 文章内容 (非指针，匹配 NOT NULL)
-	AuthorID   string    `gorm:"column:author_id;type:varchar(64);not null;default:'';index:idx_author" json:"author_id"` // 作者飞书OpenID
-	AuthorName string    `gorm:"column:author_name;type:varchar(64);not null;default:'匿名用户'" json:"author_name"`          // 作者名字
-	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt  time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP" json:"updated_at"`
+	ID         int64  `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	TenantKey  string `gorm:"column:tenant_key;type:varchar(64);not null;default:'';index:idx_tenant" json:"tenant_key,omitempty"` // 所属飞书租户，多应用部署时用于隔离文章
+	Title      string `gorm:"column:title;type:varchar(255);not null;default:''" json:"title"`                                     // 文章标题
+	Content    string `gorm:"column:content;type:text;not null;" json:"content"`                                                   // 文章内容 (非指针，匹配 NOT NULL)
+	AuthorID   string `gorm:"column:author_id;type:varchar(64);not null;default:'';index:idx_author" json:"author_id"`             // 作者飞书OpenID
+	AuthorName string `gorm:"column:author_name;type:varchar(64);not null;default:'匿名用户'" json:"author_name"`                      // 作者名字
+	CuratorID  string `gorm:"column:curator_id;type:varchar(64);not null;default:''" json:"curator_id,omitempty"`                  // 代为收录者飞书OpenID，本人投稿时为空
+	Anonymous  bool   `gorm:"column:is_anonymous;not null;default:false" json:"anonymous"`                                         // 是否匿名投稿
+	// AuthorIDEncrypted 匿名投稿时加密保存的真实作者OpenID，仅管理员可解密查看，不对外输出
+	AuthorIDEncrypted string `gorm:"column:author_id_encrypted;type:varchar(255);not null;default:''" json:"-"`
+	Source            string `gorm:"column:source;type:varchar(32);not null;default:'feishu'" json:"source"`                     // 投稿来源
+	Status            string `gorm:"column:status;type:varchar(16);not null;default:'published';index:idx_status" json:"status"` // 文章状态
+	// SourceMessageID 文章对应的来源消息ID（飞书 message_id 等），同一租户内唯一，导入历史消息时据此去重；未知时为空，保存为 NULL
+	SourceMessageID NullString `gorm:"column:source_message_id;type:varchar(64)" json:"source_message_id,omitempty"`
+	// RawContent 原始富文本 (post) JSON，用于审核通过后转发到群聊
+	RawContent string `gorm:"column:raw_content;type:mediumtext" json:"-"`
+	// SimHash 正文的 SimHash（按位保存为有符号整数），用于近似查重
+	SimHash int64 `gorm:"column:simhash;not null;default:0" json:"-"`
+	// DocToken 文章归档到飞书云文档 (document_id) 或知识库 (node_token) 后的标识，未归档时为空
+	DocToken string `gorm:"column:doc_token;type:varchar(64);not null;default:''" json:"doc_token,omitempty"`
+	// ForwardCount 文章卡片成功转发到群聊的次数
+	ForwardCount int       `gorm:"column:forward_count;not null;default:0" json:"forward_count"`
+	CreatedAt    time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP" json:"updated_at"`
+
+	Authors      []*ArticleAuthor      `gorm:"foreignKey:ArticleID" json:"authors,omitempty"` // 署名成员（作者、合著者、来源），匿名投稿不含作者本人
+	Fingerprints []*ArticleFingerprint `gorm:"foreignKey:ArticleID" json:"-"`                 // 查重指纹，随文章一并保存
 }
 
+// AnonymousAuthorName 是匿名投稿对外展示的作者名字
+const AnonymousAuthorName = "匿名用户"
+
+// 文章状态
+const (
+	ArticleStatusPending   = "pending"   // 待审核，不转发、不出现在列表和统计中
+	ArticleStatusPublished = "published" // 已发布
+	ArticleStatusRejected  = "rejected"  // 审核未通过
+	ArticleStatusWithdrawn = "withdrawn" // 发布后被管理员撤回
+)
+
+// 投稿来源
+const (
+	ArticleSourceFeishu   = "feishu"   // 飞书私聊投稿或群聊收录
+	ArticleSourceTelegram = "telegram" // Telegram 群组或频道
+)
+
 // TableName 指定 GORM 使用的表名
 func (Article) TableName() string {
 	return "articles"
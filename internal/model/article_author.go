@@ -0,0 +1,34 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// 文章署名角色
+const (
+	AuthorRoleAuthor   = "author"    // 作者
+	AuthorRoleCoAuthor = "co-author" // 合著者
+	AuthorRoleSource   = "source"    // 内容来源
+)
+
+// ArticleAuthor 代表文章与署名成员之间的关联 (表结构由 migrations/ 下的迁移维护)
+type ArticleAuthor struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"-"`
+	ArticleID int64     `gorm:"column:article_id;not null;uniqueIndex:uk_article_member_role,priority:1" json:"-"`                                       // 文章ID
+	OpenID    string    `gorm:"column:open_id;type:varchar(64);not null;uniqueIndex:uk_article_member_role,priority:2;index:idx_open_id" json:"open_id"` // 成员飞书OpenID
+	Name      string    `gorm:"column:name;type:varchar(64);not null;default:''" json:"name"`                                                            // 成员名字
+	Role      string    `gorm:"column:role;type:varchar(16);not null;uniqueIndex:uk_article_member_role,priority:3" json:"role"`                         // 署名角色
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"-"`
+}
+
+// IsExternal 判断署名成员是否来自飞书以外的投稿来源（如 Telegram）。这类成员的标识带有来源前缀
+// （如 tg:user:123），不是飞书 OpenID，无法在飞书中 @
+func (a *ArticleAuthor) IsExternal() bool {
+	return strings.Contains(a.OpenID, ":")
+}
+
+// TableName 指定 GORM 使用的表名
+func (ArticleAuthor) TableName() string {
+	return "article_authors"
+}
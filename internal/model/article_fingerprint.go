@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// 文章指纹类型
+const (
+	FingerprintKindURL     = "url"     // 规范化后的链接
+	FingerprintKindSimHash = "simhash" // 正文 SimHash 的分段
+)
+
+// ArticleFingerprint 代表用于查重的文章指纹 (表结构由 migrations/ 下的迁移维护)
+type ArticleFingerprint struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"-"`
+	ArticleID int64     `gorm:"column:article_id;not null;index:idx_article" json:"article_id"`                       // 文章ID
+	Kind      string    `gorm:"column:kind;type:varchar(16);not null;index:idx_kind_value,priority:1" json:"kind"`    // 指纹类型
+	Value     string    `gorm:"column:value;type:varchar(255);not null;index:idx_kind_value,priority:2" json:"value"` // 指纹值
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"-"`
+}
+
+// TableName 指定 GORM 使用的表名
+func (ArticleFingerprint) TableName() string {
+	return "article_fingerprints"
+}
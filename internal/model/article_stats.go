@@ -0,0 +1,17 @@
+package model
+
+// AuthorStat 表示单个作者的投稿统计
+type AuthorStat struct {
+	AuthorID     string `json:"author_id"`
+	AuthorName   string `json:"author_name"`
+	ArticleCount int64  `json:"article_count"`
+}
+
+// ArticleStats 表示文章投稿的汇总统计
+type ArticleStats struct {
+	TotalArticles  int64         `json:"total_articles"`  // 文章总数
+	TotalAuthors   int64         `json:"total_authors"`   // 投稿作者数
+	RecentArticles int64         `json:"recent_articles"` // 最近 7 天的投稿数
+	TopAuthors     []*AuthorStat `json:"top_authors"`     // 投稿最多的作者
+	TopCoAuthors   []*AuthorStat `json:"top_co_authors"`  // 参与合著最多的成员
+}
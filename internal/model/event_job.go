@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// 事件任务状态
+const (
+	EventJobPending   = "pending"   // 等待处理，或处理中（next_attempt_at 为租约到期时间）
+	EventJobSucceeded = "succeeded" // 处理完成
+	EventJobFailed    = "failed"    // 处理出错，或尝试次数用尽
+)
+
+// EventJob 代表一个待异步处理的飞书事件 (表结构由 migrations/ 下的迁移维护)
+type EventJob struct {
+	ID            int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	TenantKey     string    `gorm:"column:tenant_key;type:varchar(64);not null;default:''" json:"tenant_key"`              // 租户标识
+	EventID       string    `gorm:"column:event_id;type:varchar(64);not null" json:"event_id"`                             // 飞书事件ID，同一租户内唯一，重复推送的事件只入队一次
+	EventType     string    `gorm:"column:event_type;type:varchar(64);not null" json:"event_type"`                         // 事件类型
+	ChatID        string    `gorm:"column:chat_id;type:varchar(64);not null;default:''" json:"chat_id"`                    // 事件所属的会话，同一会话的事件按顺序处理
+	Payload       string    `gorm:"column:payload;type:mediumtext;not null" json:"payload"`                                // 事件内容
+	TraceParent   string    `gorm:"column:trace_parent;type:varchar(64);not null;default:''" json:"trace_parent"`          // 入队时的链路追踪上下文 (W3C traceparent)
+	Status        string    `gorm:"column:status;type:varchar(16);not null;default:'pending'" json:"status"`               // 任务状态
+	Attempts      int       `gorm:"column:attempts;not null;default:0" json:"attempts"`                                    // 已尝试次数
+	LastError     string    `gorm:"column:last_error;type:varchar(512);not null;default:''" json:"last_error"`             // 处理失败的原因
+	NextAttemptAt time.Time `gorm:"column:next_attempt_at;type:timestamp;not null" json:"next_attempt_at"`                 // 可被处理的时间
+	CreatedAt     time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"created_at"` // 入队时间
+	UpdatedAt     time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"updated_at"` // 更新时间
+}
+
+// TableName 指定 GORM 使用的表名
+func (EventJob) TableName() string {
+	return "event_jobs"
+}
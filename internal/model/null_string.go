@@ -0,0 +1,32 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// NullString 是空字符串以 NULL 保存的字符串，用于可以为空、又带唯一索引的列（多个 NULL 互不冲突）
+type NullString string
+
+// Value 实现 driver.Valuer，空字符串保存为 NULL
+func (s NullString) Value() (driver.Value, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return string(s), nil
+}
+
+// Scan 实现 sql.Scanner，NULL 读取为空字符串
+func (s *NullString) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*s = ""
+	case string:
+		*s = NullString(v)
+	case []byte:
+		*s = NullString(v)
+	default:
+		return fmt.Errorf("无法将 %T 转换为 NullString", value)
+	}
+	return nil
+}
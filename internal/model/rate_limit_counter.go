@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// RateLimitCounter 代表一个固定时间窗口内的计数 (表结构由 migrations/ 下的迁移维护)
+type RateLimitCounter struct {
+	CounterKey  string    `gorm:"column:counter_key;type:varchar(128);primaryKey" json:"counter_key"`            // 计数键，如 submission:hour:ou_xxx
+	WindowStart time.Time `gorm:"column:window_start;type:timestamp;primaryKey" json:"window_start"`             // 窗口开始时间
+	Count       int64     `gorm:"column:count;not null;default:0" json:"count"`                                  // 窗口内的计数
+	ExpiresAt   time.Time `gorm:"column:expires_at;type:timestamp;not null;index:idx_expires" json:"expires_at"` // 窗口结束后可清理的时间
+}
+
+// TableName 指定 GORM 使用的表名
+func (RateLimitCounter) TableName() string {
+	return "rate_limit_counters"
+}
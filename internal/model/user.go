@@ -0,0 +1,45 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// 用户状态
+const (
+	UserStatusActive   = "active"   // 在职
+	UserStatusResigned = "resigned" // 离职或已从通讯录删除
+)
+
+// User 代表从飞书通讯录同步到本地的用户 (表结构由 migrations/ 下的迁移维护)
+type User struct {
+	OpenID        string    `gorm:"column:open_id;type:varchar(64);primaryKey" json:"open_id"`                                           // 用户飞书OpenID
+	TenantKey     string    `gorm:"column:tenant_key;type:varchar(64);not null;default:'';index:idx_tenant" json:"tenant_key,omitempty"` // 所属飞书租户
+	UnionID       string    `gorm:"column:union_id;type:varchar(64);not null;default:''" json:"union_id"`                                // 用户UnionID
+	Name          string    `gorm:"column:name;type:varchar(64);not null;default:''" json:"name"`                                        // 用户名
+	EnName        string    `gorm:"column:en_name;type:varchar(64);not null;default:''" json:"en_name"`                                  // 英文名
+	AvatarURL     string    `gorm:"column:avatar_url;type:varchar(512);not null;default:''" json:"avatar_url"`                           // 头像链接 (240x240)
+	DepartmentIDs string    `gorm:"column:department_ids;type:varchar(1024);not null;default:''" json:"-"`                               // 所属部门 open_department_id，逗号分隔
+	Status        string    `gorm:"column:status;type:varchar(16);not null;default:'active'" json:"status"`                              // 用户状态
+	SyncedAt      time.Time `gorm:"column:synced_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"synced_at"`                 // 最近一次从飞书同步的时间
+	CreatedAt     time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定 GORM 使用的表名
+func (User) TableName() string {
+	return "users"
+}
+
+// Departments 返回用户所属部门的 open_department_id 列表
+func (u *User) Departments() []string {
+	if u.DepartmentIDs == "" {
+		return nil
+	}
+	return strings.Split(u.DepartmentIDs, ",")
+}
+
+// SetDepartments 设置用户所属部门
+func (u *User) SetDepartments(ids []string) {
+	u.DepartmentIDs = strings.Join(ids, ",")
+}
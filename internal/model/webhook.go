@@ -0,0 +1,79 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// webhook 端点的来源
+const (
+	WebhookSourceConfig = "config" // 配置文件，启动时同步到数据库
+	WebhookSourceAPI    = "api"    // 通过管理接口注册
+)
+
+// webhook 投递状态
+const (
+	WebhookDeliveryPending   = "pending"   // 等待投递或重试
+	WebhookDeliverySucceeded = "succeeded" // 投递成功
+	WebhookDeliveryFailed    = "failed"    // 重试次数用尽后仍失败
+)
+
+// WebhookEndpoint 代表一个接收文章生命周期事件的 webhook 端点 (表结构由 migrations/ 下的迁移维护)
+type WebhookEndpoint struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"column:name;type:varchar(64);not null;uniqueIndex:uk_name" json:"name"`    // 端点名称，唯一
+	URL       string    `gorm:"column:url;type:varchar(512);not null" json:"url"`                         // 接收事件的地址
+	Secret    string    `gorm:"column:secret;type:varchar(128);not null" json:"-"`                        // HMAC-SHA256 签名密钥，不对外输出
+	Events    string    `gorm:"column:events;type:varchar(255);not null;default:''" json:"events"`        // 订阅的事件，逗号分隔，为空表示全部
+	TenantKey string    `gorm:"column:tenant_key;type:varchar(64);not null;default:''" json:"tenant_key"` // 只接收该租户的事件，为空表示全部租户
+	Source    string    `gorm:"column:source;type:varchar(16);not null;default:'api'" json:"source"`      // 端点来源
+	Enabled   bool      `gorm:"column:enabled;not null;default:true" json:"enabled"`                      // 是否启用
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定 GORM 使用的表名
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// Subscribes 判断端点是否订阅了 tenantKey 租户的 event 事件
+func (e *WebhookEndpoint) Subscribes(event, tenantKey string) bool {
+	if !e.Enabled {
+		return false
+	}
+	if e.TenantKey != "" && e.TenantKey != tenantKey {
+		return false
+	}
+	if e.Events == "" {
+		return true
+	}
+	for _, subscribed := range strings.Split(e.Events, ",") {
+		if strings.TrimSpace(subscribed) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery 代表一次 webhook 投递及其重试状态 (表结构由 migrations/ 下的迁移维护)
+type WebhookDelivery struct {
+	ID             int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	EndpointID     int64      `gorm:"column:endpoint_id;not null;index:idx_endpoint" json:"endpoint_id"`                                        // 端点ID
+	EventID        string     `gorm:"column:event_id;type:varchar(32);not null" json:"event_id"`                                                // 事件ID，同一事件投递到多个端点时相同
+	Event          string     `gorm:"column:event;type:varchar(32);not null" json:"event"`                                                      // 事件类型
+	Payload        string     `gorm:"column:payload;type:mediumtext;not null" json:"payload"`                                                   // 投递的 JSON 内容
+	Status         string     `gorm:"column:status;type:varchar(16);not null;default:'pending';index:idx_status_next,priority:1" json:"status"` // 投递状态
+	Attempts       int        `gorm:"column:attempts;not null;default:0" json:"attempts"`                                                       // 已尝试次数
+	ResponseStatus int        `gorm:"column:response_status;not null;default:0" json:"response_status"`                                         // 最近一次响应的 HTTP 状态码
+	LastError      string     `gorm:"column:last_error;type:varchar(512);not null;default:''" json:"last_error"`                                // 最近一次失败原因
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at;type:timestamp;not null;index:idx_status_next,priority:2" json:"next_attempt_at"`   // 下次尝试时间
+	DeliveredAt    *time.Time `gorm:"column:delivered_at;type:timestamp;null" json:"delivered_at,omitempty"`                                    // 投递成功时间
+	CreatedAt      time.Time  `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定 GORM 使用的表名
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
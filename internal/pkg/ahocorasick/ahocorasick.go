@@ -0,0 +1,105 @@
+// Package ahocorasick 实现 Aho-Corasick 多模式匹配，按 rune 处理以支持中文关键词
+package ahocorasick
+
+import "strings"
+
+// node 是 trie 中的一个节点
+type node struct {
+	children map[rune]int
+	fail     int
+	outputs  []int // 以该节点结尾的模式下标（含经由 fail 链继承的）
+}
+
+// Matcher 在文本中一次性查找所有关键词，构建后可并发使用
+type Matcher struct {
+	nodes    []node
+	patterns []string
+}
+
+// New 根据关键词构建 Matcher，匹配时忽略大小写，空关键词和重复关键词会被忽略
+func New(patterns []string) *Matcher {
+	m := &Matcher{nodes: []node{{children: map[rune]int{}}}}
+	seen := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		m.insert(p, len(m.patterns))
+		m.patterns = append(m.patterns, p)
+	}
+	m.build()
+	return m
+}
+
+// insert 将关键词加入 trie
+func (m *Matcher) insert(pattern string, index int) {
+	cur := 0
+	for _, r := range pattern {
+		next, ok := m.nodes[cur].children[r]
+		if !ok {
+			m.nodes = append(m.nodes, node{children: map[rune]int{}})
+			next = len(m.nodes) - 1
+			m.nodes[cur].children[r] = next
+		}
+		cur = next
+	}
+	m.nodes[cur].outputs = append(m.nodes[cur].outputs, index)
+}
+
+// build 按层序计算 fail 指针
+func (m *Matcher) build() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].children {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].children {
+			fail := m.nodes[cur].fail
+			for fail != 0 {
+				if _, ok := m.nodes[fail].children[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].children[r]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			m.nodes[child].outputs = append(m.nodes[child].outputs, m.nodes[m.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Len 返回有效关键词数量
+func (m *Matcher) Len() int {
+	return len(m.patterns)
+}
+
+// FindAll 返回文本中出现的所有关键词（去重，按首次出现的顺序）
+func (m *Matcher) FindAll(text string) []string {
+	var found []string
+	seen := make(map[int]bool)
+	cur := 0
+	for _, r := range strings.ToLower(text) {
+		for cur != 0 {
+			if _, ok := m.nodes[cur].children[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if next, ok := m.nodes[cur].children[r]; ok {
+			cur = next
+		}
+		for _, index := range m.nodes[cur].outputs {
+			if !seen[index] {
+				seen[index] = true
+				found = append(found, m.patterns[index])
+			}
+		}
+	}
+	return found
+}
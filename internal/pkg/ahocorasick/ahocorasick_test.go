@@ -0,0 +1,43 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		want     []string
+	}{
+		{"无匹配", []string{"广告", "spam"}, "正常的分享", nil},
+		{"中文关键词", []string{"广告", "代购"}, "这是一条代购广告", []string{"代购", "广告"}},
+		{"忽略大小写", []string{"Spam"}, "no SPAM here", []string{"spam"}},
+		{"重叠关键词", []string{"he", "she", "his", "hers"}, "ushers", []string{"she", "he", "hers"}},
+		{"关键词互为前缀", []string{"赌博", "赌博网站"}, "禁止分享赌博网站", []string{"赌博", "赌博网站"}},
+		{"关键词互为后缀", []string{"网站", "赌博网站"}, "赌博网站", []string{"赌博网站", "网站"}},
+		{"经由 fail 链匹配", []string{"abcd", "bc"}, "abce", []string{"bc"}},
+		{"重复出现只返回一次", []string{"广告"}, "广告广告广告", []string{"广告"}},
+		{"重复关键词只返回一次", []string{"广告", " 广告 ", "广告"}, "广告", []string{"广告"}},
+		{"忽略空关键词", []string{"", "  ", "x"}, "xyz", []string{"x"}},
+		{"表情符号", []string{"🎰"}, "快来🎰", []string{"🎰"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.patterns).FindAll(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll(%q) = %q，期望 %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLen(t *testing.T) {
+	if n := New([]string{"a", "", "b", "A"}).Len(); n != 2 {
+		t.Errorf("Len() = %d，期望 2（忽略空关键词和重复关键词）", n)
+	}
+	if got := New(nil).FindAll("任意文本"); got != nil {
+		t.Errorf("没有关键词时不应匹配: %q", got)
+	}
+}
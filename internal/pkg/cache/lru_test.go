@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	// 读取 a 后 b 成为最久未使用的记录
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v，期望 1, true", v, ok)
+	}
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("超出容量时应淘汰最久未使用的 b")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if v, ok := c.Get(key); !ok || v != want {
+			t.Errorf("Get(%s) = %v, %v，期望 %d, true", key, v, ok, want)
+		}
+	}
+	if got := c.Len(); got != 2 {
+		t.Errorf("Len() = %d，期望 2", got)
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRU[string, int](10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("a"); !ok {
+		t.Error("刚好到达过期时间的记录仍应有效")
+	}
+	now = now.Add(time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Error("超过过期时间的记录不应返回")
+	}
+	if got := c.Len(); got != 0 {
+		t.Errorf("过期记录读取后应被清理，Len() = %d", got)
+	}
+}
+
+func TestLRUSetRefreshesEntry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRU[string, int](2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	now = now.Add(50 * time.Second)
+	// 再次写入 a 会更新值、重置过期时间，并使其成为最近使用的记录
+	c.Set("a", 10)
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b 应作为最久未使用的记录被淘汰")
+	}
+	now = now.Add(50 * time.Second)
+	if v, ok := c.Get("a"); !ok || v != 10 {
+		t.Errorf("Get(a) = %v, %v，期望重新写入的 10 且未过期", v, ok)
+	}
+	if got := c.Len(); got != 2 {
+		t.Errorf("重复写入不应新增记录，Len() = %d", got)
+	}
+}
+
+func TestLRUDelete(t *testing.T) {
+	c := NewLRU[string, int](0, 0)
+	c.Set("a", 1)
+	c.Delete("a")
+	c.Delete("missing")
+	if _, ok := c.Get("a"); ok {
+		t.Error("删除后的记录不应返回")
+	}
+}
@@ -0,0 +1,75 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNoKey 表示未配置加密密钥
+var ErrNoKey = errors.New("未配置加密密钥")
+
+// Cipher 使用 AES-256-GCM 加解密敏感字段，密文以 base64 字符串形式存储
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher 根据配置的密钥创建 Cipher。密钥为空时返回的 Cipher 不可用，调用加解密会返回 ErrNoKey。
+func NewCipher(secret string) (*Cipher, error) {
+	if secret == "" {
+		return &Cipher{}, nil
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("创建 AES 加密器失败: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("创建 GCM 加密器失败: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Enabled 返回是否配置了密钥
+func (c *Cipher) Enabled() bool {
+	return c != nil && c.aead != nil
+}
+
+// Encrypt 加密明文，返回 base64 编码的 nonce+密文
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if !c.Enabled() {
+		return "", ErrNoKey
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密 Encrypt 生成的密文
+func (c *Cipher) Decrypt(encoded string) (string, error) {
+	if !c.Enabled() {
+		return "", ErrNoKey
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("密文格式错误: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("密文长度不足")
+	}
+	plaintext, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("解密失败: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,96 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newTestCipher(t *testing.T, secret string) *Cipher {
+	t.Helper()
+	c, err := NewCipher(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c := newTestCipher(t, "secret")
+	for _, plaintext := range []string{"ou_1234567890abcdef", "", "中文 OpenID"} {
+		encrypted, err := c.Encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encrypted == plaintext && plaintext != "" {
+			t.Errorf("密文不应与明文相同: %q", encrypted)
+		}
+		decrypted, err := c.Decrypt(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decrypted != plaintext {
+			t.Errorf("Decrypt(Encrypt(%q)) = %q", plaintext, decrypted)
+		}
+	}
+}
+
+func TestCipherRandomNonce(t *testing.T) {
+	c := newTestCipher(t, "secret")
+	first, _ := c.Encrypt("ou_author")
+	second, _ := c.Encrypt("ou_author")
+	if first == second {
+		t.Error("同一明文两次加密的密文应不同，否则可据此关联匿名作者")
+	}
+}
+
+func TestCipherWrongSecret(t *testing.T) {
+	encrypted, err := newTestCipher(t, "secret").Encrypt("ou_author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTestCipher(t, "another-secret").Decrypt(encrypted); err == nil {
+		t.Error("使用错误的密钥解密应失败")
+	}
+}
+
+func TestCipherTamperedCiphertext(t *testing.T) {
+	c := newTestCipher(t, "secret")
+	encrypted, _ := c.Encrypt("ou_author")
+	data, _ := base64.StdEncoding.DecodeString(encrypted)
+
+	tampered := append([]byte(nil), data...)
+	tampered[len(tampered)-1] ^= 0x01
+	for name, input := range map[string]string{
+		"篡改的密文":    base64.StdEncoding.EncodeToString(tampered),
+		"截断的密文":    base64.StdEncoding.EncodeToString(data[:8]),
+		"非 base64": "not base64!",
+	} {
+		if _, err := c.Decrypt(input); err == nil {
+			t.Errorf("%s应解密失败", name)
+		}
+	}
+}
+
+func TestCipherWithoutKey(t *testing.T) {
+	c := newTestCipher(t, "")
+	if c.Enabled() {
+		t.Error("未配置密钥时 Enabled() 应为 false")
+	}
+	if _, err := c.Encrypt("ou_author"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Encrypt() error = %v，期望 ErrNoKey", err)
+	}
+	if _, err := c.Decrypt("ciphertext"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Decrypt() error = %v，期望 ErrNoKey", err)
+	}
+}
+
+func TestRandomToken(t *testing.T) {
+	a, b := RandomToken(16), RandomToken(16)
+	if len(a) != 32 || len(b) != 32 {
+		t.Errorf("16 字节的令牌应为 32 个十六进制字符: %q, %q", a, b)
+	}
+	if a == b {
+		t.Error("两次生成的令牌不应相同")
+	}
+}
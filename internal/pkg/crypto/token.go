@@ -0,0 +1,16 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// RandomToken 生成 n 字节的随机十六进制串，用作事件ID、签名密钥等
+func RandomToken(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("生成随机数失败: %v", err))
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,168 @@
+// Package fingerprint 计算用于查重的文章指纹：规范化后的链接和正文的 SimHash
+package fingerprint
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SimHashBands 是 SimHash 切分的段数。海明距离为 d 的两个指纹至少有 SimHashBands-d 段完全相同，
+// 因此只需按段做等值查询即可召回所有近似重复的候选
+const SimHashBands = 8
+
+// MaxDistance 是判定为近似重复的最大海明距离
+const MaxDistance = 6
+
+// MinMatchingBands 是海明距离不超过 MaxDistance 的两个指纹至少相同的段数
+const MinMatchingBands = SimHashBands - MaxDistance
+
+// shingleSize 是计算 SimHash 时的字符 n-gram 长度，按字符切分以兼容中文
+const shingleSize = 3
+
+// urlPattern 匹配文本中的 http(s) 链接，链接后直接跟中文标点时在标点处截断
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>()（）\\。，；：！？、]+`)
+
+// trackingParams 是规范化链接时去掉的跟踪参数
+var trackingParams = map[string]bool{
+	"spm": true, "from": true, "share_token": true, "share_source": true, "fbclid": true, "gclid": true,
+}
+
+// ExtractURLs 返回文本中出现的所有链接（去重，按出现顺序）
+func ExtractURLs(texts ...string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, u := range urlPattern.FindAllString(text, -1) {
+			u = strings.TrimRight(u, ".,;:!?，。；：！？")
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+	}
+	return urls
+}
+
+// NormalizeURL 规范化链接：忽略协议、www 前缀、锚点、结尾斜杠和常见跟踪参数，查询参数按名称排序
+func NormalizeURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("解析链接失败: %w", err)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host == "" {
+		return "", fmt.Errorf("链接缺少域名: %s", raw)
+	}
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || trackingParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(host)
+	b.WriteString(strings.TrimRight(u.EscapedPath(), "/"))
+	for i, key := range keys {
+		if i == 0 {
+			b.WriteByte('?')
+		} else {
+			b.WriteByte('&')
+		}
+		values := query[key]
+		sort.Strings(values)
+		for j, value := range values {
+			if j > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key) + "=" + url.QueryEscape(value))
+		}
+	}
+	return b.String(), nil
+}
+
+// SimHash 计算文本的 64 位 SimHash。文本先去掉空白和标点并转为小写，再按字符 n-gram 加权
+func SimHash(text string) uint64 {
+	runes := normalizeText(text)
+	if len(runes) == 0 {
+		return 0
+	}
+	if len(runes) < shingleSize {
+		return hashString(string(runes))
+	}
+
+	var weights [64]int
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		h := hashString(string(runes[i : i+shingleSize]))
+		for bit := 0; bit < 64; bit++ {
+			if h&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var simhash uint64
+	for bit := 0; bit < 64; bit++ {
+		if weights[bit] > 0 {
+			simhash |= 1 << uint(bit)
+		}
+	}
+	return simhash
+}
+
+// Distance 返回两个 SimHash 的海明距离
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Bands 将 SimHash 切分为 SimHashBands 段，每段以 "段号:十六进制值" 表示，用于索引查询
+func Bands(simhash uint64) []string {
+	width := 64 / SimHashBands
+	bands := make([]string, SimHashBands)
+	for i := 0; i < SimHashBands; i++ {
+		value := (simhash >> uint(i*width)) & (1<<uint(width) - 1)
+		bands[i] = fmt.Sprintf("%d:%0*x", i, width/4, value)
+	}
+	return bands
+}
+
+// TextLength 返回去掉空白和标点后的文本长度，过短的文本不适合做近似查重
+func TextLength(text string) int {
+	return len(normalizeText(text))
+}
+
+// normalizeText 去掉空白和标点并转为小写
+func normalizeText(text string) []rune {
+	runes := make([]rune, 0, len(text))
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+// hashString 计算字符串的 64 位 FNV-1a 哈希
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
@@ -0,0 +1,135 @@
+package fingerprint
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"https://example.com/a", "example.com/a", false},
+		{"http://www.Example.COM/a/", "example.com/a", false},
+		{"https://example.com/a#section", "example.com/a", false},
+		{"https://example.com:443/a", "example.com/a", false},
+		{"https://example.com:8443/a", "example.com:8443/a", false},
+		{"https://example.com/a?utm_source=x&UTM_Medium=y&spm=1&fbclid=2", "example.com/a", false},
+		{"https://example.com/a?b=2&a=1&a=0", "example.com/a?a=0&a=1&b=2", false},
+		{"https://example.com/%E4%B8%AD%E6%96%87?q=中文", "example.com/%E4%B8%AD%E6%96%87?q=%E4%B8%AD%E6%96%87", false},
+		{"  https://example.com  ", "example.com", false},
+		{"/relative/path", "", true},
+		{"https://%zz", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := NormalizeURL(tt.raw)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("NormalizeURL(%q) = %q, %v，期望 %q (wantErr %v)", tt.raw, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtractURLs(t *testing.T) {
+	text := "看看 https://example.com/a。还有（https://example.com/b）和 https://example.com/a, 以及 http://x.io/c?d=1!"
+	want := []string{"https://example.com/a", "https://example.com/b", "http://x.io/c?d=1"}
+	if got := ExtractURLs(text, `{"href":"https://example.com/b"}`); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractURLs() = %q，期望 %q", got, want)
+	}
+}
+
+func TestSimHash(t *testing.T) {
+	base := "本周我们完成了消息队列的迁移工作，新的集群在压测中表现稳定，延迟下降了百分之三十。迁移过程中发现了两个配置问题，" +
+		"已经在测试环境修复并补充了监控。下周计划先在一个业务线灰度上线，观察告警和消费延迟，确认没有问题后再逐步扩大范围，预计月底前完成全部切换。"
+	tests := []struct {
+		name        string
+		other       string
+		maxDistance int // 期望的最大距离，-1 表示期望距离大于 MaxDistance
+	}{
+		{"相同文本", base, 0},
+		{"忽略空白、标点和大小写", strings.ReplaceAll(base, "，", " , ") + "  ", 0},
+		{"改动一处", strings.Replace(base, "百分之三十", "百分之三十五", 1), MaxDistance},
+		{"追加一句", base + "欢迎大家反馈。", MaxDistance},
+		{"不同文本", "今天食堂推出了新的菜品，红烧肉和清蒸鱼都很受欢迎，大家可以中午去尝尝，晚上还有水果拼盘供应。", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Distance(SimHash(base), SimHash(tt.other))
+			if tt.maxDistance >= 0 && d > tt.maxDistance {
+				t.Errorf("海明距离 %d，期望不超过 %d", d, tt.maxDistance)
+			}
+			if tt.maxDistance < 0 && d <= MaxDistance {
+				t.Errorf("不同文本的海明距离 %d 不应判定为重复", d)
+			}
+		})
+	}
+
+	if SimHash("") != 0 || SimHash("，。！") != 0 {
+		t.Error("空文本的 SimHash 应为 0")
+	}
+	if SimHash("ab") == 0 {
+		t.Error("短于 n-gram 的文本也应有 SimHash")
+	}
+}
+
+func TestBands(t *testing.T) {
+	bands := Bands(0x0123456789abcdef)
+	want := []string{"0:ef", "1:cd", "2:ab", "3:89", "4:67", "5:45", "6:23", "7:01"}
+	if !reflect.DeepEqual(bands, want) {
+		t.Errorf("Bands() = %q，期望 %q", bands, want)
+	}
+	if got := Bands(0); got[0] != "0:00" || len(got) != SimHashBands {
+		t.Errorf("Bands(0) = %q", got)
+	}
+}
+
+// TestBandsPigeonhole 验证查重召回的前提：海明距离不超过 MaxDistance 的两个指纹至少有 MinMatchingBands 段相同
+func TestBandsPigeonhole(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		a := rng.Uint64()
+		b := a
+		// 随机翻转 MaxDistance 个不同的位
+		for _, bit := range rng.Perm(64)[:MaxDistance] {
+			b ^= 1 << uint(bit)
+		}
+		if d := Distance(a, b); d != MaxDistance {
+			t.Fatalf("海明距离应为 %d，实际 %d", MaxDistance, d)
+		}
+		if matches := matchingBands(a, b); matches < MinMatchingBands {
+			t.Fatalf("%016x 与 %016x 的距离为 %d，只有 %d 段相同，期望至少 %d 段", a, b, MaxDistance, matches, MinMatchingBands)
+		}
+	}
+
+	// 最坏情况：每个不同的位都落在不同的段
+	a := uint64(0)
+	var b uint64
+	for band := 0; band < MaxDistance; band++ {
+		b |= 1 << uint(band*64/SimHashBands)
+	}
+	if matches := matchingBands(a, b); matches != MinMatchingBands {
+		t.Errorf("最坏情况下相同的段数为 %d，期望 %d", matches, MinMatchingBands)
+	}
+}
+
+func TestTextLength(t *testing.T) {
+	if n := TextLength(" 你好，World! 🎉 "); n != 7 {
+		t.Errorf("TextLength() = %d，期望 7", n)
+	}
+}
+
+func matchingBands(a, b uint64) int {
+	matches := 0
+	bandsA, bandsB := Bands(a), Bands(b)
+	for i := range bandsA {
+		if bandsA[i] == bandsB[i] {
+			matches++
+		}
+	}
+	return matches
+}
@@ -0,0 +1,156 @@
+// Package health 汇总各组件的健康检查结果，供 /healthz/live 和 /healthz/ready 使用
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status 是组件或整体的健康状态
+type Status string
+
+const (
+	StatusUp   Status = "up"   // 正常
+	StatusDown Status = "down" // 不可用
+)
+
+// Check 检查一个组件，返回 error 表示组件不可用；details 为附加信息，如连接池状态、队列长度
+type Check func(ctx context.Context) (details map[string]any, err error)
+
+// Result 是一个组件的检查结果
+type Result struct {
+	Status    Status         `json:"status"`
+	LatencyMS float64        `json:"latency_ms"`        // 检查耗时（毫秒）
+	Error     string         `json:"error,omitempty"`   // 不可用的原因
+	Details   map[string]any `json:"details,omitempty"` // 附加信息
+}
+
+// Report 是一组检查的结果，任一组件不可用时整体为 down
+type Report struct {
+	Status     Status             `json:"status"`
+	CheckedAt  time.Time          `json:"checked_at"`
+	Components map[string]*Result `json:"components"`
+}
+
+// namedCheck 是注册到 Checker 的一项检查
+type namedCheck struct {
+	name     string
+	check    Check
+	liveness bool
+}
+
+// Checker 管理并执行健康检查
+type Checker struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	checks  []namedCheck
+}
+
+// NewChecker 创建一个 Checker，每项检查最多执行 timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// AddReadiness 注册一项就绪检查：组件不可用时服务暂时不能正常工作，但重启无济于事，如数据库连接失败
+func (c *Checker) AddReadiness(name string, check Check) {
+	c.add(namedCheck{name: name, check: check})
+}
+
+// AddLiveness 注册一项存活检查：组件不可用时需要重启进程才能恢复，如 WebSocket 长连接断开后不再重连。
+// 存活检查同样是就绪检查的一部分
+func (c *Checker) AddLiveness(name string, check Check) {
+	c.add(namedCheck{name: name, check: check, liveness: true})
+}
+
+func (c *Checker) add(check namedCheck) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check)
+}
+
+// Live 并发执行所有存活检查
+func (c *Checker) Live(ctx context.Context) *Report {
+	return c.run(ctx, true)
+}
+
+// Ready 并发执行所有检查
+func (c *Checker) Ready(ctx context.Context) *Report {
+	return c.run(ctx, false)
+}
+
+// run 并发执行检查并汇总结果，livenessOnly 为 true 时只执行存活检查
+func (c *Checker) run(ctx context.Context, livenessOnly bool) *Report {
+	c.mu.Lock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.Unlock()
+
+	report := &Report{Status: StatusUp, CheckedAt: time.Now(), Components: make(map[string]*Result)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		if livenessOnly && !check.liveness {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := c.runCheck(ctx, check.check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[check.name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// runCheck 在超时时间内执行一项检查，检查超时视为不可用
+func (c *Checker) runCheck(ctx context.Context, check Check) *Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type outcome struct {
+		details map[string]any
+		err     error
+	}
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		details, err := check(ctx)
+		done <- outcome{details, err}
+	}()
+
+	result := &Result{Status: StatusUp}
+	select {
+	case o := <-done:
+		result.Details = o.details
+		if o.err != nil {
+			result.Status, result.Error = StatusDown, o.err.Error()
+		}
+	case <-ctx.Done():
+		result.Status, result.Error = StatusDown, "检查超时: "+ctx.Err().Error()
+	}
+	result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	return result
+}
+
+// Cached 返回在 ttl 内复用上一次结果的检查，用于调用外部服务、不宜被探针频繁触发的检查
+func Cached(ttl time.Duration, check Check) Check {
+	var mu sync.Mutex
+	var checkedAt time.Time
+	var details map[string]any
+	var err error
+	return func(ctx context.Context) (map[string]any, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if checkedAt.IsZero() || time.Since(checkedAt) >= ttl {
+			details, err = check(ctx)
+			checkedAt = time.Now()
+		}
+		return details, err
+	}
+}
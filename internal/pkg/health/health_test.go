@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func up(context.Context) (map[string]any, error) {
+	return map[string]any{"ok": true}, nil
+}
+
+func down(context.Context) (map[string]any, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestCheckerAggregates(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.AddReadiness("database", up)
+	checker.AddReadiness("queue", down)
+	checker.AddLiveness("ws", up)
+
+	ready := checker.Ready(context.Background())
+	if ready.Status != StatusDown || len(ready.Components) != 3 {
+		t.Fatalf("就绪检查应包含全部 3 项且整体不可用: %+v", ready)
+	}
+	if r := ready.Components["queue"]; r.Status != StatusDown || r.Error != "unavailable" {
+		t.Errorf("queue 应不可用: %+v", r)
+	}
+	if r := ready.Components["database"]; r.Status != StatusUp || r.Details["ok"] != true {
+		t.Errorf("database 应可用并附带 details: %+v", r)
+	}
+
+	live := checker.Live(context.Background())
+	if live.Status != StatusUp || len(live.Components) != 1 || live.Components["ws"] == nil {
+		t.Errorf("存活检查应只包含存活检查项: %+v", live)
+	}
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	checker := NewChecker(20 * time.Millisecond)
+	checker.AddReadiness("slow", func(ctx context.Context) (map[string]any, error) {
+		time.Sleep(time.Second) // 忽略 ctx 的检查也不应阻塞探针
+		return nil, nil
+	})
+
+	start := time.Now()
+	report := checker.Ready(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("检查应在超时后返回，实际耗时 %s", elapsed)
+	}
+	if r := report.Components["slow"]; r.Status != StatusDown || r.LatencyMS < 20 {
+		t.Errorf("超时的检查应不可用并记录耗时: %+v", r)
+	}
+}
+
+func TestCheckerWithoutChecks(t *testing.T) {
+	report := NewChecker(time.Second).Live(context.Background())
+	if report.Status != StatusUp || len(report.Components) != 0 {
+		t.Errorf("没有检查项时应为可用: %+v", report)
+	}
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	check := Cached(time.Hour, func(context.Context) (map[string]any, error) {
+		calls++
+		return nil, errors.New("unavailable")
+	})
+	for i := 0; i < 3; i++ {
+		if _, err := check(context.Background()); err == nil {
+			t.Fatal("应返回缓存的错误")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("ttl 内应只执行一次检查，实际执行 %d 次", calls)
+	}
+
+	calls = 0
+	uncached := Cached(0, func(context.Context) (map[string]any, error) {
+		calls++
+		return nil, nil
+	})
+	_, _ = uncached(context.Background())
+	_, _ = uncached(context.Background())
+	if calls != 2 {
+		t.Errorf("ttl 为 0 时每次都应执行检查，实际执行 %d 次", calls)
+	}
+}
@@ -0,0 +1,194 @@
+package lifecycle
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Component 是由 Manager 管理生命周期的后台组件，如 HTTP 服务器、机器人和各类 worker
+type Component struct {
+	Name string
+
+	// Run 运行组件直到 ctx 被取消；ctx 只在组件停止时才取消，不会随 Manager.Run 的 ctx 一起取消
+	Run func(ctx context.Context) error
+
+	// Stop 在取消 Run 的 ctx 之前调用，用于停止接收新的工作并等待进行中的工作完成，可为 nil
+	Stop func(ctx context.Context) error
+
+	// Critical 为 true 时，组件在关闭前出错退出会触发整体关闭，并作为 Manager.Run 的错误返回
+	Critical bool
+}
+
+// Manager 按注册顺序启动组件，关闭时按相反顺序逐个停止：
+// 后注册的组件（如接收请求的 HTTP 服务器）先停止，先注册的组件（如依赖方使用的投递 worker）最后停止
+type Manager struct {
+	timeout    time.Duration
+	components []*Component
+}
+
+// NewManager 创建一个生命周期管理器，timeout 为关闭所有组件的总时长上限，<= 0 表示不限制
+func NewManager(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout}
+}
+
+// Add 注册一个组件，必须在 Run 之前调用
+func (m *Manager) Add(component *Component) {
+	m.components = append(m.components, component)
+}
+
+// running 是一个运行中的组件
+type running struct {
+	*Component
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error // Run 的返回值，done 关闭后可读
+}
+
+// Run 启动所有组件，阻塞到 ctx 被取消或关键组件出错，然后按相反顺序停止所有组件。
+// 返回关键组件的错误，或关闭超时的错误
+func (m *Manager) Run(ctx context.Context) error {
+	failed := make(chan *running, len(m.components))
+	components := make([]*running, 0, len(m.components))
+	for _, component := range m.components {
+		// 组件的 ctx 保留 ctx 中的值，但只在停止该组件时取消，以便按顺序停止
+		runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		r := &running{Component: component, cancel: cancel, done: make(chan struct{})}
+		components = append(components, r)
+
+		logger.Info("Starting component", zap.String("component", component.Name))
+		go func() {
+			defer close(r.done)
+			r.err = component.Run(runCtx)
+			if runCtx.Err() != nil {
+				return
+			}
+			if r.err != nil {
+				logger.Error("Component exited", zap.String("component", component.Name), zap.Error(r.err))
+			} else {
+				logger.Warn("Component exited", zap.String("component", component.Name))
+			}
+			if component.Critical {
+				failed <- r
+			}
+		}()
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutting down", zap.Duration("timeout", m.timeout))
+	case r := <-failed:
+		runErr = fmt.Errorf("%s 异常退出: %w", r.Name, r.err)
+		logger.Error("Critical component exited, shutting down", zap.String("component", r.Name))
+	}
+
+	if err := m.shutdown(components); err != nil {
+		return errors.Join(runErr, err)
+	}
+	return runErr
+}
+
+// shutdown 按注册的相反顺序停止组件，所有组件共用 timeout 时长
+func (m *Manager) shutdown(components []*running) error {
+	ctx := context.Background()
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	for i := len(components) - 1; i >= 0; i-- {
+		r := components[i]
+		start := time.Now()
+		if r.Stop != nil {
+			if err := r.Stop(ctx); err != nil {
+				logger.Warn("Failed to stop component gracefully", zap.String("component", r.Name), zap.Error(err))
+			}
+		}
+		r.cancel()
+		select {
+		case <-r.done:
+			logger.Info("Component stopped", zap.String("component", r.Name), zap.Duration("elapsed", time.Since(start)))
+		case <-ctx.Done():
+			return fmt.Errorf("关闭超时，未停止的组件: %s", strings.Join(pending(components[:i+1]), ", "))
+		}
+	}
+	return nil
+}
+
+// pending 返回尚未退出的组件名称
+func pending(components []*running) []string {
+	var names []string
+	for _, r := range components {
+		select {
+		case <-r.done:
+		default:
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// Group 跟踪进行中的工作，关闭时拒绝新的工作并等待进行中的工作完成
+type Group struct {
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// ErrClosed 表示 Group 已关闭，不再接受新的工作
+var ErrClosed = errors.New("正在关闭，不再接受新的工作")
+
+// Go 在后台执行 fn，Group 关闭后返回 ErrClosed
+func (g *Group) Go(fn func()) error {
+	if err := g.Add(); err != nil {
+		return err
+	}
+	go func() {
+		defer g.Done()
+		fn()
+	}()
+	return nil
+}
+
+// Add 登记一项将在当前 goroutine 中执行的工作，完成后须调用 Done；Group 关闭后返回 ErrClosed
+func (g *Group) Add() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return ErrClosed
+	}
+	g.wg.Add(1)
+	return nil
+}
+
+// Done 标记一项工作完成
+func (g *Group) Done() {
+	g.wg.Done()
+}
+
+// Close 拒绝新的工作，并等待进行中的工作完成或 ctx 结束
+func (g *Group) Close(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待进行中的工作完成超时: %w", ctx.Err())
+	}
+}
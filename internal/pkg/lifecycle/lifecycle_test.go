@@ -0,0 +1,146 @@
+package lifecycle
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	logDir, err := os.MkdirTemp("", "miko-lifecycle-test")
+	if err != nil {
+		panic(err)
+	}
+	logger.InitLogger(filepath.Join(logDir, "test.log"), "warn")
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+// recorder 记录组件的停止顺序
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.events, ",")
+}
+
+// component 返回一个运行到 ctx 取消的组件，停止和退出时记录事件
+func (r *recorder) component(name string) *Component {
+	return &Component{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			r.add(name + ":exit")
+			return ctx.Err()
+		},
+		Stop: func(context.Context) error {
+			r.add(name + ":stop")
+			return nil
+		},
+	}
+}
+
+func TestManagerStopsInReverseOrder(t *testing.T) {
+	rec := &recorder{}
+	manager := NewManager(time.Second)
+	manager.Add(rec.component("worker"))
+	manager.Add(rec.component("bot"))
+	manager.Add(rec.component("server"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := manager.Run(ctx); err != nil {
+		t.Fatalf("Run 返回错误: %v", err)
+	}
+	want := "server:stop,server:exit,bot:stop,bot:exit,worker:stop,worker:exit"
+	if got := rec.String(); got != want {
+		t.Errorf("停止顺序不符:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestManagerCriticalComponentFailure(t *testing.T) {
+	rec := &recorder{}
+	manager := NewManager(time.Second)
+	manager.Add(rec.component("worker"))
+	listenErr := errors.New("address already in use")
+	manager.Add(&Component{
+		Name:     "server",
+		Run:      func(context.Context) error { return listenErr },
+		Critical: true,
+	})
+
+	err := manager.Run(context.Background())
+	if !errors.Is(err, listenErr) {
+		t.Fatalf("期望返回关键组件的错误，实际: %v", err)
+	}
+	if got := rec.String(); got != "worker:stop,worker:exit" {
+		t.Errorf("关键组件出错后应停止其余组件，实际: %s", got)
+	}
+}
+
+func TestManagerShutdownTimeout(t *testing.T) {
+	manager := NewManager(20 * time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	manager.Add(&Component{
+		Name: "stuck",
+		Run: func(context.Context) error {
+			<-release
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := manager.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "stuck") {
+		t.Fatalf("期望返回关闭超时的错误，实际: %v", err)
+	}
+}
+
+func TestGroupClose(t *testing.T) {
+	var group Group
+	release := make(chan struct{})
+	finished := false
+	if err := group.Go(func() {
+		<-release
+		finished = true
+	}); err != nil {
+		t.Fatalf("Go 返回错误: %v", err)
+	}
+
+	// 进行中的工作未完成时，Close 等到 ctx 结束
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := group.Close(ctx); err == nil {
+		t.Fatal("期望等待超时")
+	}
+	if err := group.Add(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("关闭后应拒绝新的工作，实际: %v", err)
+	}
+
+	close(release)
+	if err := group.Close(context.Background()); err != nil {
+		t.Fatalf("Close 返回错误: %v", err)
+	}
+	if !finished {
+		t.Error("Close 返回时进行中的工作应已完成")
+	}
+}
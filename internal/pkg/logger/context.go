@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// ContextLogger 是附带链路追踪标识的日志记录器，由 Ctx 创建
+type ContextLogger struct {
+	sugar *zap.SugaredLogger // 日志记录器未初始化时为 nil，此时不输出日志
+}
+
+// Ctx 返回在每条日志中附带 ctx 中 span 的 trace_id 和 span_id 的日志记录器，便于按链路检索日志；
+// ctx 中没有 span 时与全局的日志方法相同
+func Ctx(ctx context.Context) *ContextLogger {
+	if log == nil {
+		return &ContextLogger{}
+	}
+	sugar := log.Sugar()
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		sugar = sugar.With("trace_id", spanContext.TraceID().String(), "span_id", spanContext.SpanID().String())
+	}
+	return &ContextLogger{sugar: sugar}
+}
+
+// Debug 输出调试日志，支持k-v对
+func (l *ContextLogger) Debug(msg string, keysAndValues ...interface{}) {
+	if l.sugar != nil {
+		l.sugar.Debugw(msg, keysAndValues...)
+	}
+}
+
+// Info 输出信息日志，支持k-v对
+func (l *ContextLogger) Info(msg string, keysAndValues ...interface{}) {
+	if l.sugar != nil {
+		l.sugar.Infow(msg, keysAndValues...)
+	}
+}
+
+// Warn 输出警告日志，支持k-v对
+func (l *ContextLogger) Warn(msg string, keysAndValues ...interface{}) {
+	if l.sugar != nil {
+		l.sugar.Warnw(msg, keysAndValues...)
+	}
+}
+
+// Error 输出错误日志，支持k-v对
+func (l *ContextLogger) Error(msg string, keysAndValues ...interface{}) {
+	if l.sugar != nil {
+		l.sugar.Errorw(msg, keysAndValues...)
+	}
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCtx(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	previous := log
+	log = zap.New(core)
+	defer func() { log = previous }()
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02},
+		SpanID:     trace.SpanID{0x03},
+		TraceFlags: trace.FlagsSampled,
+	})
+	Ctx(trace.ContextWithSpanContext(context.Background(), spanContext)).Info("traced", "key", "value")
+	Ctx(context.Background()).Info("untraced")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("应输出 2 条日志，实际为 %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["trace_id"] != spanContext.TraceID().String() || fields["span_id"] != spanContext.SpanID().String() || fields["key"] != "value" {
+		t.Errorf("日志应附带链路追踪标识: %v", fields)
+	}
+	if _, ok := entries[1].ContextMap()["trace_id"]; ok {
+		t.Error("ctx 中没有 span 时不应附带 trace_id")
+	}
+}
+
+func TestCtxWithoutLogger(t *testing.T) {
+	previous := log
+	log = nil
+	defer func() { log = previous }()
+
+	// 日志记录器未初始化时不输出日志，也不会 panic
+	Ctx(context.Background()).Error("ignored")
+}
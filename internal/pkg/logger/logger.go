@@ -12,6 +12,9 @@ import (
 
 var log *zap.Logger
 
+// Console 是日志在控制台的输出位置，需在 InitLogger 之前设置。命令行子命令将其改为标准错误，避免日志混入命令输出
+var Console zapcore.WriteSyncer = os.Stdout
+
 // InitLogger 初始化 zap 日志记录器
 func InitLogger(logPath string, logLevel string) {
 	// 配置 lumberjack 用于日志分割
@@ -64,7 +67,7 @@ func InitLogger(logPath string, logLevel string) {
 	// 设置核心：同时输出到控制台和文件
 	core := zapcore.NewCore(
 		zapcore.NewConsoleEncoder(encoderConfig),
-		zapcore.NewMultiWriteSyncer(zapcore.AddSync(os.Stdout), zapcore.AddSync(lumberjackLogger)),
+		zapcore.NewMultiWriteSyncer(Console, zapcore.AddSync(lumberjackLogger)),
 		zap.NewAtomicLevelAt(level),
 	)
 
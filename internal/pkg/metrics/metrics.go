@@ -0,0 +1,202 @@
+// Package metrics 定义服务的 Prometheus 指标，并通过 Handler 以 /metrics 的形式对外暴露
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 是所有指标名的前缀
+const namespace = "miko"
+
+// FeishuCodeRequestFailed 是未收到飞书响应（网络错误、超时等）时记录的错误码标签
+const FeishuCodeRequestFailed = "request_failed"
+
+// registry 是本服务的指标注册表，不使用全局的 DefaultRegisterer，避免与依赖库注册的指标混在一起
+var registry = prometheus.NewRegistry()
+
+var (
+	eventsReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_received_total",
+		Help:      "收到的飞书事件数",
+	}, []string{"tenant", "event_type"})
+
+	strategyMatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "strategy_matches_total",
+		Help:      "消息处理策略匹配到消息的次数",
+	}, []string{"strategy"})
+
+	strategyFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "strategy_failures_total",
+		Help:      "消息处理策略处理消息失败的次数",
+	}, []string{"strategy"})
+
+	feishuAPIDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "feishu_api_request_duration_seconds",
+		Help:      "飞书 API 调用耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	feishuAPIRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feishu_api_requests_total",
+		Help:      "飞书 API 调用次数，code 为飞书返回的错误码，0 表示成功",
+	}, []string{"endpoint", "code"})
+
+	articleForwards = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "article_forwards_total",
+		Help:      "文章转发到群聊的次数，result 为 success 或 failure",
+	}, []string{"chat_id", "result"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP 请求处理耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	eventQueuePending = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "event_queue_pending",
+		Help:      "事件队列中等待处理的任务数，定期从数据库统计",
+	}, []string{"tenant"})
+
+	eventQueueBuffered = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "event_queue_buffered",
+		Help:      "已分配到工作协程缓冲区、尚未开始处理的任务数",
+	}, []string{"tenant"})
+
+	eventQueueActive = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "event_queue_active",
+		Help:      "正在处理的任务数",
+	}, []string{"tenant"})
+
+	eventQueueSucceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_queue_succeeded_total",
+		Help:      "事件队列处理成功的任务数",
+	}, []string{"tenant"})
+
+	eventQueueFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_queue_failed_total",
+		Help:      "事件队列处理失败的任务数",
+	}, []string{"tenant"})
+
+	eventQueueDuplicates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_queue_duplicates_total",
+		Help:      "因飞书重复推送而忽略的事件数",
+	}, []string{"tenant"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		eventsReceived,
+		strategyMatches,
+		strategyFailures,
+		feishuAPIDuration,
+		feishuAPIRequests,
+		articleForwards,
+		httpRequestDuration,
+		eventQueuePending,
+		eventQueueBuffered,
+		eventQueueActive,
+		eventQueueSucceeded,
+		eventQueueFailed,
+		eventQueueDuplicates,
+	)
+}
+
+// Handler 返回以 Prometheus 文本格式输出所有指标的 HTTP handler
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// RegisterDBStats 注册数据库连接池指标 go_sql_*（连接数、等待次数与时长等），数据来自 sql.DB.Stats()，
+// name 作为 db_name 标签。同一个 name 重复注册时返回错误
+func RegisterDBStats(db *sql.DB, name string) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// EventReceived 记录收到一个飞书事件
+func EventReceived(tenant, eventType string) {
+	eventsReceived.WithLabelValues(tenant, eventType).Inc()
+}
+
+// StrategyMatched 记录消息处理策略匹配到一条消息
+func StrategyMatched(strategy string) {
+	strategyMatches.WithLabelValues(strategy).Inc()
+}
+
+// StrategyFailed 记录消息处理策略处理消息失败
+func StrategyFailed(strategy string) {
+	strategyFailures.WithLabelValues(strategy).Inc()
+}
+
+// ObserveFeishuAPI 记录一次飞书 API 调用的耗时和错误码，code 为飞书返回的错误码或 FeishuCodeRequestFailed
+func ObserveFeishuAPI(endpoint string, start time.Time, code string) {
+	feishuAPIDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	feishuAPIRequests.WithLabelValues(endpoint, code).Inc()
+}
+
+// ArticleForwarded 记录一次文章转发到群聊的结果
+func ArticleForwarded(chatID string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	articleForwards.WithLabelValues(chatID, result).Inc()
+}
+
+// ObserveHTTPRequest 记录一次 HTTP 请求的处理耗时，route 为匹配到的路由模板，避免路径参数造成标签膨胀
+func ObserveHTTPRequest(method, route string, status int, latency time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(latency.Seconds())
+}
+
+// SetEventQueuePending 记录租户事件队列中等待处理的任务数
+func SetEventQueuePending(tenant string, pending int64) {
+	eventQueuePending.WithLabelValues(tenant).Set(float64(pending))
+}
+
+// AddEventQueueBuffered 调整租户事件队列缓冲区中的任务数，delta 可为负数
+func AddEventQueueBuffered(tenant string, delta int) {
+	eventQueueBuffered.WithLabelValues(tenant).Add(float64(delta))
+}
+
+// AddEventQueueActive 调整租户事件队列正在处理的任务数，delta 可为负数
+func AddEventQueueActive(tenant string, delta int) {
+	eventQueueActive.WithLabelValues(tenant).Add(float64(delta))
+}
+
+// EventQueueFinished 记录租户事件队列处理完一个任务的结果
+func EventQueueFinished(tenant string, err error) {
+	if err != nil {
+		eventQueueFailed.WithLabelValues(tenant).Inc()
+		return
+	}
+	eventQueueSucceeded.WithLabelValues(tenant).Inc()
+}
+
+// EventQueueDuplicate 记录租户事件队列忽略了一个重复推送的事件
+func EventQueueDuplicate(tenant string) {
+	eventQueueDuplicates.WithLabelValues(tenant).Inc()
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecordMetrics(t *testing.T) {
+	EventReceived("tenant", "im.message.receive_v1")
+	StrategyMatched("*messagehandler.submissionHandler")
+	StrategyFailed("*messagehandler.submissionHandler")
+	ObserveFeishuAPI("im.message.create", time.Now(), "0")
+	ObserveFeishuAPI("im.message.create", time.Now(), FeishuCodeRequestFailed)
+	ArticleForwarded("oc_a", nil)
+	ArticleForwarded("oc_a", errors.New("failed"))
+	ObserveHTTPRequest("GET", "", 404, time.Millisecond)
+	SetEventQueuePending("tenant", 3)
+	AddEventQueueBuffered("tenant", 2)
+	AddEventQueueBuffered("tenant", -1)
+	AddEventQueueActive("tenant", 1)
+	EventQueueFinished("tenant", nil)
+	EventQueueFinished("tenant", errors.New("failed"))
+	EventQueueDuplicate("tenant")
+
+	if got := testutil.ToFloat64(eventsReceived.WithLabelValues("tenant", "im.message.receive_v1")); got != 1 {
+		t.Errorf("收到的事件数应为 1，实际为 %v", got)
+	}
+	if got := testutil.ToFloat64(feishuAPIRequests.WithLabelValues("im.message.create", FeishuCodeRequestFailed)); got != 1 {
+		t.Errorf("未收到响应的飞书 API 调用数应为 1，实际为 %v", got)
+	}
+	if got := testutil.ToFloat64(articleForwards.WithLabelValues("oc_a", "failure")); got != 1 {
+		t.Errorf("转发失败次数应为 1，实际为 %v", got)
+	}
+	if got := testutil.ToFloat64(eventQueueBuffered.WithLabelValues("tenant")); got != 1 {
+		t.Errorf("缓冲区中的任务数应为 1，实际为 %v", got)
+	}
+
+	// 通过 /metrics 输出所有指标
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`miko_events_received_total{event_type="im.message.receive_v1",tenant="tenant"} 1`,
+		`miko_strategy_failures_total{strategy="*messagehandler.submissionHandler"} 1`,
+		`miko_feishu_api_request_duration_seconds_count{endpoint="im.message.create"} 2`,
+		`miko_article_forwards_total{chat_id="oc_a",result="success"} 1`,
+		`miko_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+		`miko_event_queue_pending{tenant="tenant"} 3`,
+		`miko_event_queue_active{tenant="tenant"} 1`,
+		`miko_event_queue_succeeded_total{tenant="tenant"} 1`,
+		`miko_event_queue_failed_total{tenant="tenant"} 1`,
+		`miko_event_queue_duplicates_total{tenant="tenant"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("/metrics 输出缺少 %s", want)
+		}
+	}
+}
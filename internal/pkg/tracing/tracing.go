@@ -0,0 +1,129 @@
+// Package tracing 封装 OpenTelemetry 链路追踪：初始化 OTLP 导出、创建 span，以及在进程内外传递追踪上下文
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 是本服务创建的 span 所属的 instrumentation scope
+const instrumentationName = "MikoNews"
+
+// traceParentHeader 是 W3C Trace Context 中携带追踪上下文的字段
+const traceParentHeader = "traceparent"
+
+func init() {
+	// 未启用导出时也传递追踪上下文，使上游请求的 trace ID 能出现在日志中
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Setup 将 span 通过 OTLP/HTTP 导出到 endpoint（为空时使用 OTEL_EXPORTER_OTLP_* 环境变量），并设置为全局的 TracerProvider。
+// sampleRatio 为新链路的采样比例。返回的 shutdown 导出剩余的 span 并关闭导出器，应在进程退出前调用
+func Setup(ctx context.Context, endpoint, serviceName string, sampleRatio float64) (shutdown func(context.Context) error, err error) {
+	var opts []otlptracehttp.Option
+	if endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建 OTLP 导出器失败: %w", err)
+	}
+	provider := NewProvider(sdktrace.NewBatchSpanProcessor(exporter), serviceName, sampleRatio)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider 创建一个将 span 交给 processor 的 TracerProvider。测试中可搭配 tracetest.NewInMemoryExporter 使用
+func NewProvider(processor sdktrace.SpanProcessor, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+}
+
+// Start 创建一个 span，调用方需要调用 End 结束它
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartKind 创建一个指定类型的 span，如接收外部请求的 server span、调用外部服务的 client span
+func StartKind(ctx context.Context, kind trace.SpanKind, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End 结束 span，err 非 nil 时将其记录到 span 并标记为失败
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceParent 返回 ctx 中追踪上下文的 W3C traceparent 表示，用于将追踪上下文随数据保存，ctx 中没有 span 时返回空字符串
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier.Get(traceParentHeader)
+}
+
+// WithTraceParent 返回携带 traceParent 所表示的追踪上下文的 ctx，在其中创建的 span 成为原链路的一部分
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier{traceParentHeader: traceParent})
+}
+
+// FromHeader 返回携带 HTTP 请求头中传入的追踪上下文的 ctx，请求头中没有追踪上下文时原样返回
+func FromHeader(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Transport 为经过 base 的每个 HTTP 请求创建 client span，并在请求头中传递追踪上下文。base 为 nil 时使用 http.DefaultTransport
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+// transport 是 Transport 返回的 http.RoundTripper
+type transport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := StartKind(req.Context(), trace.SpanKindClient, "HTTP "+req.Method,
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.ServerAddress(req.URL.Hostname()),
+		semconv.URLPath(req.URL.Path),
+	)
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setupTestProvider 将 span 同步导出到内存中
+func setupTestProvider(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), "miko-news-test", 1)
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func TestTraceParentRoundTrip(t *testing.T) {
+	setupTestProvider(t)
+	if got := TraceParent(context.Background()); got != "" {
+		t.Errorf("没有 span 时应返回空字符串，实际为 %q", got)
+	}
+
+	ctx, span := Start(context.Background(), "enqueue")
+	traceParent := TraceParent(ctx)
+	span.End()
+	if traceParent == "" {
+		t.Fatal("有 span 时应返回 traceparent")
+	}
+
+	_, child := Start(WithTraceParent(context.Background(), traceParent), "process")
+	defer child.End()
+	if child.SpanContext().TraceID() != span.SpanContext().TraceID() {
+		t.Error("在恢复的追踪上下文中创建的 span 应属于原链路")
+	}
+}
+
+func TestEndRecordsError(t *testing.T) {
+	exporter := setupTestProvider(t)
+	_, span := Start(context.Background(), "failing")
+	End(span, errors.New("boom"))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error || spans[0].Status.Description != "boom" {
+		t.Fatalf("span 应被标记为失败: %+v", spans)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	exporter := setupTestProvider(t)
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, parent := Start(context.Background(), "caller")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/open-apis/im/v1/messages", nil)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("应导出 2 个 span，实际为 %d", len(spans))
+	}
+	client := spans[0]
+	if client.Name != "HTTP POST" || client.SpanKind != trace.SpanKindClient || client.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("应为请求创建调用方的子 span: %+v", client)
+	}
+	if received == "" || received != TraceParent(trace.ContextWithSpanContext(context.Background(), client.SpanContext)) {
+		t.Errorf("请求头应携带 client span 的追踪上下文，实际为 %q", received)
+	}
+}
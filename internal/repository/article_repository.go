@@ -3,13 +3,51 @@ package repository
 import (
 	"MikoNews/internal/model"
 	"context"
+	"time"
 )
 
 // ArticleRepository 定义文章数据访问接口
 type ArticleRepository interface {
-	// Create 保存一篇新的文章投稿
+	// Create 保存一篇新的文章投稿，article.Authors 中的署名会一并保存；同一租户内来源消息ID重复时返回 gorm.ErrDuplicatedKey
 	Create(ctx context.Context, article *model.Article) error
 
-	// FindByID 根据ID查找文章
+	// FindByID 根据ID查找文章，并加载署名成员
 	FindByID(ctx context.Context, id int64) (*model.Article, error)
+
+	// FindBySourceMessageID 根据来源消息ID查找文章（不限状态），不存在时返回 gorm.ErrRecordNotFound
+	FindBySourceMessageID(ctx context.Context, messageID string) (*model.Article, error)
+
+	// UpdateStatus 将文章状态从 fromStatus 更新为 toStatus，文章不存在或状态不符时返回 gorm.ErrRecordNotFound
+	UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error
+
+	// UpdateDocToken 保存文章归档到飞书云文档或知识库后的标识
+	UpdateDocToken(ctx context.Context, id int64, docToken string) error
+
+	// IncrementForwardCount 为文章的转发次数增加 n
+	IncrementForwardCount(ctx context.Context, id int64, n int) error
+
+	// FindAfter 按ID正序返回ID大于 afterID 的 limit 篇文章（不限状态），用于分页遍历全部文章
+	FindAfter(ctx context.Context, afterID int64, limit int) ([]*model.Article, error)
+
+	// FindLatest 按创建时间倒序返回最新的 limit 篇已发布文章
+	FindLatest(ctx context.Context, limit int) ([]*model.Article, error)
+
+	// Search 按关键字在已发布文章的标题和内容中搜索，按创建时间倒序返回
+	Search(ctx context.Context, keyword string, limit int) ([]*model.Article, error)
+
+	// FindByFingerprints 返回至少命中 minMatches 个 kind 类型指纹的未驳回文章，按创建时间正序返回
+	FindByFingerprints(ctx context.Context, kind string, values []string, minMatches int, limit int) ([]*model.Article, error)
+
+	// FindSimHashCandidates 返回至少命中 minMatches 个 SimHash 分段的全部未驳回文章，按创建时间正序返回。
+	// 只加载 ID、SimHash 和创建时间，由调用方按海明距离筛选；候选不设上限，否则偶然命中的旧文章会挤掉真正的近似重复
+	FindSimHashCandidates(ctx context.Context, bands []string, minMatches int) ([]*model.Article, error)
+
+	// AddAuthors 为已有文章追加署名，已存在的署名会被忽略，文章不存在时返回 gorm.ErrRecordNotFound
+	AddAuthors(ctx context.Context, articleID int64, authors []*model.ArticleAuthor) error
+
+	// UpdateAuthorName 成员改名后同步更新其署名（匿名投稿不受影响）
+	UpdateAuthorName(ctx context.Context, openID, name string) error
+
+	// Stats 统计已发布文章的总数、作者数、since 之后的投稿数以及投稿、合著最多的 topN 位成员
+	Stats(ctx context.Context, since time.Time, topN int) (*model.ArticleStats, error)
 }
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"MikoNews/internal/model"
+	"context"
+	"time"
+)
+
+// EventJobRepository 定义飞书事件处理队列的数据访问接口，只读写所属租户的任务
+type EventJobRepository interface {
+	// CreateJob 新增一个任务，同一事件ID的任务已存在时不新增并返回 false
+	CreateJob(ctx context.Context, job *model.EventJob) (bool, error)
+
+	// ListDueJobs 返回 now 之前到期、等待处理的任务，按入队顺序
+	ListDueJobs(ctx context.Context, now time.Time, limit int) ([]*model.EventJob, error)
+
+	// ClaimJob 将到期任务的下次处理时间推迟到 leaseUntil 并增加尝试次数，返回是否抢占成功，避免多实例重复处理
+	ClaimJob(ctx context.Context, job *model.EventJob, leaseUntil time.Time) (bool, error)
+
+	// UpdateJob 保存任务的处理结果
+	UpdateJob(ctx context.Context, job *model.EventJob) error
+
+	// CountPending 返回等待处理的任务数，以及其中最早的入队时间（没有任务时为零值）
+	CountPending(ctx context.Context) (int64, time.Time, error)
+
+	// DeleteFinishedJobs 删除 before 之前处理完成或失败的任务，返回删除的数量
+	DeleteFinishedJobs(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,82 @@
+// Package memory 提供仓库接口的内存实现，适用于单机部署和测试
+package memory
+
+import (
+	"MikoNews/internal/repository"
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimitKey 是内存计数的键
+type rateLimitKey struct {
+	key         string
+	windowStart int64
+}
+
+// rateLimitEntry 是内存中的一个计数
+type rateLimitEntry struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// rateLimitRepository 实现了 RateLimitRepository 接口，计数仅保存在当前进程中
+type rateLimitRepository struct {
+	mu       sync.Mutex
+	counters map[rateLimitKey]*rateLimitEntry
+}
+
+// NewRateLimitRepository 创建一个新的内存 rateLimitRepository 实例
+func NewRateLimitRepository() repository.RateLimitRepository {
+	return &rateLimitRepository{counters: make(map[rateLimitKey]*rateLimitEntry)}
+}
+
+// Count 返回 key 在窗口内的计数
+func (r *rateLimitRepository) Count(ctx context.Context, key string, windowStart time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.counters[rateLimitKey{key, windowStart.Unix()}]; ok {
+		return entry.count, nil
+	}
+	return 0, nil
+}
+
+// Increment 将窗口内的计数加一并返回加一后的计数
+func (r *rateLimitRepository) Increment(ctx context.Context, key string, windowStart, expiresAt time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := rateLimitKey{key, windowStart.Unix()}
+	entry, ok := r.counters[k]
+	if !ok {
+		entry = &rateLimitEntry{expiresAt: expiresAt}
+		r.counters[k] = entry
+	}
+	entry.count++
+	return entry.count, nil
+}
+
+// Decrement 将窗口内的计数减一
+func (r *rateLimitRepository) Decrement(ctx context.Context, key string, windowStart time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.counters[rateLimitKey{key, windowStart.Unix()}]; ok && entry.count > 0 {
+		entry.count--
+	}
+	return nil
+}
+
+// DeleteExpired 清理已过期的计数
+func (r *rateLimitRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, entry := range r.counters {
+		if entry.expiresAt.Before(before) {
+			delete(r.counters, k)
+		}
+	}
+	return nil
+}
@@ -4,32 +4,276 @@ import (
 	"MikoNews/internal/model"
 	"MikoNews/internal/repository"
 	"context"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// articleRepository 实现了 ArticleRepository 接口
+// articleRepository 实现了 ArticleRepository 接口，所有读写都限定在 tenantKey 所属租户内
 type articleRepository struct {
-	db *gorm.DB
+	db        *gorm.DB
+	tenantKey string
 }
 
 // NewArticleRepository 创建一个新的 articleRepository 实例
-func NewArticleRepository(db *gorm.DB) repository.ArticleRepository {
-	return &articleRepository{db: db}
+// tenantKey 为文章所属的飞书租户，单应用部署时为空字符串
+func NewArticleRepository(db *gorm.DB, tenantKey string) repository.ArticleRepository {
+	return &articleRepository{db: db, tenantKey: tenantKey}
 }
 
-// Create 保存一篇新的文章投稿
+// scoped 返回限定在当前租户文章内的查询
+func (r *articleRepository) scoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Where("articles.tenant_key = ?", r.tenantKey)
+}
+
+// Create 保存一篇新的文章投稿，article.Authors 中的署名会一并保存
 func (r *articleRepository) Create(ctx context.Context, article *model.Article) error {
+	article.TenantKey = r.tenantKey
 	result := r.db.WithContext(ctx).Create(article)
 	return result.Error
 }
 
-// FindByID 根据ID查找文章
+// FindByID 根据ID查找文章，其他租户的文章视为不存在
 func (r *articleRepository) FindByID(ctx context.Context, id int64) (*model.Article, error) {
 	var article model.Article
-	result := r.db.WithContext(ctx).First(&article, id)
+	result := r.scoped(ctx).Preload("Authors").First(&article, id)
 	if result.Error != nil {
 		return nil, result.Error // GORM 会自动处理 ErrRecordNotFound
 	}
 	return &article, nil
-}
\ No newline at end of file
+}
+
+// FindBySourceMessageID 根据来源消息ID查找文章，空 ID 视为不存在
+func (r *articleRepository) FindBySourceMessageID(ctx context.Context, messageID string) (*model.Article, error) {
+	if messageID == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var article model.Article
+	result := r.scoped(ctx).Where("source_message_id = ?", messageID).Order("id ASC").First(&article)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &article, nil
+}
+
+// UpdateStatus 将文章状态从 fromStatus 更新为 toStatus，文章不存在或状态不符时返回 gorm.ErrRecordNotFound
+func (r *articleRepository) UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error {
+	result := r.scoped(ctx).Model(&model.Article{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Update("status", toStatus)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateDocToken 保存文章归档到飞书云文档或知识库后的标识
+func (r *articleRepository) UpdateDocToken(ctx context.Context, id int64, docToken string) error {
+	result := r.scoped(ctx).Model(&model.Article{}).
+		Where("id = ?", id).
+		Update("doc_token", docToken)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IncrementForwardCount 为文章的转发次数增加 n
+func (r *articleRepository) IncrementForwardCount(ctx context.Context, id int64, n int) error {
+	result := r.scoped(ctx).Model(&model.Article{}).
+		Where("id = ?", id).
+		Update("forward_count", gorm.Expr("forward_count + ?", n))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindAfter 按ID正序返回ID大于 afterID 的 limit 篇文章（不限状态），并加载署名成员
+func (r *articleRepository) FindAfter(ctx context.Context, afterID int64, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	result := r.scoped(ctx).
+		Preload("Authors").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&articles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return articles, nil
+}
+
+// FindLatest 按创建时间倒序返回最新的 limit 篇已发布文章
+func (r *articleRepository) FindLatest(ctx context.Context, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	result := r.scoped(ctx).
+		Where("status = ?", model.ArticleStatusPublished).
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&articles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return articles, nil
+}
+
+// Search 按关键字在已发布文章的标题和内容中搜索
+func (r *articleRepository) Search(ctx context.Context, keyword string, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	pattern := "%" + EscapeLike(keyword) + "%"
+	result := r.scoped(ctx).
+		Where("status = ?", model.ArticleStatusPublished).
+		Where("title LIKE ? OR content LIKE ?", pattern, pattern).
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&articles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return articles, nil
+}
+
+// FindByFingerprints 按指纹查找未驳回的文章
+func (r *articleRepository) FindByFingerprints(ctx context.Context, kind string, values []string, minMatches int, limit int) ([]*model.Article, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	matched := r.db.WithContext(ctx).Model(&model.ArticleFingerprint{}).
+		Select("article_id").
+		Where("kind = ? AND value IN ?", kind, values).
+		Group("article_id").
+		Having("COUNT(DISTINCT value) >= ?", minMatches)
+
+	var articles []*model.Article
+	result := r.scoped(ctx).
+		Where("id IN (?)", matched).
+		Where("status <> ?", model.ArticleStatusRejected).
+		Order("created_at ASC").
+		Order("id ASC").
+		Limit(limit).
+		Find(&articles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return articles, nil
+}
+
+// FindSimHashCandidates 按 SimHash 分段查找未驳回文章的 ID、SimHash 和创建时间
+func (r *articleRepository) FindSimHashCandidates(ctx context.Context, bands []string, minMatches int) ([]*model.Article, error) {
+	if len(bands) == 0 {
+		return nil, nil
+	}
+	matched := r.db.WithContext(ctx).Model(&model.ArticleFingerprint{}).
+		Select("article_id").
+		Where("kind = ? AND value IN ?", model.FingerprintKindSimHash, bands).
+		Group("article_id").
+		Having("COUNT(DISTINCT value) >= ?", minMatches)
+
+	var articles []*model.Article
+	result := r.scoped(ctx).
+		Select("id", "simhash", "created_at").
+		Where("id IN (?)", matched).
+		Where("status <> ?", model.ArticleStatusRejected).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&articles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return articles, nil
+}
+
+// AddAuthors 为已有文章追加署名，已存在的署名会被忽略，文章不存在时返回 gorm.ErrRecordNotFound
+func (r *articleRepository) AddAuthors(ctx context.Context, articleID int64, authors []*model.ArticleAuthor) error {
+	if len(authors) == 0 {
+		return nil
+	}
+	var count int64
+	if err := r.scoped(ctx).Model(&model.Article{}).Where("id = ?", articleID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	for _, author := range authors {
+		author.ArticleID = articleID
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(authors).Error
+}
+
+// UpdateAuthorName 成员改名后同步更新 articles 和 article_authors 中的名字
+func (r *articleRepository) UpdateAuthorName(ctx context.Context, openID, name string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Article{}).
+			Where("tenant_key = ? AND author_id = ? AND is_anonymous = ?", r.tenantKey, openID, false).
+			Update("author_name", name).Error; err != nil {
+			return err
+		}
+		tenantArticles := tx.Model(&model.Article{}).Select("id").Where("tenant_key = ?", r.tenantKey)
+		return tx.Model(&model.ArticleAuthor{}).
+			Where("open_id = ? AND article_id IN (?)", openID, tenantArticles).
+			Update("name", name).Error
+	})
+}
+
+// Stats 统计已发布文章的投稿情况
+func (r *articleRepository) Stats(ctx context.Context, since time.Time, topN int) (*model.ArticleStats, error) {
+	stats := &model.ArticleStats{}
+	db := r.scoped(ctx).Model(&model.Article{}).Where("status = ?", model.ArticleStatusPublished)
+
+	if err := db.Session(&gorm.Session{}).Count(&stats.TotalArticles).Error; err != nil {
+		return nil, err
+	}
+	// 匿名投稿不计入作者统计
+	if err := db.Session(&gorm.Session{}).Where("is_anonymous = ?", false).Distinct("author_id").Count(&stats.TotalAuthors).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Session(&gorm.Session{}).Where("created_at >= ?", since).Count(&stats.RecentArticles).Error; err != nil {
+		return nil, err
+	}
+
+	stats.TopAuthors = make([]*model.AuthorStat, 0, topN)
+	if err := db.Session(&gorm.Session{}).
+		Select("author_id, MAX(author_name) AS author_name, COUNT(*) AS article_count").
+		Where("is_anonymous = ?", false).
+		Group("author_id").
+		Order("article_count DESC").
+		Limit(topN).
+		Scan(&stats.TopAuthors).Error; err != nil {
+		return nil, err
+	}
+
+	stats.TopCoAuthors = make([]*model.AuthorStat, 0, topN)
+	if err := r.db.WithContext(ctx).Model(&model.ArticleAuthor{}).
+		Select("article_authors.open_id AS author_id, MAX(article_authors.name) AS author_name, COUNT(*) AS article_count").
+		Joins("JOIN articles ON articles.id = article_authors.article_id").
+		Where("article_authors.role = ? AND articles.status = ?", model.AuthorRoleCoAuthor, model.ArticleStatusPublished).
+		Where("articles.tenant_key = ?", r.tenantKey).
+		Group("article_authors.open_id").
+		Order("article_count DESC").
+		Limit(topN).
+		Scan(&stats.TopCoAuthors).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// EscapeLike 转义 LIKE 查询中的通配符，以反斜杠作为转义字符，供其他数据库的实现复用
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/repository"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// eventJobRepository 实现了 EventJobRepository 接口，只读写 tenantKey 所属租户的任务
+type eventJobRepository struct {
+	db        *gorm.DB
+	tenantKey string
+}
+
+// NewEventJobRepository 创建一个新的 eventJobRepository 实例
+func NewEventJobRepository(db *gorm.DB, tenantKey string) repository.EventJobRepository {
+	return &eventJobRepository{db: db, tenantKey: tenantKey}
+}
+
+// CreateJob 新增一个任务，事件ID冲突时忽略
+func (r *eventJobRepository) CreateJob(ctx context.Context, job *model.EventJob) (bool, error) {
+	job.TenantKey = r.tenantKey
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListDueJobs 返回到期、等待处理的任务
+func (r *eventJobRepository) ListDueJobs(ctx context.Context, now time.Time, limit int) ([]*model.EventJob, error) {
+	var jobs []*model.EventJob
+	err := r.db.WithContext(ctx).
+		Where("tenant_key = ? AND status = ? AND next_attempt_at <= ?", r.tenantKey, model.EventJobPending, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ClaimJob 以下次处理时间作为乐观锁抢占任务
+func (r *eventJobRepository) ClaimJob(ctx context.Context, job *model.EventJob, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.EventJob{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", job.ID, model.EventJobPending, job.NextAttemptAt).
+		Updates(map[string]any{
+			"next_attempt_at": leaseUntil,
+			"attempts":        gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	job.NextAttemptAt = leaseUntil
+	job.Attempts++
+	return true, nil
+}
+
+// UpdateJob 保存任务的处理结果
+func (r *eventJobRepository) UpdateJob(ctx context.Context, job *model.EventJob) error {
+	return r.db.WithContext(ctx).Model(job).Select(
+		"status", "attempts", "last_error", "next_attempt_at", "updated_at",
+	).Updates(job).Error
+}
+
+// CountPending 返回等待处理的任务数和最早的入队时间
+func (r *eventJobRepository) CountPending(ctx context.Context) (int64, time.Time, error) {
+	var oldest model.EventJob
+	err := r.db.WithContext(ctx).
+		Where("tenant_key = ? AND status = ?", r.tenantKey, model.EventJobPending).
+		Order("id ASC").
+		Limit(1).
+		Find(&oldest).Error
+	if err != nil || oldest.ID == 0 {
+		return 0, time.Time{}, err
+	}
+
+	var count int64
+	err = r.db.WithContext(ctx).Model(&model.EventJob{}).
+		Where("tenant_key = ? AND status = ?", r.tenantKey, model.EventJobPending).
+		Count(&count).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return count, oldest.CreatedAt, nil
+}
+
+// DeleteFinishedJobs 删除 before 之前处理完成或失败的任务
+func (r *eventJobRepository) DeleteFinishedJobs(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("tenant_key = ? AND status IN ? AND updated_at < ?", r.tenantKey, []string{model.EventJobSucceeded, model.EventJobFailed}, before).
+		Delete(&model.EventJob{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/repository"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rateLimitRepository 实现了 RateLimitRepository 接口，计数保存在 rate_limit_counters 表中
+type rateLimitRepository struct {
+	db *gorm.DB
+}
+
+// NewRateLimitRepository 创建一个新的 rateLimitRepository 实例
+func NewRateLimitRepository(db *gorm.DB) repository.RateLimitRepository {
+	return &rateLimitRepository{db: db}
+}
+
+// Count 返回 key 在窗口内的计数
+func (r *rateLimitRepository) Count(ctx context.Context, key string, windowStart time.Time) (int64, error) {
+	var counter model.RateLimitCounter
+	err := r.db.WithContext(ctx).
+		Where("counter_key = ? AND window_start = ?", key, windowStart).
+		First(&counter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return counter.Count, nil
+}
+
+// Increment 原子地将窗口内的计数加一并返回加一后的计数。
+// upsert 持有该行的写锁直到事务提交，同一事务内读到的计数即为本次加一的结果，不受并发写入影响
+func (r *rateLimitRepository) Increment(ctx context.Context, key string, windowStart, expiresAt time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		counter := &model.RateLimitCounter{
+			CounterKey:  key,
+			WindowStart: windowStart,
+			Count:       1,
+			ExpiresAt:   expiresAt,
+		}
+		// 带上表名引用已有的计数，PostgreSQL 的 ON CONFLICT 中不带表名的 count 有歧义
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "counter_key"}, {Name: "window_start"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("rate_limit_counters.count + 1")}),
+		}).Create(counter).Error
+		if err != nil {
+			return err
+		}
+		var saved model.RateLimitCounter
+		if err := tx.Where("counter_key = ? AND window_start = ?", key, windowStart).Take(&saved).Error; err != nil {
+			return err
+		}
+		count = saved.Count
+		return nil
+	})
+	return count, err
+}
+
+// Decrement 将窗口内的计数减一
+func (r *rateLimitRepository) Decrement(ctx context.Context, key string, windowStart time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.RateLimitCounter{}).
+		Where("counter_key = ? AND window_start = ? AND count > 0", key, windowStart).
+		Update("count", gorm.Expr("count - 1")).Error
+}
+
+// DeleteExpired 清理已过期的计数
+func (r *rateLimitRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Delete(&model.RateLimitCounter{}).Error
+}
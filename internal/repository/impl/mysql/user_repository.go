@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/repository"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userRepository 实现了 UserRepository 接口，只读写 tenantKey 所属租户的用户
+type userRepository struct {
+	db        *gorm.DB
+	tenantKey string
+}
+
+// NewUserRepository 创建一个新的 userRepository 实例
+// tenantKey 为用户所属的飞书租户，单应用部署时为空字符串
+func NewUserRepository(db *gorm.DB, tenantKey string) repository.UserRepository {
+	return &userRepository{db: db, tenantKey: tenantKey}
+}
+
+// Upsert 新增或更新一个用户（以 open_id 为主键）
+func (r *userRepository) Upsert(ctx context.Context, user *model.User) error {
+	user.TenantKey = r.tenantKey
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "open_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tenant_key", "union_id", "name", "en_name", "avatar_url", "department_ids", "status", "synced_at", "updated_at"}),
+	}).Create(user).Error
+}
+
+// FindByOpenID 根据 OpenID 查找用户
+func (r *userRepository) FindByOpenID(ctx context.Context, openID string) (*model.User, error) {
+	var user model.User
+	result := r.db.WithContext(ctx).Where("tenant_key = ? AND open_id = ?", r.tenantKey, openID).First(&user)
+	if result.Error != nil {
+		return nil, result.Error // GORM 会自动处理 ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+// UpdateStatus 更新用户状态
+func (r *userRepository) UpdateStatus(ctx context.Context, openID, status string) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).
+		Where("tenant_key = ? AND open_id = ?", r.tenantKey, openID).
+		Update("status", status).Error
+}
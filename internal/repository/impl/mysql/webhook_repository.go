@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/repository"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// webhookRepository 实现了 WebhookRepository 接口
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository 创建一个新的 webhookRepository 实例
+func NewWebhookRepository(db *gorm.DB) repository.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// ListEndpoints 返回所有 webhook 端点
+func (r *webhookRepository) ListEndpoints(ctx context.Context) ([]*model.WebhookEndpoint, error) {
+	var endpoints []*model.WebhookEndpoint
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// FindEndpoint 根据ID查找端点
+func (r *webhookRepository) FindEndpoint(ctx context.Context, id int64) (*model.WebhookEndpoint, error) {
+	var endpoint model.WebhookEndpoint
+	if err := r.db.WithContext(ctx).First(&endpoint, id).Error; err != nil {
+		return nil, err // GORM 会自动处理 ErrRecordNotFound
+	}
+	return &endpoint, nil
+}
+
+// CreateEndpoint 新增一个端点
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error {
+	return r.db.WithContext(ctx).Create(endpoint).Error
+}
+
+// UpsertEndpointByName 按名称新增或更新端点
+func (r *webhookRepository) UpsertEndpointByName(ctx context.Context, endpoint *model.WebhookEndpoint) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"url", "secret", "events", "tenant_key", "source", "enabled", "updated_at"}),
+	}).Create(endpoint).Error
+}
+
+// DeleteEndpoint 删除端点
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&model.WebhookEndpoint{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateDeliveries 批量新增投递记录
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(deliveries).Error
+}
+
+// FindDelivery 根据ID查找投递记录
+func (r *webhookRepository) FindDelivery(ctx context.Context, id int64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UpdateDelivery 保存投递结果
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(delivery).Select(
+		"status", "attempts", "response_status", "last_error", "next_attempt_at", "delivered_at", "updated_at",
+	).Updates(delivery).Error
+}
+
+// ListDueDeliveries 返回到期、等待投递的记录
+func (r *webhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDelivery 以下次尝试时间作为乐观锁抢占投递记录
+func (r *webhookRepository) ClaimDelivery(ctx context.Context, delivery *model.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, model.WebhookDeliveryPending, delivery.NextAttemptAt).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	delivery.NextAttemptAt = leaseUntil
+	return true, nil
+}
+
+// ListDeliveries 返回端点最近的投递记录
+func (r *webhookRepository) ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("endpoint_id = ?", endpointID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/repository/impl/mysql"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// articleRepository 实现了 ArticleRepository 接口
+// 除 Search 外的查询只用到 GORM 的通用写法，直接复用 MySQL 实现
+type articleRepository struct {
+	repository.ArticleRepository
+	db        *gorm.DB
+	tenantKey string
+}
+
+// NewArticleRepository 创建一个新的 articleRepository 实例
+// tenantKey 为文章所属的飞书租户，单应用部署时为空字符串
+func NewArticleRepository(db *gorm.DB, tenantKey string) repository.ArticleRepository {
+	return &articleRepository{
+		ArticleRepository: mysql.NewArticleRepository(db, tenantKey),
+		db:                db,
+		tenantKey:         tenantKey,
+	}
+}
+
+// Search 按关键字在已发布文章的标题和内容中搜索
+// PostgreSQL 的 LIKE 区分大小写，使用 ILIKE 与 MySQL 默认排序规则下的行为保持一致
+func (r *articleRepository) Search(ctx context.Context, keyword string, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	pattern := "%" + mysql.EscapeLike(keyword) + "%"
+	result := r.db.WithContext(ctx).
+		Where("articles.tenant_key = ?", r.tenantKey).
+		Where("status = ?", model.ArticleStatusPublished).
+		Where("title ILIKE ? OR content ILIKE ?", pattern, pattern).
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&articles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return articles, nil
+}
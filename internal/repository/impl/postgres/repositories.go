@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"MikoNews/internal/repository"
+	"MikoNews/internal/repository/impl/mysql"
+
+	"gorm.io/gorm"
+)
+
+// 用户、限流计数、webhook 和事件队列的读写与 MySQL 实现完全一致（upsert 由 GORM 按方言生成 ON CONFLICT），直接复用
+
+// NewUserRepository 创建 PostgreSQL 上的 UserRepository
+func NewUserRepository(db *gorm.DB, tenantKey string) repository.UserRepository {
+	return mysql.NewUserRepository(db, tenantKey)
+}
+
+// NewRateLimitRepository 创建 PostgreSQL 上的 RateLimitRepository
+func NewRateLimitRepository(db *gorm.DB) repository.RateLimitRepository {
+	return mysql.NewRateLimitRepository(db)
+}
+
+// NewWebhookRepository 创建 PostgreSQL 上的 WebhookRepository
+func NewWebhookRepository(db *gorm.DB) repository.WebhookRepository {
+	return mysql.NewWebhookRepository(db)
+}
+
+// NewEventJobRepository 创建 PostgreSQL 上的 EventJobRepository
+func NewEventJobRepository(db *gorm.DB, tenantKey string) repository.EventJobRepository {
+	return mysql.NewEventJobRepository(db, tenantKey)
+}
@@ -0,0 +1,71 @@
+// Package impl 按数据库驱动选择仓库实现
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/database"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/repository/impl/mysql"
+	"MikoNews/internal/repository/impl/postgres"
+	"MikoNews/internal/repository/impl/sqlite"
+)
+
+// NewArticleRepository 创建 db 所用驱动对应的 ArticleRepository
+func NewArticleRepository(db *database.DB, tenantKey string) repository.ArticleRepository {
+	switch db.Driver {
+	case config.DatabaseDriverSQLite:
+		return sqlite.NewArticleRepository(db.DB, tenantKey)
+	case config.DatabaseDriverPostgres:
+		return postgres.NewArticleRepository(db.DB, tenantKey)
+	default:
+		return mysql.NewArticleRepository(db.DB, tenantKey)
+	}
+}
+
+// NewUserRepository 创建 db 所用驱动对应的 UserRepository
+func NewUserRepository(db *database.DB, tenantKey string) repository.UserRepository {
+	switch db.Driver {
+	case config.DatabaseDriverSQLite:
+		return sqlite.NewUserRepository(db.DB, tenantKey)
+	case config.DatabaseDriverPostgres:
+		return postgres.NewUserRepository(db.DB, tenantKey)
+	default:
+		return mysql.NewUserRepository(db.DB, tenantKey)
+	}
+}
+
+// NewRateLimitRepository 创建 db 所用驱动对应的 RateLimitRepository
+func NewRateLimitRepository(db *database.DB) repository.RateLimitRepository {
+	switch db.Driver {
+	case config.DatabaseDriverSQLite:
+		return sqlite.NewRateLimitRepository(db.DB)
+	case config.DatabaseDriverPostgres:
+		return postgres.NewRateLimitRepository(db.DB)
+	default:
+		return mysql.NewRateLimitRepository(db.DB)
+	}
+}
+
+// NewWebhookRepository 创建 db 所用驱动对应的 WebhookRepository
+func NewWebhookRepository(db *database.DB) repository.WebhookRepository {
+	switch db.Driver {
+	case config.DatabaseDriverSQLite:
+		return sqlite.NewWebhookRepository(db.DB)
+	case config.DatabaseDriverPostgres:
+		return postgres.NewWebhookRepository(db.DB)
+	default:
+		return mysql.NewWebhookRepository(db.DB)
+	}
+}
+
+// NewEventJobRepository 创建 db 所用驱动对应的 EventJobRepository
+func NewEventJobRepository(db *database.DB, tenantKey string) repository.EventJobRepository {
+	switch db.Driver {
+	case config.DatabaseDriverSQLite:
+		return sqlite.NewEventJobRepository(db.DB, tenantKey)
+	case config.DatabaseDriverPostgres:
+		return postgres.NewEventJobRepository(db.DB, tenantKey)
+	default:
+		return mysql.NewEventJobRepository(db.DB, tenantKey)
+	}
+}
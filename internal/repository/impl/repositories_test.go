@@ -0,0 +1,87 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/database"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/repository/repositorytest"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	logDir, err := os.MkdirTemp("", "miko-repository-test")
+	if err != nil {
+		panic(err)
+	}
+	logger.InitLogger(filepath.Join(logDir, "test.log"), "warn")
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+// TestSQLiteRepositories 在临时的 SQLite 数据库上运行一致性测试
+func TestSQLiteRepositories(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) *repositorytest.Repositories {
+		return openRepositories(t, &config.DatabaseConfig{
+			Driver: config.DatabaseDriverSQLite,
+			Path:   filepath.Join(t.TempDir(), "miko_news.db"),
+		})
+	})
+}
+
+// TestPostgresRepositories 需要通过 MIKO_TEST_POSTGRES_DSN 指定一个可随意清空的 PostgreSQL 数据库
+func TestPostgresRepositories(t *testing.T) {
+	dsn := os.Getenv("MIKO_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("未设置 MIKO_TEST_POSTGRES_DSN，跳过 PostgreSQL 测试")
+	}
+	repositorytest.Run(t, func(t *testing.T) *repositorytest.Repositories {
+		return openRepositories(t, &config.DatabaseConfig{Driver: config.DatabaseDriverPostgres, DSN: dsn, MaxOpenConns: 5})
+	})
+}
+
+// TestMySQLRepositories 需要通过 MIKO_TEST_MYSQL_DSN 指定一个可随意清空的 MySQL 数据库，DSN 需包含 parseTime=True
+func TestMySQLRepositories(t *testing.T) {
+	dsn := os.Getenv("MIKO_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("未设置 MIKO_TEST_MYSQL_DSN，跳过 MySQL 测试")
+	}
+	repositorytest.Run(t, func(t *testing.T) *repositorytest.Repositories {
+		return openRepositories(t, &config.DatabaseConfig{Driver: config.DatabaseDriverMySQL, DSN: dsn, MaxOpenConns: 5})
+	})
+}
+
+// openRepositories 连接数据库、回滚并重新执行全部迁移，然后按驱动创建仓库
+func openRepositories(t *testing.T, conf *config.DatabaseConfig) *repositorytest.Repositories {
+	t.Helper()
+	db, err := database.New(conf)
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("加载迁移脚本失败: %v", err)
+	}
+	if _, err := migrator.Down(context.Background(), 1<<20); err != nil {
+		t.Fatalf("回滚迁移失败: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+
+	return &repositorytest.Repositories{
+		NewArticle: func(tenantKey string) repository.ArticleRepository { return NewArticleRepository(db, tenantKey) },
+		NewUser:    func(tenantKey string) repository.UserRepository { return NewUserRepository(db, tenantKey) },
+		RateLimit:  NewRateLimitRepository(db),
+		Webhook:    NewWebhookRepository(db),
+		NewEventJob: func(tenantKey string) repository.EventJobRepository {
+			return NewEventJobRepository(db, tenantKey)
+		},
+	}
+}
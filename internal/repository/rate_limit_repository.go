@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitRepository 定义限流计数的存储接口
+// 多实例部署时使用数据库实现共享计数，单机部署和测试可使用内存实现
+type RateLimitRepository interface {
+	// Count 返回 key 在 windowStart 开始的窗口内的计数，没有记录时返回 0
+	Count(ctx context.Context, key string, windowStart time.Time) (int64, error)
+
+	// Increment 原子地将 key 在 windowStart 开始的窗口内的计数加一并返回加一后的计数，expiresAt 之后该计数可被清理。
+	// 并发调用（包括多个实例共享数据库时）各自得到不同的计数，调用方据此判断是否超出配额
+	Increment(ctx context.Context, key string, windowStart, expiresAt time.Time) (int64, error)
+
+	// Decrement 将 key 在 windowStart 开始的窗口内的计数减一，用于归还未使用的配额，计数不会小于 0
+	Decrement(ctx context.Context, key string, windowStart time.Time) error
+
+	// DeleteExpired 清理 before 之前已过期的计数
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
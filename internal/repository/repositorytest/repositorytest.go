@@ -0,0 +1,821 @@
+// Package repositorytest 提供仓库实现的一致性测试，各数据库驱动的实现都应通过同一套用例
+package repositorytest
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Repositories 是基于同一个空数据库创建的一组仓库
+type Repositories struct {
+	NewArticle  func(tenantKey string) repository.ArticleRepository
+	NewUser     func(tenantKey string) repository.UserRepository
+	RateLimit   repository.RateLimitRepository
+	Webhook     repository.WebhookRepository
+	NewEventJob func(tenantKey string) repository.EventJobRepository
+}
+
+// Run 运行全部一致性测试，setup 需要为每个子测试准备一个已建表的空数据库
+func Run(t *testing.T, setup func(t *testing.T) *Repositories) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repos *Repositories)
+	}{
+		{"ArticleCreateAndFind", testArticleCreateAndFind},
+		{"ArticleTenantIsolation", testArticleTenantIsolation},
+		{"ArticleUpdateStatus", testArticleUpdateStatus},
+		{"ArticleUpdateDocToken", testArticleUpdateDocToken},
+		{"ArticleFindBySourceMessageID", testArticleFindBySourceMessageID},
+		{"ArticleIncrementForwardCount", testArticleIncrementForwardCount},
+		{"ArticleFindAfter", testArticleFindAfter},
+		{"ArticleFindLatest", testArticleFindLatest},
+		{"ArticleSearch", testArticleSearch},
+		{"ArticleFindByFingerprints", testArticleFindByFingerprints},
+		{"ArticleFindSimHashCandidates", testArticleFindSimHashCandidates},
+		{"ArticleAddAuthors", testArticleAddAuthors},
+		{"ArticleUpdateAuthorName", testArticleUpdateAuthorName},
+		{"ArticleStats", testArticleStats},
+		{"User", testUser},
+		{"RateLimit", testRateLimit},
+		{"WebhookEndpoints", testWebhookEndpoints},
+		{"WebhookDeliveries", testWebhookDeliveries},
+		{"EventJobs", testEventJobs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, setup(t))
+		})
+	}
+}
+
+// baseTime 是测试数据使用的基准时间，取整到秒以兼容 MySQL TIMESTAMP 的精度
+var baseTime = time.Date(2025, 3, 1, 10, 0, 0, 0, time.Local)
+
+// articleOption 用于定制测试文章
+type articleOption func(*model.Article)
+
+func withStatus(status string) articleOption {
+	return func(a *model.Article) { a.Status = status }
+}
+
+func withAuthor(openID, name string) articleOption {
+	return func(a *model.Article) { a.AuthorID, a.AuthorName = openID, name }
+}
+
+func withContent(content string) articleOption {
+	return func(a *model.Article) { a.Content = content }
+}
+
+func withCreatedAt(at time.Time) articleOption {
+	return func(a *model.Article) { a.CreatedAt, a.UpdatedAt = at, at }
+}
+
+func anonymous() articleOption {
+	return func(a *model.Article) { a.Anonymous, a.AuthorName = true, model.AnonymousAuthorName }
+}
+
+// createArticle 保存一篇已发布的测试文章
+func createArticle(t *testing.T, repo repository.ArticleRepository, title string, opts ...articleOption) *model.Article {
+	t.Helper()
+	article := &model.Article{
+		Title:      title,
+		Content:    fmt.Sprintf("这是 %s 的测试内容。", title),
+		AuthorID:   "ou_author",
+		AuthorName: "测试用户",
+		Source:     model.ArticleSourceFeishu,
+		Status:     model.ArticleStatusPublished,
+		CreatedAt:  baseTime,
+		UpdatedAt:  baseTime,
+	}
+	for _, opt := range opts {
+		opt(article)
+	}
+	if err := repo.Create(context.Background(), article); err != nil {
+		t.Fatalf("创建文章失败 (Title: %s): %v", title, err)
+	}
+	if article.ID <= 0 {
+		t.Fatalf("创建文章后返回的ID无效: %d", article.ID)
+	}
+	return article
+}
+
+// titles 返回文章标题列表，便于比较结果
+func titles(articles []*model.Article) []string {
+	result := make([]string, len(articles))
+	for i, article := range articles {
+		result[i] = article.Title
+	}
+	return result
+}
+
+// assertTitles 校验文章标题及顺序
+func assertTitles(t *testing.T, articles []*model.Article, want ...string) {
+	t.Helper()
+	got := titles(articles)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("文章列表不符，期望: %v, 实际: %v", want, got)
+	}
+}
+
+// assertNotFound 校验返回 gorm.ErrRecordNotFound
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("期望返回 gorm.ErrRecordNotFound，实际返回: %v", err)
+	}
+}
+
+func testArticleCreateAndFind(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	created := createArticle(t, repo, "Test Article CRUD", func(a *model.Article) {
+		a.Authors = []*model.ArticleAuthor{
+			{OpenID: "ou_author", Name: "测试用户", Role: model.AuthorRoleAuthor},
+			{OpenID: "ou_co", Name: "合著者", Role: model.AuthorRoleCoAuthor},
+		}
+	})
+
+	article, err := repo.FindByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("按 ID 读取文章失败 (ID: %d): %v", created.ID, err)
+	}
+	if article.Title != created.Title || article.Content != created.Content {
+		t.Errorf("文章内容不匹配，期望: %s/%s, 实际: %s/%s", created.Title, created.Content, article.Title, article.Content)
+	}
+	if article.Status != model.ArticleStatusPublished || article.Source != model.ArticleSourceFeishu {
+		t.Errorf("文章状态或来源不匹配: %s/%s", article.Status, article.Source)
+	}
+	if !article.CreatedAt.Equal(baseTime) {
+		t.Errorf("创建时间不匹配，期望: %v, 实际: %v", baseTime, article.CreatedAt)
+	}
+	if len(article.Authors) != 2 {
+		t.Fatalf("期望加载 2 个署名，实际: %d", len(article.Authors))
+	}
+
+	_, err = repo.FindByID(ctx, created.ID+1000)
+	assertNotFound(t, err)
+}
+
+func testArticleTenantIsolation(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	tenantA, tenantB := repos.NewArticle("tenant_a"), repos.NewArticle("tenant_b")
+	article := createArticle(t, tenantA, "Tenant A")
+
+	if article.TenantKey != "tenant_a" {
+		t.Errorf("创建时应写入租户标识，实际: %q", article.TenantKey)
+	}
+	_, err := tenantB.FindByID(ctx, article.ID)
+	assertNotFound(t, err)
+	assertNotFound(t, tenantB.UpdateStatus(ctx, article.ID, model.ArticleStatusPublished, model.ArticleStatusWithdrawn))
+
+	latest, err := tenantB.FindLatest(ctx, 10)
+	if err != nil {
+		t.Fatalf("FindLatest 失败: %v", err)
+	}
+	assertTitles(t, latest)
+}
+
+func testArticleUpdateStatus(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	article := createArticle(t, repo, "Pending", withStatus(model.ArticleStatusPending))
+
+	if err := repo.UpdateStatus(ctx, article.ID, model.ArticleStatusPending, model.ArticleStatusPublished); err != nil {
+		t.Fatalf("更新状态失败: %v", err)
+	}
+	// 状态已不是 pending，重复审核应视为不存在
+	assertNotFound(t, repo.UpdateStatus(ctx, article.ID, model.ArticleStatusPending, model.ArticleStatusRejected))
+
+	found, err := repo.FindByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("读取文章失败: %v", err)
+	}
+	if found.Status != model.ArticleStatusPublished {
+		t.Errorf("期望状态为 published，实际: %s", found.Status)
+	}
+}
+
+func testArticleUpdateDocToken(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	article := createArticle(t, repo, "Archived")
+
+	if err := repo.UpdateDocToken(ctx, article.ID, "doxcnToken"); err != nil {
+		t.Fatalf("保存文档标识失败: %v", err)
+	}
+	found, err := repo.FindByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("读取文章失败: %v", err)
+	}
+	if found.DocToken != "doxcnToken" {
+		t.Errorf("期望文档标识为 doxcnToken，实际: %q", found.DocToken)
+	}
+	assertNotFound(t, repo.UpdateDocToken(ctx, article.ID+1000, "missing"))
+}
+
+func testArticleFindBySourceMessageID(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	withMessage := func(a *model.Article) { a.SourceMessageID = "om_imported" }
+	article := createArticle(t, repo, "Imported", withMessage, withStatus(model.ArticleStatusWithdrawn))
+	createArticle(t, repos.NewArticle("other"), "Other tenant", withMessage)
+	createArticle(t, repo, "Without message")
+	createArticle(t, repo, "Another without message") // 空的来源消息ID不参与唯一约束
+
+	duplicate := &model.Article{Title: "Duplicate", Content: "重复导入", SourceMessageID: "om_imported", CreatedAt: baseTime, UpdatedAt: baseTime}
+	if err := repo.Create(ctx, duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("同一租户内重复的来源消息ID应返回 gorm.ErrDuplicatedKey，实际: %v", err)
+	}
+
+	found, err := repo.FindBySourceMessageID(ctx, "om_imported")
+	if err != nil {
+		t.Fatalf("按来源消息ID查找失败: %v", err)
+	}
+	if found.ID != article.ID {
+		t.Errorf("期望找到文章 %d，实际: %d", article.ID, found.ID)
+	}
+	_, err = repo.FindBySourceMessageID(ctx, "om_missing")
+	assertNotFound(t, err)
+	_, err = repo.FindBySourceMessageID(ctx, "")
+	assertNotFound(t, err)
+}
+
+func testArticleIncrementForwardCount(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	article := createArticle(t, repo, "Forwarded")
+
+	for _, n := range []int{2, 3} {
+		if err := repo.IncrementForwardCount(ctx, article.ID, n); err != nil {
+			t.Fatalf("增加转发次数失败: %v", err)
+		}
+	}
+	found, err := repo.FindByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("读取文章失败: %v", err)
+	}
+	if found.ForwardCount != 5 {
+		t.Errorf("期望转发次数为 5，实际: %d", found.ForwardCount)
+	}
+	assertNotFound(t, repo.IncrementForwardCount(ctx, article.ID+1000, 1))
+}
+
+func testArticleFindAfter(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	first := createArticle(t, repo, "First")
+	createArticle(t, repo, "Second", withStatus(model.ArticleStatusRejected))
+	createArticle(t, repo, "Third", withStatus(model.ArticleStatusPending))
+	createArticle(t, repos.NewArticle("other"), "Other Tenant")
+
+	page, err := repo.FindAfter(ctx, 0, 2)
+	if err != nil {
+		t.Fatalf("FindAfter 失败: %v", err)
+	}
+	assertTitles(t, page, "First", "Second")
+
+	page, err = repo.FindAfter(ctx, page[len(page)-1].ID, 2)
+	if err != nil {
+		t.Fatalf("FindAfter 失败: %v", err)
+	}
+	assertTitles(t, page, "Third")
+
+	page, err = repo.FindAfter(ctx, first.ID, 10)
+	if err != nil {
+		t.Fatalf("FindAfter 失败: %v", err)
+	}
+	assertTitles(t, page, "Second", "Third")
+}
+
+func testArticleFindLatest(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	createArticle(t, repo, "Old", withCreatedAt(baseTime))
+	createArticle(t, repo, "New", withCreatedAt(baseTime.Add(time.Hour)))
+	createArticle(t, repo, "Same Time Later ID", withCreatedAt(baseTime))
+	createArticle(t, repo, "Pending", withStatus(model.ArticleStatusPending), withCreatedAt(baseTime.Add(2*time.Hour)))
+	createArticle(t, repo, "Withdrawn", withStatus(model.ArticleStatusWithdrawn), withCreatedAt(baseTime.Add(2*time.Hour)))
+
+	latest, err := repo.FindLatest(ctx, 10)
+	if err != nil {
+		t.Fatalf("FindLatest 失败: %v", err)
+	}
+	assertTitles(t, latest, "New", "Same Time Later ID", "Old")
+
+	latest, err = repo.FindLatest(ctx, 1)
+	if err != nil {
+		t.Fatalf("FindLatest 失败: %v", err)
+	}
+	assertTitles(t, latest, "New")
+}
+
+func testArticleSearch(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	createArticle(t, repo, "GoLang 1.24 发布", withContent("新版本带来了泛型类型别名"))
+	createArticle(t, repo, "折扣", withContent("全场 100% 返现"), withCreatedAt(baseTime.Add(time.Minute)))
+	createArticle(t, repo, "数字", withContent("共有 1000 人参加"))
+	createArticle(t, repo, "file_name", withContent("下划线"))
+	createArticle(t, repo, "filename", withContent("没有下划线"))
+	createArticle(t, repo, "golang 草稿", withStatus(model.ArticleStatusPending))
+
+	tests := []struct {
+		keyword string
+		want    []string
+	}{
+		{"golang", []string{"GoLang 1.24 发布"}}, // 不区分大小写，且不含未发布文章
+		{"泛型", []string{"GoLang 1.24 发布"}},     // 同时搜索正文
+		{"100%", []string{"折扣"}},               // % 按字面匹配
+		{"file_", []string{"file_name"}},       // _ 按字面匹配
+		{"不存在", nil},
+	}
+	for _, tt := range tests {
+		result, err := repo.Search(ctx, tt.keyword, 10)
+		if err != nil {
+			t.Fatalf("搜索 %q 失败: %v", tt.keyword, err)
+		}
+		if fmt.Sprint(titles(result)) != fmt.Sprint(tt.want) {
+			t.Errorf("搜索 %q，期望: %v, 实际: %v", tt.keyword, tt.want, titles(result))
+		}
+	}
+}
+
+func testArticleFindByFingerprints(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	fingerprints := func(values ...string) articleOption {
+		return func(a *model.Article) {
+			for _, value := range values {
+				a.Fingerprints = append(a.Fingerprints, &model.ArticleFingerprint{Kind: model.FingerprintKindSimHash, Value: value})
+			}
+		}
+	}
+	createArticle(t, repo, "Two Matches", fingerprints("0:a", "1:b", "2:c"))
+	createArticle(t, repo, "One Match", fingerprints("0:a", "1:x"), withCreatedAt(baseTime.Add(-time.Hour)))
+	createArticle(t, repo, "Rejected", fingerprints("0:a", "1:b"), withStatus(model.ArticleStatusRejected))
+	createArticle(t, repo, "URL", func(a *model.Article) {
+		a.Fingerprints = []*model.ArticleFingerprint{{Kind: model.FingerprintKindURL, Value: "0:a"}}
+	})
+
+	matched, err := repo.FindByFingerprints(ctx, model.FingerprintKindSimHash, []string{"0:a", "1:b"}, 2, 10)
+	if err != nil {
+		t.Fatalf("FindByFingerprints 失败: %v", err)
+	}
+	assertTitles(t, matched, "Two Matches")
+
+	matched, err = repo.FindByFingerprints(ctx, model.FingerprintKindSimHash, []string{"0:a"}, 1, 10)
+	if err != nil {
+		t.Fatalf("FindByFingerprints 失败: %v", err)
+	}
+	assertTitles(t, matched, "One Match", "Two Matches")
+
+	matched, err = repo.FindByFingerprints(ctx, model.FingerprintKindSimHash, nil, 1, 10)
+	if err != nil || len(matched) != 0 {
+		t.Errorf("没有指纹时应返回空结果，实际: %v, %v", titles(matched), err)
+	}
+}
+
+func testArticleFindSimHashCandidates(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	bands := func(simhash int64, values ...string) articleOption {
+		return func(a *model.Article) {
+			a.SimHash = simhash
+			for _, value := range values {
+				a.Fingerprints = append(a.Fingerprints, &model.ArticleFingerprint{Kind: model.FingerprintKindSimHash, Value: value})
+			}
+		}
+	}
+	// 大量偶然命中 2 段的旧文章不应挤掉较新的候选
+	for i := 0; i < 25; i++ {
+		createArticle(t, repo, fmt.Sprintf("Old %d", i), bands(int64(i), "0:a", "1:b"), withCreatedAt(baseTime.Add(-time.Duration(25-i)*time.Hour)))
+	}
+	newest := createArticle(t, repo, "Newest", bands(-42, "0:a", "1:b", "2:c"))
+	createArticle(t, repo, "One Band", bands(1, "0:a"))
+	createArticle(t, repo, "Rejected", bands(1, "0:a", "1:b"), withStatus(model.ArticleStatusRejected))
+	createArticle(t, repos.NewArticle("tenant_b"), "Other Tenant", bands(1, "0:a", "1:b"))
+
+	candidates, err := repo.FindSimHashCandidates(ctx, []string{"0:a", "1:b", "2:c"}, 2)
+	if err != nil {
+		t.Fatalf("FindSimHashCandidates 失败: %v", err)
+	}
+	if len(candidates) != 26 {
+		t.Fatalf("应返回全部 26 个候选，实际: %d", len(candidates))
+	}
+	last := candidates[len(candidates)-1]
+	if last.ID != newest.ID || last.SimHash != -42 || last.CreatedAt.IsZero() {
+		t.Errorf("候选应按创建时间正序并包含 SimHash: %+v", last)
+	}
+	if last.Title != "" || last.Content != "" {
+		t.Errorf("候选不应加载其他字段: %+v", last)
+	}
+
+	candidates, err = repo.FindSimHashCandidates(ctx, nil, 2)
+	if err != nil || len(candidates) != 0 {
+		t.Errorf("没有分段时应返回空结果，实际: %d, %v", len(candidates), err)
+	}
+}
+
+func testArticleAddAuthors(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	article := createArticle(t, repo, "Co-authored", func(a *model.Article) {
+		a.Authors = []*model.ArticleAuthor{{OpenID: "ou_author", Name: "测试用户", Role: model.AuthorRoleAuthor}}
+	})
+
+	added := []*model.ArticleAuthor{
+		{OpenID: "ou_author", Name: "测试用户", Role: model.AuthorRoleAuthor}, // 已存在，应被忽略
+		{OpenID: "ou_co", Name: "合著者", Role: model.AuthorRoleCoAuthor},
+	}
+	if err := repo.AddAuthors(ctx, article.ID, added); err != nil {
+		t.Fatalf("追加署名失败: %v", err)
+	}
+	found, err := repo.FindByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("读取文章失败: %v", err)
+	}
+	if len(found.Authors) != 2 {
+		t.Errorf("期望 2 个署名，实际: %d", len(found.Authors))
+	}
+
+	assertNotFound(t, repo.AddAuthors(ctx, article.ID+1000, []*model.ArticleAuthor{{OpenID: "ou_x", Role: model.AuthorRoleCoAuthor}}))
+	if err := repo.AddAuthors(ctx, article.ID, nil); err != nil {
+		t.Errorf("没有署名时不应出错: %v", err)
+	}
+}
+
+func testArticleUpdateAuthorName(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	named := createArticle(t, repo, "Named", func(a *model.Article) {
+		a.Authors = []*model.ArticleAuthor{{OpenID: "ou_author", Name: "测试用户", Role: model.AuthorRoleAuthor}}
+	})
+	anon := createArticle(t, repo, "Anonymous", anonymous())
+	other := createArticle(t, repos.NewArticle("other"), "Other Tenant")
+
+	if err := repo.UpdateAuthorName(ctx, "ou_author", "新名字"); err != nil {
+		t.Fatalf("更新作者名失败: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, named.ID)
+	if err != nil {
+		t.Fatalf("读取文章失败: %v", err)
+	}
+	if found.AuthorName != "新名字" || len(found.Authors) != 1 || found.Authors[0].Name != "新名字" {
+		t.Errorf("署名未更新: %s", found.AuthorName)
+	}
+	if found, _ = repo.FindByID(ctx, anon.ID); found.AuthorName != model.AnonymousAuthorName {
+		t.Errorf("匿名投稿不应更新作者名，实际: %s", found.AuthorName)
+	}
+	if found, _ = repos.NewArticle("other").FindByID(ctx, other.ID); found.AuthorName != "测试用户" {
+		t.Errorf("其他租户的文章不应更新作者名，实际: %s", found.AuthorName)
+	}
+}
+
+func testArticleStats(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewArticle("")
+	coAuthor := func(a *model.Article) {
+		a.Authors = []*model.ArticleAuthor{{OpenID: "ou_co", Name: "合著者", Role: model.AuthorRoleCoAuthor}}
+	}
+	createArticle(t, repo, "A1", withAuthor("ou_a", "甲"), coAuthor)
+	createArticle(t, repo, "A2", withAuthor("ou_a", "甲"), withCreatedAt(baseTime.Add(48*time.Hour)))
+	createArticle(t, repo, "B1", withAuthor("ou_b", "乙"), coAuthor, withCreatedAt(baseTime.Add(48*time.Hour)))
+	createArticle(t, repo, "Anon", withAuthor("ou_c", "丙"), anonymous())
+	createArticle(t, repo, "Pending", withAuthor("ou_d", "丁"), withStatus(model.ArticleStatusPending))
+
+	stats, err := repo.Stats(ctx, baseTime.Add(24*time.Hour), 1)
+	if err != nil {
+		t.Fatalf("Stats 失败: %v", err)
+	}
+	if stats.TotalArticles != 4 || stats.TotalAuthors != 2 || stats.RecentArticles != 2 {
+		t.Errorf("统计不符，期望 4/2/2，实际: %d/%d/%d", stats.TotalArticles, stats.TotalAuthors, stats.RecentArticles)
+	}
+	if len(stats.TopAuthors) != 1 || stats.TopAuthors[0].AuthorID != "ou_a" || stats.TopAuthors[0].ArticleCount != 2 {
+		t.Errorf("投稿榜不符: %+v", stats.TopAuthors)
+	}
+	if len(stats.TopCoAuthors) != 1 || stats.TopCoAuthors[0].AuthorID != "ou_co" || stats.TopCoAuthors[0].ArticleCount != 2 {
+		t.Errorf("合著榜不符: %+v", stats.TopCoAuthors)
+	}
+}
+
+func testUser(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewUser("tenant_a")
+	user := &model.User{OpenID: "ou_user", Name: "旧名字", Status: model.UserStatusActive, SyncedAt: baseTime, CreatedAt: baseTime, UpdatedAt: baseTime}
+	user.SetDepartments([]string{"od_1", "od_2"})
+	if err := repo.Upsert(ctx, user); err != nil {
+		t.Fatalf("新增用户失败: %v", err)
+	}
+	user.Name = "新名字"
+	if err := repo.Upsert(ctx, user); err != nil {
+		t.Fatalf("更新用户失败: %v", err)
+	}
+
+	found, err := repo.FindByOpenID(ctx, "ou_user")
+	if err != nil {
+		t.Fatalf("读取用户失败: %v", err)
+	}
+	if found.Name != "新名字" || found.TenantKey != "tenant_a" || len(found.Departments()) != 2 {
+		t.Errorf("用户信息不符: %+v", found)
+	}
+
+	if err := repo.UpdateStatus(ctx, "ou_user", model.UserStatusResigned); err != nil {
+		t.Fatalf("更新用户状态失败: %v", err)
+	}
+	if found, _ = repo.FindByOpenID(ctx, "ou_user"); found.Status != model.UserStatusResigned {
+		t.Errorf("期望用户状态为 resigned，实际: %s", found.Status)
+	}
+
+	_, err = repos.NewUser("tenant_b").FindByOpenID(ctx, "ou_user")
+	assertNotFound(t, err)
+}
+
+func testRateLimit(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.RateLimit
+	window, nextWindow := baseTime, baseTime.Add(time.Hour)
+
+	count, err := repo.Count(ctx, "submission:hour:ou_a", window)
+	if err != nil || count != 0 {
+		t.Fatalf("没有记录时应返回 0，实际: %d, %v", count, err)
+	}
+	for i := 1; i <= 3; i++ {
+		count, err := repo.Increment(ctx, "submission:hour:ou_a", window, nextWindow)
+		if err != nil {
+			t.Fatalf("计数加一失败: %v", err)
+		}
+		if count != int64(i) {
+			t.Errorf("第 %d 次加一后应返回 %d，实际: %d", i, i, count)
+		}
+	}
+	if _, err := repo.Increment(ctx, "submission:hour:ou_a", nextWindow, nextWindow.Add(time.Hour)); err != nil {
+		t.Fatalf("计数加一失败: %v", err)
+	}
+
+	if count, _ = repo.Count(ctx, "submission:hour:ou_a", window); count != 3 {
+		t.Errorf("期望窗口内计数为 3，实际: %d", count)
+	}
+
+	// 减一归还配额，计数不会小于 0
+	if err := repo.Decrement(ctx, "submission:hour:ou_a", window); err != nil {
+		t.Fatalf("计数减一失败: %v", err)
+	}
+	if count, _ = repo.Count(ctx, "submission:hour:ou_a", window); count != 2 {
+		t.Errorf("减一后期望计数为 2，实际: %d", count)
+	}
+	if err := repo.Decrement(ctx, "submission:hour:ou_b", window); err != nil {
+		t.Fatalf("不存在的计数减一不应报错: %v", err)
+	}
+	if count, _ = repo.Count(ctx, "submission:hour:ou_b", window); count != 0 {
+		t.Errorf("不存在的计数减一后应为 0，实际: %d", count)
+	}
+	if _, err := repo.Increment(ctx, "submission:hour:ou_a", window, nextWindow); err != nil {
+		t.Fatalf("计数加一失败: %v", err)
+	}
+
+	// 并发加一时每次调用得到不同的计数
+	const concurrent = 8
+	seen := make(chan int64, concurrent)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count, err := repo.Increment(ctx, "submission:day:ou_c", window, nextWindow)
+			if err != nil {
+				t.Errorf("并发计数加一失败: %v", err)
+			}
+			seen <- count
+		}()
+	}
+	wg.Wait()
+	close(seen)
+	counts := make(map[int64]bool)
+	for count := range seen {
+		counts[count] = true
+	}
+	for i := int64(1); i <= concurrent; i++ {
+		if !counts[i] {
+			t.Errorf("并发加一应依次得到 1..%d，实际: %v", concurrent, counts)
+			break
+		}
+	}
+	if count, _ = repo.Count(ctx, "submission:hour:ou_b", window); count != 0 {
+		t.Errorf("其他键的计数应为 0，实际: %d", count)
+	}
+
+	if err := repo.DeleteExpired(ctx, nextWindow.Add(time.Minute)); err != nil {
+		t.Fatalf("清理过期计数失败: %v", err)
+	}
+	if count, _ = repo.Count(ctx, "submission:hour:ou_a", window); count != 0 {
+		t.Errorf("过期计数应被清理，实际: %d", count)
+	}
+	if count, _ = repo.Count(ctx, "submission:hour:ou_a", nextWindow); count != 1 {
+		t.Errorf("未过期的计数不应被清理，实际: %d", count)
+	}
+}
+
+func testWebhookEndpoints(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.Webhook
+	configured := &model.WebhookEndpoint{Name: "config", URL: "https://a.example.com", Secret: "s1", Source: model.WebhookSourceConfig, Enabled: true}
+	if err := repo.UpsertEndpointByName(ctx, configured); err != nil {
+		t.Fatalf("同步端点失败: %v", err)
+	}
+	updated := &model.WebhookEndpoint{Name: "config", URL: "https://b.example.com", Secret: "s2", Events: "article.published", TenantKey: "tenant_a", Source: model.WebhookSourceConfig, Enabled: true}
+	if err := repo.UpsertEndpointByName(ctx, updated); err != nil {
+		t.Fatalf("更新端点失败: %v", err)
+	}
+	api := &model.WebhookEndpoint{Name: "api", URL: "https://c.example.com", Secret: "s3", Source: model.WebhookSourceAPI, Enabled: true}
+	if err := repo.CreateEndpoint(ctx, api); err != nil {
+		t.Fatalf("新增端点失败: %v", err)
+	}
+	duplicate := &model.WebhookEndpoint{Name: "api", URL: "https://d.example.com", Secret: "s4", Source: model.WebhookSourceAPI, Enabled: true}
+	if err := repo.CreateEndpoint(ctx, duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("重名端点应返回 gorm.ErrDuplicatedKey，实际: %v", err)
+	}
+
+	endpoints, err := repo.ListEndpoints(ctx)
+	if err != nil {
+		t.Fatalf("列出端点失败: %v", err)
+	}
+	if len(endpoints) != 2 || endpoints[0].Name != "config" || endpoints[1].Name != "api" {
+		t.Fatalf("端点列表不符: %+v", endpoints)
+	}
+	if e := endpoints[0]; e.URL != "https://b.example.com" || e.Secret != "s2" || e.Events != "article.published" || e.TenantKey != "tenant_a" {
+		t.Errorf("同名端点应被更新: %+v", e)
+	}
+
+	found, err := repo.FindEndpoint(ctx, api.ID)
+	if err != nil || found.Name != "api" {
+		t.Fatalf("读取端点失败: %+v, %v", found, err)
+	}
+	if err := repo.DeleteEndpoint(ctx, api.ID); err != nil {
+		t.Fatalf("删除端点失败: %v", err)
+	}
+	assertNotFound(t, repo.DeleteEndpoint(ctx, api.ID))
+	_, err = repo.FindEndpoint(ctx, api.ID)
+	assertNotFound(t, err)
+}
+
+func testWebhookDeliveries(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.Webhook
+	endpoint := &model.WebhookEndpoint{Name: "deliveries", URL: "https://a.example.com", Secret: "s", Source: model.WebhookSourceAPI, Enabled: true}
+	if err := repo.CreateEndpoint(ctx, endpoint); err != nil {
+		t.Fatalf("新增端点失败: %v", err)
+	}
+
+	newDelivery := func(eventID string, nextAttemptAt time.Time) *model.WebhookDelivery {
+		return &model.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       eventID,
+			Event:         "article.published",
+			Payload:       `{"event":"article.published"}`,
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: nextAttemptAt,
+		}
+	}
+	deliveries := []*model.WebhookDelivery{
+		newDelivery("due-late", baseTime.Add(-time.Minute)),
+		newDelivery("due-early", baseTime.Add(-time.Hour)),
+		newDelivery("future", baseTime.Add(time.Hour)),
+	}
+	if err := repo.CreateDeliveries(ctx, deliveries); err != nil {
+		t.Fatalf("新增投递记录失败: %v", err)
+	}
+	for _, delivery := range deliveries {
+		if delivery.ID <= 0 {
+			t.Fatalf("批量新增后应回填ID: %+v", delivery)
+		}
+	}
+
+	due, err := repo.ListDueDeliveries(ctx, baseTime, 10)
+	if err != nil {
+		t.Fatalf("列出到期投递失败: %v", err)
+	}
+	if len(due) != 2 || due[0].EventID != "due-early" || due[1].EventID != "due-late" {
+		t.Fatalf("到期投递不符: %+v", due)
+	}
+
+	// 同一条记录只能被抢占一次
+	stale := *due[0]
+	claimed, err := repo.ClaimDelivery(ctx, due[0], baseTime.Add(time.Minute))
+	if err != nil || !claimed {
+		t.Fatalf("抢占投递记录失败: %v, %v", claimed, err)
+	}
+	if claimed, err = repo.ClaimDelivery(ctx, &stale, baseTime.Add(time.Minute)); err != nil || claimed {
+		t.Errorf("重复抢占应失败: %v, %v", claimed, err)
+	}
+
+	deliveredAt := baseTime
+	due[0].Status = model.WebhookDeliverySucceeded
+	due[0].Attempts = 1
+	due[0].ResponseStatus = 200
+	due[0].DeliveredAt = &deliveredAt
+	if err := repo.UpdateDelivery(ctx, due[0]); err != nil {
+		t.Fatalf("保存投递结果失败: %v", err)
+	}
+	found, err := repo.FindDelivery(ctx, due[0].ID)
+	if err != nil {
+		t.Fatalf("读取投递记录失败: %v", err)
+	}
+	if found.Status != model.WebhookDeliverySucceeded || found.ResponseStatus != 200 || found.DeliveredAt == nil || !found.DeliveredAt.Equal(deliveredAt) {
+		t.Errorf("投递结果不符: %+v", found)
+	}
+
+	listed, err := repo.ListDeliveries(ctx, endpoint.ID, 2)
+	if err != nil {
+		t.Fatalf("列出投递记录失败: %v", err)
+	}
+	if len(listed) != 2 || listed[0].EventID != "future" || listed[1].EventID != "due-early" {
+		t.Errorf("投递记录应按ID倒序返回: %+v", listed)
+	}
+}
+
+func testEventJobs(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	repo := repos.NewEventJob("tenant-a")
+
+	newJob := func(eventID string, nextAttemptAt time.Time) *model.EventJob {
+		return &model.EventJob{
+			EventID:       eventID,
+			EventType:     "im.message.receive_v1",
+			ChatID:        "oc_chat",
+			Payload:       `{"event":{}}`,
+			TraceParent:   "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01",
+			Status:        model.EventJobPending,
+			NextAttemptAt: nextAttemptAt,
+		}
+	}
+	for _, job := range []*model.EventJob{
+		newJob("ev-1", baseTime.Add(-time.Hour)),
+		newJob("ev-2", baseTime.Add(-time.Minute)),
+		newJob("ev-future", baseTime.Add(time.Hour)),
+	} {
+		created, err := repo.CreateJob(ctx, job)
+		if err != nil || !created || job.ID <= 0 {
+			t.Fatalf("新增任务失败: %v, %v, %+v", created, err, job)
+		}
+	}
+
+	// 重复推送的事件不再入队，其他租户可以使用相同的事件ID
+	if created, err := repo.CreateJob(ctx, newJob("ev-1", baseTime)); err != nil || created {
+		t.Errorf("重复的事件不应入队: %v, %v", created, err)
+	}
+	other := repos.NewEventJob("tenant-b")
+	if created, err := other.CreateJob(ctx, newJob("ev-1", baseTime)); err != nil || !created {
+		t.Errorf("其他租户的事件应入队: %v, %v", created, err)
+	}
+
+	due, err := repo.ListDueJobs(ctx, baseTime, 10)
+	if err != nil {
+		t.Fatalf("列出到期任务失败: %v", err)
+	}
+	if len(due) != 2 || due[0].EventID != "ev-1" || due[1].EventID != "ev-2" || due[0].TenantKey != "tenant-a" || due[0].TraceParent == "" {
+		t.Fatalf("到期任务不符: %+v", due)
+	}
+
+	// 同一任务只能被抢占一次，抢占时增加尝试次数
+	stale := *due[0]
+	claimed, err := repo.ClaimJob(ctx, due[0], baseTime.Add(time.Minute))
+	if err != nil || !claimed || due[0].Attempts != 1 {
+		t.Fatalf("抢占任务失败: %v, %v, %+v", claimed, err, due[0])
+	}
+	if claimed, err = repo.ClaimJob(ctx, &stale, baseTime.Add(time.Minute)); err != nil || claimed {
+		t.Errorf("重复抢占应失败: %v, %v", claimed, err)
+	}
+	if due, err = repo.ListDueJobs(ctx, baseTime, 10); err != nil || len(due) != 1 || due[0].EventID != "ev-2" {
+		t.Errorf("抢占后的任务在租约到期前不应再被列出: %+v, %v", due, err)
+	}
+
+	count, oldest, err := repo.CountPending(ctx)
+	if err != nil || count != 3 || oldest.IsZero() {
+		t.Errorf("等待处理的任务数不符: %d, %v, %v", count, oldest, err)
+	}
+
+	due[0].Status = model.EventJobSucceeded
+	if err := repo.UpdateJob(ctx, due[0]); err != nil {
+		t.Fatalf("保存任务结果失败: %v", err)
+	}
+	if count, _, err = repo.CountPending(ctx); err != nil || count != 2 {
+		t.Errorf("处理完成的任务不应计入等待数: %d, %v", count, err)
+	}
+
+	deleted, err := repo.DeleteFinishedJobs(ctx, time.Now().Add(time.Minute))
+	if err != nil || deleted != 1 {
+		t.Errorf("删除已处理任务不符: %d, %v", deleted, err)
+	}
+	if count, _, err = other.CountPending(ctx); err != nil || count != 1 {
+		t.Errorf("其他租户的任务不应受影响: %d, %v", count, err)
+	}
+}
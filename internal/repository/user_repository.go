@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"MikoNews/internal/model"
+	"context"
+)
+
+// UserRepository 定义本地用户目录的数据访问接口
+type UserRepository interface {
+	// Upsert 新增或更新一个用户
+	Upsert(ctx context.Context, user *model.User) error
+
+	// FindByOpenID 根据 OpenID 查找用户
+	FindByOpenID(ctx context.Context, openID string) (*model.User, error)
+
+	// UpdateStatus 更新用户状态
+	UpdateStatus(ctx context.Context, openID, status string) error
+}
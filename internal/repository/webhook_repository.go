@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"MikoNews/internal/model"
+	"context"
+	"time"
+)
+
+// WebhookRepository 定义 webhook 端点与投递记录的数据访问接口
+type WebhookRepository interface {
+	// ListEndpoints 返回所有 webhook 端点
+	ListEndpoints(ctx context.Context) ([]*model.WebhookEndpoint, error)
+
+	// FindEndpoint 根据ID查找端点
+	FindEndpoint(ctx context.Context, id int64) (*model.WebhookEndpoint, error)
+
+	// CreateEndpoint 新增一个端点
+	CreateEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error
+
+	// UpsertEndpointByName 按名称新增或更新端点，用于同步配置文件中的端点
+	UpsertEndpointByName(ctx context.Context, endpoint *model.WebhookEndpoint) error
+
+	// DeleteEndpoint 删除端点
+	DeleteEndpoint(ctx context.Context, id int64) error
+
+	// CreateDeliveries 批量新增投递记录
+	CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error
+
+	// FindDelivery 根据ID查找投递记录
+	FindDelivery(ctx context.Context, id int64) (*model.WebhookDelivery, error)
+
+	// UpdateDelivery 保存投递结果
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+
+	// ListDueDeliveries 返回 now 之前到期、等待投递的记录，按到期时间正序
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error)
+
+	// ClaimDelivery 将到期的投递记录的下次尝试时间推迟到 leaseUntil，返回是否抢占成功，避免多实例重复投递
+	ClaimDelivery(ctx context.Context, delivery *model.WebhookDelivery, leaseUntil time.Time) (bool, error)
+
+	// ListDeliveries 按创建时间倒序返回端点最近的 limit 条投递记录
+	ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]*model.WebhookDelivery, error)
+}
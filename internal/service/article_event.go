@@ -0,0 +1,38 @@
+package service
+
+import (
+	"MikoNews/internal/model"
+	"context"
+	"time"
+)
+
+// ArticleEventType 文章生命周期事件类型
+type ArticleEventType string
+
+// 文章生命周期事件
+const (
+	ArticleEventCreated   ArticleEventType = "article.created"   // 投稿已保存（含待审核）
+	ArticleEventApproved  ArticleEventType = "article.approved"  // 管理员审核通过
+	ArticleEventPublished ArticleEventType = "article.published" // 文章已发布（直接发布或审核通过）
+	ArticleEventRejected  ArticleEventType = "article.rejected"  // 管理员驳回待审核的投稿
+	ArticleEventWithdrawn ArticleEventType = "article.withdrawn" // 已发布的文章被撤回
+	ArticleEventForwarded ArticleEventType = "article.forwarded" // 文章卡片已转发到群聊，转发次数变化
+)
+
+// ArticleEventTypes 返回所有文章生命周期事件类型
+func ArticleEventTypes() []ArticleEventType {
+	return []ArticleEventType{ArticleEventCreated, ArticleEventApproved, ArticleEventPublished, ArticleEventRejected, ArticleEventWithdrawn, ArticleEventForwarded}
+}
+
+// ArticleEvent 描述一次文章状态变化
+type ArticleEvent struct {
+	Type       ArticleEventType
+	Article    *model.Article
+	OccurredAt time.Time
+}
+
+// ArticleEventListener 接收文章生命周期事件，例如投递 webhook、同步到外部系统
+// OnArticleEvent 在状态变更后同步调用，实现应尽快返回，耗时操作需自行异步处理；失败只应记录日志，不影响投稿流程
+type ArticleEventListener interface {
+	OnArticleEvent(ctx context.Context, event *ArticleEvent)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// ImportRecord is a historical post read from a JSON or CSV export.
+type ImportRecord struct {
+	MessageID  string    `json:"message_id"`  // source message ID; records whose message was already imported are skipped
+	AuthorID   string    `json:"author_id"`   // Feishu open_id of the author
+	AuthorName string    `json:"author_name"` // looked up in the user directory when empty
+	Title      string    `json:"title"`       // overrides the title parsed from the content when set
+	Content    string    `json:"content"`     // plain text content, used when RawContent is empty
+	RawContent string    `json:"raw_content"` // Feishu post JSON
+	CreatedAt  time.Time `json:"created_at"`  // original publish time, the import time when zero
+}
+
+// ChatHistoryQuery selects the messages of a Feishu chat to import.
+type ChatHistoryQuery struct {
+	ChatID string
+	Start  time.Time // zero imports from the beginning of the chat
+	End    time.Time // zero imports up to now
+
+	// IncludeText also imports plain text messages; by default only rich text (post) messages are imported,
+	// since short text messages in a group are mostly conversation rather than posts
+	IncludeText bool
+}
+
+// ImportResult counts the outcome of an import.
+type ImportResult struct {
+	Imported int // new articles saved
+	Skipped  int // messages already imported, or not posts by members
+	Failed   int // records that could not be parsed or saved
+}
+
+// ArticleImportService imports historical posts as published articles.
+// Posts are parsed with the same code as submissions, keep their original author and publish time,
+// and are deduplicated by their source message ID. Imported articles are never forwarded.
+type ArticleImportService interface {
+	// ImportChatHistory pages through the history of a chat and imports the posts sent by members.
+	// Replies, recalled messages and messages sent by bots are skipped.
+	ImportChatHistory(ctx context.Context, query *ChatHistoryQuery) (*ImportResult, error)
+
+	// ImportRecords imports records read from an export. A record that fails is counted and logged,
+	// and the import continues with the next one.
+	ImportRecords(ctx context.Context, records []*ImportRecord) (*ImportResult, error)
+}
@@ -3,15 +3,74 @@ package service
 import (
 	"MikoNews/internal/model"
 	"context"
+	"errors"
 )
 
+// ErrSubmissionSaved 表示投稿的来源消息已保存过，例如飞书重复推送的事件或重新处理的队列任务
+var ErrSubmissionSaved = errors.New("该消息已保存为文章")
+
+// Submission 描述一次待保存的投稿
+type Submission struct {
+	AuthorID   string // 作者飞书 OpenID
+	AuthorName string // 作者名字
+	CuratorID  string // 代为收录者的飞书 OpenID，作者本人投稿时为空
+	Anonymous  bool   // 是否匿名投稿，匿名时真实作者 OpenID 加密保存
+	Title      string // 文章标题
+	Content    string // 纯文本内容
+	RawContent string // 原始富文本 (post) JSON，审核通过后据此转发
+	Source     string // 投稿来源，为空时视为飞书
+	MessageID  string // 投稿对应的来源消息ID，导入历史消息时据此去重
+
+	// ReviewRequired 为 true 时文章保存为待审核状态，管理员通过后才会转发
+	ReviewRequired bool
+
+	// Credits 是投稿中署名的其他成员（合著者、来源），作者本人会自动署名
+	Credits []*model.ArticleAuthor
+}
+
 // ArticleService 定义文章业务逻辑接口
 type ArticleService interface {
 	// SaveSubmission 处理并保存用户通过飞书发送的投稿
-	// 参数包括消息的唯一ID、来源会话ID、作者ID、作者名、标题、纯文本内容、原始富文本内容(JSON)
-	// 返回创建的文章对象（如果成功）和错误
-	SaveSubmission(ctx context.Context, authorID, authorName, title string, textContent string) (*model.Article, error)
+	// 返回创建的文章对象（如果成功）和错误；来源消息已保存过时返回已有的文章和 ErrSubmissionSaved
+	SaveSubmission(ctx context.Context, submission *Submission) (*model.Article, error)
+
+	// ImportArticle 导入历史文章：保留文章原有的作者、状态和时间，计算署名和查重指纹后保存。
+	// 导入的文章不会转发，也不会通知监听者；来源消息ID已导入过时跳过并返回 false
+	ImportArticle(ctx context.Context, article *model.Article) (bool, error)
 
 	// FindArticleByID 根据ID查找文章 (如果需要此功能)
 	FindArticleByID(ctx context.Context, id int64) (*model.Article, error)
+
+	// FindPublishedArticle 根据ID查找已发布的文章，供公开接口使用；待审核、已驳回和已撤回的文章视为不存在
+	FindPublishedArticle(ctx context.Context, id int64) (*model.Article, error)
+
+	// RevealAuthor 返回文章真实作者的 OpenID，匿名投稿会解密后返回，仅供管理员使用
+	RevealAuthor(ctx context.Context, id int64) (string, error)
+
+	// ReviewArticle 审核待审核的文章，approve 为 true 时发布，否则驳回
+	ReviewArticle(ctx context.Context, id int64, approve bool) (*model.Article, error)
+
+	// WithdrawArticle 撤回已发布的文章，撤回后不再出现在列表和统计中
+	WithdrawArticle(ctx context.Context, id int64) (*model.Article, error)
+
+	// RecordDocToken 记录文章归档到飞书云文档或知识库后的标识
+	RecordDocToken(ctx context.Context, id int64, docToken string) error
+
+	// RecordForwards 记录文章卡片成功转发到 n 个群聊
+	RecordForwards(ctx context.Context, id int64, n int) error
+
+	// FindDuplicate 查找与投稿内容重复的已有文章（相同链接或近似正文），没有重复时返回 nil
+	FindDuplicate(ctx context.Context, content, rawContent string) (*model.Article, error)
+
+	// MergeCredits 将重复投稿合并到已有文章，为其追加署名
+	MergeCredits(ctx context.Context, id int64, credits []*model.ArticleAuthor) (*model.Article, error)
+
+	// ListLatestArticles 返回最新的 limit 篇文章
+	ListLatestArticles(ctx context.Context, limit int) ([]*model.Article, error)
+
+	// SearchArticles 按关键字搜索文章
+	SearchArticles(ctx context.Context, keyword string, limit int) ([]*model.Article, error)
+
+	// GetArticleStats 返回投稿统计信息
+	GetArticleStats(ctx context.Context) (*model.ArticleStats, error)
 }
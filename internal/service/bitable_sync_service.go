@@ -0,0 +1,22 @@
+package service
+
+import "context"
+
+// BitableSyncService keeps a Feishu Bitable table in sync with the articles of a tenant, one record per article.
+// As an ArticleEventListener it queues incremental syncs on lifecycle changes, which Run applies in order.
+type BitableSyncService interface {
+	ArticleEventListener
+
+	// Run applies queued incremental syncs until ctx is cancelled or Drain returns.
+	Run(ctx context.Context) error
+
+	// Drain applies the syncs that are still queued and waits until they are done or ctx ends.
+	// Articles left in the queue when ctx ends are synced by the next full resync.
+	Drain(ctx context.Context) error
+
+	// SyncArticle creates or updates the record of an article.
+	SyncArticle(ctx context.Context, id int64) error
+
+	// Resync upserts every article of the tenant and returns the number of articles synced.
+	Resync(ctx context.Context) (int, error)
+}
@@ -0,0 +1,55 @@
+package service
+
+import "context"
+
+// FilterVerdict is the decision of a content filter.
+type FilterVerdict int
+
+// Verdicts are ordered by severity, so the chain keeps the most severe one.
+const (
+	FilterAllow  FilterVerdict = iota // the content may be published
+	FilterFlag                        // the content must be reviewed by an admin before it is published
+	FilterReject                      // the content is rejected and will not be saved
+)
+
+// String returns the verdict name used in logs and by the external moderation service.
+func (v FilterVerdict) String() string {
+	switch v {
+	case FilterFlag:
+		return "flag"
+	case FilterReject:
+		return "reject"
+	default:
+		return "allow"
+	}
+}
+
+// ModerationInput is the submission content checked by the content filters.
+type ModerationInput struct {
+	AuthorID   string // real author open_id, also for anonymous submissions
+	Title      string
+	Content    string // plain text content
+	RawContent string // post JSON, used to count images and find links
+}
+
+// FilterResult is the outcome of a content filter.
+type FilterResult struct {
+	Verdict FilterVerdict
+	Filter  string // name of the filter that produced the verdict
+	Reason  string // explanation for flagged or rejected content, replied to the author on rejection
+}
+
+// ContentFilter checks a submission before it is saved and forwarded.
+type ContentFilter interface {
+	// Name returns the filter name used in logs.
+	Name() string
+
+	// Check returns the verdict of the filter for the submission.
+	Check(ctx context.Context, input *ModerationInput) (*FilterResult, error)
+}
+
+// ContentModerationService runs submissions through the configured chain of content filters.
+type ContentModerationService interface {
+	// Moderate returns the most severe verdict of the chain; a rejection stops the chain early.
+	Moderate(ctx context.Context, input *ModerationInput) (*FilterResult, error)
+}
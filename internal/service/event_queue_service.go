@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// EventHandler processes the payload of a queued event.
+type EventHandler func(ctx context.Context, payload []byte) error
+
+// QueuedEvent is an event to be processed asynchronously.
+type QueuedEvent struct {
+	Type    string // selects the handler registered with Handle
+	ID      string // an event redelivered with the same ID is queued only once
+	ChatID  string // events of the same chat are processed in order; empty spreads the events across workers
+	Payload []byte
+}
+
+// EventQueueStats is a snapshot of the queue of one tenant.
+type EventQueueStats struct {
+	Pending       int64         // events waiting in the database, including those buffered and being processed
+	OldestPending time.Duration // age of the oldest pending event, zero when none is pending
+	Buffered      int           // events claimed and waiting for a worker
+	Active        int           // events being processed
+	Succeeded     int64         // events processed since start
+	Failed        int64         // events whose handler failed, or that ran out of attempts, since start
+	Duplicates    int64         // redelivered events ignored since start
+}
+
+// EventQueueService persists events and processes them with a bounded pool of workers, so that event callbacks
+// can acknowledge immediately. Events are stored before Enqueue returns and are processed after a restart if the
+// process exits first. Events of the same chat are processed one at a time, in the order they were received.
+type EventQueueService interface {
+	// Handle registers the handler of an event type. It must be called before Run.
+	Handle(eventType string, handler EventHandler)
+
+	// Enqueue stores an event for processing. It fails once Drain has been called.
+	Enqueue(ctx context.Context, event *QueuedEvent) error
+
+	// Run processes queued events until ctx is cancelled or Drain returns.
+	Run(ctx context.Context) error
+
+	// Drain stops accepting events and waits until the events being processed are done or ctx ends.
+	// Buffered events that were not started stay in the database and are processed after a restart.
+	Drain(ctx context.Context) error
+
+	// Stats returns the current queue statistics.
+	Stats(ctx context.Context) (*EventQueueStats, error)
+}
@@ -0,0 +1,20 @@
+package service
+
+import "context"
+
+// ExternalSubmission is a submission received from a source outside Feishu, such as a Telegram chat.
+type ExternalSubmission struct {
+	Source     string // origin of the submission, one of the model.ArticleSource* constants
+	AuthorID   string // sender identifier in the source, prefixed with the source, e.g. "tg:123456"
+	AuthorName string // sender display name in the source
+	Text       string // plain text of the message; the first line becomes the title
+	Reference  string // identifies the original message in the source, used for logging
+}
+
+// ExternalSubmissionService turns messages from external sources into articles.
+type ExternalSubmissionService interface {
+	// Submit moderates and saves the submission, then forwards it to the configured Feishu group chats
+	// or asks the admins to review it. A submission duplicating an existing article is merged into it,
+	// crediting the sender as a source.
+	Submit(ctx context.Context, submission *ExternalSubmission) (*ArchiveResult, error)
+}
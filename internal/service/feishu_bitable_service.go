@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+// FeishuBitableService defines the interface for reading and writing Feishu Bitable records.
+// Fields are keyed by column name, with values in the format of the column type (text, number, date in ms, ...).
+type FeishuBitableService interface {
+	// FindRecord returns the ID of the first record whose field equals value, or "" when there is none.
+	FindRecord(ctx context.Context, appToken, tableID, field, value string) (string, error)
+
+	// CreateRecord creates a record and returns its ID.
+	CreateRecord(ctx context.Context, appToken, tableID string, fields map[string]interface{}) (string, error)
+
+	// UpdateRecord overwrites the given fields of a record; other fields are left untouched.
+	UpdateRecord(ctx context.Context, appToken, tableID, recordID string, fields map[string]interface{}) error
+}
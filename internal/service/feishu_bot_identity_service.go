@@ -0,0 +1,9 @@
+package service
+
+import "context"
+
+// FeishuBotIdentityService resolves the identity of the bot itself.
+type FeishuBotIdentityService interface {
+	// BotOpenID returns the open_id of the bot, used to recognize @-mentions of the bot in group chats.
+	BotOpenID(ctx context.Context) (string, error)
+}
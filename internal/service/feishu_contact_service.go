@@ -11,4 +11,8 @@ type FeishuContactService interface {
 	// GetUserInfoByOpenID fetches user details using their Open ID.
 	// It returns the User object from the SDK or an error.
 	GetUserInfoByOpenID(ctx context.Context, openID string) (*larkcontact.User, error)
+
+	// ListAllUsers walks every department visible to the app and returns all of its users.
+	// Users belonging to several departments are returned once.
+	ListAllUsers(ctx context.Context) ([]*larkcontact.User, error)
 }
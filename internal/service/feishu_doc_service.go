@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larkwiki "github.com/larksuite/oapi-sdk-go/v3/service/wiki/v2"
+)
+
+// FeishuDocService defines the interface for writing Feishu Docs (docx) and Wiki nodes.
+type FeishuDocService interface {
+	// CreateDocument creates an empty docx document in the given folder (the app's root folder when empty) and returns its document_id.
+	CreateDocument(ctx context.Context, folderToken string, title string) (string, error)
+
+	// AppendBlocks appends blocks to the end of a document and returns the created blocks, in the same order.
+	AppendBlocks(ctx context.Context, documentID string, blocks []*larkdocx.Block) ([]*larkdocx.Block, error)
+
+	// ReplaceImage uploads an image and sets it as the content of an (empty) image block.
+	ReplaceImage(ctx context.Context, documentID string, blockID string, image []byte) error
+
+	// FindWikiNode returns the child of parentNodeToken (the space root when empty) with the given title, or nil when there is none.
+	FindWikiNode(ctx context.Context, spaceID string, parentNodeToken string, title string) (*larkwiki.Node, error)
+
+	// CreateWikiNode creates a docx node under parentNodeToken (the space root when empty).
+	CreateWikiNode(ctx context.Context, spaceID string, parentNodeToken string, title string) (*larkwiki.Node, error)
+}
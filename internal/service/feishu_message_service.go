@@ -3,6 +3,7 @@ package service
 import (
 	"MikoNews/internal/config"
 	"context"
+	"time"
 
 	lark "github.com/larksuite/oapi-sdk-go/v3"
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
@@ -25,12 +26,27 @@ type FeishuMessageService interface {
 	// SendCardMessage sends an interactive card message to a specified chat ID.
 	SendCardMessage(ctx context.Context, chatID string, card *MessageCardContent) (*larkim.CreateMessageResp, error)
 
+	// SendTextMessageToUser sends a plain text message to a user's P2P chat with the bot, identified by open_id.
+	SendTextMessageToUser(ctx context.Context, openID string, text string) (*larkim.CreateMessageResp, error)
+
 	// ReplyTextMessage replies to a specific message with plain text.
 	ReplyTextMessage(ctx context.Context, msgID string, text string) (*larkim.ReplyMessageResp, error)
 
 	// ReplyCardMessage replies to a specific message with an interactive card.
-	// Note: The implementation needs this method added if required.
-	// ReplyCardMessage(ctx context.Context, msgID string, card *MessageCardContent) (*larkim.ReplyMessageResp, error)
+	ReplyCardMessage(ctx context.Context, msgID string, card *MessageCardContent) (*larkim.ReplyMessageResp, error)
+
+	// GetMessage fetches a single message by its ID, e.g. the parent of a reply.
+	GetMessage(ctx context.Context, msgID string) (*larkim.Message, error)
+
+	// DownloadImage downloads an image by its image_key, e.g. to re-upload it to another platform.
+	DownloadImage(ctx context.Context, imageKey string) ([]byte, error)
+
+	// ListChatMemberIDs returns the open_ids of all members of a chat the bot belongs to.
+	ListChatMemberIDs(ctx context.Context, chatID string) ([]string, error)
+
+	// ListChatMessages returns one page of the messages sent to a chat between start and end, oldest first.
+	// Zero times leave the range open. The returned page token is empty on the last page.
+	ListChatMessages(ctx context.Context, chatID string, start, end time.Time, pageToken string) ([]*larkim.Message, string, error)
 
 	// TODO: Consider adding methods for updating cards, sending other message types etc. if needed.
 }
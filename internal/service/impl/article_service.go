@@ -2,11 +2,16 @@ package impl
 
 import (
 	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/crypto"
+	apperrors "MikoNews/internal/pkg/errors"
+	"MikoNews/internal/pkg/fingerprint"
 	"MikoNews/internal/pkg/logger"
 	"MikoNews/internal/repository"
 	"MikoNews/internal/service"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -15,42 +20,280 @@ import (
 
 // articleService 实现了 ArticleService 接口
 type articleService struct {
-	repo repository.ArticleRepository
+	repo      repository.ArticleRepository
+	cipher    *crypto.Cipher                 // 用于加密匿名投稿的作者身份
+	listeners []service.ArticleEventListener // 文章生命周期事件的接收者
 }
 
 // NewArticleService 创建一个新的 articleService 实例
-// 移除了 bot 参数
-func NewArticleService(repo repository.ArticleRepository) service.ArticleService {
+// 移除了 bot 参数；listeners 会在文章创建、审核、发布、撤回后收到通知
+func NewArticleService(repo repository.ArticleRepository, cipher *crypto.Cipher, listeners ...service.ArticleEventListener) service.ArticleService {
 	return &articleService{
-		repo: repo,
+		repo:      repo,
+		cipher:    cipher,
+		listeners: listeners,
 	}
 }
 
 // SaveSubmission 处理并保存用户通过飞书发送的投稿
-func (s *articleService) SaveSubmission(ctx context.Context, authorID, authorName, title string, textContent string) (*model.Article, error) {
+func (s *articleService) SaveSubmission(ctx context.Context, submission *service.Submission) (*model.Article, error) {
 	now := time.Now()
 	article := &model.Article{
-		AuthorID:    authorID,
-		AuthorName:  authorName,
-		Title:       title,
-		Content:     textContent,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		AuthorID:   submission.AuthorID,
+		AuthorName: submission.AuthorName,
+		CuratorID:  submission.CuratorID,
+		Title:      submission.Title,
+		Content:    submission.Content,
+		RawContent: submission.RawContent,
+		Source:     submission.Source,
+		Status:     model.ArticleStatusPublished,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+
+		SourceMessageID: model.NullString(submission.MessageID),
+	}
+	if submission.ReviewRequired {
+		article.Status = model.ArticleStatusPending
+	}
+	if article.Source == "" {
+		article.Source = model.ArticleSourceFeishu
 	}
 
+	// 匿名投稿：真实作者 OpenID 仅以密文保存，对外隐藏身份
+	if submission.Anonymous {
+		encrypted, err := s.cipher.Encrypt(submission.AuthorID)
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to encrypt anonymous author ID", zap.Error(err))
+			return nil, fmt.Errorf("匿名投稿失败: %w", err)
+		}
+		article.Anonymous = true
+		article.AuthorIDEncrypted = encrypted
+		article.AuthorID = ""
+		article.AuthorName = model.AnonymousAuthorName
+	}
+	article.Authors = buildArticleAuthors(article, submission.AuthorID, submission.Credits)
+	simhash, fingerprints := buildFingerprints(submission.Content, submission.RawContent)
+	article.SimHash = int64(simhash)
+	article.Fingerprints = fingerprints
+
 	err := s.repo.Create(ctx, article) // 调用更新后的 Create 方法
+	if err != nil && submission.MessageID != "" && errors.Is(err, gorm.ErrDuplicatedKey) {
+		// 同一条消息再次投递时，由来源消息ID的唯一索引保证只保存一篇
+		existing, findErr := s.repo.FindBySourceMessageID(ctx, submission.MessageID)
+		if findErr != nil {
+			logger.Ctx(ctx).Error("Failed to find saved submission", zap.String("messageID", submission.MessageID), zap.Error(findErr))
+			return nil, fmt.Errorf("查询已保存的投稿失败: %w", findErr)
+		}
+		logger.Ctx(ctx).Info("Submission already saved", zap.String("messageID", submission.MessageID), zap.Int64("articleID", existing.ID))
+		return existing, service.ErrSubmissionSaved
+	}
 	if err != nil {
-		logger.Error("Failed to save submission to repository",
-			zap.String("authorID", authorID),
+		logger.Ctx(ctx).Error("Failed to save submission to repository",
+			zap.String("authorID", submission.AuthorID),
+			zap.String("curatorID", submission.CuratorID),
 			zap.Error(err),
 		)
 		return nil, fmt.Errorf("保存投稿失败: %w", err)
 	}
 
-	logger.Info("Submission saved successfully", zap.Int64("articleID", article.ID))
+	logger.Ctx(ctx).Info("Submission saved successfully",
+		zap.Int64("articleID", article.ID),
+		zap.Bool("anonymous", article.Anonymous),
+		zap.String("status", article.Status),
+	)
+	s.emit(ctx, service.ArticleEventCreated, article)
+	if article.Status == model.ArticleStatusPublished {
+		s.emit(ctx, service.ArticleEventPublished, article)
+	}
 	return article, nil
 }
 
+// ImportArticle 导入历史文章，article.Authors 为空时按作者本人生成署名
+func (s *articleService) ImportArticle(ctx context.Context, article *model.Article) (bool, error) {
+	messageID := string(article.SourceMessageID)
+	if messageID != "" {
+		existing, err := s.repo.FindBySourceMessageID(ctx, messageID)
+		if err == nil {
+			logger.Ctx(ctx).Debug("Article already imported", zap.String("messageID", messageID), zap.Int64("articleID", existing.ID))
+			return false, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			logger.Ctx(ctx).Error("Failed to find article by source message ID", zap.String("messageID", messageID), zap.Error(err))
+			return false, fmt.Errorf("导入文章失败: %w", err)
+		}
+	}
+	if article.CreatedAt.IsZero() {
+		article.CreatedAt = time.Now()
+	}
+	if article.UpdatedAt.IsZero() {
+		article.UpdatedAt = article.CreatedAt
+	}
+	if article.Source == "" {
+		article.Source = model.ArticleSourceFeishu
+	}
+	if article.Status == "" {
+		article.Status = model.ArticleStatusPublished
+	}
+	if article.Anonymous {
+		article.AuthorID = ""
+		article.AuthorName = model.AnonymousAuthorName
+	}
+	if len(article.Authors) == 0 {
+		article.Authors = buildArticleAuthors(article, article.AuthorID, nil)
+	}
+	for _, author := range article.Authors {
+		if author.CreatedAt.IsZero() {
+			author.CreatedAt = article.CreatedAt
+		}
+	}
+	simhash, fingerprints := buildFingerprints(article.Content, article.RawContent)
+	article.SimHash = int64(simhash)
+	article.Fingerprints = fingerprints
+
+	if err := s.repo.Create(ctx, article); err != nil {
+		if messageID != "" && errors.Is(err, gorm.ErrDuplicatedKey) {
+			// 并发导入同一条消息时，由来源消息ID的唯一索引保证只保存一篇
+			logger.Ctx(ctx).Debug("Article imported concurrently", zap.String("messageID", messageID))
+			return false, nil
+		}
+		logger.Ctx(ctx).Error("Failed to import article", zap.String("authorID", article.AuthorID), zap.Error(err))
+		return false, fmt.Errorf("导入文章失败: %w", err)
+	}
+	return true, nil
+}
+
+// buildArticleAuthors 组装文章署名：作者本人（匿名投稿除外）加上投稿中署名的成员，按成员和角色去重。
+// authorID 是投稿人的 OpenID；匿名投稿的 article.AuthorID 已被清空，需由调用方传入真实身份，
+// 以便去掉投稿人给自己的署名，否则其身份会通过署名泄露
+func buildArticleAuthors(article *model.Article, authorID string, credits []*model.ArticleAuthor) []*model.ArticleAuthor {
+	authors := make([]*model.ArticleAuthor, 0, len(credits)+1)
+	seen := make(map[string]bool, len(credits)+1)
+	add := func(openID, name, role string) {
+		key := openID + "|" + role
+		if openID == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		authors = append(authors, &model.ArticleAuthor{OpenID: openID, Name: name, Role: role, CreatedAt: article.CreatedAt})
+	}
+
+	if !article.Anonymous {
+		add(article.AuthorID, article.AuthorName, model.AuthorRoleAuthor)
+	}
+	for _, credit := range credits {
+		if credit.OpenID == authorID && (article.Anonymous || credit.Role == model.AuthorRoleCoAuthor) {
+			continue // 作者本人无需再作为合著者署名；匿名投稿不以任何角色署名
+		}
+		add(credit.OpenID, credit.Name, credit.Role)
+	}
+	return authors
+}
+
+// withoutAuthor 去掉 openID 的署名
+func withoutAuthor(credits []*model.ArticleAuthor, openID string) []*model.ArticleAuthor {
+	kept := make([]*model.ArticleAuthor, 0, len(credits))
+	for _, credit := range credits {
+		if credit.OpenID != openID {
+			kept = append(kept, credit)
+		}
+	}
+	return kept
+}
+
+// buildFingerprints 计算文章的查重指纹：规范化链接，以及正文足够长时的 SimHash 分段
+func buildFingerprints(content, rawContent string) (uint64, []*model.ArticleFingerprint) {
+	var fingerprints []*model.ArticleFingerprint
+	for _, value := range normalizedURLs(content, rawContent) {
+		fingerprints = append(fingerprints, &model.ArticleFingerprint{Kind: model.FingerprintKindURL, Value: value})
+	}
+
+	if fingerprint.TextLength(content) < minSimHashLength {
+		return 0, fingerprints
+	}
+	simhash := fingerprint.SimHash(content)
+	for _, band := range fingerprint.Bands(simhash) {
+		fingerprints = append(fingerprints, &model.ArticleFingerprint{Kind: model.FingerprintKindSimHash, Value: band})
+	}
+	return simhash, fingerprints
+}
+
+// normalizedURLs 返回正文和富文本链接中规范化后的链接（去重）
+func normalizedURLs(content, rawContent string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, link := range fingerprint.ExtractURLs(content, rawContent) {
+		value, err := fingerprint.NormalizeURL(link)
+		if err != nil || seen[value] || len(value) > maxFingerprintLength {
+			continue
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+	return values
+}
+
+// FindDuplicate 查找重复文章：优先匹配相同链接，其次匹配 SimHash 海明距离足够小的正文
+func (s *articleService) FindDuplicate(ctx context.Context, content, rawContent string) (*model.Article, error) {
+	if urls := normalizedURLs(content, rawContent); len(urls) > 0 {
+		articles, err := s.repo.FindByFingerprints(ctx, model.FingerprintKindURL, urls, 1, 1)
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to find articles by URL fingerprints", zap.Error(err))
+			return nil, fmt.Errorf("查重失败: %w", err)
+		}
+		if len(articles) > 0 {
+			return articles[0], nil
+		}
+	}
+
+	if fingerprint.TextLength(content) < minSimHashLength {
+		return nil, nil
+	}
+	simhash := fingerprint.SimHash(content)
+	// 每段只有 8 位，约万分之四的文章会偶然命中 2 段，因此取回全部候选后再按海明距离筛选
+	candidates, err := s.repo.FindSimHashCandidates(ctx, fingerprint.Bands(simhash), fingerprint.MinMatchingBands)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to find articles by SimHash fingerprints", zap.Error(err))
+		return nil, fmt.Errorf("查重失败: %w", err)
+	}
+	for _, candidate := range candidates {
+		if fingerprint.Distance(uint64(candidate.SimHash), simhash) <= fingerprint.MaxDistance {
+			article, err := s.repo.FindByID(ctx, candidate.ID)
+			if err != nil {
+				logger.Ctx(ctx).Error("Failed to load duplicate article", zap.Int64("id", candidate.ID), zap.Error(err))
+				return nil, fmt.Errorf("查重失败: %w", err)
+			}
+			return article, nil
+		}
+	}
+	return nil, nil
+}
+
+// MergeCredits 将重复投稿合并到已有文章
+func (s *articleService) MergeCredits(ctx context.Context, id int64, credits []*model.ArticleAuthor) (*model.Article, error) {
+	article, err := s.FindArticleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// 匿名文章的真实作者重复投稿时不为其署名，否则其身份会通过署名泄露
+	if article.Anonymous {
+		authorID, err := s.cipher.Decrypt(article.AuthorIDEncrypted)
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to decrypt anonymous author ID", zap.Int64("id", id), zap.Error(err))
+			return nil, fmt.Errorf("合并投稿失败: %w", err)
+		}
+		credits = withoutAuthor(credits, authorID)
+		if len(credits) == 0 {
+			return article, nil
+		}
+	}
+	if err := s.repo.AddAuthors(ctx, id, credits); err != nil {
+		logger.Ctx(ctx).Error("Failed to merge credits into article", zap.Int64("id", id), zap.Error(err))
+		return nil, fmt.Errorf("合并投稿失败: %w", err)
+	}
+	logger.Ctx(ctx).Info("Duplicate submission merged", zap.Int64("articleID", id), zap.Int("credits", len(credits)))
+	return s.FindArticleByID(ctx, id)
+}
+
 // FindArticleByID 根据ID查找文章
 func (s *articleService) FindArticleByID(ctx context.Context, id int64) (*model.Article, error) {
 	article, err := s.repo.FindByID(ctx, id) // 调用更新后的 FindByID 方法
@@ -58,8 +301,180 @@ func (s *articleService) FindArticleByID(ctx context.Context, id int64) (*model.
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("文章未找到 (ID: %d)", id)
 		}
-		logger.Error("Failed to find article by ID", zap.Int64("id", id), zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to find article by ID", zap.Int64("id", id), zap.Error(err))
+		return nil, fmt.Errorf("查找文章失败: %w", err)
+	}
+	return article, nil
+}
+
+// FindPublishedArticle 根据ID查找已发布的文章，其他状态的文章与不存在的文章一样返回 404
+func (s *articleService) FindPublishedArticle(ctx context.Context, id int64) (*model.Article, error) {
+	article, err := s.repo.FindByID(ctx, id)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.Ctx(ctx).Error("Failed to find article by ID", zap.Int64("id", id), zap.Error(err))
 		return nil, fmt.Errorf("查找文章失败: %w", err)
 	}
+	if err == gorm.ErrRecordNotFound || article.Status != model.ArticleStatusPublished {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("文章未找到 (ID: %d)", id), nil)
+	}
+	return article, nil
+}
+
+// RevealAuthor 返回文章真实作者的 OpenID
+func (s *articleService) RevealAuthor(ctx context.Context, id int64) (string, error) {
+	article, err := s.FindArticleByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if !article.Anonymous {
+		return article.AuthorID, nil
+	}
+
+	authorID, err := s.cipher.Decrypt(article.AuthorIDEncrypted)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to decrypt anonymous author ID", zap.Int64("id", id), zap.Error(err))
+		return "", fmt.Errorf("解密作者身份失败: %w", err)
+	}
+	logger.Ctx(ctx).Info("Anonymous author revealed", zap.Int64("articleID", id))
+	return authorID, nil
+}
+
+// ReviewArticle 审核待审核的文章
+func (s *articleService) ReviewArticle(ctx context.Context, id int64, approve bool) (*model.Article, error) {
+	status := model.ArticleStatusRejected
+	if approve {
+		status = model.ArticleStatusPublished
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, model.ArticleStatusPending, status); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("文章不存在或不是待审核状态 (ID: %d)", id)
+		}
+		logger.Ctx(ctx).Error("Failed to update article status", zap.Int64("id", id), zap.String("status", status), zap.Error(err))
+		return nil, fmt.Errorf("审核文章失败: %w", err)
+	}
+
+	logger.Ctx(ctx).Info("Article reviewed", zap.Int64("articleID", id), zap.String("status", status))
+	article, err := s.FindArticleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if approve {
+		s.emit(ctx, service.ArticleEventApproved, article)
+		s.emit(ctx, service.ArticleEventPublished, article)
+	} else {
+		s.emit(ctx, service.ArticleEventRejected, article)
+	}
+	return article, nil
+}
+
+// WithdrawArticle 撤回已发布的文章
+func (s *articleService) WithdrawArticle(ctx context.Context, id int64) (*model.Article, error) {
+	if err := s.repo.UpdateStatus(ctx, id, model.ArticleStatusPublished, model.ArticleStatusWithdrawn); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("文章不存在或不是已发布状态 (ID: %d)", id)
+		}
+		logger.Ctx(ctx).Error("Failed to withdraw article", zap.Int64("id", id), zap.Error(err))
+		return nil, fmt.Errorf("撤回文章失败: %w", err)
+	}
+
+	logger.Ctx(ctx).Info("Article withdrawn", zap.Int64("articleID", id))
+	article, err := s.FindArticleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.emit(ctx, service.ArticleEventWithdrawn, article)
 	return article, nil
 }
+
+// RecordDocToken 记录文章归档到飞书云文档或知识库后的标识
+func (s *articleService) RecordDocToken(ctx context.Context, id int64, docToken string) error {
+	if err := s.repo.UpdateDocToken(ctx, id, docToken); err != nil {
+		logger.Ctx(ctx).Error("Failed to record article doc token", zap.Int64("id", id), zap.Error(err))
+		return fmt.Errorf("保存文章归档标识失败: %w", err)
+	}
+	return nil
+}
+
+// RecordForwards 为文章的转发次数增加 n，并通知监听者
+func (s *articleService) RecordForwards(ctx context.Context, id int64, n int) error {
+	if err := s.repo.IncrementForwardCount(ctx, id, n); err != nil {
+		logger.Ctx(ctx).Error("Failed to record article forwards", zap.Int64("id", id), zap.Error(err))
+		return fmt.Errorf("保存转发次数失败: %w", err)
+	}
+	if len(s.listeners) == 0 {
+		return nil
+	}
+	article, err := s.FindArticleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.emit(ctx, service.ArticleEventForwarded, article)
+	return nil
+}
+
+// emit 通知所有监听者文章状态发生了变化
+func (s *articleService) emit(ctx context.Context, eventType service.ArticleEventType, article *model.Article) {
+	if len(s.listeners) == 0 {
+		return
+	}
+	event := &service.ArticleEvent{Type: eventType, Article: article, OccurredAt: time.Now()}
+	for _, listener := range s.listeners {
+		listener.OnArticleEvent(ctx, event)
+	}
+}
+
+// ListLatestArticles 返回最新的 limit 篇文章
+func (s *articleService) ListLatestArticles(ctx context.Context, limit int) ([]*model.Article, error) {
+	articles, err := s.repo.FindLatest(ctx, normalizeLimit(limit))
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to list latest articles", zap.Int("limit", limit), zap.Error(err))
+		return nil, fmt.Errorf("查询最新文章失败: %w", err)
+	}
+	return articles, nil
+}
+
+// SearchArticles 按关键字搜索文章
+func (s *articleService) SearchArticles(ctx context.Context, keyword string, limit int) ([]*model.Article, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, fmt.Errorf("搜索关键字不能为空")
+	}
+	articles, err := s.repo.Search(ctx, keyword, normalizeLimit(limit))
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to search articles", zap.String("keyword", keyword), zap.Error(err))
+		return nil, fmt.Errorf("搜索文章失败: %w", err)
+	}
+	return articles, nil
+}
+
+// GetArticleStats 返回投稿统计信息
+func (s *articleService) GetArticleStats(ctx context.Context) (*model.ArticleStats, error) {
+	stats, err := s.repo.Stats(ctx, time.Now().AddDate(0, 0, -statsRecentDays), statsTopAuthors)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to get article stats", zap.Error(err))
+		return nil, fmt.Errorf("统计文章失败: %w", err)
+	}
+	return stats, nil
+}
+
+const (
+	defaultListLimit = 5  // 列表查询默认返回条数
+	maxListLimit     = 20 // 列表查询最大返回条数
+	statsRecentDays  = 7  // 统计“最近投稿”的天数
+	statsTopAuthors  = 3  // 统计中展示的作者数量
+
+	minSimHashLength     = 30  // 正文去掉标点后少于该字数时不做近似查重
+	maxFingerprintLength = 255 // 指纹值的最大长度，与 article_fingerprints.value 一致
+)
+
+// normalizeLimit 将列表查询条数限制在合理范围内
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return limit
+}
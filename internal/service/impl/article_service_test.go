@@ -0,0 +1,290 @@
+package impl
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/crypto"
+	apperrors "MikoNews/internal/pkg/errors"
+	"MikoNews/internal/pkg/fingerprint"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func (r *fakeArticleRepo) Create(_ context.Context, article *model.Article) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.articles {
+		if article.SourceMessageID != "" && existing.SourceMessageID == article.SourceMessageID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	article.ID = int64(len(r.articles) + 1)
+	copied := *article
+	r.articles[article.ID] = &copied
+	return nil
+}
+
+func (r *fakeArticleRepo) FindBySourceMessageID(_ context.Context, messageID string) (*model.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, article := range r.articles {
+		if messageID != "" && string(article.SourceMessageID) == messageID {
+			return article, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeArticleRepo) AddAuthors(_ context.Context, articleID int64, authors []*model.ArticleAuthor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	article := r.articles[articleID]
+	article.Authors = append(article.Authors, authors...)
+	return nil
+}
+
+func newTestArticleService(t *testing.T) (service.ArticleService, *fakeArticleRepo) {
+	t.Helper()
+	cipher, err := crypto.NewCipher("test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newFakeArticleRepo()
+	return NewArticleService(repo, cipher), repo
+}
+
+func TestSaveSubmissionAuthors(t *testing.T) {
+	credits := []*model.ArticleAuthor{
+		{OpenID: "ou_author", Name: "Alice", Role: model.AuthorRoleCoAuthor},
+		{OpenID: "ou_author", Name: "Alice", Role: model.AuthorRoleSource},
+		{OpenID: "ou_bob", Name: "Bob", Role: model.AuthorRoleCoAuthor},
+	}
+	tests := []struct {
+		name      string
+		anonymous bool
+		want      []string // open_id|role
+	}{
+		{"named", false, []string{"ou_author|author", "ou_author|source", "ou_bob|co-author"}},
+		{"anonymous", true, []string{"ou_bob|co-author"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestArticleService(t)
+			article, err := svc.SaveSubmission(context.Background(), &service.Submission{
+				AuthorID:   "ou_author",
+				AuthorName: "Alice",
+				Anonymous:  tt.anonymous,
+				Title:      "Title",
+				Content:    "Content",
+				Credits:    credits,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			saved, _ := repo.FindByID(context.Background(), article.ID)
+			var got []string
+			for _, author := range saved.Authors {
+				got = append(got, author.OpenID+"|"+author.Role)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("authors = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("authors = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveSubmissionAnonymous(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestArticleService(t)
+	article, err := svc.SaveSubmission(ctx, &service.Submission{
+		AuthorID: "ou_author", AuthorName: "Alice", Anonymous: true, Title: "Title", Content: "Content",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := repo.FindByID(ctx, article.ID)
+	if !saved.Anonymous || saved.AuthorID != "" || saved.AuthorName != model.AnonymousAuthorName {
+		t.Errorf("saved author = %q %q (anonymous %v), want a blank ID and %q", saved.AuthorID, saved.AuthorName, saved.Anonymous, model.AnonymousAuthorName)
+	}
+	if saved.AuthorIDEncrypted == "" || strings.Contains(saved.AuthorIDEncrypted, "ou_author") {
+		t.Errorf("the author ID should only be stored encrypted, got %q", saved.AuthorIDEncrypted)
+	}
+	if got, err := svc.RevealAuthor(ctx, article.ID); err != nil || got != "ou_author" {
+		t.Errorf("RevealAuthor() = %q, %v; want ou_author", got, err)
+	}
+
+	// Anonymous submissions are refused when no secret key is configured
+	noKey := NewArticleService(repo, nil)
+	if _, err := noKey.SaveSubmission(ctx, &service.Submission{AuthorID: "ou_author", Anonymous: true, Title: "Title", Content: "Content"}); !errors.Is(err, crypto.ErrNoKey) {
+		t.Errorf("SaveSubmission() without a key: err = %v, want ErrNoKey", err)
+	}
+}
+
+// racingArticleRepo misses articles saved by a concurrent import, so only the unique index catches the duplicate.
+type racingArticleRepo struct {
+	*fakeArticleRepo
+}
+
+func (r racingArticleRepo) FindBySourceMessageID(context.Context, string) (*model.Article, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestImportArticleSkipsImportedMessages(t *testing.T) {
+	ctx := context.Background()
+	articles, repo := newTestArticleService(t)
+	newArticle := func(messageID string) *model.Article {
+		return &model.Article{Title: "周报", Content: "本周完成了迁移", AuthorID: "ou_a", AuthorName: "张三", SourceMessageID: model.NullString(messageID)}
+	}
+
+	if imported, err := articles.ImportArticle(ctx, newArticle("om_1")); err != nil || !imported {
+		t.Fatalf("ImportArticle() = %v, %v; want imported", imported, err)
+	}
+	if imported, err := articles.ImportArticle(ctx, newArticle("om_1")); err != nil || imported {
+		t.Errorf("importing the same message again = %v, %v; want skipped", imported, err)
+	}
+
+	racing := NewArticleService(racingArticleRepo{repo}, nil)
+	if imported, err := racing.ImportArticle(ctx, newArticle("om_1")); err != nil || imported {
+		t.Errorf("a concurrent duplicate import = %v, %v; want skipped", imported, err)
+	}
+	if imported, err := racing.ImportArticle(ctx, newArticle("")); err != nil || !imported {
+		t.Errorf("articles without a source message should always be imported, got %v, %v", imported, err)
+	}
+	if len(repo.articles) != 2 {
+		t.Errorf("saved %d articles, want 2", len(repo.articles))
+	}
+}
+
+func TestSaveSubmissionRedeliveredEvent(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestArticleService(t)
+	submission := &service.Submission{AuthorID: "ou_author", AuthorName: "Alice", Title: "Title", Content: "短消息", MessageID: "om_1"}
+
+	first, err := svc.SaveSubmission(ctx, submission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Feishu redelivers the event, or the event queue resumes the job after a crash
+	again, err := svc.SaveSubmission(ctx, submission)
+	if !errors.Is(err, service.ErrSubmissionSaved) {
+		t.Fatalf("saving the same message again: err = %v, want ErrSubmissionSaved", err)
+	}
+	if again == nil || again.ID != first.ID {
+		t.Errorf("saving the same message again returned %+v, want article %d", again, first.ID)
+	}
+	if len(repo.articles) != 1 {
+		t.Errorf("saved %d articles, want 1", len(repo.articles))
+	}
+
+	// Submissions without a source message are never treated as redelivered
+	for i := 0; i < 2; i++ {
+		if _, err := svc.SaveSubmission(ctx, &service.Submission{AuthorID: "ou_author", Title: "Title", Content: "短消息"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(repo.articles) != 3 {
+		t.Errorf("saved %d articles, want 3", len(repo.articles))
+	}
+}
+
+func TestMergeCreditsHidesAnonymousAuthor(t *testing.T) {
+	svc, repo := newTestArticleService(t)
+	article, err := svc.SaveSubmission(context.Background(), &service.Submission{
+		AuthorID: "ou_author", AuthorName: "Alice", Anonymous: true, Title: "Title", Content: "Content",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = svc.MergeCredits(context.Background(), article.ID, []*model.ArticleAuthor{
+		{OpenID: "ou_author", Name: "Alice", Role: model.AuthorRoleSource},
+		{OpenID: "ou_bob", Name: "Bob", Role: model.AuthorRoleSource},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := repo.FindByID(context.Background(), article.ID)
+	for _, author := range saved.Authors {
+		if author.OpenID == "ou_author" {
+			t.Errorf("the real author of an anonymous article must not be credited: %+v", author)
+		}
+	}
+	if len(saved.Authors) != 1 || saved.Authors[0].OpenID != "ou_bob" {
+		t.Errorf("authors = %+v, want only ou_bob", saved.Authors)
+	}
+}
+
+func TestFindPublishedArticle(t *testing.T) {
+	svc, repo := newTestArticleService(t)
+	for _, status := range []string{model.ArticleStatusPublished, model.ArticleStatusPending, model.ArticleStatusRejected, model.ArticleStatusWithdrawn} {
+		article := &model.Article{Title: status, Status: status}
+		_ = repo.Create(context.Background(), article)
+
+		found, err := svc.FindPublishedArticle(context.Background(), article.ID)
+		if status == model.ArticleStatusPublished {
+			if err != nil || found.ID != article.ID {
+				t.Errorf("published article: got %v, %v", found, err)
+			}
+			continue
+		}
+		var appErr *apperrors.AppError
+		if !errors.As(err, &appErr) || appErr.HTTPStatus != http.StatusNotFound {
+			t.Errorf("%s article should be not found, got %v", status, err)
+		}
+	}
+	if _, err := svc.FindPublishedArticle(context.Background(), 999); err == nil {
+		t.Error("missing article should be not found")
+	}
+}
+
+func (r *fakeArticleRepo) FindSimHashCandidates(_ context.Context, bands []string, minMatches int) ([]*model.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var candidates []*model.Article
+	for id := int64(1); id <= int64(len(r.articles)); id++ {
+		article := r.articles[id]
+		matches := 0
+		for i, band := range fingerprint.Bands(uint64(article.SimHash)) {
+			if band == bands[i] {
+				matches++
+			}
+		}
+		if matches >= minMatches {
+			candidates = append(candidates, &model.Article{ID: article.ID, SimHash: article.SimHash})
+		}
+	}
+	return candidates, nil
+}
+
+func TestFindDuplicateComparesAllCandidates(t *testing.T) {
+	svc, repo := newTestArticleService(t)
+	content := strings.Repeat("本周我们完成了消息队列的迁移工作，新的集群在压测中表现稳定。", 3)
+	simhash := fingerprint.SimHash(content)
+
+	// Many older articles share two bands by chance but are far from the submission
+	for i := 0; i < 50; i++ {
+		_ = repo.Create(context.Background(), &model.Article{Title: "unrelated", SimHash: int64(simhash ^ 0xffffffff_ffff0000)})
+	}
+	// The real near-duplicate differs in MaxDistance bits spread over different bands
+	original := &model.Article{Title: "original", SimHash: int64(simhash ^ 0x00000101_01010101)}
+	_ = repo.Create(context.Background(), original)
+
+	duplicate, err := svc.FindDuplicate(context.Background(), content, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if duplicate == nil || duplicate.ID != original.ID || duplicate.Title != "original" {
+		t.Errorf("FindDuplicate() = %+v, want the near-duplicate article %d", duplicate, original.ID)
+	}
+}
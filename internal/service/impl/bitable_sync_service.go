@@ -0,0 +1,248 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	bitableQueueSize  = 256 // 等待增量同步的文章数上限，超出时丢弃并等待全量同步补齐
+	bitableResyncPage = 100 // 全量同步时每页读取的文章数
+)
+
+// bitableStatusLabels 是文章状态在多维表格中显示的选项
+var bitableStatusLabels = map[string]string{
+	model.ArticleStatusPending:   "待审核",
+	model.ArticleStatusPublished: "已发布",
+	model.ArticleStatusRejected:  "已驳回",
+	model.ArticleStatusWithdrawn: "已撤回",
+}
+
+// hashtagPattern 匹配正文中的 #话题 标签，# 前需为行首或空白，避免匹配链接中的锚点
+var hashtagPattern = regexp.MustCompile(`(?:^|\s)#([^\s#]+)`)
+
+// bitableSyncService 实现了 BitableSyncService 接口
+type bitableSyncService struct {
+	repo      repository.ArticleRepository
+	bitable   service.FeishuBitableService
+	conf      *config.FeishuBitableConfig
+	serverCfg *config.ServerConfig
+	tenantKey string
+	queue     chan int64
+	stopping  chan struct{} // Drain 时关闭
+	stopOnce  sync.Once
+	started   atomic.Bool
+	done      chan struct{} // Run 返回前关闭
+
+	mu        sync.Mutex       // 串行执行同步，避免同一篇文章被并发创建两条记录
+	recordIDs map[int64]string // 文章ID到记录ID的缓存
+}
+
+var _ service.BitableSyncService = (*bitableSyncService)(nil)
+
+// NewBitableSyncService 创建一个新的 bitableSyncService 实例
+// 文章直接从 repo 读取，使其可以作为文章服务的监听者，而不依赖文章服务本身
+func NewBitableSyncService(
+	repo repository.ArticleRepository,
+	bitable service.FeishuBitableService,
+	conf *config.FeishuBitableConfig,
+	serverCfg *config.ServerConfig,
+	tenantKey string,
+) service.BitableSyncService {
+	return &bitableSyncService{
+		repo:      repo,
+		bitable:   bitable,
+		conf:      conf,
+		serverCfg: serverCfg,
+		tenantKey: tenantKey,
+		queue:     make(chan int64, bitableQueueSize),
+		stopping:  make(chan struct{}),
+		done:      make(chan struct{}),
+		recordIDs: make(map[int64]string),
+	}
+}
+
+// OnArticleEvent 将文章加入增量同步队列，不阻塞投稿流程
+func (s *bitableSyncService) OnArticleEvent(ctx context.Context, event *service.ArticleEvent) {
+	select {
+	case s.queue <- event.Article.ID:
+	default:
+		logger.Ctx(ctx).Warn("Bitable sync queue is full, dropping article", zap.Int64("articleID", event.Article.ID))
+	}
+}
+
+// Run 按入队顺序执行增量同步，直到 ctx 被取消，或 Drain 后队列中的文章同步完成
+func (s *bitableSyncService) Run(ctx context.Context) error {
+	s.started.Store(true)
+	defer close(s.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.stopping:
+			// 同步队列中剩余的文章，队列只在内存中，退出后未同步的文章要等下次全量同步补齐
+			for {
+				select {
+				case id := <-s.queue:
+					s.sync(ctx, id)
+				default:
+					return nil
+				}
+			}
+		case id := <-s.queue:
+			s.sync(ctx, id)
+		}
+	}
+}
+
+// Drain 同步队列中剩余的文章，并等待同步完成或 ctx 结束
+func (s *bitableSyncService) Drain(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopping) })
+	if !s.started.Load() {
+		return nil
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待多维表格同步完成超时，%d 篇文章未同步: %w", len(s.queue), ctx.Err())
+	}
+}
+
+// sync 执行一篇文章的增量同步，失败只记录日志
+func (s *bitableSyncService) sync(ctx context.Context, id int64) {
+	if err := s.SyncArticle(ctx, id); err != nil {
+		logger.Ctx(ctx).Error("Failed to sync article to bitable", zap.Int64("articleID", id), zap.Error(err))
+	}
+}
+
+// SyncArticle 读取文章的最新状态并写入多维表格
+func (s *bitableSyncService) SyncArticle(ctx context.Context, id int64) error {
+	article, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("文章未找到 (ID: %d)", id)
+		}
+		return fmt.Errorf("查找文章失败: %w", err)
+	}
+	return s.upsert(ctx, article)
+}
+
+// Resync 分页遍历租户的全部文章并逐一写入多维表格，单篇失败不影响其他文章
+func (s *bitableSyncService) Resync(ctx context.Context) (int, error) {
+	synced := 0
+	var errs []error
+	var afterID int64
+	for {
+		articles, err := s.repo.FindAfter(ctx, afterID, bitableResyncPage)
+		if err != nil {
+			return synced, fmt.Errorf("读取文章失败: %w", err)
+		}
+		for _, article := range articles {
+			if err := s.upsert(ctx, article); err != nil {
+				errs = append(errs, fmt.Errorf("文章 #%d: %w", article.ID, err))
+				continue
+			}
+			synced++
+		}
+		if len(articles) < bitableResyncPage {
+			break
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+
+	logger.Ctx(ctx).Info("Bitable resync finished", zap.String("tenantKey", s.tenantKey), zap.Int("synced", synced), zap.Int("failed", len(errs)))
+	return synced, errors.Join(errs...)
+}
+
+// upsert 创建或更新文章对应的记录
+func (s *bitableSyncService) upsert(ctx context.Context, article *model.Article) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := s.recordFields(article)
+	recordID, ok := s.recordIDs[article.ID]
+	if !ok {
+		var err error
+		recordID, err = s.bitable.FindRecord(ctx, s.conf.AppToken, s.conf.TableID, s.conf.FieldName(config.BitableFieldID), strconv.FormatInt(article.ID, 10))
+		if err != nil {
+			return err
+		}
+	}
+
+	if recordID == "" {
+		recordID, err := s.bitable.CreateRecord(ctx, s.conf.AppToken, s.conf.TableID, fields)
+		if err != nil {
+			return err
+		}
+		s.recordIDs[article.ID] = recordID
+		logger.Ctx(ctx).Debug("Bitable record created", zap.Int64("articleID", article.ID), zap.String("recordID", recordID))
+		return nil
+	}
+
+	if err := s.bitable.UpdateRecord(ctx, s.conf.AppToken, s.conf.TableID, recordID, fields); err != nil {
+		return err
+	}
+	s.recordIDs[article.ID] = recordID
+	logger.Ctx(ctx).Debug("Bitable record updated", zap.Int64("articleID", article.ID), zap.String("recordID", recordID))
+	return nil
+}
+
+// recordFields 按字段映射构造记录内容，映射为空的字段不写入
+func (s *bitableSyncService) recordFields(article *model.Article) map[string]interface{} {
+	values := map[string]interface{}{
+		config.BitableFieldID:           article.ID,
+		config.BitableFieldTitle:        article.Title,
+		config.BitableFieldAuthor:       article.AuthorName,
+		config.BitableFieldStatus:       bitableStatusLabel(article.Status),
+		config.BitableFieldSource:       article.Source,
+		config.BitableFieldCreatedAt:    article.CreatedAt.UnixMilli(),
+		config.BitableFieldTags:         articleTags(article.Content),
+		config.BitableFieldForwardCount: article.ForwardCount,
+	}
+	if link := s.serverCfg.ArticleURL(s.tenantKey, article.ID); link != "" {
+		values[config.BitableFieldLink] = map[string]string{"text": fmt.Sprintf("#%d", article.ID), "link": link}
+	}
+
+	fields := make(map[string]interface{}, len(values))
+	for field, value := range values {
+		if name := s.conf.FieldName(field); name != "" {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// bitableStatusLabel 返回文章状态在多维表格中显示的选项，未知状态原样返回
+func bitableStatusLabel(status string) string {
+	if label, ok := bitableStatusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// articleTags 提取正文中的 #话题 标签，去重并保持出现顺序
+func articleTags(content string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, m := range hashtagPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			tags = append(tags, m[1])
+		}
+	}
+	return tags
+}
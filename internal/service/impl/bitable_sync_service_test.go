@@ -0,0 +1,378 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	testAppToken = "bascnTest"
+	testTableID  = "tblTest"
+)
+
+// fakeBitableAPI is a minimal Feishu Open API server with a single Bitable table kept in memory.
+type fakeBitableAPI struct {
+	mu       sync.Mutex
+	records  map[string]map[string]interface{} // fields by record_id
+	searches int
+	nextID   int
+}
+
+func newFakeBitableAPI() *fakeBitableAPI {
+	return &fakeBitableAPI{records: map[string]map[string]interface{}{}}
+}
+
+func (f *fakeBitableAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/open-apis/auth/v3/tenant_access_token/internal" {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "msg": "ok", "tenant_access_token": "t-test", "expire": 7200})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer t-test" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 99991663, "msg": "invalid access token"})
+		return
+	}
+
+	prefix := fmt.Sprintf("/open-apis/bitable/v1/apps/%s/tables/%s/records", testAppToken, testTableID)
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 91402, "msg": "NOTEXIST"})
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, prefix)
+
+	var body struct {
+		Fields map[string]interface{} `json:"fields"`
+		Filter struct {
+			Conditions []struct {
+				FieldName string   `json:"field_name"`
+				Operator  string   `json:"operator"`
+				Value     []string `json:"value"`
+			} `json:"conditions"`
+		} `json:"filter"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPost && rest == "/search":
+		f.searches++
+		items := []interface{}{}
+		for id, fields := range f.records {
+			matched := true
+			for _, c := range body.Filter.Conditions {
+				if c.Operator != "is" || fmt.Sprint(fields[c.FieldName]) != c.Value[0] {
+					matched = false
+				}
+			}
+			if matched {
+				items = append(items, map[string]interface{}{"record_id": id, "fields": fields})
+			}
+		}
+		f.ok(w, map[string]interface{}{"items": items, "has_more": false, "total": len(items)})
+	case r.Method == http.MethodPost && rest == "":
+		f.nextID++
+		id := fmt.Sprintf("rec%d", f.nextID)
+		f.records[id] = body.Fields
+		f.ok(w, map[string]interface{}{"record": map[string]interface{}{"record_id": id, "fields": body.Fields}})
+	case r.Method == http.MethodPut && strings.HasPrefix(rest, "/"):
+		id := strings.TrimPrefix(rest, "/")
+		fields, ok := f.records[id]
+		if !ok {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 1254043, "msg": "RecordIdNotFound"})
+			return
+		}
+		for name, value := range body.Fields {
+			fields[name] = value
+		}
+		f.ok(w, map[string]interface{}{"record": map[string]interface{}{"record_id": id, "fields": fields}})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeBitableAPI) ok(w http.ResponseWriter, data interface{}) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "msg": "success", "data": data})
+}
+
+// snapshot returns the records sorted by record_id.
+func (f *fakeBitableAPI) snapshot() []map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.records))
+	for id := range f.records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	records := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, f.records[id])
+	}
+	return records
+}
+
+// fakeArticleRepo serves articles from memory; only the methods used by the sync are implemented.
+type fakeArticleRepo struct {
+	repository.ArticleRepository
+	mu       sync.Mutex
+	articles map[int64]*model.Article
+}
+
+func (r *fakeArticleRepo) FindByID(_ context.Context, id int64) (*model.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	article, ok := r.articles[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *article
+	return &copied, nil
+}
+
+func (r *fakeArticleRepo) FindAfter(_ context.Context, afterID int64, limit int) ([]*model.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var articles []*model.Article
+	for _, article := range r.articles {
+		if article.ID > afterID {
+			copied := *article
+			articles = append(articles, &copied)
+		}
+	}
+	sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+	if len(articles) > limit {
+		articles = articles[:limit]
+	}
+	return articles, nil
+}
+
+func (r *fakeArticleRepo) setStatus(id int64, status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.articles[id].Status = status
+}
+
+var testCreatedAt = time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+func newFakeArticleRepo(ids ...int64) *fakeArticleRepo {
+	repo := &fakeArticleRepo{articles: map[int64]*model.Article{}}
+	for _, id := range ids {
+		repo.articles[id] = &model.Article{
+			ID:           id,
+			Title:        fmt.Sprintf("文章 %d", id),
+			Content:      "正文 #周报 #上线 链接 https://example.com/#anchor #周报",
+			AuthorName:   "张三",
+			Status:       model.ArticleStatusPublished,
+			Source:       model.ArticleSourceFeishu,
+			ForwardCount: 2,
+			CreatedAt:    testCreatedAt,
+		}
+	}
+	return repo
+}
+
+func newTestBitableSync(t *testing.T, api *fakeBitableAPI, repo repository.ArticleRepository, fields map[string]string) service.BitableSyncService {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	client := lark.NewClient("cli_test", "secret", lark.WithOpenBaseUrl(server.URL))
+	conf := &config.FeishuBitableConfig{AppToken: testAppToken, TableID: testTableID, Fields: fields}
+	serverCfg := &config.ServerConfig{PublicURL: "https://news.example.com"}
+	return NewBitableSyncService(repo, NewFeishuBitableService(client), conf, serverCfg, "")
+}
+
+func TestBitableSyncCreatesThenUpdatesRecord(t *testing.T) {
+	api := newFakeBitableAPI()
+	repo := newFakeArticleRepo(1)
+	syncer := newTestBitableSync(t, api, repo, nil)
+	ctx := context.Background()
+
+	if err := syncer.SyncArticle(ctx, 1); err != nil {
+		t.Fatalf("SyncArticle() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"文章ID": float64(1),
+		"标题":   "文章 1",
+		"作者":   "张三",
+		"状态":   "已发布",
+		"来源":   "feishu",
+		"投稿时间": float64(testCreatedAt.UnixMilli()),
+		"标签":   []interface{}{"周报", "上线"},
+		"转发次数": float64(2),
+		"链接":   map[string]interface{}{"text": "#1", "link": "https://news.example.com/api/v1/articles/1"},
+	}
+	if records := api.snapshot(); len(records) != 1 || !reflect.DeepEqual(records[0], want) {
+		t.Fatalf("records = %v, want [%v]", records, want)
+	}
+
+	repo.setStatus(1, model.ArticleStatusWithdrawn)
+	if err := syncer.SyncArticle(ctx, 1); err != nil {
+		t.Fatalf("SyncArticle() error = %v", err)
+	}
+	records := api.snapshot()
+	if len(records) != 1 || records[0]["状态"] != "已撤回" {
+		t.Fatalf("records after update = %v, want one record with status 已撤回", records)
+	}
+	if api.searches != 1 {
+		t.Errorf("searches = %d, want 1 (the record ID is cached after the first sync)", api.searches)
+	}
+}
+
+func TestBitableSyncFindsExistingRecord(t *testing.T) {
+	api := newFakeBitableAPI()
+	repo := newFakeArticleRepo(1)
+	if err := newTestBitableSync(t, api, repo, nil).SyncArticle(context.Background(), 1); err != nil {
+		t.Fatalf("SyncArticle() error = %v", err)
+	}
+
+	// A new instance, e.g. after a restart, has no cache and must find the record instead of creating another one
+	repo.setStatus(1, model.ArticleStatusRejected)
+	if err := newTestBitableSync(t, api, repo, nil).SyncArticle(context.Background(), 1); err != nil {
+		t.Fatalf("SyncArticle() error = %v", err)
+	}
+	records := api.snapshot()
+	if len(records) != 1 || records[0]["状态"] != "已驳回" {
+		t.Fatalf("records = %v, want one record with status 已驳回", records)
+	}
+}
+
+func TestBitableSyncFieldMapping(t *testing.T) {
+	api := newFakeBitableAPI()
+	fields := map[string]string{
+		config.BitableFieldID:    "ID",
+		config.BitableFieldTitle: "Title",
+		config.BitableFieldLink:  "", // not synced
+		config.BitableFieldTags:  "",
+	}
+	if err := newTestBitableSync(t, api, newFakeArticleRepo(7), fields).SyncArticle(context.Background(), 7); err != nil {
+		t.Fatalf("SyncArticle() error = %v", err)
+	}
+
+	records := api.snapshot()
+	if len(records) != 1 {
+		t.Fatalf("records = %v, want one record", records)
+	}
+	var columns []string
+	for column := range records[0] {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	want := []string{"ID", "Title", "作者", "投稿时间", "来源", "状态", "转发次数"}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("columns = %v, want %v", columns, want)
+	}
+}
+
+func TestBitableSyncResync(t *testing.T) {
+	api := newFakeBitableAPI()
+	repo := newFakeArticleRepo(1, 2, 3)
+	syncer := newTestBitableSync(t, api, repo, nil)
+	ctx := context.Background()
+	if err := syncer.SyncArticle(ctx, 2); err != nil {
+		t.Fatalf("SyncArticle() error = %v", err)
+	}
+
+	synced, err := syncer.Resync(ctx)
+	if err != nil {
+		t.Fatalf("Resync() error = %v", err)
+	}
+	if synced != 3 {
+		t.Errorf("Resync() = %d, want 3", synced)
+	}
+	var ids []float64
+	for _, record := range api.snapshot() {
+		ids = append(ids, record["文章ID"].(float64))
+	}
+	if !reflect.DeepEqual(ids, []float64{2, 1, 3}) {
+		t.Errorf("record article IDs = %v, want [2 1 3] (article 2 updated, not duplicated)", ids)
+	}
+}
+
+func TestBitableSyncRunAppliesEvents(t *testing.T) {
+	api := newFakeBitableAPI()
+	repo := newFakeArticleRepo(5)
+	syncer := newTestBitableSync(t, api, repo, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- syncer.Run(ctx) }()
+
+	article, _ := repo.FindByID(ctx, 5)
+	syncer.OnArticleEvent(ctx, &service.ArticleEvent{Type: service.ArticleEventCreated, Article: article})
+	syncer.OnArticleEvent(ctx, &service.ArticleEvent{Type: service.ArticleEventPublished, Article: article})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(api.snapshot()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+	if records := api.snapshot(); len(records) != 1 {
+		t.Errorf("records = %v, want one record for both events", records)
+	}
+}
+
+func TestBitableSyncDrainFlushesQueue(t *testing.T) {
+	api := newFakeBitableAPI()
+	repo := newFakeArticleRepo(1, 2, 3, 4)
+	syncer := newTestBitableSync(t, api, repo, nil)
+	publish := func(id int64) {
+		article, _ := repo.FindByID(context.Background(), id)
+		syncer.OnArticleEvent(context.Background(), &service.ArticleEvent{Type: service.ArticleEventPublished, Article: article})
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- syncer.Run(context.Background()) }()
+	publish(1)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(api.snapshot()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Articles still queued when shutting down are synced before Drain returns
+	publish(2)
+	publish(3)
+	publish(4)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := syncer.Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if records := api.snapshot(); len(records) != 4 {
+		t.Errorf("records = %d after Drain, want 4", len(records))
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v after Drain, want nil", err)
+	}
+}
+
+func TestArticleTags(t *testing.T) {
+	got := articleTags("#开场 正文 #周报\n#上线 https://example.com/#anchor 价格#1 #周报")
+	want := []string{"开场", "周报", "上线"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("articleTags() = %v, want %v", got, want)
+	}
+}
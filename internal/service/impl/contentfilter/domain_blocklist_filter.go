@@ -0,0 +1,59 @@
+package contentfilter
+
+import (
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// domainBlocklistFilter rejects submissions linking to blocked domains, including their subdomains.
+type domainBlocklistFilter struct {
+	domains []string
+}
+
+// NewDomainBlocklistFilter creates a filter rejecting links to the given domains.
+func NewDomainBlocklistFilter(domains []string) service.ContentFilter {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		if domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), "."); domain != "" {
+			normalized = append(normalized, domain)
+		}
+	}
+	return &domainBlocklistFilter{domains: normalized}
+}
+
+// Name returns the filter name.
+func (f *domainBlocklistFilter) Name() string {
+	return "domain_blocklist"
+}
+
+// Check rejects the submission if any link points to a blocked domain.
+func (f *domainBlocklistFilter) Check(ctx context.Context, input *service.ModerationInput) (*service.FilterResult, error) {
+	for _, link := range extractLinks(input.RawContent, input.Content) {
+		parsed, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		if domain, blocked := f.blocked(parsed.Hostname()); blocked {
+			return &service.FilterResult{
+				Verdict: service.FilterReject,
+				Filter:  f.Name(),
+				Reason:  fmt.Sprintf("不允许分享来自 %s 的链接", domain),
+			}, nil
+		}
+	}
+	return &service.FilterResult{Verdict: service.FilterAllow, Filter: f.Name()}, nil
+}
+
+// blocked reports whether host is a blocked domain or one of its subdomains.
+func (f *domainBlocklistFilter) blocked(host string) (string, bool) {
+	host = strings.ToLower(host)
+	for _, domain := range f.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return domain, true
+		}
+	}
+	return "", false
+}
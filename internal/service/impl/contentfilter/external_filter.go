@@ -0,0 +1,78 @@
+package contentfilter
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/service"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// externalRequest is the JSON body posted to the external moderation service.
+type externalRequest struct {
+	AuthorID string `json:"author_id"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+}
+
+// externalResponse is the JSON response expected from the external moderation service.
+type externalResponse struct {
+	Verdict string `json:"verdict"` // allow, flag or reject
+	Reason  string `json:"reason"`
+}
+
+// externalFilter delegates the decision to an external moderation HTTP service.
+type externalFilter struct {
+	conf   *config.ExternalModerationConfig
+	client *http.Client
+}
+
+// NewExternalFilter creates a filter calling the external moderation service.
+func NewExternalFilter(conf *config.ExternalModerationConfig) service.ContentFilter {
+	return &externalFilter{
+		conf:   conf,
+		client: &http.Client{Timeout: conf.Timeout},
+	}
+}
+
+// Name returns the filter name.
+func (f *externalFilter) Name() string {
+	return "external"
+}
+
+// Check posts the submission to the external service and maps its verdict.
+func (f *externalFilter) Check(ctx context.Context, input *service.ModerationInput) (*service.FilterResult, error) {
+	body, err := json.Marshal(&externalRequest{AuthorID: input.AuthorID, Title: input.Title, Content: input.Content})
+	if err != nil {
+		return nil, fmt.Errorf("序列化审核请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建审核请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.conf.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+f.conf.Token)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("调用审核服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("审核服务返回异常状态 %d: %s", resp.StatusCode, data)
+	}
+
+	var result externalResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析审核结果失败: %w", err)
+	}
+	return &service.FilterResult{Verdict: verdictFromString(result.Verdict), Filter: f.Name(), Reason: result.Reason}, nil
+}
@@ -0,0 +1,154 @@
+package contentfilter
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSizeFilter(t *testing.T) {
+	images := `{"content":[[{"tag":"img"},{"tag":"text","text":"x"}],[{"tag":"img"}]]}`
+	tests := []struct {
+		name      string
+		maxLength int
+		maxImages int
+		input     service.ModerationInput
+		want      service.FilterVerdict
+	}{
+		{"within limits", 5, 2, service.ModerationInput{Content: "五个汉字啊", RawContent: images}, service.FilterAllow},
+		{"too long counts runes", 4, 0, service.ModerationInput{Content: "五个汉字啊"}, service.FilterReject},
+		{"too many images", 0, 1, service.ModerationInput{RawContent: images}, service.FilterReject},
+		{"unlimited", 0, 0, service.ModerationInput{Content: strings.Repeat("长", 10000), RawContent: images}, service.FilterAllow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewSizeFilter(tt.maxLength, tt.maxImages).Check(context.Background(), &tt.input)
+			if err != nil || result.Verdict != tt.want {
+				t.Errorf("Check() = %+v, %v, want %v", result, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSensitiveWordFilter(t *testing.T) {
+	filter := NewSensitiveWordFilter([]string{"代购", "Spam"}, service.FilterReject)
+	tests := []struct {
+		name       string
+		input      service.ModerationInput
+		want       service.FilterVerdict
+		wantReason string
+	}{
+		{"clean", service.ModerationInput{Title: "周报", Content: "本周上线了新功能"}, service.FilterAllow, ""},
+		{"in title", service.ModerationInput{Title: "SPAM", Content: "正文"}, service.FilterReject, "内容包含敏感词：spam"},
+		{"in content", service.ModerationInput{Title: "周报", Content: "专业代购，spam"}, service.FilterReject, "内容包含敏感词：代购、spam"},
+		{"not across title and content", service.ModerationInput{Title: "代", Content: "购物"}, service.FilterAllow, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := filter.Check(context.Background(), &tt.input)
+			if err != nil || result.Verdict != tt.want || result.Reason != tt.wantReason {
+				t.Errorf("Check() = %+v, %v, want %v %q", result, err, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestDomainBlocklistFilter(t *testing.T) {
+	filter := NewDomainBlocklistFilter([]string{" Example.COM. ", ""})
+	tests := []struct {
+		name  string
+		input service.ModerationInput
+		want  service.FilterVerdict
+	}{
+		{"no links", service.ModerationInput{Content: "example.com 不是链接"}, service.FilterAllow},
+		{"blocked domain in text", service.ModerationInput{Content: "看这里 https://example.com/a"}, service.FilterReject},
+		{"blocked subdomain", service.ModerationInput{Content: "http://news.EXAMPLE.com/a"}, service.FilterReject},
+		{"similar domain", service.ModerationInput{Content: "https://notexample.com/a"}, service.FilterAllow},
+		{"blocked link in post", service.ModerationInput{RawContent: `{"content":[[{"tag":"a","text":"链接","href":"https://www.example.com"}]]}`}, service.FilterReject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := filter.Check(context.Background(), &tt.input)
+			if err != nil || result.Verdict != tt.want {
+				t.Errorf("Check() = %+v, %v, want %v", result, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExternalFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    service.FilterVerdict
+		wantErr bool
+	}{
+		{"allow", replyJSON(`{"verdict":"allow"}`), service.FilterAllow, false},
+		{"flag with reason", replyJSON(`{"verdict":"flag","reason":"needs review"}`), service.FilterFlag, false},
+		{"reject", replyJSON(`{"verdict":"reject","reason":"spam"}`), service.FilterReject, false},
+		{"unknown verdict rejects", replyJSON(`{"verdict":"maybe"}`), service.FilterReject, false},
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}, 0, true},
+		{"invalid json", replyJSON(`not json`), 0, true},
+		{"timeout", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received externalRequest
+			var auth string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("Authorization")
+				_ = json.NewDecoder(r.Body).Decode(&received)
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			filter := NewExternalFilter(&config.ExternalModerationConfig{URL: server.URL, Token: "secret", Timeout: 50 * time.Millisecond})
+			result, err := filter.Check(context.Background(), &service.ModerationInput{AuthorID: "ou_a", Title: "T", Content: "C"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (result.Verdict != tt.want || result.Filter != "external") {
+				t.Errorf("Check() = %+v, want %v", result, tt.want)
+			}
+			if auth != "Bearer secret" || received != (externalRequest{AuthorID: "ou_a", Title: "T", Content: "C"}) {
+				t.Errorf("request = %+v with Authorization %q", received, auth)
+			}
+		})
+	}
+}
+
+func TestExternalFilterFailureFlagsSubmission(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	moderation := NewContentModerationService(
+		NewSizeFilter(100, 0),
+		NewExternalFilter(&config.ExternalModerationConfig{URL: server.URL, Timeout: time.Second}),
+	)
+	result, err := moderation.Moderate(context.Background(), &service.ModerationInput{Content: "正文"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != service.FilterFlag || result.Filter != "external" {
+		t.Errorf("an unavailable external service should flag the submission for review, got %+v", result)
+	}
+}
+
+func replyJSON(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+}
@@ -0,0 +1,89 @@
+// Package contentfilter implements the content filters run on submissions before they are saved and forwarded.
+package contentfilter
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// moderationServiceImpl implements service.ContentModerationService as a chain of filters.
+type moderationServiceImpl struct {
+	filters []service.ContentFilter
+}
+
+// NewContentModerationService creates a moderation service running the filters in order.
+func NewContentModerationService(filters ...service.ContentFilter) service.ContentModerationService {
+	return &moderationServiceImpl{filters: filters}
+}
+
+// NewFiltersFromConfig builds the filter chain enabled in the config: cheap local checks first, the external service last.
+func NewFiltersFromConfig(conf *config.ModerationConfig) ([]service.ContentFilter, error) {
+	var filters []service.ContentFilter
+	if conf.MaxLength > 0 || conf.MaxImages > 0 {
+		filters = append(filters, NewSizeFilter(conf.MaxLength, conf.MaxImages))
+	}
+
+	words, err := loadSensitiveWords(conf)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) > 0 {
+		filters = append(filters, NewSensitiveWordFilter(words, verdictFromString(conf.SensitiveWordAction)))
+	}
+
+	if len(conf.BlockedDomains) > 0 {
+		filters = append(filters, NewDomainBlocklistFilter(conf.BlockedDomains))
+	}
+	if conf.External.URL != "" {
+		filters = append(filters, NewExternalFilter(&conf.External))
+	}
+	return filters, nil
+}
+
+// Moderate runs every filter and keeps the most severe verdict; a rejection stops the chain.
+// A failing filter flags the submission for review instead of blocking or silently publishing it.
+func (s *moderationServiceImpl) Moderate(ctx context.Context, input *service.ModerationInput) (*service.FilterResult, error) {
+	result := &service.FilterResult{Verdict: service.FilterAllow}
+	for _, filter := range s.filters {
+		filterResult, err := filter.Check(ctx, input)
+		if err != nil {
+			logger.Ctx(ctx).Error("Content filter failed, flagging submission for review", zap.String("filter", filter.Name()), zap.Error(err))
+			filterResult = &service.FilterResult{Verdict: service.FilterFlag, Filter: filter.Name(), Reason: "内容审核服务暂时不可用"}
+		}
+		if filterResult.Verdict > result.Verdict {
+			result = filterResult
+		}
+		if result.Verdict == service.FilterReject {
+			break
+		}
+	}
+
+	if result.Verdict != service.FilterAllow {
+		logger.Ctx(ctx).Info("Submission moderated",
+			zap.String("authorOpenID", input.AuthorID),
+			zap.String("verdict", result.Verdict.String()),
+			zap.String("filter", result.Filter),
+			zap.String("reason", result.Reason),
+		)
+	}
+	return result, nil
+}
+
+// verdictFromString parses a verdict name from the config, defaulting to reject.
+func verdictFromString(s string) service.FilterVerdict {
+	switch s {
+	case "allow":
+		return service.FilterAllow
+	case "flag":
+		return service.FilterFlag
+	default:
+		return service.FilterReject
+	}
+}
+
+// Ensure moderationServiceImpl implements ContentModerationService
+var _ service.ContentModerationService = (*moderationServiceImpl)(nil)
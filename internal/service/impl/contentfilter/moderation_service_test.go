@@ -0,0 +1,127 @@
+package contentfilter
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// stubFilter returns a fixed verdict or error and records that it ran.
+type stubFilter struct {
+	name    string
+	verdict service.FilterVerdict
+	err     error
+	ran     *[]string
+}
+
+func (f *stubFilter) Name() string { return f.name }
+
+func (f *stubFilter) Check(context.Context, *service.ModerationInput) (*service.FilterResult, error) {
+	*f.ran = append(*f.ran, f.name)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &service.FilterResult{Verdict: f.verdict, Filter: f.name, Reason: f.name + " reason"}, nil
+}
+
+func TestModerate(t *testing.T) {
+	allow, flag, reject := service.FilterAllow, service.FilterFlag, service.FilterReject
+	failure := errors.New("unavailable")
+	tests := []struct {
+		name        string
+		filters     []stubFilter
+		wantVerdict service.FilterVerdict
+		wantFilter  string
+		wantRan     []string
+	}{
+		{"no filters", nil, allow, "", nil},
+		{"all allow", []stubFilter{{name: "a", verdict: allow}, {name: "b", verdict: allow}}, allow, "", []string{"a", "b"}},
+		{"first flag wins over later flags", []stubFilter{{name: "a", verdict: flag}, {name: "b", verdict: flag}}, flag, "a", []string{"a", "b"}},
+		{"most severe verdict wins", []stubFilter{{name: "a", verdict: flag}, {name: "b", verdict: reject}}, reject, "b", []string{"a", "b"}},
+		{"reject stops the chain", []stubFilter{{name: "a", verdict: reject}, {name: "b", verdict: allow}}, reject, "a", []string{"a"}},
+		{"failing filter flags", []stubFilter{{name: "a", verdict: allow}, {name: "external", err: failure}}, flag, "external", []string{"a", "external"}},
+		{"failure does not downgrade a reject", []stubFilter{{name: "external", err: failure}, {name: "b", verdict: reject}}, reject, "b", []string{"external", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+			filters := make([]service.ContentFilter, len(tt.filters))
+			for i := range tt.filters {
+				tt.filters[i].ran = &ran
+				filters[i] = &tt.filters[i]
+			}
+			result, err := NewContentModerationService(filters...).Moderate(context.Background(), &service.ModerationInput{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Verdict != tt.wantVerdict || result.Filter != tt.wantFilter {
+				t.Errorf("Moderate() = %v from %q, want %v from %q", result.Verdict, result.Filter, tt.wantVerdict, tt.wantFilter)
+			}
+			if !reflect.DeepEqual(ran, tt.wantRan) {
+				t.Errorf("filters ran = %v, want %v", ran, tt.wantRan)
+			}
+		})
+	}
+}
+
+func TestVerdictFromString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want service.FilterVerdict
+	}{
+		{"allow", service.FilterAllow},
+		{"flag", service.FilterFlag},
+		{"reject", service.FilterReject},
+		{"", service.FilterReject},      // unset defaults to reject
+		{"Allow", service.FilterReject}, // unknown verdicts fail closed
+		{"block", service.FilterReject},
+	}
+	for _, tt := range tests {
+		if got := verdictFromString(tt.in); got != tt.want {
+			t.Errorf("verdictFromString(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewFiltersFromConfig(t *testing.T) {
+	wordsFile := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordsFile, []byte("# comment\n\n代购\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	conf := &config.ModerationConfig{
+		External:            config.ExternalModerationConfig{URL: "http://moderation.invalid"},
+		BlockedDomains:      []string{"example.com"},
+		SensitiveWordsFile:  wordsFile,
+		SensitiveWordAction: "flag",
+		MaxLength:           100,
+	}
+	filters, err := NewFiltersFromConfig(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, filter := range filters {
+		names = append(names, filter.Name())
+	}
+	// Cheap local checks run first, the external service last
+	if want := []string{"size", "sensitive_word", "domain_blocklist", "external"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("filters = %v, want %v", names, want)
+	}
+
+	result, _ := filters[1].Check(context.Background(), &service.ModerationInput{Content: "代购"})
+	if result.Verdict != service.FilterFlag {
+		t.Errorf("words from the file should use the configured action, got %v", result.Verdict)
+	}
+
+	if filters, _ := NewFiltersFromConfig(&config.ModerationConfig{}); len(filters) != 0 {
+		t.Errorf("no filters should be enabled by default, got %d", len(filters))
+	}
+	if _, err := NewFiltersFromConfig(&config.ModerationConfig{SensitiveWordsFile: filepath.Join(t.TempDir(), "missing.txt")}); err == nil {
+		t.Error("a missing words file should be an error")
+	}
+}
@@ -0,0 +1,52 @@
+package contentfilter
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// urlPattern matches http(s) links in plain text.
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>()（）]+`)
+
+// postElement holds the post element fields the filters care about.
+type postElement struct {
+	Tag  string `json:"tag"`
+	Href string `json:"href"`
+}
+
+// parsePost decodes the elements of post JSON; invalid JSON yields no elements.
+func parsePost(rawContent string) []postElement {
+	var post struct {
+		Content [][]postElement `json:"content"`
+	}
+	if rawContent == "" || json.Unmarshal([]byte(rawContent), &post) != nil {
+		return nil
+	}
+	var elements []postElement
+	for _, line := range post.Content {
+		elements = append(elements, line...)
+	}
+	return elements
+}
+
+// countImages returns the number of img elements in post JSON.
+func countImages(rawContent string) int {
+	count := 0
+	for _, element := range parsePost(rawContent) {
+		if element.Tag == "img" {
+			count++
+		}
+	}
+	return count
+}
+
+// extractLinks returns the links of a tags in the post and the URLs appearing in the text.
+func extractLinks(rawContent, text string) []string {
+	var links []string
+	for _, element := range parsePost(rawContent) {
+		if element.Tag == "a" && element.Href != "" {
+			links = append(links, element.Href)
+		}
+	}
+	return append(links, urlPattern.FindAllString(text, -1)...)
+}
@@ -0,0 +1,67 @@
+package contentfilter
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/ahocorasick"
+	"MikoNews/internal/service"
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// sensitiveWordFilter matches the title and content against a sensitive word list.
+type sensitiveWordFilter struct {
+	matcher *ahocorasick.Matcher
+	verdict service.FilterVerdict // verdict for content containing sensitive words
+}
+
+// NewSensitiveWordFilter creates a filter returning verdict when any of the words appears.
+func NewSensitiveWordFilter(words []string, verdict service.FilterVerdict) service.ContentFilter {
+	return &sensitiveWordFilter{matcher: ahocorasick.New(words), verdict: verdict}
+}
+
+// Name returns the filter name.
+func (f *sensitiveWordFilter) Name() string {
+	return "sensitive_word"
+}
+
+// Check looks for sensitive words in a single pass over the title and content.
+func (f *sensitiveWordFilter) Check(ctx context.Context, input *service.ModerationInput) (*service.FilterResult, error) {
+	found := f.matcher.FindAll(input.Title + "\n" + input.Content)
+	if len(found) == 0 {
+		return &service.FilterResult{Verdict: service.FilterAllow, Filter: f.Name()}, nil
+	}
+	return &service.FilterResult{
+		Verdict: f.verdict,
+		Filter:  f.Name(),
+		Reason:  fmt.Sprintf("内容包含敏感词：%s", strings.Join(found, "、")),
+	}, nil
+}
+
+// loadSensitiveWords merges the words configured inline with those in the words file (one per line, # for comments).
+func loadSensitiveWords(conf *config.ModerationConfig) ([]string, error) {
+	words := append([]string(nil), conf.SensitiveWords...)
+	if conf.SensitiveWordsFile == "" {
+		return words, nil
+	}
+
+	file, err := os.Open(conf.SensitiveWordsFile)
+	if err != nil {
+		return nil, fmt.Errorf("打开敏感词文件失败: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取敏感词文件失败: %w", err)
+	}
+	return words, nil
+}
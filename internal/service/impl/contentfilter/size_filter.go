@@ -0,0 +1,43 @@
+package contentfilter
+
+import (
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"unicode/utf8"
+)
+
+// sizeFilter rejects submissions that are too long or contain too many images.
+type sizeFilter struct {
+	maxLength int // in characters, 0 means unlimited
+	maxImages int // 0 means unlimited
+}
+
+// NewSizeFilter creates a filter limiting the content length and the number of images.
+func NewSizeFilter(maxLength, maxImages int) service.ContentFilter {
+	return &sizeFilter{maxLength: maxLength, maxImages: maxImages}
+}
+
+// Name returns the filter name.
+func (f *sizeFilter) Name() string {
+	return "size"
+}
+
+// Check rejects the submission if it exceeds the limits.
+func (f *sizeFilter) Check(ctx context.Context, input *service.ModerationInput) (*service.FilterResult, error) {
+	if length := utf8.RuneCountInString(input.Content); f.maxLength > 0 && length > f.maxLength {
+		return &service.FilterResult{
+			Verdict: service.FilterReject,
+			Filter:  f.Name(),
+			Reason:  fmt.Sprintf("内容过长（%d 字），最多 %d 字", length, f.maxLength),
+		}, nil
+	}
+	if images := countImages(input.RawContent); f.maxImages > 0 && images > f.maxImages {
+		return &service.FilterResult{
+			Verdict: service.FilterReject,
+			Filter:  f.Name(),
+			Reason:  fmt.Sprintf("图片过多（%d 张），最多 %d 张", images, f.maxImages),
+		}, nil
+	}
+	return &service.FilterResult{Verdict: service.FilterAllow, Filter: f.Name()}, nil
+}
@@ -0,0 +1,400 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/crypto"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/metrics"
+	"MikoNews/internal/pkg/tracing"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	eventQueuePollInterval    = 5 * time.Second // 扫描到期任务的间隔，新事件入队时立即扫描
+	eventQueueBatchSize       = 50              // 每次扫描最多读取的任务数
+	eventQueueLease           = 5 * time.Minute // 抢占任务后的租约，进程退出前未完成的任务在租约到期后重新处理
+	eventQueueStatsInterval   = time.Minute     // 输出队列统计日志的间隔
+	eventQueueCleanupInterval = time.Hour       // 清理已处理任务的间隔
+)
+
+// ErrEventQueueClosed 表示队列正在关闭，不再接受新的事件
+var ErrEventQueueClosed = errors.New("事件队列正在关闭")
+
+// eventQueueService 实现了 EventQueueService 接口
+//
+// 单个扫描协程按入队顺序读取到期任务，抢占后按会话分配给固定的工作协程，每个工作协程依次处理自己缓冲区中的任务，
+// 因此同一会话的事件按顺序处理。缓冲区满时扫描协程等待，未读取的任务留在数据库中
+type eventQueueService struct {
+	repo      repository.EventJobRepository
+	conf      *config.EventQueueConfig
+	tenantKey string
+	handlers  map[string]service.EventHandler
+	workers   []chan *model.EventJob
+	wake      chan struct{} // 新事件入队时通知扫描协程
+	stopping  chan struct{} // Drain 时关闭
+	stopOnce  sync.Once
+	started   atomic.Bool
+	done      chan struct{} // Run 返回前关闭
+	now       func() time.Time
+
+	buffered   atomic.Int64
+	active     atomic.Int64
+	succeeded  atomic.Int64
+	failed     atomic.Int64
+	duplicates atomic.Int64
+}
+
+var _ service.EventQueueService = (*eventQueueService)(nil)
+
+// NewEventQueueService 创建一个新的 eventQueueService 实例，repo 限定在 tenantKey 所属租户
+func NewEventQueueService(repo repository.EventJobRepository, conf *config.EventQueueConfig, tenantKey string) service.EventQueueService {
+	workers := make([]chan *model.EventJob, conf.Workers)
+	for i := range workers {
+		workers[i] = make(chan *model.EventJob, conf.Buffer)
+	}
+	return &eventQueueService{
+		repo:      repo,
+		conf:      conf,
+		tenantKey: tenantKey,
+		handlers:  make(map[string]service.EventHandler),
+		workers:   workers,
+		wake:      make(chan struct{}, 1),
+		stopping:  make(chan struct{}),
+		done:      make(chan struct{}),
+		now:       time.Now,
+	}
+}
+
+// Handle 注册事件类型的处理函数
+func (s *eventQueueService) Handle(eventType string, handler service.EventHandler) {
+	s.handlers[eventType] = handler
+}
+
+// Enqueue 将事件写入数据库并通知扫描协程，重复推送的事件直接忽略
+func (s *eventQueueService) Enqueue(ctx context.Context, event *service.QueuedEvent) error {
+	select {
+	case <-s.stopping:
+		return ErrEventQueueClosed
+	default:
+	}
+
+	eventID := event.ID
+	if eventID == "" {
+		eventID = crypto.RandomToken(16)
+	}
+	job := &model.EventJob{
+		EventID:   eventID,
+		EventType: event.Type,
+		ChatID:    event.ChatID,
+		Payload:   string(event.Payload),
+		Status:    model.EventJobPending,
+		// 处理时延续接收事件时的链路
+		TraceParent: tracing.TraceParent(ctx),
+		// 取整到秒，避免 MySQL TIMESTAMP 进位后晚于扫描时的当前时间
+		NextAttemptAt: s.now().Truncate(time.Second),
+	}
+	created, err := s.repo.CreateJob(ctx, job)
+	if err != nil {
+		return fmt.Errorf("事件入队失败: %w", err)
+	}
+	if !created {
+		s.duplicates.Add(1)
+		metrics.EventQueueDuplicate(s.tenantKey)
+		logger.Ctx(ctx).Info("Ignored redelivered event", zap.String("tenantKey", s.tenantKey), zap.String("eventType", event.Type), zap.String("eventID", eventID))
+		return nil
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run 启动工作协程并扫描到期任务，直到 ctx 被取消或 Drain 被调用。
+// 工作协程使用与 ctx 解绑的 context 处理任务，退出前处理完当前任务，缓冲区中尚未开始的任务交还数据库
+func (s *eventQueueService) Run(ctx context.Context) error {
+	s.started.Store(true)
+	defer close(s.done)
+
+	var wg sync.WaitGroup
+	for _, jobs := range s.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx, jobs)
+		}()
+	}
+
+	logger.Ctx(ctx).Info("Event queue started", zap.String("tenantKey", s.tenantKey), zap.Int("workers", len(s.workers)))
+	s.poll(ctx)
+	for _, jobs := range s.workers {
+		close(jobs)
+	}
+	wg.Wait()
+	return nil
+}
+
+// Drain 停止接收和分配事件，并等待工作协程处理完当前任务
+func (s *eventQueueService) Drain(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopping) })
+	if !s.started.Load() {
+		return nil
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待事件处理完成超时: %w", ctx.Err())
+	}
+}
+
+// Stats 返回队列的统计信息
+func (s *eventQueueService) Stats(ctx context.Context) (*service.EventQueueStats, error) {
+	pending, oldest, err := s.repo.CountPending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("统计待处理事件失败: %w", err)
+	}
+	stats := &service.EventQueueStats{
+		Pending:    pending,
+		Buffered:   int(s.buffered.Load()),
+		Active:     int(s.active.Load()),
+		Succeeded:  s.succeeded.Load(),
+		Failed:     s.failed.Load(),
+		Duplicates: s.duplicates.Load(),
+	}
+	if !oldest.IsZero() {
+		stats.OldestPending = s.now().Sub(oldest)
+	}
+	return stats, nil
+}
+
+// stopped 判断队列是否正在关闭
+func (s *eventQueueService) stopped(ctx context.Context) bool {
+	select {
+	case <-s.stopping:
+		return true
+	default:
+		return ctx.Err() != nil
+	}
+}
+
+// poll 定期扫描到期任务并分配给工作协程，同时定期输出统计和清理已处理的任务
+func (s *eventQueueService) poll(ctx context.Context) {
+	ticker := time.NewTicker(eventQueuePollInterval)
+	defer ticker.Stop()
+	statsTicker := time.NewTicker(eventQueueStatsInterval)
+	defer statsTicker.Stop()
+	cleanupTicker := time.NewTicker(eventQueueCleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		s.dispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopping:
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		case <-statsTicker.C:
+			s.logStats(ctx)
+		case <-cleanupTicker.C:
+			s.cleanup(ctx)
+		}
+	}
+}
+
+// dispatchDue 按入队顺序抢占到期任务，放入所属会话的工作协程的缓冲区
+func (s *eventQueueService) dispatchDue(ctx context.Context) {
+	for !s.stopped(ctx) {
+		now := s.now()
+		jobs, err := s.repo.ListDueJobs(ctx, now, eventQueueBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Ctx(ctx).Error("Failed to list due event jobs", zap.String("tenantKey", s.tenantKey), zap.Error(err))
+			}
+			return
+		}
+		for _, job := range jobs {
+			if s.stopped(ctx) {
+				return
+			}
+			claimed, err := s.repo.ClaimJob(ctx, job, now.Add(eventQueueLease))
+			if err != nil {
+				logger.Ctx(ctx).Error("Failed to claim event job", zap.Int64("jobID", job.ID), zap.Error(err))
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			if job.Attempts > s.conf.MaxAttempts {
+				// 多次在处理中途退出，可能是该事件导致进程崩溃，不再重试
+				s.finish(ctx, job, fmt.Errorf("尝试 %d 次仍未处理完成", s.conf.MaxAttempts))
+				continue
+			}
+
+			s.addBuffered(1)
+			select {
+			case s.workers[s.route(job)] <- job:
+			case <-ctx.Done():
+				s.addBuffered(-1)
+				s.release(ctx, job)
+				return
+			case <-s.stopping:
+				s.addBuffered(-1)
+				s.release(ctx, job)
+				return
+			}
+		}
+		if len(jobs) < eventQueueBatchSize {
+			return
+		}
+	}
+}
+
+// route 返回处理任务的工作协程，同一会话的任务总是分配给同一个工作协程
+func (s *eventQueueService) route(job *model.EventJob) int {
+	if job.ChatID == "" {
+		return int(job.ID % int64(len(s.workers)))
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(job.ChatID))
+	return int(h.Sum32() % uint32(len(s.workers)))
+}
+
+// work 依次处理缓冲区中的任务，队列关闭后将尚未开始的任务交还数据库
+func (s *eventQueueService) work(ctx context.Context, jobs <-chan *model.EventJob) {
+	for job := range jobs {
+		s.addBuffered(-1)
+		if s.stopped(ctx) {
+			s.release(ctx, job)
+			continue
+		}
+		s.process(ctx, job)
+	}
+}
+
+// process 在入队时的链路中处理一个任务并保存结果
+func (s *eventQueueService) process(ctx context.Context, job *model.EventJob) {
+	jobCtx := tracing.WithTraceParent(context.WithoutCancel(ctx), job.TraceParent)
+	jobCtx, span := tracing.Start(jobCtx, "event_queue.process",
+		attribute.String("event.type", job.EventType),
+		attribute.Int64("event_queue.job_id", job.ID),
+		attribute.Int("event_queue.attempts", job.Attempts),
+	)
+	err := s.handle(jobCtx, job)
+	s.finish(jobCtx, job, err)
+	tracing.End(span, err)
+}
+
+// handle 调用事件类型对应的处理函数
+func (s *eventQueueService) handle(ctx context.Context, job *model.EventJob) (err error) {
+	handler, ok := s.handlers[job.EventType]
+	if !ok {
+		return fmt.Errorf("未注册的事件类型: %s", job.EventType)
+	}
+
+	s.addActive(1)
+	defer s.addActive(-1)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("处理事件时发生 panic: %v", r)
+			logger.Ctx(ctx).Error("Event handler panicked", zap.Int64("jobID", job.ID), zap.Any("panic", r), zap.ByteString("stack", debug.Stack()))
+		}
+	}()
+	return handler(ctx, []byte(job.Payload))
+}
+
+// finish 保存任务的处理结果。处理函数已向用户回复了出错信息，重试可能导致重复回复，因此出错的任务不再重试
+func (s *eventQueueService) finish(ctx context.Context, job *model.EventJob, err error) {
+	job.Status = model.EventJobSucceeded
+	job.LastError = ""
+	if err != nil {
+		job.Status = model.EventJobFailed
+		job.LastError = truncateError(err.Error())
+		s.failed.Add(1)
+		metrics.EventQueueFinished(s.tenantKey, err)
+		logger.Ctx(ctx).Error("Error processing queued event",
+			zap.String("tenantKey", s.tenantKey),
+			zap.Int64("jobID", job.ID),
+			zap.String("eventType", job.EventType),
+			zap.Int("attempts", job.Attempts),
+			zap.Error(err),
+		)
+	} else {
+		s.succeeded.Add(1)
+		metrics.EventQueueFinished(s.tenantKey, nil)
+	}
+	if err := s.repo.UpdateJob(context.WithoutCancel(ctx), job); err != nil {
+		logger.Ctx(ctx).Error("Failed to save event job", zap.Int64("jobID", job.ID), zap.Error(err))
+	}
+}
+
+// addBuffered 调整缓冲区中的任务数，同时更新指标
+func (s *eventQueueService) addBuffered(delta int) {
+	s.buffered.Add(int64(delta))
+	metrics.AddEventQueueBuffered(s.tenantKey, delta)
+}
+
+// addActive 调整正在处理的任务数，同时更新指标
+func (s *eventQueueService) addActive(delta int) {
+	s.active.Add(int64(delta))
+	metrics.AddEventQueueActive(s.tenantKey, delta)
+}
+
+// release 将抢占但未开始处理的任务交还数据库，重启后立即处理，且不计入尝试次数
+func (s *eventQueueService) release(ctx context.Context, job *model.EventJob) {
+	job.Attempts--
+	job.NextAttemptAt = s.now().Truncate(time.Second)
+	if err := s.repo.UpdateJob(context.WithoutCancel(ctx), job); err != nil {
+		logger.Ctx(ctx).Warn("Failed to release event job, it will be retried after the lease expires", zap.Int64("jobID", job.ID), zap.Error(err))
+	}
+}
+
+// logStats 更新待处理任务数指标，并在有待处理事件时输出队列统计
+func (s *eventQueueService) logStats(ctx context.Context) {
+	stats, err := s.Stats(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Warn("Failed to get event queue stats", zap.String("tenantKey", s.tenantKey), zap.Error(err))
+		return
+	}
+	metrics.SetEventQueuePending(s.tenantKey, stats.Pending)
+	if stats.Pending == 0 {
+		return
+	}
+	logger.Ctx(ctx).Info("Event queue stats",
+		zap.String("tenantKey", s.tenantKey),
+		zap.Int64("pending", stats.Pending),
+		zap.Duration("oldestPending", stats.OldestPending),
+		zap.Int("buffered", stats.Buffered),
+		zap.Int("active", stats.Active),
+		zap.Int64("succeeded", stats.Succeeded),
+		zap.Int64("failed", stats.Failed),
+	)
+}
+
+// cleanup 删除超过保留时长的已处理任务
+func (s *eventQueueService) cleanup(ctx context.Context) {
+	deleted, err := s.repo.DeleteFinishedJobs(ctx, s.now().Add(-s.conf.Retention))
+	if err != nil {
+		logger.Ctx(ctx).Warn("Failed to delete finished event jobs", zap.String("tenantKey", s.tenantKey), zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		logger.Ctx(ctx).Info("Deleted finished event jobs", zap.String("tenantKey", s.tenantKey), zap.Int64("count", deleted))
+	}
+}
@@ -0,0 +1,361 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/tracing"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fakeEventJobRepo keeps event jobs in memory with the semantics of the database implementation.
+type fakeEventJobRepo struct {
+	mu     sync.Mutex
+	jobs   []*model.EventJob
+	nextID int64
+}
+
+var _ repository.EventJobRepository = (*fakeEventJobRepo)(nil)
+
+func (r *fakeEventJobRepo) CreateJob(_ context.Context, job *model.EventJob) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.jobs {
+		if existing.EventID == job.EventID {
+			return false, nil
+		}
+	}
+	r.nextID++
+	job.ID = r.nextID
+	job.CreatedAt = time.Now()
+	saved := *job
+	r.jobs = append(r.jobs, &saved)
+	return true, nil
+}
+
+func (r *fakeEventJobRepo) ListDueJobs(_ context.Context, now time.Time, limit int) ([]*model.EventJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*model.EventJob
+	for _, job := range r.jobs {
+		if job.Status == model.EventJobPending && !job.NextAttemptAt.After(now) && len(due) < limit {
+			copied := *job
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeEventJobRepo) ClaimJob(_ context.Context, job *model.EventJob, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := r.find(job.ID)
+	if saved.Status != model.EventJobPending || !saved.NextAttemptAt.Equal(job.NextAttemptAt) {
+		return false, nil
+	}
+	saved.NextAttemptAt = leaseUntil
+	saved.Attempts++
+	job.NextAttemptAt, job.Attempts = saved.NextAttemptAt, saved.Attempts
+	return true, nil
+}
+
+func (r *fakeEventJobRepo) UpdateJob(_ context.Context, job *model.EventJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := r.find(job.ID)
+	saved.Status, saved.Attempts, saved.LastError, saved.NextAttemptAt = job.Status, job.Attempts, job.LastError, job.NextAttemptAt
+	return nil
+}
+
+func (r *fakeEventJobRepo) CountPending(_ context.Context) (int64, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	var oldest time.Time
+	for _, job := range r.jobs {
+		if job.Status == model.EventJobPending {
+			if count == 0 {
+				oldest = job.CreatedAt
+			}
+			count++
+		}
+	}
+	return count, oldest, nil
+}
+
+func (r *fakeEventJobRepo) DeleteFinishedJobs(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeEventJobRepo) find(id int64) *model.EventJob {
+	for _, job := range r.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	panic(fmt.Sprintf("job %d not found", id))
+}
+
+// get returns a copy of the saved job with the given event ID.
+func (r *fakeEventJobRepo) get(eventID string) model.EventJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.EventID == eventID {
+			return *job
+		}
+	}
+	panic(fmt.Sprintf("event %s not found", eventID))
+}
+
+func newTestEventQueue(repo repository.EventJobRepository, workers int) *eventQueueService {
+	conf := &config.EventQueueConfig{Mode: config.EventQueueModeAsync, Workers: workers, Buffer: 2, MaxAttempts: 2, Retention: time.Hour}
+	return NewEventQueueService(repo, conf, "tenant").(*eventQueueService)
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEventQueueProcessesChatsInOrder(t *testing.T) {
+	repo := &fakeEventJobRepo{}
+	queue := newTestEventQueue(repo, 3)
+
+	var mu sync.Mutex
+	processed := map[string][]string{}
+	queue.Handle("message", func(_ context.Context, payload []byte) error {
+		var chat, text string
+		_, _ = fmt.Sscanf(string(payload), "%s %s", &chat, &text)
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		processed[chat] = append(processed[chat], text)
+		return nil
+	})
+	queue.Handle("broken", func(context.Context, []byte) error {
+		return fmt.Errorf("broken event")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = queue.Run(ctx) }()
+
+	want := map[string][]string{}
+	for i := 0; i < 10; i++ {
+		for _, chat := range []string{"oc_a", "oc_b", "oc_c"} {
+			text := fmt.Sprintf("m%02d", i)
+			want[chat] = append(want[chat], text)
+			event := &service.QueuedEvent{Type: "message", ID: chat + text, ChatID: chat, Payload: []byte(chat + " " + text)}
+			if err := queue.Enqueue(ctx, event); err != nil {
+				t.Fatalf("Enqueue: %v", err)
+			}
+		}
+	}
+	// A redelivered event is ignored
+	if err := queue.Enqueue(ctx, &service.QueuedEvent{Type: "message", ID: "oc_am00", ChatID: "oc_a", Payload: []byte("oc_a dup")}); err != nil {
+		t.Fatalf("Enqueue duplicate: %v", err)
+	}
+	if err := queue.Enqueue(ctx, &service.QueuedEvent{Type: "broken", ID: "broken"}); err != nil {
+		t.Fatalf("Enqueue broken: %v", err)
+	}
+
+	waitFor(t, "all events", func() bool {
+		stats, err := queue.Stats(ctx)
+		return err == nil && stats.Pending == 0
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(processed, want) {
+		t.Errorf("events of a chat must be processed in order:\n got: %v\nwant: %v", processed, want)
+	}
+	stats, _ := queue.Stats(ctx)
+	if stats.Succeeded != 30 || stats.Failed != 1 || stats.Duplicates != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if job := repo.get("broken"); job.Status != model.EventJobFailed || job.LastError != "broken event" {
+		t.Errorf("a failed event must be saved with its error: %+v", job)
+	}
+}
+
+func TestEventQueueResumesUnfinishedJobs(t *testing.T) {
+	repo := &fakeEventJobRepo{}
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Minute).Truncate(time.Second)
+	// A job whose lease expired after the process exited, and one that already used up its attempts
+	for _, job := range []*model.EventJob{
+		{EventID: "interrupted", EventType: "message", Status: model.EventJobPending, Attempts: 1, NextAttemptAt: expired},
+		{EventID: "crashing", EventType: "message", Status: model.EventJobPending, Attempts: 2, NextAttemptAt: expired},
+	} {
+		if _, err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queue := newTestEventQueue(repo, 1)
+	var mu sync.Mutex
+	var handled []string
+	queue.Handle("message", func(context.Context, []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, "handled")
+		return nil
+	})
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() { _ = queue.Run(runCtx) }()
+
+	waitFor(t, "resumed jobs", func() bool {
+		return repo.get("interrupted").Status == model.EventJobSucceeded && repo.get("crashing").Status == model.EventJobFailed
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 {
+		t.Errorf("only the interrupted job must be handled, handled %d", len(handled))
+	}
+	if job := repo.get("interrupted"); job.Attempts != 2 {
+		t.Errorf("the resumed attempt must be counted: %+v", job)
+	}
+}
+
+func TestEventQueueDrain(t *testing.T) {
+	repo := &fakeEventJobRepo{}
+	queue := newTestEventQueue(repo, 1)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled []string
+	queue.Handle("message", func(_ context.Context, payload []byte) error {
+		started <- struct{}{}
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, string(payload))
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runDone := make(chan struct{})
+	go func() {
+		_ = queue.Run(ctx)
+		close(runDone)
+	}()
+
+	for _, id := range []string{"first", "second"} {
+		if err := queue.Enqueue(ctx, &service.QueuedEvent{Type: "message", ID: id, ChatID: "oc_a", Payload: []byte(id)}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	<-started
+	waitFor(t, "the second event to be buffered", func() bool {
+		stats, _ := queue.Stats(ctx)
+		return stats.Buffered == 1
+	})
+
+	drained := make(chan error, 1)
+	go func() { drained <- queue.Drain(context.Background()) }()
+	select {
+	case <-drained:
+		t.Fatal("Drain must wait for the event being processed")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if err := queue.Enqueue(ctx, &service.QueuedEvent{Type: "message", ID: "late"}); err != ErrEventQueueClosed {
+		t.Errorf("Enqueue after Drain must fail, got %v", err)
+	}
+
+	close(release)
+	if err := <-drained; err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	<-runDone
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(handled, []string{"first"}) {
+		t.Errorf("only the started event must be processed, got %v", handled)
+	}
+	// The buffered event is released to be processed after a restart, without using an attempt
+	second := repo.get("second")
+	if second.Status != model.EventJobPending || second.Attempts != 0 || second.NextAttemptAt.After(time.Now()) {
+		t.Errorf("the buffered event must be released: %+v", second)
+	}
+}
+
+func TestEventQueueRoute(t *testing.T) {
+	queue := newTestEventQueue(&fakeEventJobRepo{}, 4)
+	workers := map[int]bool{}
+	for i := int64(1); i <= 8; i++ {
+		job := &model.EventJob{ID: i, ChatID: "oc_same"}
+		workers[queue.route(job)] = true
+	}
+	if len(workers) != 1 {
+		t.Errorf("events of a chat must go to one worker, got %v", workers)
+	}
+
+	var spread []int
+	for i := int64(1); i <= 4; i++ {
+		spread = append(spread, queue.route(&model.EventJob{ID: i}))
+	}
+	sort.Ints(spread)
+	if !reflect.DeepEqual(spread, []int{0, 1, 2, 3}) {
+		t.Errorf("events without a chat must be spread across workers, got %v", spread)
+	}
+}
+
+func TestEventQueueContinuesTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), "miko-news-test", 1)
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	repo := &fakeEventJobRepo{}
+	queue := newTestEventQueue(repo, 1)
+	handled := make(chan trace.SpanContext, 1)
+	queue.Handle("message", func(ctx context.Context, _ []byte) error {
+		handled <- trace.SpanContextFromContext(ctx)
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = queue.Run(ctx) }()
+
+	receiveCtx, receive := tracing.Start(ctx, "receive")
+	if err := queue.Enqueue(receiveCtx, &service.QueuedEvent{Type: "message", ID: "traced"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	receive.End()
+
+	// The handler runs in a span of the trace the event was received in
+	got := <-handled
+	if got.TraceID() != receive.SpanContext().TraceID() {
+		t.Errorf("the event must be processed in the trace it was received in")
+	}
+	waitFor(t, "the process span", func() bool {
+		for _, span := range exporter.GetSpans() {
+			if span.Name == "event_queue.process" {
+				return span.Parent.SpanID() == receive.SpanContext().SpanID()
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,119 @@
+package impl
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkbitable "github.com/larksuite/oapi-sdk-go/v3/service/bitable/v1"
+	"go.uber.org/zap"
+)
+
+// feishuBitableServiceImpl implements service.FeishuBitableService
+type feishuBitableServiceImpl struct {
+	client *lark.Client
+}
+
+// NewFeishuBitableService creates a new Feishu Bitable service implementation.
+func NewFeishuBitableService(client *lark.Client) service.FeishuBitableService {
+	return &feishuBitableServiceImpl{
+		client: client,
+	}
+}
+
+// FindRecord 按字段值查找记录，返回第一条匹配记录的ID
+func (s *feishuBitableServiceImpl) FindRecord(ctx context.Context, appToken, tableID, field, value string) (string, error) {
+	req := larkbitable.NewSearchAppTableRecordReqBuilder().
+		AppToken(appToken).
+		TableId(tableID).
+		PageSize(1).
+		Body(larkbitable.NewSearchAppTableRecordReqBodyBuilder().
+			FieldNames([]string{field}).
+			Filter(larkbitable.NewFilterInfoBuilder().
+				Conjunction("and").
+				Conditions([]*larkbitable.Condition{
+					larkbitable.NewConditionBuilder().
+						FieldName(field).
+						Operator("is").
+						Value([]string{value}).
+						Build(),
+				}).
+				Build()).
+			Build()).
+		Build()
+
+	apiCtx, finish := startFeishuAPI(ctx, "bitable.app_table_record.search")
+	resp, err := s.client.Bitable.V1.AppTableRecord.Search(apiCtx, req)
+	finish(err, func() int { return resp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu search records API", zap.String("tableID", tableID), zap.Error(err))
+		return "", fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+	if !resp.Success() {
+		logger.Ctx(ctx).Error("Feishu search records API call unsuccessful",
+			zap.String("tableID", tableID),
+			zap.Int("code", resp.Code),
+			zap.String("msg", resp.Msg),
+		)
+		return "", fmt.Errorf("查询多维表格记录失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+	if len(resp.Data.Items) == 0 || resp.Data.Items[0].RecordId == nil {
+		return "", nil
+	}
+	return *resp.Data.Items[0].RecordId, nil
+}
+
+// CreateRecord 新增一条记录
+func (s *feishuBitableServiceImpl) CreateRecord(ctx context.Context, appToken, tableID string, fields map[string]interface{}) (string, error) {
+	req := larkbitable.NewCreateAppTableRecordReqBuilder().
+		AppToken(appToken).
+		TableId(tableID).
+		AppTableRecord(larkbitable.NewAppTableRecordBuilder().Fields(fields).Build()).
+		Build()
+
+	apiCtx, finish := startFeishuAPI(ctx, "bitable.app_table_record.create")
+	resp, err := s.client.Bitable.V1.AppTableRecord.Create(apiCtx, req)
+	finish(err, func() int { return resp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu create record API", zap.String("tableID", tableID), zap.Error(err))
+		return "", fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+	if !resp.Success() {
+		logger.Ctx(ctx).Error("Feishu create record API call unsuccessful",
+			zap.String("tableID", tableID),
+			zap.Int("code", resp.Code),
+			zap.String("msg", resp.Msg),
+		)
+		return "", fmt.Errorf("新增多维表格记录失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+	return *resp.Data.Record.RecordId, nil
+}
+
+// UpdateRecord 更新记录的指定字段
+func (s *feishuBitableServiceImpl) UpdateRecord(ctx context.Context, appToken, tableID, recordID string, fields map[string]interface{}) error {
+	req := larkbitable.NewUpdateAppTableRecordReqBuilder().
+		AppToken(appToken).
+		TableId(tableID).
+		RecordId(recordID).
+		AppTableRecord(larkbitable.NewAppTableRecordBuilder().Fields(fields).Build()).
+		Build()
+
+	apiCtx, finish := startFeishuAPI(ctx, "bitable.app_table_record.update")
+	resp, err := s.client.Bitable.V1.AppTableRecord.Update(apiCtx, req)
+	finish(err, func() int { return resp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu update record API", zap.String("recordID", recordID), zap.Error(err))
+		return fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+	if !resp.Success() {
+		logger.Ctx(ctx).Error("Feishu update record API call unsuccessful",
+			zap.String("recordID", recordID),
+			zap.Int("code", resp.Code),
+			zap.String("msg", resp.Msg),
+		)
+		return fmt.Errorf("更新多维表格记录失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+	return nil
+}
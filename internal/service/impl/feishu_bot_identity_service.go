@@ -0,0 +1,137 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	"go.uber.org/zap"
+)
+
+// botInfoResp 对应 /open-apis/bot/v3/info 的响应结构
+type botInfoResp struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Bot  struct {
+		AppName string `json:"app_name"`
+		OpenID  string `json:"open_id"`
+	} `json:"bot"`
+}
+
+// botIdentityRetryInterval 获取机器人信息失败后，在该时长内直接返回上次的错误，避免 API 故障期间每条群消息都发起请求
+const botIdentityRetryInterval = 30 * time.Second
+
+// feishuBotIdentityServiceImpl implements the FeishuBotIdentityService interface.
+// The open_id comes from config if set, otherwise it is fetched from the bot info API and cached.
+// Concurrent callers share one request, and a failure is cached for botIdentityRetryInterval.
+type feishuBotIdentityServiceImpl struct {
+	client *lark.Client
+	cfg    *config.FeishuConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	openID   string
+	fetching chan struct{} // 请求进行中时非空，请求结束后关闭
+	lastErr  error         // 上次请求的错误
+	retryAt  time.Time     // 在此之前不再重试，直接返回 lastErr
+}
+
+// NewFeishuBotIdentityService creates a new bot identity service implementation.
+func NewFeishuBotIdentityService(client *lark.Client, cfg *config.FeishuConfig) service.FeishuBotIdentityService {
+	return &feishuBotIdentityServiceImpl{
+		client: client,
+		cfg:    cfg,
+		now:    time.Now,
+		openID: cfg.BotOpenID,
+	}
+}
+
+// BotOpenID returns the open_id of the bot.
+func (s *feishuBotIdentityServiceImpl) BotOpenID(ctx context.Context) (string, error) {
+	for {
+		s.mu.Lock()
+		if s.openID != "" {
+			openID := s.openID
+			s.mu.Unlock()
+			return openID, nil
+		}
+		if s.now().Before(s.retryAt) {
+			err := s.lastErr
+			s.mu.Unlock()
+			return "", err
+		}
+		if s.fetching == nil {
+			break // 由当前调用方发起请求，此时仍持有锁
+		}
+		// 等待进行中的请求，结束后重新读取结果
+		fetching := s.fetching
+		s.mu.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	fetching := make(chan struct{})
+	s.fetching = fetching
+	s.mu.Unlock()
+
+	// 请求期间不持有锁，其他调用方等待 fetching 关闭或自己的 ctx 取消
+	openID, err := s.fetchOpenID(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetching = nil
+	close(fetching)
+	if err != nil {
+		// 调用方取消导致的失败不缓存，等待中的调用方会重新发起请求
+		if ctx.Err() == nil {
+			s.lastErr = err
+			s.retryAt = s.now().Add(botIdentityRetryInterval)
+		}
+		return "", err
+	}
+	s.openID = openID
+	return openID, nil
+}
+
+// fetchOpenID 调用机器人信息 API 获取机器人的 open_id
+func (s *feishuBotIdentityServiceImpl) fetchOpenID(ctx context.Context) (string, error) {
+	apiCtx, finish := startFeishuAPI(ctx, "bot.info")
+	resp, err := s.client.Get(apiCtx, "/open-apis/bot/v3/info", nil, larkcore.AccessTokenTypeTenant)
+	if err != nil {
+		finish(err, nil)
+		logger.Ctx(ctx).Error("Failed to call Feishu bot info API", zap.Error(err))
+		return "", fmt.Errorf("飞书机器人信息 API 调用失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("获取机器人信息失败: HTTP %d (request_id: %s)", resp.StatusCode, resp.RequestId())
+		finish(err, nil)
+		return "", err
+	}
+
+	var info botInfoResp
+	if err := json.Unmarshal(resp.RawBody, &info); err != nil {
+		finish(err, nil)
+		return "", fmt.Errorf("解析机器人信息失败: %w", err)
+	}
+	finish(nil, func() int { return info.Code })
+	if info.Code != 0 || info.Bot.OpenID == "" {
+		logger.Ctx(ctx).Error("Feishu bot info API call unsuccessful", zap.Int("code", info.Code), zap.String("msg", info.Msg))
+		return "", fmt.Errorf("获取机器人信息失败: %s (code: %d)", info.Msg, info.Code)
+	}
+
+	logger.Ctx(ctx).Info("Resolved bot identity", zap.String("botOpenID", info.Bot.OpenID), zap.String("appName", info.Bot.AppName))
+	return info.Bot.OpenID, nil
+}
+
+// Ensure feishuBotIdentityServiceImpl implements FeishuBotIdentityService
+var _ service.FeishuBotIdentityService = (*feishuBotIdentityServiceImpl)(nil)
@@ -0,0 +1,114 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+)
+
+// fakeBotInfoAPI serves the bot info API, failing while down is set. Requests block until release is closed.
+type fakeBotInfoAPI struct {
+	down    atomic.Bool
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (f *fakeBotInfoAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/open-apis/auth/v3/tenant_access_token/internal" {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "msg": "ok", "tenant_access_token": "t-test", "expire": 7200})
+		return
+	}
+	f.calls.Add(1)
+	<-f.release
+	if f.down.Load() {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 500, "msg": "internal error"})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "msg": "ok", "bot": map[string]string{"app_name": "Miko", "open_id": "ou_bot"}})
+}
+
+func newTestBotIdentity(t *testing.T, api *fakeBotInfoAPI) *feishuBotIdentityServiceImpl {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	client := lark.NewClient("cli_test", "secret", lark.WithOpenBaseUrl(server.URL))
+	return NewFeishuBotIdentityService(client, &config.FeishuConfig{}).(*feishuBotIdentityServiceImpl)
+}
+
+func TestBotOpenIDSharesOneRequest(t *testing.T) {
+	api := &fakeBotInfoAPI{release: make(chan struct{})}
+	identity := newTestBotIdentity(t, api)
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = identity.BotOpenID(context.Background())
+		}()
+	}
+	// Let the callers pile up behind the first request before answering it
+	for api.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(api.release)
+	wg.Wait()
+
+	for _, got := range results {
+		if got != "ou_bot" {
+			t.Errorf("BotOpenID() = %q, want ou_bot", got)
+		}
+	}
+	if got := api.calls.Load(); got != 1 {
+		t.Errorf("bot info API called %d times, want 1", got)
+	}
+	if _, err := identity.BotOpenID(context.Background()); err != nil || api.calls.Load() != 1 {
+		t.Errorf("the open_id should be cached, got err %v after %d calls", err, api.calls.Load())
+	}
+}
+
+func TestBotOpenIDCachesFailures(t *testing.T) {
+	api := &fakeBotInfoAPI{release: make(chan struct{})}
+	close(api.release)
+	api.down.Store(true)
+	identity := newTestBotIdentity(t, api)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	identity.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := identity.BotOpenID(context.Background()); err == nil {
+			t.Fatal("BotOpenID() should fail while the API is down")
+		}
+	}
+	if got := api.calls.Load(); got != 1 {
+		t.Errorf("bot info API called %d times during the backoff, want 1", got)
+	}
+
+	// Retry once the backoff has passed
+	api.down.Store(false)
+	now = now.Add(botIdentityRetryInterval)
+	if got, err := identity.BotOpenID(context.Background()); err != nil || got != "ou_bot" {
+		t.Errorf("BotOpenID() after the backoff = %q, %v; want ou_bot", got, err)
+	}
+	if got := api.calls.Load(); got != 2 {
+		t.Errorf("bot info API called %d times, want 2", got)
+	}
+}
+
+func TestBotOpenIDFromConfig(t *testing.T) {
+	identity := NewFeishuBotIdentityService(nil, &config.FeishuConfig{BotOpenID: "ou_configured"})
+	if got, err := identity.BotOpenID(context.Background()); err != nil || got != "ou_configured" {
+		t.Errorf("BotOpenID() = %q, %v; want the configured open_id", got, err)
+	}
+}
@@ -30,22 +30,24 @@ func (s *feishuContactServiceImpl) GetUserInfoByOpenID(ctx context.Context, open
 	req := larkcontact.NewGetUserReqBuilder().
 		UserId(openID).
 		UserIdType(larkcontact.UserIdTypeOpenId). // Specify we are using open_id
-		// DepartmentIdType could be set if needed, but often not necessary when querying by user ID directly
-		// DepartmentIdType(larkcontact.DepartmentIdTypeOpenDepartmentId).
+		// Use open_department_id so departments match the ones stored by the user directory
+		DepartmentIdType(larkcontact.DepartmentIdTypeOpenDepartmentId).
 		Build()
 
 	// 2. Make the API call
-	resp, err := s.client.Contact.V3.User.Get(ctx, req)
+	apiCtx, finish := startFeishuAPI(ctx, "contact.user.get")
+	resp, err := s.client.Contact.V3.User.Get(apiCtx, req)
+	finish(err, func() int { return resp.Code })
 
 	// 3. Handle potential errors during the API call
 	if err != nil {
-		logger.Error("Failed to call Feishu Contact API", zap.String("openID", openID), zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to call Feishu Contact API", zap.String("openID", openID), zap.Error(err))
 		return nil, fmt.Errorf("飞书联系人 API 调用失败: %w", err)
 	}
 
 	// 4. Handle unsuccessful responses from the Feishu server
 	if !resp.Success() {
-		logger.Error("Feishu Contact API call unsuccessful",
+		logger.Ctx(ctx).Error("Feishu Contact API call unsuccessful",
 			zap.String("openID", openID),
 			zap.String("requestID", resp.RequestId()),
 			zap.Int("code", resp.Code),
@@ -57,13 +59,114 @@ func (s *feishuContactServiceImpl) GetUserInfoByOpenID(ctx context.Context, open
 
 	// 5. Handle success case
 	if resp.Data == nil || resp.Data.User == nil {
-		logger.Warn("Feishu Contact API successful but user data is nil", zap.String("openID", openID), zap.String("requestID", resp.RequestId()))
+		logger.Ctx(ctx).Warn("Feishu Contact API successful but user data is nil", zap.String("openID", openID), zap.String("requestID", resp.RequestId()))
 		return nil, fmt.Errorf("获取飞书用户信息成功，但用户数据为空 (request_id: %s)", resp.RequestId())
 	}
 
-	logger.Debug("Successfully fetched Feishu user info", zap.String("openID", openID), zap.String("userName", *resp.Data.User.Name))
+	logger.Ctx(ctx).Debug("Successfully fetched Feishu user info", zap.String("openID", openID), zap.String("userName", *resp.Data.User.Name))
 	return resp.Data.User, nil
 }
 
+// ListAllUsers lists the users of the root department and all of its descendants.
+func (s *feishuContactServiceImpl) ListAllUsers(ctx context.Context) ([]*larkcontact.User, error) {
+	departmentIDs, err := s.listDepartmentIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	users := make([]*larkcontact.User, 0)
+	for _, departmentID := range departmentIDs {
+		pageToken := ""
+		for {
+			builder := larkcontact.NewFindByDepartmentUserReqBuilder().
+				DepartmentId(departmentID).
+				UserIdType(larkcontact.UserIdTypeOpenId).
+				DepartmentIdType(larkcontact.DepartmentIdTypeOpenDepartmentId).
+				PageSize(50)
+			if pageToken != "" {
+				builder.PageToken(pageToken)
+			}
+
+			apiCtx, finish := startFeishuAPI(ctx, "contact.user.find_by_department")
+			resp, err := s.client.Contact.V3.User.FindByDepartment(apiCtx, builder.Build())
+			finish(err, func() int { return resp.Code })
+			if err != nil {
+				logger.Ctx(ctx).Error("Failed to call Feishu find users by department API", zap.String("departmentID", departmentID), zap.Error(err))
+				return nil, fmt.Errorf("飞书联系人 API 调用失败: %w", err)
+			}
+			if !resp.Success() {
+				logger.Ctx(ctx).Error("Feishu find users by department API call unsuccessful",
+					zap.String("departmentID", departmentID),
+					zap.String("requestID", resp.RequestId()),
+					zap.Int("code", resp.Code),
+					zap.String("msg", resp.Msg),
+				)
+				return nil, fmt.Errorf("获取部门用户失败: %s (code: %d, request_id: %s)", resp.Msg, resp.Code, resp.RequestId())
+			}
+
+			for _, user := range resp.Data.Items {
+				if user == nil || user.OpenId == nil || seen[*user.OpenId] {
+					continue
+				}
+				seen[*user.OpenId] = true
+				users = append(users, user)
+			}
+
+			if resp.Data.HasMore == nil || !*resp.Data.HasMore || resp.Data.PageToken == nil {
+				break
+			}
+			pageToken = *resp.Data.PageToken
+		}
+	}
+
+	logger.Ctx(ctx).Info("Listed all Feishu users", zap.Int("departments", len(departmentIDs)), zap.Int("users", len(users)))
+	return users, nil
+}
+
+// listDepartmentIDs returns the root department "0" followed by all of its descendants.
+func (s *feishuContactServiceImpl) listDepartmentIDs(ctx context.Context) ([]string, error) {
+	departmentIDs := []string{"0"}
+	pageToken := ""
+	for {
+		builder := larkcontact.NewChildrenDepartmentReqBuilder().
+			DepartmentId("0").
+			DepartmentIdType(larkcontact.DepartmentIdTypeOpenDepartmentId).
+			FetchChild(true).
+			PageSize(50)
+		if pageToken != "" {
+			builder.PageToken(pageToken)
+		}
+
+		apiCtx, finish := startFeishuAPI(ctx, "contact.department.children")
+		resp, err := s.client.Contact.V3.Department.Children(apiCtx, builder.Build())
+		finish(err, func() int { return resp.Code })
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to call Feishu department children API", zap.Error(err))
+			return nil, fmt.Errorf("飞书部门 API 调用失败: %w", err)
+		}
+		if !resp.Success() {
+			logger.Ctx(ctx).Error("Feishu department children API call unsuccessful",
+				zap.String("requestID", resp.RequestId()),
+				zap.Int("code", resp.Code),
+				zap.String("msg", resp.Msg),
+			)
+			return nil, fmt.Errorf("获取子部门失败: %s (code: %d, request_id: %s)", resp.Msg, resp.Code, resp.RequestId())
+		}
+
+		for _, department := range resp.Data.Items {
+			if department != nil && department.OpenDepartmentId != nil {
+				departmentIDs = append(departmentIDs, *department.OpenDepartmentId)
+			}
+		}
+
+		if resp.Data.HasMore == nil || !*resp.Data.HasMore || resp.Data.PageToken == nil {
+			break
+		}
+		pageToken = *resp.Data.PageToken
+	}
+	return departmentIDs, nil
+}
+
 // Ensure feishuContactServiceImpl implements FeishuContactService
 var _ service.FeishuContactService = (*feishuContactServiceImpl)(nil)
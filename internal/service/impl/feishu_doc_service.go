@@ -0,0 +1,219 @@
+package impl
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"bytes"
+	"context"
+	"fmt"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
+	larkwiki "github.com/larksuite/oapi-sdk-go/v3/service/wiki/v2"
+	"go.uber.org/zap"
+)
+
+// docxMaxChildren 是单次创建子块的数量上限
+const docxMaxChildren = 50
+
+// feishuDocServiceImpl implements service.FeishuDocService
+type feishuDocServiceImpl struct {
+	client *lark.Client
+}
+
+// NewFeishuDocService creates a new Feishu docs and wiki service implementation.
+func NewFeishuDocService(client *lark.Client) service.FeishuDocService {
+	return &feishuDocServiceImpl{
+		client: client,
+	}
+}
+
+// CreateDocument 创建空白云文档，返回 document_id
+func (s *feishuDocServiceImpl) CreateDocument(ctx context.Context, folderToken string, title string) (string, error) {
+	req := larkdocx.NewCreateDocumentReqBuilder().
+		Body(larkdocx.NewCreateDocumentReqBodyBuilder().
+			FolderToken(folderToken).
+			Title(title).
+			Build()).
+		Build()
+
+	apiCtx, finish := startFeishuAPI(ctx, "docx.document.create")
+	resp, err := s.client.Docx.V1.Document.Create(apiCtx, req)
+	finish(err, func() int { return resp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu create document API", zap.Error(err))
+		return "", fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+	if !resp.Success() {
+		logger.Ctx(ctx).Error("Feishu create document API call unsuccessful", zap.Int("code", resp.Code), zap.String("msg", resp.Msg))
+		return "", fmt.Errorf("创建云文档失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+	return *resp.Data.Document.DocumentId, nil
+}
+
+// AppendBlocks 在文档末尾追加块，超过单次上限时分批创建
+func (s *feishuDocServiceImpl) AppendBlocks(ctx context.Context, documentID string, blocks []*larkdocx.Block) ([]*larkdocx.Block, error) {
+	created := make([]*larkdocx.Block, 0, len(blocks))
+	for start := 0; start < len(blocks); start += docxMaxChildren {
+		end := min(start+docxMaxChildren, len(blocks))
+		req := larkdocx.NewCreateDocumentBlockChildrenReqBuilder().
+			DocumentId(documentID).
+			BlockId(documentID). // 文档的根块ID与 document_id 相同
+			DocumentRevisionId(-1).
+			Body(larkdocx.NewCreateDocumentBlockChildrenReqBodyBuilder().
+				Children(blocks[start:end]).
+				Index(-1).
+				Build()).
+			Build()
+
+		apiCtx, finish := startFeishuAPI(ctx, "docx.document_block_children.create")
+		resp, err := s.client.Docx.V1.DocumentBlockChildren.Create(apiCtx, req)
+		finish(err, func() int { return resp.Code })
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to call Feishu create blocks API", zap.String("documentID", documentID), zap.Error(err))
+			return nil, fmt.Errorf("飞书 API 调用失败: %w", err)
+		}
+		if !resp.Success() {
+			logger.Ctx(ctx).Error("Feishu create blocks API call unsuccessful",
+				zap.String("documentID", documentID),
+				zap.Int("code", resp.Code),
+				zap.String("msg", resp.Msg),
+			)
+			return nil, fmt.Errorf("写入云文档失败: %s (code: %d)", resp.Msg, resp.Code)
+		}
+		created = append(created, resp.Data.Children...)
+	}
+	return created, nil
+}
+
+// ReplaceImage 上传图片素材并设置到图片块
+func (s *feishuDocServiceImpl) ReplaceImage(ctx context.Context, documentID string, blockID string, image []byte) error {
+	uploadReq := larkdrive.NewUploadAllMediaReqBuilder().
+		Body(larkdrive.NewUploadAllMediaReqBodyBuilder().
+			FileName("image.png").
+			ParentType("docx_image").
+			ParentNode(blockID).
+			Size(len(image)).
+			File(bytes.NewReader(image)).
+			Build()).
+		Build()
+
+	apiCtx, finish := startFeishuAPI(ctx, "drive.media.upload_all")
+	uploadResp, err := s.client.Drive.V1.Media.UploadAll(apiCtx, uploadReq)
+	finish(err, func() int { return uploadResp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu upload media API", zap.String("blockID", blockID), zap.Error(err))
+		return fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+	if !uploadResp.Success() {
+		logger.Ctx(ctx).Error("Feishu upload media API call unsuccessful",
+			zap.String("blockID", blockID),
+			zap.Int("code", uploadResp.Code),
+			zap.String("msg", uploadResp.Msg),
+		)
+		return fmt.Errorf("上传图片失败: %s (code: %d)", uploadResp.Msg, uploadResp.Code)
+	}
+
+	patchReq := larkdocx.NewPatchDocumentBlockReqBuilder().
+		DocumentId(documentID).
+		BlockId(blockID).
+		DocumentRevisionId(-1).
+		UpdateBlockRequest(larkdocx.NewUpdateBlockRequestBuilder().
+			ReplaceImage(larkdocx.NewReplaceImageRequestBuilder().
+				Token(*uploadResp.Data.FileToken).
+				Build()).
+			Build()).
+		Build()
+
+	apiCtx, finish = startFeishuAPI(ctx, "docx.document_block.patch")
+	patchResp, err := s.client.Docx.V1.DocumentBlock.Patch(apiCtx, patchReq)
+	finish(err, func() int { return patchResp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu patch block API", zap.String("blockID", blockID), zap.Error(err))
+		return fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+	if !patchResp.Success() {
+		logger.Ctx(ctx).Error("Feishu patch block API call unsuccessful",
+			zap.String("blockID", blockID),
+			zap.Int("code", patchResp.Code),
+			zap.String("msg", patchResp.Msg),
+		)
+		return fmt.Errorf("设置图片失败: %s (code: %d)", patchResp.Msg, patchResp.Code)
+	}
+	return nil
+}
+
+// FindWikiNode 在父节点下按标题查找子节点
+func (s *feishuDocServiceImpl) FindWikiNode(ctx context.Context, spaceID string, parentNodeToken string, title string) (*larkwiki.Node, error) {
+	pageToken := ""
+	for {
+		builder := larkwiki.NewListSpaceNodeReqBuilder().
+			SpaceId(spaceID).
+			PageSize(50)
+		if parentNodeToken != "" {
+			builder.ParentNodeToken(parentNodeToken)
+		}
+		if pageToken != "" {
+			builder.PageToken(pageToken)
+		}
+
+		apiCtx, finish := startFeishuAPI(ctx, "wiki.space_node.list")
+		resp, err := s.client.Wiki.V2.SpaceNode.List(apiCtx, builder.Build())
+		finish(err, func() int { return resp.Code })
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to call Feishu list wiki nodes API", zap.String("spaceID", spaceID), zap.Error(err))
+			return nil, fmt.Errorf("飞书 API 调用失败: %w", err)
+		}
+		if !resp.Success() {
+			logger.Ctx(ctx).Error("Feishu list wiki nodes API call unsuccessful",
+				zap.String("spaceID", spaceID),
+				zap.Int("code", resp.Code),
+				zap.String("msg", resp.Msg),
+			)
+			return nil, fmt.Errorf("查询知识库节点失败: %s (code: %d)", resp.Msg, resp.Code)
+		}
+
+		for _, node := range resp.Data.Items {
+			if node.Title != nil && *node.Title == title {
+				return node, nil
+			}
+		}
+		if resp.Data.HasMore == nil || !*resp.Data.HasMore || resp.Data.PageToken == nil {
+			return nil, nil
+		}
+		pageToken = *resp.Data.PageToken
+	}
+}
+
+// CreateWikiNode 在父节点下创建云文档类型的知识库节点
+func (s *feishuDocServiceImpl) CreateWikiNode(ctx context.Context, spaceID string, parentNodeToken string, title string) (*larkwiki.Node, error) {
+	node := larkwiki.NewNodeBuilder().
+		ObjType("docx").
+		NodeType("origin").
+		Title(title)
+	if parentNodeToken != "" {
+		node.ParentNodeToken(parentNodeToken)
+	}
+	req := larkwiki.NewCreateSpaceNodeReqBuilder().
+		SpaceId(spaceID).
+		Node(node.Build()).
+		Build()
+
+	apiCtx, finish := startFeishuAPI(ctx, "wiki.space_node.create")
+	resp, err := s.client.Wiki.V2.SpaceNode.Create(apiCtx, req)
+	finish(err, func() int { return resp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu create wiki node API", zap.String("spaceID", spaceID), zap.Error(err))
+		return nil, fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+	if !resp.Success() {
+		logger.Ctx(ctx).Error("Feishu create wiki node API call unsuccessful",
+			zap.String("spaceID", spaceID),
+			zap.Int("code", resp.Code),
+			zap.String("msg", resp.Msg),
+		)
+		return nil, fmt.Errorf("创建知识库节点失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+	return resp.Data.Node, nil
+}
@@ -5,9 +5,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"time"
 
 	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/metrics"
+	"MikoNews/internal/pkg/tracing"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	lark "github.com/larksuite/oapi-sdk-go/v3"
@@ -39,11 +46,22 @@ func (s *feishuMessageServiceImpl) SendTextMessage(ctx context.Context, chatID s
 
 	contentStr, err := json.Marshal(content)
 	if err != nil {
-		logger.Error("Failed to marshal text message content", zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to marshal text message content", zap.Error(err))
 		return nil, fmt.Errorf("序列化文本消息失败: %w", err)
 	}
 
-	return s.createMessage(ctx, chatID, larkim.MsgTypeText, string(contentStr))
+	return s.createMessage(ctx, larkim.ReceiveIdTypeChatId, chatID, larkim.MsgTypeText, string(contentStr))
+}
+
+// SendTextMessageToUser 向用户私聊发送文本消息
+func (s *feishuMessageServiceImpl) SendTextMessageToUser(ctx context.Context, openID string, text string) (*larkim.CreateMessageResp, error) {
+	contentStr, err := json.Marshal(&MessageContent{Text: text})
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to marshal text message content", zap.Error(err))
+		return nil, fmt.Errorf("序列化文本消息失败: %w", err)
+	}
+
+	return s.createMessage(ctx, larkim.ReceiveIdTypeOpenId, openID, larkim.MsgTypeText, string(contentStr))
 }
 
 // SendCardMessage 发送卡片消息
@@ -51,11 +69,11 @@ func (s *feishuMessageServiceImpl) SendTextMessage(ctx context.Context, chatID s
 func (s *feishuMessageServiceImpl) SendCardMessage(ctx context.Context, chatID string, card *service.MessageCardContent) (*larkim.CreateMessageResp, error) {
 	contentStr, err := json.Marshal(card)
 	if err != nil {
-		logger.Error("Failed to marshal card message content", zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to marshal card message content", zap.Error(err))
 		return nil, fmt.Errorf("序列化卡片消息失败: %w", err)
 	}
 
-	return s.createMessage(ctx, chatID, larkim.MsgTypeInteractive, string(contentStr))
+	return s.createMessage(ctx, larkim.ReceiveIdTypeChatId, chatID, larkim.MsgTypeInteractive, string(contentStr))
 }
 
 // ReplyMessage 回复消息 (internal helper, not part of the interface directly shown here)
@@ -68,21 +86,23 @@ func (s *feishuMessageServiceImpl) replyMessage(ctx context.Context, msgID, msgT
 			Build()).
 		Build()
 
-	resp, err := s.client.Im.V1.Message.Reply(ctx, req)
+	apiCtx, finish := startFeishuAPI(ctx, "im.message.reply")
+	resp, err := s.client.Im.V1.Message.Reply(apiCtx, req)
+	finish(err, func() int { return resp.Code })
 	if err != nil {
-		logger.Error("Failed to call Feishu reply API", zap.String("messageID", msgID), zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to call Feishu reply API", zap.String("messageID", msgID), zap.Error(err))
 		return nil, fmt.Errorf("飞书 API 调用失败: %w", err)
 	}
 
 	if !resp.Success() {
-		logger.Error("Feishu reply API call unsuccessful",
+		logger.Ctx(ctx).Error("Feishu reply API call unsuccessful",
 			zap.String("messageID", msgID),
 			zap.Int("code", resp.Code),
 			zap.String("msg", resp.Msg),
 		)
 		return nil, fmt.Errorf("回复消息失败: %s (code: %d)", resp.Msg, resp.Code)
 	}
-	logger.Debug("Successfully replied to message", zap.String("messageID", msgID))
+	logger.Ctx(ctx).Debug("Successfully replied to message", zap.String("messageID", msgID))
 	return resp, nil
 }
 
@@ -94,16 +114,178 @@ func (s *feishuMessageServiceImpl) ReplyTextMessage(ctx context.Context, msgID s
 
 	contentStr, err := json.Marshal(content)
 	if err != nil {
-		logger.Error("Failed to marshal reply text message content", zap.String("messageID", msgID), zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to marshal reply text message content", zap.String("messageID", msgID), zap.Error(err))
 		return nil, fmt.Errorf("序列化回复文本消息失败: %w", err)
 	}
 	return s.replyMessage(ctx, msgID, larkim.MsgTypeText, string(contentStr))
 }
 
+// ReplyCardMessage 回复卡片消息
+func (s *feishuMessageServiceImpl) ReplyCardMessage(ctx context.Context, msgID string, card *service.MessageCardContent) (*larkim.ReplyMessageResp, error) {
+	contentStr, err := json.Marshal(card)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to marshal reply card message content", zap.String("messageID", msgID), zap.Error(err))
+		return nil, fmt.Errorf("序列化回复卡片消息失败: %w", err)
+	}
+	return s.replyMessage(ctx, msgID, larkim.MsgTypeInteractive, string(contentStr))
+}
+
+// GetMessage 获取指定消息的内容
+func (s *feishuMessageServiceImpl) GetMessage(ctx context.Context, msgID string) (*larkim.Message, error) {
+	req := larkim.NewGetMessageReqBuilder().
+		MessageId(msgID).
+		UserIdType(larkim.UserIdTypeOpenId).
+		Build()
+
+	apiCtx, finish := startFeishuAPI(ctx, "im.message.get")
+	resp, err := s.client.Im.V1.Message.Get(apiCtx, req)
+	finish(err, func() int { return resp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu get message API", zap.String("messageID", msgID), zap.Error(err))
+		return nil, fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+
+	if !resp.Success() {
+		logger.Ctx(ctx).Error("Feishu get message API call unsuccessful",
+			zap.String("messageID", msgID),
+			zap.Int("code", resp.Code),
+			zap.String("msg", resp.Msg),
+		)
+		return nil, fmt.Errorf("获取消息失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+
+	if resp.Data == nil || len(resp.Data.Items) == 0 || resp.Data.Items[0] == nil {
+		return nil, fmt.Errorf("获取消息成功，但消息数据为空 (message_id: %s)", msgID)
+	}
+	logger.Ctx(ctx).Debug("Successfully fetched message", zap.String("messageID", msgID))
+	return resp.Data.Items[0], nil
+}
+
+// DownloadImage 下载 image_key 对应的图片
+func (s *feishuMessageServiceImpl) DownloadImage(ctx context.Context, imageKey string) ([]byte, error) {
+	req := larkim.NewGetImageReqBuilder().
+		ImageKey(imageKey).
+		Build()
+
+	apiCtx, finish := startFeishuAPI(ctx, "im.image.get")
+	resp, err := s.client.Im.V1.Image.Get(apiCtx, req)
+	finish(err, func() int { return resp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu get image API", zap.String("imageKey", imageKey), zap.Error(err))
+		return nil, fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+
+	if !resp.Success() {
+		logger.Ctx(ctx).Error("Feishu get image API call unsuccessful",
+			zap.String("imageKey", imageKey),
+			zap.Int("code", resp.Code),
+			zap.String("msg", resp.Msg),
+		)
+		return nil, fmt.Errorf("下载图片失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+
+	data, err := io.ReadAll(resp.File)
+	if err != nil {
+		return nil, fmt.Errorf("读取图片内容失败: %w", err)
+	}
+	return data, nil
+}
+
+// ListChatMemberIDs 获取群成员的 open_id 列表
+func (s *feishuMessageServiceImpl) ListChatMemberIDs(ctx context.Context, chatID string) ([]string, error) {
+	var memberIDs []string
+	pageToken := ""
+	for {
+		builder := larkim.NewGetChatMembersReqBuilder().
+			ChatId(chatID).
+			MemberIdType(larkim.UserIdTypeOpenId).
+			PageSize(100)
+		if pageToken != "" {
+			builder.PageToken(pageToken)
+		}
+
+		apiCtx, finish := startFeishuAPI(ctx, "im.chat_members.get")
+		resp, err := s.client.Im.V1.ChatMembers.Get(apiCtx, builder.Build())
+		finish(err, func() int { return resp.Code })
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to call Feishu get chat members API", zap.String("chatID", chatID), zap.Error(err))
+			return nil, fmt.Errorf("飞书 API 调用失败: %w", err)
+		}
+		if !resp.Success() {
+			logger.Ctx(ctx).Error("Feishu get chat members API call unsuccessful",
+				zap.String("chatID", chatID),
+				zap.Int("code", resp.Code),
+				zap.String("msg", resp.Msg),
+			)
+			return nil, fmt.Errorf("获取群成员失败: %s (code: %d)", resp.Msg, resp.Code)
+		}
+		if resp.Data == nil {
+			break
+		}
+
+		for _, member := range resp.Data.Items {
+			if member != nil && member.MemberId != nil {
+				memberIDs = append(memberIDs, *member.MemberId)
+			}
+		}
+		if resp.Data.HasMore == nil || !*resp.Data.HasMore || resp.Data.PageToken == nil {
+			break
+		}
+		pageToken = *resp.Data.PageToken
+	}
+
+	logger.Ctx(ctx).Debug("Successfully listed chat members", zap.String("chatID", chatID), zap.Int("count", len(memberIDs)))
+	return memberIDs, nil
+}
+
+// ListChatMessages 按发送时间正序获取群聊在 start 与 end 之间的一页消息
+func (s *feishuMessageServiceImpl) ListChatMessages(ctx context.Context, chatID string, start, end time.Time, pageToken string) ([]*larkim.Message, string, error) {
+	builder := larkim.NewListMessageReqBuilder().
+		ContainerIdType("chat").
+		ContainerId(chatID).
+		SortType("ByCreateTimeAsc").
+		PageSize(50)
+	if !start.IsZero() {
+		builder.StartTime(strconv.FormatInt(start.Unix(), 10))
+	}
+	if !end.IsZero() {
+		builder.EndTime(strconv.FormatInt(end.Unix(), 10))
+	}
+	if pageToken != "" {
+		builder.PageToken(pageToken)
+	}
+
+	apiCtx, finish := startFeishuAPI(ctx, "im.message.list")
+	resp, err := s.client.Im.V1.Message.List(apiCtx, builder.Build())
+	finish(err, func() int { return resp.Code })
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to call Feishu list messages API", zap.String("chatID", chatID), zap.Error(err))
+		return nil, "", fmt.Errorf("飞书 API 调用失败: %w", err)
+	}
+	if !resp.Success() {
+		logger.Ctx(ctx).Error("Feishu list messages API call unsuccessful",
+			zap.String("chatID", chatID),
+			zap.Int("code", resp.Code),
+			zap.String("msg", resp.Msg),
+		)
+		return nil, "", fmt.Errorf("获取群聊历史消息失败: %s (code: %d)", resp.Msg, resp.Code)
+	}
+	if resp.Data == nil {
+		return nil, "", nil
+	}
+
+	nextPageToken := ""
+	if resp.Data.HasMore != nil && *resp.Data.HasMore && resp.Data.PageToken != nil {
+		nextPageToken = *resp.Data.PageToken
+	}
+	logger.Ctx(ctx).Debug("Successfully listed chat messages", zap.String("chatID", chatID), zap.Int("count", len(resp.Data.Items)))
+	return resp.Data.Items, nextPageToken, nil
+}
+
 // createMessage 创建并发送消息 (internal helper)
-func (s *feishuMessageServiceImpl) createMessage(ctx context.Context, chatID, msgType, content string) (*larkim.CreateMessageResp, error) {
+func (s *feishuMessageServiceImpl) createMessage(ctx context.Context, receiveIDType, chatID, msgType, content string) (*larkim.CreateMessageResp, error) {
 	req := larkim.NewCreateMessageReqBuilder().
-		ReceiveIdType(larkim.ReceiveIdTypeChatId).
+		ReceiveIdType(receiveIDType).
 		Body(larkim.NewCreateMessageReqBodyBuilder().
 			ReceiveId(chatID).
 			MsgType(msgType).
@@ -111,14 +293,16 @@ func (s *feishuMessageServiceImpl) createMessage(ctx context.Context, chatID, ms
 			Build()).
 		Build()
 
-	resp, err := s.client.Im.V1.Message.Create(ctx, req)
+	apiCtx, finish := startFeishuAPI(ctx, "im.message.create")
+	resp, err := s.client.Im.V1.Message.Create(apiCtx, req)
+	finish(err, func() int { return resp.Code })
 	if err != nil {
-		logger.Error("Failed to call Feishu create message API", zap.String("chatID", chatID), zap.String("msgType", msgType), zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to call Feishu create message API", zap.String("chatID", chatID), zap.String("msgType", msgType), zap.Error(err))
 		return nil, fmt.Errorf("飞书 API 调用失败: %w", err)
 	}
 
 	if !resp.Success() {
-		logger.Error("Feishu create message API call unsuccessful",
+		logger.Ctx(ctx).Error("Feishu create message API call unsuccessful",
 			zap.String("chatID", chatID),
 			zap.String("msgType", msgType),
 			zap.Int("code", resp.Code),
@@ -126,9 +310,30 @@ func (s *feishuMessageServiceImpl) createMessage(ctx context.Context, chatID, ms
 		)
 		return nil, fmt.Errorf("发送消息失败: %s (code: %d)", resp.Msg, resp.Code)
 	}
-	logger.Debug("Successfully created message", zap.String("chatID", chatID), zap.String("msgType", msgType))
+	logger.Ctx(ctx).Debug("Successfully created message", zap.String("chatID", chatID), zap.String("msgType", msgType))
 	return resp, nil
 }
 
 // Ensure feishuMessageServiceImpl implements FeishuMessageService
 var _ service.FeishuMessageService = (*feishuMessageServiceImpl)(nil)
+
+// startFeishuAPI 开始一次飞书 API 调用的 span，返回 span 的 ctx 和 finish。finish 记录调用耗时和错误码并结束 span，
+// err 为 nil 时才调用 code 读取响应中的错误码
+func startFeishuAPI(ctx context.Context, endpoint string) (context.Context, func(err error, code func() int)) {
+	started := time.Now()
+	ctx, span := tracing.StartKind(ctx, trace.SpanKindClient, "feishu "+endpoint, attribute.String("feishu.endpoint", endpoint))
+	return ctx, func(err error, code func() int) {
+		if err != nil {
+			metrics.ObserveFeishuAPI(endpoint, started, metrics.FeishuCodeRequestFailed)
+			tracing.End(span, err)
+			return
+		}
+		c := code()
+		metrics.ObserveFeishuAPI(endpoint, started, strconv.Itoa(c))
+		span.SetAttributes(attribute.Int("feishu.code", c))
+		if c != 0 {
+			err = fmt.Errorf("飞书 API 返回错误码 %d", c)
+		}
+		tracing.End(span, err)
+	}
+}
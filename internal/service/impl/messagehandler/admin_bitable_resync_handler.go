@@ -0,0 +1,82 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// bitableResyncCommand is the P2P text command admins use to resync every article into the Bitable table.
+const bitableResyncCommand = "/同步表格"
+
+// AdminBitableResyncHandlerStrategy handles "/同步表格" sent by admins in P2P chats,
+// running a full Bitable resync in the background and replying with the result.
+type AdminBitableResyncHandlerStrategy struct {
+	bitableSync   service.BitableSyncService
+	feishuService service.FeishuMessageService
+	adminCfg      *config.AdminConfig
+}
+
+// NewAdminBitableResyncHandlerStrategy creates a new admin Bitable resync strategy.
+func NewAdminBitableResyncHandlerStrategy(
+	bitableSync service.BitableSyncService,
+	feishuService service.FeishuMessageService,
+	adminCfg *config.AdminConfig,
+) service.MessageHandlerStrategy {
+	return &AdminBitableResyncHandlerStrategy{
+		bitableSync:   bitableSync,
+		feishuService: feishuService,
+		adminCfg:      adminCfg,
+	}
+}
+
+// ShouldHandle checks if the message is a P2P text message starting with "/同步表格".
+// Non-admins are matched as well so that they get an explicit permission error.
+func (s *AdminBitableResyncHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
+	_, ok := parseP2PTextCommand(event, bitableResyncCommand)
+	return ok
+}
+
+// Handle starts the resync if the sender is an admin. The resync may take a while for large tables,
+// so it runs detached from the event and the result is sent as a reply when it finishes.
+func (s *AdminBitableResyncHandlerStrategy) Handle(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	msgID := *event.Event.Message.MessageId
+
+	senderID := ""
+	if event.Event.Sender != nil && event.Event.Sender.SenderId != nil && event.Event.Sender.SenderId.OpenId != nil {
+		senderID = *event.Event.Sender.SenderId.OpenId
+	}
+	if !s.adminCfg.IsAdmin(senderID) {
+		logger.Ctx(ctx).Warn("Non-admin attempted to resync bitable", zap.String("senderOpenID", senderID))
+		return s.reply(ctx, msgID, "只有管理员可以同步多维表格")
+	}
+
+	logger.Ctx(ctx).Info("Bitable resync requested by admin", zap.String("adminOpenID", senderID))
+	if err := s.reply(ctx, msgID, "开始全量同步多维表格，完成后会通知你"); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+		synced, err := s.bitableSync.Resync(ctx)
+		text := fmt.Sprintf("多维表格同步完成，共同步 %d 篇文章", synced)
+		if err != nil {
+			text = fmt.Sprintf("多维表格同步完成，成功 %d 篇，部分失败：%s", synced, err)
+		}
+		_ = s.reply(ctx, msgID, text)
+	}()
+	return nil
+}
+
+func (s *AdminBitableResyncHandlerStrategy) reply(ctx context.Context, msgID, text string) error {
+	if _, err := s.feishuService.ReplyTextMessage(ctx, msgID, text); err != nil {
+		logger.Ctx(ctx).Error("Failed to reply bitable resync command", zap.String("messageID", msgID), zap.Error(err))
+		return fmt.Errorf("reply failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// revealAuthorCommand is the P2P text command admins use to look up the real author of an article.
+const revealAuthorCommand = "/作者"
+
+// AdminRevealAuthorHandlerStrategy handles "/作者 <文章ID>" sent by admins in P2P chats,
+// replying with the real author of the article, including anonymous submissions.
+type AdminRevealAuthorHandlerStrategy struct {
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+	adminCfg       *config.AdminConfig
+}
+
+// NewAdminRevealAuthorHandlerStrategy creates a new admin reveal-author strategy.
+func NewAdminRevealAuthorHandlerStrategy(
+	articleService service.ArticleService,
+	feishuService service.FeishuMessageService,
+	adminCfg *config.AdminConfig,
+) service.MessageHandlerStrategy {
+	return &AdminRevealAuthorHandlerStrategy{
+		articleService: articleService,
+		feishuService:  feishuService,
+		adminCfg:       adminCfg,
+	}
+}
+
+// ShouldHandle checks if the message is a P2P text message starting with "/作者".
+// Non-admins are matched as well so that they get an explicit permission error.
+func (s *AdminRevealAuthorHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
+	_, ok := parseP2PTextCommand(event, revealAuthorCommand)
+	return ok
+}
+
+// Handle replies with the real author open_id if the sender is an admin.
+func (s *AdminRevealAuthorHandlerStrategy) Handle(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	msgID := *event.Event.Message.MessageId
+	args, _ := parseP2PTextCommand(event, revealAuthorCommand)
+
+	senderID := ""
+	if event.Event.Sender != nil && event.Event.Sender.SenderId != nil && event.Event.Sender.SenderId.OpenId != nil {
+		senderID = *event.Event.Sender.SenderId.OpenId
+	}
+	if !s.adminCfg.IsAdmin(senderID) {
+		logger.Ctx(ctx).Warn("Non-admin attempted to reveal article author", zap.String("senderOpenID", senderID))
+		return s.reply(ctx, msgID, "只有管理员可以查看投稿作者")
+	}
+
+	id, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		return s.reply(ctx, msgID, fmt.Sprintf("用法：%s <文章ID>", revealAuthorCommand))
+	}
+
+	authorID, err := s.articleService.RevealAuthor(ctx, id)
+	if err != nil {
+		return s.reply(ctx, msgID, fmt.Sprintf("查询作者失败：%s", err))
+	}
+
+	logger.Ctx(ctx).Info("Article author revealed to admin", zap.Int64("articleID", id), zap.String("adminOpenID", senderID))
+	return s.reply(ctx, msgID, fmt.Sprintf("文章 #%d 的作者：<at user_id=\"%s\"></at> (%s)", id, authorID, authorID))
+}
+
+func (s *AdminRevealAuthorHandlerStrategy) reply(ctx context.Context, msgID, text string) error {
+	if _, err := s.feishuService.ReplyTextMessage(ctx, msgID, text); err != nil {
+		logger.Ctx(ctx).Error("Failed to reply reveal-author command", zap.String("messageID", msgID), zap.Error(err))
+		return fmt.Errorf("reply failed: %w", err)
+	}
+	return nil
+}
+
+// parseP2PTextCommand returns the arguments of a P2P text command such as "/作者 <文章ID>".
+func parseP2PTextCommand(event *larkim.P2MessageReceiveV1, command string) (string, bool) {
+	if event.Event == nil || event.Event.Message == nil || event.Event.Message.ChatType == nil ||
+		event.Event.Message.MessageType == nil || event.Event.Message.Content == nil ||
+		*event.Event.Message.ChatType != "p2p" || *event.Event.Message.MessageType != larkim.MsgTypeText {
+		return "", false
+	}
+	var content textContent
+	if err := json.Unmarshal([]byte(*event.Event.Message.Content), &content); err != nil {
+		return "", false
+	}
+	text := strings.TrimSpace(content.Text)
+	if !strings.HasPrefix(text, command) {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(text, command)), true
+}
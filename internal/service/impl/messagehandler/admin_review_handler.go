@@ -0,0 +1,126 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// P2P text commands admins use to review pending submissions.
+const (
+	approveCommand = "/通过"
+	rejectCommand  = "/驳回"
+)
+
+// AdminReviewHandlerStrategy handles "/通过 <文章ID>" and "/驳回 <文章ID> [原因]" sent by admins in P2P chats.
+// Approved articles are forwarded to the group chats; the author is notified either way.
+type AdminReviewHandlerStrategy struct {
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+	publisher      service.Publisher
+	adminCfg       *config.AdminConfig
+}
+
+// NewAdminReviewHandlerStrategy creates a new admin review strategy.
+func NewAdminReviewHandlerStrategy(
+	articleService service.ArticleService,
+	feishuService service.FeishuMessageService,
+	publisher service.Publisher,
+	adminCfg *config.AdminConfig,
+) service.MessageHandlerStrategy {
+	return &AdminReviewHandlerStrategy{
+		articleService: articleService,
+		feishuService:  feishuService,
+		publisher:      publisher,
+		adminCfg:       adminCfg,
+	}
+}
+
+// ShouldHandle checks if the message is a P2P text message starting with "/通过" or "/驳回".
+// Non-admins are matched as well so that they get an explicit permission error.
+func (s *AdminReviewHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
+	_, _, ok := parseReviewCommand(event)
+	return ok
+}
+
+// Handle approves or rejects the pending article if the sender is an admin.
+func (s *AdminReviewHandlerStrategy) Handle(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	msgID := *event.Event.Message.MessageId
+	command, args, _ := parseReviewCommand(event)
+
+	senderID := ""
+	if event.Event.Sender != nil && event.Event.Sender.SenderId != nil && event.Event.Sender.SenderId.OpenId != nil {
+		senderID = *event.Event.Sender.SenderId.OpenId
+	}
+	if !s.adminCfg.IsAdmin(senderID) {
+		logger.Ctx(ctx).Warn("Non-admin attempted to review article", zap.String("senderOpenID", senderID))
+		return s.reply(ctx, msgID, "只有管理员可以审核投稿")
+	}
+
+	idArg, reason, _ := strings.Cut(args, " ")
+	id, err := strconv.ParseInt(idArg, 10, 64)
+	if err != nil {
+		return s.reply(ctx, msgID, fmt.Sprintf("用法：%s <文章ID> 或 %s <文章ID> [原因]", approveCommand, rejectCommand))
+	}
+
+	approve := command == approveCommand
+	article, err := s.articleService.ReviewArticle(ctx, id, approve)
+	if err != nil {
+		return s.reply(ctx, msgID, fmt.Sprintf("审核失败：%s", err))
+	}
+	logger.Ctx(ctx).Info("Article reviewed by admin", zap.Int64("articleID", id), zap.Bool("approved", approve), zap.String("adminOpenID", senderID))
+
+	var adminText, authorText string
+	if approve {
+		publishArticle(ctx, s.publisher, article, msgID)
+		adminText = fmt.Sprintf("已通过文章 #%d '%s'，正在转发到群聊", article.ID, article.Title)
+		authorText = fmt.Sprintf("您的投稿 '%s' (ID: %d) 已通过审核，正在转发到群聊，感谢您的分享！", article.Title, article.ID)
+	} else {
+		adminText = fmt.Sprintf("已驳回文章 #%d '%s'", article.ID, article.Title)
+		authorText = fmt.Sprintf("很抱歉，您的投稿 '%s' (ID: %d) 未通过审核。", article.Title, article.ID)
+		if reason = strings.TrimSpace(reason); reason != "" {
+			authorText += "原因：" + reason
+		}
+	}
+
+	s.notifyAuthor(ctx, article, authorText)
+	return s.reply(ctx, msgID, adminText)
+}
+
+// notifyAuthor sends the review result to the author; the real author of anonymous submissions is decrypted first.
+func (s *AdminReviewHandlerStrategy) notifyAuthor(ctx context.Context, article *model.Article, text string) {
+	authorID, err := s.articleService.RevealAuthor(ctx, article.ID)
+	if err != nil || authorID == "" {
+		logger.Ctx(ctx).Warn("Cannot resolve author to notify review result", zap.Int64("articleID", article.ID), zap.Error(err))
+		return
+	}
+	if _, err := s.feishuService.SendTextMessageToUser(ctx, authorID, text); err != nil {
+		logger.Ctx(ctx).Error("Failed to notify author of review result", zap.Int64("articleID", article.ID), zap.Error(err))
+	}
+}
+
+func (s *AdminReviewHandlerStrategy) reply(ctx context.Context, msgID, text string) error {
+	if _, err := s.feishuService.ReplyTextMessage(ctx, msgID, text); err != nil {
+		logger.Ctx(ctx).Error("Failed to reply review command", zap.String("messageID", msgID), zap.Error(err))
+		return fmt.Errorf("reply failed: %w", err)
+	}
+	return nil
+}
+
+// parseReviewCommand returns the command ("/通过" or "/驳回") and its arguments.
+func parseReviewCommand(event *larkim.P2MessageReceiveV1) (string, string, bool) {
+	for _, command := range []string{approveCommand, rejectCommand} {
+		if args, ok := parseP2PTextCommand(event, command); ok {
+			return command, args, true
+		}
+	}
+	return "", "", false
+}
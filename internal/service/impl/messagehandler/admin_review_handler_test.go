@@ -0,0 +1,212 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// fakeFeishu records the replies and direct messages sent by the handlers.
+type fakeFeishu struct {
+	service.FeishuMessageService
+	mu       sync.Mutex
+	replies  []string                   // text replies
+	direct   []string                   // open_id: text
+	messages map[string]*larkim.Message // served by GetMessage
+}
+
+func (f *fakeFeishu) ReplyTextMessage(_ context.Context, _ string, text string) (*larkim.ReplyMessageResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.replies = append(f.replies, text)
+	return &larkim.ReplyMessageResp{}, nil
+}
+
+func (f *fakeFeishu) SendTextMessageToUser(_ context.Context, openID string, text string) (*larkim.CreateMessageResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.direct = append(f.direct, openID+": "+text)
+	return &larkim.CreateMessageResp{}, nil
+}
+
+func (f *fakeFeishu) GetMessage(_ context.Context, msgID string) (*larkim.Message, error) {
+	message, ok := f.messages[msgID]
+	if !ok {
+		return nil, fmt.Errorf("message %s not found", msgID)
+	}
+	return message, nil
+}
+
+func (f *fakeFeishu) lastReply() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.replies) == 0 {
+		return ""
+	}
+	return f.replies[len(f.replies)-1]
+}
+
+// fakePublisher records the published articles.
+type fakePublisher struct {
+	published []int64
+}
+
+func (p *fakePublisher) Name() string { return "fake" }
+
+func (p *fakePublisher) Publish(_ context.Context, article *model.Article) error {
+	p.published = append(p.published, article.ID)
+	return nil
+}
+
+// fakeReviewArticles keeps articles in a map and reviews pending ones.
+type fakeReviewArticles struct {
+	service.ArticleService
+	articles map[int64]*model.Article
+	authors  map[int64]string // real author open_id, including anonymous ones
+}
+
+func (f *fakeReviewArticles) ReviewArticle(_ context.Context, id int64, approve bool) (*model.Article, error) {
+	article, ok := f.articles[id]
+	if !ok || article.Status != model.ArticleStatusPending {
+		return nil, fmt.Errorf("文章不存在或不是待审核状态 (ID: %d)", id)
+	}
+	article.Status = model.ArticleStatusRejected
+	if approve {
+		article.Status = model.ArticleStatusPublished
+	}
+	return article, nil
+}
+
+func (f *fakeReviewArticles) RevealAuthor(_ context.Context, id int64) (string, error) {
+	return f.authors[id], nil
+}
+
+// p2pTextEvent builds a P2P text message event sent by senderID.
+func p2pTextEvent(senderID, text string) *larkim.P2MessageReceiveV1 {
+	content, _ := json.Marshal(textContent{Text: text})
+	return &larkim.P2MessageReceiveV1{Event: &larkim.P2MessageReceiveV1Data{
+		Sender: &larkim.EventSender{SenderId: &larkim.UserId{OpenId: strPtr(senderID)}},
+		Message: &larkim.EventMessage{
+			MessageId:   strPtr("om_command"),
+			ChatType:    strPtr("p2p"),
+			MessageType: strPtr(larkim.MsgTypeText),
+			Content:     strPtr(string(content)),
+		},
+	}}
+}
+
+func TestParseReviewCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       *larkim.P2MessageReceiveV1
+		wantOK      bool
+		wantCommand string
+		wantArgs    string
+	}{
+		{"approve", p2pTextEvent("ou_admin", "/通过 12"), true, approveCommand, "12"},
+		{"reject with a reason", p2pTextEvent("ou_admin", "  /驳回 12 内容 重复  "), true, rejectCommand, "12 内容 重复"},
+		{"without arguments", p2pTextEvent("ou_admin", "/驳回"), true, rejectCommand, ""},
+		{"other command", p2pTextEvent("ou_admin", "/作者 12"), false, "", ""},
+		{"plain text", p2pTextEvent("ou_admin", "通过 12"), false, "", ""},
+		{"group chat", groupTextEvent("/通过 12"), false, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, args, ok := parseReviewCommand(tt.event)
+			if ok != tt.wantOK || command != tt.wantCommand || args != tt.wantArgs {
+				t.Errorf("parseReviewCommand() = %q, %q, %v; want %q, %q, %v", command, args, ok, tt.wantCommand, tt.wantArgs, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAdminReviewHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		sender        string
+		text          string
+		wantStatus    string
+		wantPublished bool
+		wantReply     string
+		wantDirect    string
+	}{
+		{
+			name: "approve", sender: "ou_admin", text: "/通过 1",
+			wantStatus: model.ArticleStatusPublished, wantPublished: true,
+			wantReply:  "已通过文章 #1 '周报'，正在转发到群聊",
+			wantDirect: "ou_author: 您的投稿 '周报' (ID: 1) 已通过审核，正在转发到群聊，感谢您的分享！",
+		},
+		{
+			name: "reject with a reason", sender: "ou_admin", text: "/驳回 1 与已有投稿 重复",
+			wantStatus: model.ArticleStatusRejected,
+			wantReply:  "已驳回文章 #1 '周报'",
+			wantDirect: "ou_author: 很抱歉，您的投稿 '周报' (ID: 1) 未通过审核。原因：与已有投稿 重复",
+		},
+		{
+			name: "reject without a reason", sender: "ou_admin", text: "/驳回 1",
+			wantStatus: model.ArticleStatusRejected,
+			wantReply:  "已驳回文章 #1 '周报'",
+			wantDirect: "ou_author: 很抱歉，您的投稿 '周报' (ID: 1) 未通过审核。",
+		},
+		{
+			name: "non-admin", sender: "ou_author", text: "/通过 1",
+			wantStatus: model.ArticleStatusPending,
+			wantReply:  "只有管理员可以审核投稿",
+		},
+		{
+			name: "invalid article ID", sender: "ou_admin", text: "/通过 abc",
+			wantStatus: model.ArticleStatusPending,
+			wantReply:  "用法：/通过 <文章ID> 或 /驳回 <文章ID> [原因]",
+		},
+		{
+			name: "article not pending", sender: "ou_admin", text: "/通过 2",
+			wantStatus: model.ArticleStatusPending,
+			wantReply:  "审核失败：文章不存在或不是待审核状态 (ID: 2)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := &fakeReviewArticles{
+				articles: map[int64]*model.Article{
+					1: {ID: 1, Title: "周报", Status: model.ArticleStatusPending},
+					2: {ID: 2, Title: "月报", Status: model.ArticleStatusPublished},
+				},
+				authors: map[int64]string{1: "ou_author", 2: "ou_author"},
+			}
+			feishu := &fakeFeishu{}
+			publisher := &fakePublisher{}
+			handler := NewAdminReviewHandlerStrategy(articles, feishu, publisher, &config.AdminConfig{OpenIDs: []string{"ou_admin"}})
+
+			event := p2pTextEvent(tt.sender, tt.text)
+			if !handler.ShouldHandle(context.Background(), event) {
+				t.Fatal("ShouldHandle() = false")
+			}
+			if err := handler.Handle(context.Background(), event); err != nil {
+				t.Fatal(err)
+			}
+			if got := articles.articles[1].Status; got != tt.wantStatus {
+				t.Errorf("article status = %s, want %s", got, tt.wantStatus)
+			}
+			if got := len(publisher.published) > 0; got != tt.wantPublished {
+				t.Errorf("published = %v, want %v", publisher.published, tt.wantPublished)
+			}
+			if got := feishu.lastReply(); got != tt.wantReply {
+				t.Errorf("reply = %q, want %q", got, tt.wantReply)
+			}
+			var wantDirect []string
+			if tt.wantDirect != "" {
+				wantDirect = []string{tt.wantDirect}
+			}
+			if fmt.Sprint(feishu.direct) != fmt.Sprint(wantDirect) {
+				t.Errorf("messages to the author = %q, want %q", feishu.direct, wantDirect)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// articleImportServiceImpl implements service.ArticleImportService.
+// It lives next to the submission strategy because it shares the post parsing code.
+type articleImportServiceImpl struct {
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+	userDirectory  service.UserDirectoryService
+}
+
+// NewArticleImportService creates a new article import service.
+func NewArticleImportService(
+	articleService service.ArticleService,
+	feishuService service.FeishuMessageService,
+	userDirectory service.UserDirectoryService,
+) service.ArticleImportService {
+	return &articleImportServiceImpl{
+		articleService: articleService,
+		feishuService:  feishuService,
+		userDirectory:  userDirectory,
+	}
+}
+
+// ImportChatHistory imports the member posts of a chat page by page, oldest first.
+func (s *articleImportServiceImpl) ImportChatHistory(ctx context.Context, query *service.ChatHistoryQuery) (*service.ImportResult, error) {
+	result := &service.ImportResult{}
+	pageToken := ""
+	for {
+		messages, nextPageToken, err := s.feishuService.ListChatMessages(ctx, query.ChatID, query.Start, query.End, pageToken)
+		if err != nil {
+			return result, err
+		}
+		for _, msg := range messages {
+			record, err := recordFromMessage(msg, query.IncludeText)
+			if err != nil {
+				result.Failed++
+				logger.Ctx(ctx).Warn("Failed to parse chat message for import", zap.Stringp("messageID", msg.MessageId), zap.Error(err))
+				continue
+			}
+			if record == nil {
+				result.Skipped++
+				continue
+			}
+			s.importRecord(ctx, record, result)
+		}
+
+		logger.Ctx(ctx).Info("Imported a page of chat history",
+			zap.String("chatID", query.ChatID),
+			zap.Int("imported", result.Imported),
+			zap.Int("skipped", result.Skipped),
+			zap.Int("failed", result.Failed),
+		)
+		if nextPageToken == "" {
+			return result, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// ImportRecords imports records read from an export.
+func (s *articleImportServiceImpl) ImportRecords(ctx context.Context, records []*service.ImportRecord) (*service.ImportResult, error) {
+	result := &service.ImportResult{}
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		s.importRecord(ctx, record, result)
+	}
+	return result, nil
+}
+
+// importRecord parses the record like a submission and saves it with its original author and time
+func (s *articleImportServiceImpl) importRecord(ctx context.Context, record *service.ImportRecord, result *service.ImportResult) {
+	imported, err := s.saveRecord(ctx, record)
+	switch {
+	case err != nil:
+		result.Failed++
+		logger.Ctx(ctx).Warn("Failed to import record", zap.String("messageID", record.MessageID), zap.Error(err))
+	case imported:
+		result.Imported++
+	default:
+		result.Skipped++
+	}
+}
+
+func (s *articleImportServiceImpl) saveRecord(ctx context.Context, record *service.ImportRecord) (bool, error) {
+	rawContent := record.RawContent
+	if rawContent == "" {
+		if strings.TrimSpace(record.Content) == "" {
+			return false, fmt.Errorf("内容为空")
+		}
+		var err error
+		if rawContent, err = textToPostContent(record.Content); err != nil {
+			return false, err
+		}
+	}
+	title, textContent, err := parsePostContentForSubmission(rawContent)
+	if err != nil {
+		return false, fmt.Errorf("解析内容失败: %w", err)
+	}
+	if record.Title != "" {
+		title = record.Title
+	}
+
+	authorName := record.AuthorName
+	if authorName == "" {
+		if record.AuthorID == "" {
+			return false, fmt.Errorf("缺少作者")
+		}
+		authorName = resolveAuthorName(ctx, s.userDirectory, record.AuthorID)
+	}
+
+	return s.articleService.ImportArticle(ctx, &model.Article{
+		Title:           title,
+		Content:         textContent,
+		RawContent:      rawContent,
+		AuthorID:        record.AuthorID,
+		AuthorName:      authorName,
+		Source:          model.ArticleSourceFeishu,
+		SourceMessageID: model.NullString(record.MessageID),
+		Status:          model.ArticleStatusPublished,
+		CreatedAt:       record.CreatedAt,
+	})
+}
+
+// recordFromMessage converts a chat message into an import record, or returns nil for messages that are not member posts
+func recordFromMessage(msg *larkim.Message, includeText bool) (*service.ImportRecord, error) {
+	if msg == nil || msg.MessageId == nil || msg.MsgType == nil || msg.Body == nil || msg.Body.Content == nil {
+		return nil, nil
+	}
+	if msg.Deleted != nil && *msg.Deleted {
+		return nil, nil
+	}
+	if msg.ParentId != nil && *msg.ParentId != "" {
+		return nil, nil
+	}
+	if msg.Sender == nil || msg.Sender.Id == nil || msg.Sender.SenderType == nil || *msg.Sender.SenderType != "user" {
+		return nil, nil
+	}
+	if *msg.MsgType != larkim.MsgTypePost && (*msg.MsgType != larkim.MsgTypeText || !includeText) {
+		return nil, nil
+	}
+
+	// Normalize the content into the post structure used by submissions, as when archiving a message
+	rawContent, err := messageToPostContent(*msg.MsgType, *msg.Body.Content, msg.Mentions)
+	if err != nil {
+		return nil, err
+	}
+	if rawContent, err = resolvePostMentions(rawContent, mentionsFromMessage(msg.Mentions)); err != nil {
+		return nil, fmt.Errorf("解析消息内容失败: %w", err)
+	}
+
+	record := &service.ImportRecord{
+		MessageID:  *msg.MessageId,
+		AuthorID:   *msg.Sender.Id,
+		RawContent: rawContent,
+	}
+	if msg.CreateTime != nil {
+		if ms, err := strconv.ParseInt(*msg.CreateTime, 10, 64); err == nil {
+			record.CreatedAt = time.UnixMilli(ms)
+		}
+	}
+	return record, nil
+}
+
+// Ensure articleImportServiceImpl implements ArticleImportService
+var _ service.ArticleImportService = (*articleImportServiceImpl)(nil)
@@ -0,0 +1,151 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+func TestMain(m *testing.M) {
+	logDir, err := os.MkdirTemp("", "miko-messagehandler-test")
+	if err != nil {
+		panic(err)
+	}
+	logger.InitLogger(filepath.Join(logDir, "test.log"), "warn")
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+// fakeHistory 按页返回群聊历史消息，页码即分页标记
+type fakeHistory struct {
+	service.FeishuMessageService
+	pages [][]*larkim.Message
+}
+
+func (f *fakeHistory) ListChatMessages(_ context.Context, _ string, _, _ time.Time, pageToken string) ([]*larkim.Message, string, error) {
+	page, _ := strconv.Atoi(pageToken)
+	next := ""
+	if page+1 < len(f.pages) {
+		next = strconv.Itoa(page + 1)
+	}
+	return f.pages[page], next, nil
+}
+
+// fakeDirectory 返回固定的用户名
+type fakeDirectory struct {
+	service.UserDirectoryService
+	names map[string]string
+}
+
+func (f *fakeDirectory) GetUser(_ context.Context, openID string) (*model.User, error) {
+	return &model.User{OpenID: openID, Name: f.names[openID]}, nil
+}
+
+// fakeImporter 记录导入的文章，并按来源消息ID去重
+type fakeImporter struct {
+	service.ArticleService
+	articles []*model.Article
+}
+
+func (f *fakeImporter) ImportArticle(_ context.Context, article *model.Article) (bool, error) {
+	for _, existing := range f.articles {
+		if article.SourceMessageID != "" && existing.SourceMessageID == article.SourceMessageID {
+			return false, nil
+		}
+	}
+	f.articles = append(f.articles, article)
+	return true, nil
+}
+
+func chatMessage(id, senderType, msgType, content string, createdAt time.Time) *larkim.Message {
+	return &larkim.Message{
+		MessageId:  larkcore.StringPtr(id),
+		MsgType:    larkcore.StringPtr(msgType),
+		CreateTime: larkcore.StringPtr(strconv.FormatInt(createdAt.UnixMilli(), 10)),
+		Sender:     &larkim.Sender{Id: larkcore.StringPtr("ou_author"), SenderType: larkcore.StringPtr(senderType)},
+		Body:       &larkim.MessageBody{Content: larkcore.StringPtr(content)},
+	}
+}
+
+func TestImportChatHistory(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Date(2023, 6, 1, 9, 0, 0, 0, time.Local)
+	post := `{"title":"","content":[[{"tag":"text","text":"周报标题","style":["bold"]}],[{"tag":"text","text":"正文内容"}]]}`
+	reply := chatMessage("om_reply", "user", larkim.MsgTypePost, post, createdAt)
+	reply.ParentId = larkcore.StringPtr("om_post")
+	recalled := chatMessage("om_recalled", "user", larkim.MsgTypePost, post, createdAt)
+	recalled.Deleted = larkcore.BoolPtr(true)
+
+	history := &fakeHistory{pages: [][]*larkim.Message{
+		{
+			chatMessage("om_post", "user", larkim.MsgTypePost, post, createdAt),
+			chatMessage("om_text", "user", larkim.MsgTypeText, `{"text":"收到"}`, createdAt),
+			chatMessage("om_card", "app", larkim.MsgTypeInteractive, `{}`, createdAt),
+		},
+		{reply, recalled, chatMessage("om_broken", "user", larkim.MsgTypePost, `not json`, createdAt)},
+	}}
+	importer := &fakeImporter{}
+	importService := NewArticleImportService(importer, history, &fakeDirectory{names: map[string]string{"ou_author": "作者"}})
+
+	result, err := importService.ImportChatHistory(ctx, &service.ChatHistoryQuery{ChatID: "oc_group"})
+	if err != nil {
+		t.Fatalf("导入群聊历史失败: %v", err)
+	}
+	if result.Imported != 1 || result.Skipped != 4 || result.Failed != 1 {
+		t.Errorf("导入结果不符: %+v", result)
+	}
+	if len(importer.articles) != 1 {
+		t.Fatalf("期望导入 1 篇文章，实际: %d", len(importer.articles))
+	}
+	article := importer.articles[0]
+	if article.Title != "周报标题" || article.AuthorID != "ou_author" || article.AuthorName != "作者" ||
+		article.SourceMessageID != "om_post" || !article.CreatedAt.Equal(createdAt) || article.Status != model.ArticleStatusPublished {
+		t.Errorf("导入的文章不符: %+v", article)
+	}
+
+	// 再次导入时已导入的消息被跳过，包含文本消息时导入文本消息
+	result, err = importService.ImportChatHistory(ctx, &service.ChatHistoryQuery{ChatID: "oc_group", IncludeText: true})
+	if err != nil {
+		t.Fatalf("再次导入失败: %v", err)
+	}
+	if result.Imported != 1 || len(importer.articles) != 2 || importer.articles[1].SourceMessageID != "om_text" {
+		t.Errorf("再次导入结果不符: %+v", result)
+	}
+}
+
+func TestImportRecords(t *testing.T) {
+	importer := &fakeImporter{}
+	importService := NewArticleImportService(importer, nil, &fakeDirectory{names: map[string]string{"ou_a": "目录中的名字"}})
+	createdAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.Local)
+
+	result, err := importService.ImportRecords(context.Background(), []*service.ImportRecord{
+		{MessageID: "m1", AuthorID: "ou_a", Content: "第一行是标题\n正文", CreatedAt: createdAt},
+		{MessageID: "m2", AuthorID: "ou_b", AuthorName: "导出中的名字", Title: "指定标题", Content: "正文"},
+		{MessageID: "m1", AuthorID: "ou_a", Content: "重复的消息"},
+		{MessageID: "m3", AuthorID: "ou_a"},
+		{MessageID: "m4", Content: "没有作者"},
+	})
+	if err != nil {
+		t.Fatalf("导入记录失败: %v", err)
+	}
+	if result.Imported != 2 || result.Skipped != 1 || result.Failed != 2 {
+		t.Errorf("导入结果不符: %+v", result)
+	}
+	first, second := importer.articles[0], importer.articles[1]
+	if first.AuthorName != "目录中的名字" || !first.CreatedAt.Equal(createdAt) || first.RawContent == "" {
+		t.Errorf("第一篇文章不符: %+v", first)
+	}
+	if second.Title != "指定标题" || second.AuthorName != "导出中的名字" {
+		t.Errorf("第二篇文章不符: %+v", second)
+	}
+}
@@ -37,7 +37,7 @@ func (s *DefaultMessageHandlerStrategy) Handle(ctx context.Context, event *larki
 		senderID = *event.Event.Sender.SenderId.OpenId
 	}
 
-	logger.Warn("Unhandled P2P message",
+	logger.Ctx(ctx).Warn("Unhandled P2P message",
 		zap.String("messageID", msgID),
 		zap.String("senderID", senderID),
 		zap.Stringp("messageType", event.Event.Message.MessageType),
@@ -0,0 +1,120 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// externalSubmissionServiceImpl implements service.ExternalSubmissionService.
+// It lives next to the submission strategy because it shares the post parsing and card building code.
+type externalSubmissionServiceImpl struct {
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+	publisher      service.Publisher
+	moderation     service.ContentModerationService
+	adminCfg       *config.AdminConfig
+}
+
+// NewExternalSubmissionService creates a new external submission service.
+func NewExternalSubmissionService(
+	articleService service.ArticleService,
+	feishuService service.FeishuMessageService,
+	publisher service.Publisher,
+	moderation service.ContentModerationService,
+	adminCfg *config.AdminConfig,
+) service.ExternalSubmissionService {
+	return &externalSubmissionServiceImpl{
+		articleService: articleService,
+		feishuService:  feishuService,
+		publisher:      publisher,
+		moderation:     moderation,
+		adminCfg:       adminCfg,
+	}
+}
+
+// Submit saves an external submission as an article and forwards it to the group chats.
+func (s *externalSubmissionServiceImpl) Submit(ctx context.Context, submission *service.ExternalSubmission) (*service.ArchiveResult, error) {
+	if strings.TrimSpace(submission.Text) == "" {
+		return nil, fmt.Errorf("投稿内容为空")
+	}
+
+	// 1. Normalize the text into the post structure used by submissions
+	rawContent, err := textToPostContent(submission.Text)
+	if err != nil {
+		return nil, err
+	}
+	title, textContent, err := parsePostContentForSubmission(rawContent)
+	if err != nil {
+		return nil, fmt.Errorf("解析投稿内容失败: %w", err)
+	}
+
+	// 2. Run the content filters
+	moderation, err := s.moderation.Moderate(ctx, &service.ModerationInput{
+		AuthorID:   submission.AuthorID,
+		Title:      title,
+		Content:    textContent,
+		RawContent: rawContent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if moderation.Verdict == service.FilterReject {
+		return nil, fmt.Errorf("内容未通过审核：%s", moderation.Reason)
+	}
+
+	// 3. Merge duplicates of existing articles instead of saving them again
+	duplicate, err := s.articleService.FindDuplicate(ctx, textContent, rawContent)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate != nil {
+		merged, err := s.articleService.MergeCredits(ctx, duplicate.ID, []*model.ArticleAuthor{
+			{OpenID: submission.AuthorID, Name: submission.AuthorName, Role: model.AuthorRoleSource},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logger.Ctx(ctx).Info("External submission merged into existing article",
+			zap.String("source", submission.Source),
+			zap.String("reference", submission.Reference),
+			zap.Int64("articleID", merged.ID),
+		)
+		return &service.ArchiveResult{Article: merged, Merged: true}, nil
+	}
+
+	article, err := s.articleService.SaveSubmission(ctx, &service.Submission{
+		AuthorID:   submission.AuthorID,
+		AuthorName: submission.AuthorName,
+		Title:      title,
+		Content:    textContent,
+		RawContent: rawContent,
+		Source:     submission.Source,
+
+		ReviewRequired: moderation.Verdict == service.FilterFlag,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Forward to the configured group chats, or wait for an admin to review it
+	if article.Status == model.ArticleStatusPending {
+		notifyReviewers(ctx, s.feishuService, s.adminCfg, article)
+	} else {
+		publishArticle(ctx, s.publisher, article, submission.Reference)
+	}
+
+	logger.Ctx(ctx).Info("External submission saved",
+		zap.String("source", submission.Source),
+		zap.String("reference", submission.Reference),
+		zap.Int64("articleID", article.ID),
+		zap.String("authorID", submission.AuthorID),
+	)
+	return &service.ArchiveResult{Article: article}, nil
+}
@@ -0,0 +1,75 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/metrics"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// feishuPublisher implements service.Publisher by forwarding articles as cards to Feishu group chats.
+// It lives next to the submission strategy because it shares the card building code.
+type feishuPublisher struct {
+	feishuService  service.FeishuMessageService
+	articleService service.ArticleService
+	groupChats     []string
+}
+
+// NewFeishuPublisher creates a publisher that forwards articles to the given group chats.
+// Successful forwards are counted on the article through articleService.
+func NewFeishuPublisher(feishuService service.FeishuMessageService, articleService service.ArticleService, groupChats []string) service.Publisher {
+	return &feishuPublisher{
+		feishuService:  feishuService,
+		articleService: articleService,
+		groupChats:     groupChats,
+	}
+}
+
+// Name identifies the publisher in logs.
+func (p *feishuPublisher) Name() string {
+	return "feishu"
+}
+
+// Publish sends the article card to every configured group chat, using the raw post content stored with it.
+func (p *feishuPublisher) Publish(ctx context.Context, article *model.Article) error {
+	if len(p.groupChats) == 0 {
+		logger.Ctx(ctx).Warn("No group chats configured for forwarding", zap.Int64("articleID", article.ID))
+		return nil
+	}
+	card, err := buildForwardingCard(article.RawContent, articleByline(article))
+	if err != nil {
+		return fmt.Errorf("构建转发卡片失败: %w", err)
+	}
+
+	var errs []error
+	forwarded := 0
+	for _, groupID := range p.groupChats {
+		_, err := p.feishuService.SendCardMessage(ctx, groupID, card)
+		metrics.ArticleForwarded(groupID, err)
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to forward card to group chat",
+				zap.Int64("articleID", article.ID),
+				zap.String("groupID", groupID),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("转发到群聊 %s 失败: %w", groupID, err))
+			continue
+		}
+		forwarded++
+		logger.Ctx(ctx).Info("Successfully forwarded card to group chat",
+			zap.Int64("articleID", article.ID),
+			zap.String("groupID", groupID),
+		)
+	}
+	if forwarded > 0 {
+		if err := p.articleService.RecordForwards(ctx, article.ID, forwarded); err != nil {
+			logger.Ctx(ctx).Error("Failed to record forwards", zap.Int64("articleID", article.ID), zap.Error(err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,63 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// GroupArchiveHandlerStrategy handles "@Miko 收录" sent as a reply to another group message,
+// archiving the replied-to message as an article on behalf of its sender.
+type GroupArchiveHandlerStrategy struct {
+	groupCommandMatcher
+	archiveService service.MessageArchiveService
+	feishuService  service.FeishuMessageService
+}
+
+// NewGroupArchiveHandlerStrategy creates a new group archive strategy.
+func NewGroupArchiveHandlerStrategy(
+	archiveService service.MessageArchiveService,
+	feishuService service.FeishuMessageService,
+	botIdentity service.FeishuBotIdentityService,
+) service.MessageHandlerStrategy {
+	return &GroupArchiveHandlerStrategy{
+		groupCommandMatcher: groupCommandMatcher{botIdentity: botIdentity},
+		archiveService:      archiveService,
+		feishuService:       feishuService,
+	}
+}
+
+// ShouldHandle checks if the bot is @-mentioned with the "收录" command.
+func (s *GroupArchiveHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
+	_, ok := s.match(ctx, event, "收录", "archive")
+	return ok
+}
+
+// Handle archives the parent message and replies with the result.
+func (s *GroupArchiveHandlerStrategy) Handle(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	msg := event.Event.Message
+	if msg.ParentId == nil || *msg.ParentId == "" {
+		return replyGroupText(ctx, s.feishuService, event, "请回复需要收录的消息，并在回复中 @我 收录")
+	}
+	if event.Event.Sender == nil || event.Event.Sender.SenderId == nil || event.Event.Sender.SenderId.OpenId == nil {
+		return fmt.Errorf("archive command without sender open_id")
+	}
+	curatorID := *event.Event.Sender.SenderId.OpenId
+
+	result, err := s.archiveService.ArchiveMessage(ctx, *msg.ParentId, curatorID)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to archive replied message",
+			zap.String("parentMessageID", *msg.ParentId),
+			zap.String("curatorOpenID", curatorID),
+			zap.Error(err),
+		)
+		_ = replyGroupText(ctx, s.feishuService, event, fmt.Sprintf("收录失败：%s", err))
+		return fmt.Errorf("archive message failed: %w", err)
+	}
+
+	return replyGroupText(ctx, s.feishuService, event, ArchiveResultText(result))
+}
@@ -0,0 +1,188 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// textContent 对应文本消息的 Content 结构
+type textContent struct {
+	Text string `json:"text"`
+}
+
+// groupCommand 表示群聊中 @机器人 发出的一条命令，例如 "@Miko 搜索 golang"
+type groupCommand struct {
+	Name     string                 // 命令名，如 "搜索"
+	Args     string                 // 命令参数，已去除首尾空白
+	Mentions []*larkim.MentionEvent // 除机器人之外被 @ 的成员
+}
+
+// groupCommandKey 是 ctx 中保存本次事件群聊命令解析结果的键
+type groupCommandKey struct{}
+
+// parsedGroupCommand 保存一次事件的群聊命令解析结果，各群聊策略的 ShouldHandle 和 Handle 共用，只解析一次
+type parsedGroupCommand struct {
+	once sync.Once
+	cmd  *groupCommand
+	ok   bool
+}
+
+// withGroupCommand 返回用于保存本次事件群聊命令解析结果的 ctx，每个事件调用一次
+func withGroupCommand(ctx context.Context) context.Context {
+	return context.WithValue(ctx, groupCommandKey{}, &parsedGroupCommand{})
+}
+
+// groupCommandMatcher 为群聊命令策略提供公共的 @机器人 解析逻辑
+type groupCommandMatcher struct {
+	botIdentity service.FeishuBotIdentityService
+}
+
+// parse 解析群聊命令，ctx 由 withGroupCommand 创建时复用本次事件已解析的结果
+func (m *groupCommandMatcher) parse(ctx context.Context, event *larkim.P2MessageReceiveV1) (*groupCommand, bool) {
+	parsed, ok := ctx.Value(groupCommandKey{}).(*parsedGroupCommand)
+	if !ok {
+		return parseGroupCommand(ctx, event, m.botIdentity)
+	}
+	parsed.once.Do(func() {
+		parsed.cmd, parsed.ok = parseGroupCommand(ctx, event, m.botIdentity)
+	})
+	return parsed.cmd, parsed.ok
+}
+
+// match 解析群聊命令，并在命令名属于 names 之一时返回命令。names 为空时匹配任意命令。
+func (m *groupCommandMatcher) match(ctx context.Context, event *larkim.P2MessageReceiveV1, names ...string) (*groupCommand, bool) {
+	cmd, ok := m.parse(ctx, event)
+	if !ok {
+		return nil, false
+	}
+	if len(names) == 0 {
+		return cmd, true
+	}
+	for _, name := range names {
+		if strings.EqualFold(cmd.Name, name) {
+			return cmd, true
+		}
+	}
+	return nil, false
+}
+
+// parseGroupCommand 从群聊文本消息中解析出 @机器人 的命令。
+// 只有消息的 mentions 中包含机器人本身时才视为命令，其余成员的 @ 占位符会被替换为其姓名。
+func parseGroupCommand(ctx context.Context, event *larkim.P2MessageReceiveV1, botIdentity service.FeishuBotIdentityService) (*groupCommand, bool) {
+	if event.Event == nil || event.Event.Message == nil || event.Event.Message.ChatType == nil ||
+		event.Event.Message.MessageType == nil || event.Event.Message.Content == nil {
+		return nil, false
+	}
+	msg := event.Event.Message
+	if *msg.ChatType != "group" && *msg.ChatType != "topic_group" {
+		return nil, false
+	}
+	if *msg.MessageType != larkim.MsgTypeText || len(msg.Mentions) == 0 {
+		return nil, false
+	}
+
+	botOpenID, err := botIdentity.BotOpenID(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Warn("GroupCommand: Failed to resolve bot identity", zap.Error(err))
+		return nil, false
+	}
+
+	var content textContent
+	if err := json.Unmarshal([]byte(*msg.Content), &content); err != nil {
+		logger.Ctx(ctx).Warn("GroupCommand: Failed to unmarshal text content", zap.Error(err), zap.String("rawContent", *msg.Content))
+		return nil, false
+	}
+
+	text := content.Text
+	mentioned := false
+	others := make([]*larkim.MentionEvent, 0, len(msg.Mentions))
+	for _, mention := range msg.Mentions {
+		if mention == nil || mention.Key == nil {
+			continue
+		}
+		if mention.Id != nil && mention.Id.OpenId != nil && *mention.Id.OpenId == botOpenID {
+			mentioned = true
+			text = strings.ReplaceAll(text, *mention.Key, "")
+			continue
+		}
+		name := ""
+		if mention.Name != nil {
+			name = *mention.Name
+		}
+		text = strings.ReplaceAll(text, *mention.Key, "@"+name)
+		others = append(others, mention)
+	}
+	if !mentioned {
+		return nil, false
+	}
+
+	text = strings.TrimSpace(text)
+	name, args, _ := strings.Cut(text, " ")
+	return &groupCommand{
+		Name:     strings.TrimSpace(name),
+		Args:     strings.TrimSpace(args),
+		Mentions: others,
+	}, true
+}
+
+// replyGroupCard 以卡片形式回复群聊命令
+func replyGroupCard(ctx context.Context, feishuService service.FeishuMessageService, event *larkim.P2MessageReceiveV1, card *service.MessageCardContent) error {
+	msgID := *event.Event.Message.MessageId
+	if _, err := feishuService.ReplyCardMessage(ctx, msgID, card); err != nil {
+		logger.Ctx(ctx).Error("Failed to reply group command with card", zap.String("messageID", msgID), zap.Error(err))
+		return fmt.Errorf("reply card failed: %w", err)
+	}
+	return nil
+}
+
+// replyGroupText 以文本形式回复群聊命令
+func replyGroupText(ctx context.Context, feishuService service.FeishuMessageService, event *larkim.P2MessageReceiveV1, text string) error {
+	msgID := *event.Event.Message.MessageId
+	if _, err := feishuService.ReplyTextMessage(ctx, msgID, text); err != nil {
+		logger.Ctx(ctx).Error("Failed to reply group command with text", zap.String("messageID", msgID), zap.Error(err))
+		return fmt.Errorf("reply text failed: %w", err)
+	}
+	return nil
+}
+
+// buildArticleListCard 构建文章列表卡片
+func buildArticleListCard(title string, articles []*model.Article, emptyText string) *service.MessageCardContent {
+	var md strings.Builder
+	if len(articles) == 0 {
+		md.WriteString(emptyText)
+	}
+	for i, article := range articles {
+		if i > 0 {
+			md.WriteString("\n")
+		}
+		md.WriteString(fmt.Sprintf("%d. **%s**\n    %s · %s · #%d",
+			i+1, article.Title, article.AuthorName, article.CreatedAt.Format("2006-01-02"), article.ID))
+	}
+	return buildMarkdownCard(title, "blue", md.String())
+}
+
+// buildMarkdownCard 构建仅包含一段 lark_md 内容的卡片
+func buildMarkdownCard(title, template, content string) *service.MessageCardContent {
+	return &service.MessageCardContent{
+		Config: map[string]bool{"wide_screen_mode": true},
+		Header: map[string]interface{}{
+			"template": template,
+			"title":    map[string]string{"tag": "plain_text", "content": title},
+		},
+		Elements: []interface{}{
+			map[string]interface{}{
+				"tag":  "div",
+				"text": map[string]string{"tag": "lark_md", "content": content},
+			},
+		},
+	}
+}
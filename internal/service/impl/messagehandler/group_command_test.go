@@ -0,0 +1,126 @@
+package messagehandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+const testBotOpenID = "ou_bot"
+
+// fakeBotIdentity returns a fixed bot open_id and counts the lookups.
+type fakeBotIdentity struct {
+	openID string
+	err    error
+	calls  atomic.Int32
+}
+
+func (f *fakeBotIdentity) BotOpenID(context.Context) (string, error) {
+	f.calls.Add(1)
+	return f.openID, f.err
+}
+
+func strPtr(s string) *string { return &s }
+
+func mention(key, openID, name string) *larkim.MentionEvent {
+	return &larkim.MentionEvent{Key: strPtr(key), Id: &larkim.UserId{OpenId: strPtr(openID)}, Name: strPtr(name)}
+}
+
+// groupTextEvent builds a group text message event.
+func groupTextEvent(text string, mentions ...*larkim.MentionEvent) *larkim.P2MessageReceiveV1 {
+	content, _ := json.Marshal(textContent{Text: text})
+	return &larkim.P2MessageReceiveV1{Event: &larkim.P2MessageReceiveV1Data{Message: &larkim.EventMessage{
+		MessageId:   strPtr("om_1"),
+		ChatId:      strPtr("oc_1"),
+		ChatType:    strPtr("group"),
+		MessageType: strPtr(larkim.MsgTypeText),
+		Content:     strPtr(string(content)),
+		Mentions:    mentions,
+	}}}
+}
+
+func TestParseGroupCommand(t *testing.T) {
+	bot := mention("@_user_1", testBotOpenID, "Miko")
+	alice := mention("@_user_2", "ou_alice", "Alice")
+	p2p := groupTextEvent("@_user_1 最新", bot)
+	p2p.Event.Message.ChatType = strPtr("p2p")
+	image := groupTextEvent("@_user_1 最新", bot)
+	image.Event.Message.MessageType = strPtr(larkim.MsgTypeImage)
+
+	tests := []struct {
+		name         string
+		event        *larkim.P2MessageReceiveV1
+		wantOK       bool
+		wantName     string
+		wantArgs     string
+		wantMentions int
+	}{
+		{"bot mentioned", groupTextEvent("@_user_1 搜索 golang", bot), true, "搜索", "golang", 0},
+		{"another user mentioned", groupTextEvent("@_user_2 搜索 golang", alice), false, "", "", 0},
+		{"no mentions", groupTextEvent("搜索 golang"), false, "", "", 0},
+		{"mention not at the start", groupTextEvent("最新 5 @_user_1", bot), true, "最新", "5", 0},
+		{"bare command", groupTextEvent("@_user_1", bot), true, "", "", 0},
+		{"arguments with extra spaces", groupTextEvent("  @_user_1   搜索    golang   tips  ", bot), true, "搜索", "golang   tips", 0},
+		{"other mentions are replaced by names", groupTextEvent("@_user_1 收录 @_user_2", bot, alice), true, "收录", "@Alice", 1},
+		{"topic group", func() *larkim.P2MessageReceiveV1 {
+			event := groupTextEvent("@_user_1 统计", bot)
+			event.Event.Message.ChatType = strPtr("topic_group")
+			return event
+		}(), true, "统计", "", 0},
+		{"p2p chat", p2p, false, "", "", 0},
+		{"not a text message", image, false, "", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, ok := parseGroupCommand(context.Background(), tt.event, &fakeBotIdentity{openID: testBotOpenID})
+			if ok != tt.wantOK {
+				t.Fatalf("parseGroupCommand() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if cmd.Name != tt.wantName || cmd.Args != tt.wantArgs || len(cmd.Mentions) != tt.wantMentions {
+				t.Errorf("parseGroupCommand() = %q %q with %d mentions, want %q %q with %d mentions",
+					cmd.Name, cmd.Args, len(cmd.Mentions), tt.wantName, tt.wantArgs, tt.wantMentions)
+			}
+		})
+	}
+}
+
+func TestParseGroupCommandBotIdentityUnavailable(t *testing.T) {
+	identity := &fakeBotIdentity{err: errors.New("bot info API unavailable")}
+	event := groupTextEvent("@_user_1 最新", mention("@_user_1", testBotOpenID, "Miko"))
+	if _, ok := parseGroupCommand(context.Background(), event, identity); ok {
+		t.Error("commands should not match while the bot identity is unknown")
+	}
+}
+
+func TestGroupCommandParsedOncePerEvent(t *testing.T) {
+	identity := &fakeBotIdentity{openID: testBotOpenID}
+	handling := NewMessageHandlingService(
+		NewGroupLatestHandlerStrategy(nil, nil, identity),
+		NewGroupSearchHandlerStrategy(nil, nil, identity),
+		NewGroupStatsHandlerStrategy(nil, nil, identity),
+		NewGroupHelpHandlerStrategy(nil, identity),
+	)
+
+	// No strategy matches a message that only mentions another member, so every strategy checks it
+	event := groupTextEvent("@_user_2 最新", mention("@_user_2", "ou_alice", "Alice"))
+	if err := handling.ProcessReceivedMessage(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if got := identity.calls.Load(); got != 1 {
+		t.Errorf("resolved the bot identity %d times for one event, want 1", got)
+	}
+
+	if err := handling.ProcessReceivedMessage(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if got := identity.calls.Load(); got != 2 {
+		t.Errorf("each event should be parsed again, got %d lookups for two events", got)
+	}
+}
@@ -0,0 +1,45 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/service"
+	"context"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// groupHelpText 列出群聊中可用的命令
+const groupHelpText = "**可用命令**\n" +
+	"@我 最新 [数量]：查看最新投稿\n" +
+	"@我 搜索 <关键字>：按关键字搜索投稿\n" +
+	"@我 统计：查看投稿统计\n" +
+	"回复某条消息并 @我 收录：将该消息收录为投稿\n" +
+	"@我 帮助：显示本帮助"
+
+// GroupHelpHandlerStrategy handles any group message that @-mentions the bot
+// but was not handled by a more specific group command strategy.
+type GroupHelpHandlerStrategy struct {
+	groupCommandMatcher
+	feishuService service.FeishuMessageService
+}
+
+// NewGroupHelpHandlerStrategy creates a new group help strategy.
+func NewGroupHelpHandlerStrategy(
+	feishuService service.FeishuMessageService,
+	botIdentity service.FeishuBotIdentityService,
+) service.MessageHandlerStrategy {
+	return &GroupHelpHandlerStrategy{
+		groupCommandMatcher: groupCommandMatcher{botIdentity: botIdentity},
+		feishuService:       feishuService,
+	}
+}
+
+// ShouldHandle matches any group command, acting as the fallback for group chats.
+func (s *GroupHelpHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
+	_, ok := s.match(ctx, event)
+	return ok
+}
+
+// Handle replies with the list of available commands.
+func (s *GroupHelpHandlerStrategy) Handle(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	return replyGroupCard(ctx, s.feishuService, event, buildMarkdownCard("MikoNews 使用帮助", "wathet", groupHelpText))
+}
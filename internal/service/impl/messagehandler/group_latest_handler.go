@@ -0,0 +1,62 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"strconv"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// GroupLatestHandlerStrategy handles "@Miko 最新 [N]" in group chats.
+type GroupLatestHandlerStrategy struct {
+	groupCommandMatcher
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+}
+
+// NewGroupLatestHandlerStrategy creates a new group "latest articles" strategy.
+func NewGroupLatestHandlerStrategy(
+	articleService service.ArticleService,
+	feishuService service.FeishuMessageService,
+	botIdentity service.FeishuBotIdentityService,
+) service.MessageHandlerStrategy {
+	return &GroupLatestHandlerStrategy{
+		groupCommandMatcher: groupCommandMatcher{botIdentity: botIdentity},
+		articleService:      articleService,
+		feishuService:       feishuService,
+	}
+}
+
+// ShouldHandle checks if the bot is @-mentioned with the "最新" command.
+func (s *GroupLatestHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
+	_, ok := s.match(ctx, event, "最新", "latest")
+	return ok
+}
+
+// Handle replies with a card listing the latest articles.
+func (s *GroupLatestHandlerStrategy) Handle(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	cmd, _ := s.match(ctx, event)
+
+	limit := 0
+	if cmd.Args != "" {
+		n, err := strconv.Atoi(cmd.Args)
+		if err != nil {
+			return replyGroupText(ctx, s.feishuService, event, fmt.Sprintf("无效的数量：%s，用法：@我 最新 [数量]", cmd.Args))
+		}
+		limit = n
+	}
+
+	articles, err := s.articleService.ListLatestArticles(ctx, limit)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to list latest articles for group command", zap.Error(err))
+		_ = replyGroupText(ctx, s.feishuService, event, "查询最新投稿失败，请稍后再试")
+		return fmt.Errorf("list latest articles failed: %w", err)
+	}
+
+	card := buildArticleListCard("最新投稿", articles, "还没有任何投稿")
+	return replyGroupCard(ctx, s.feishuService, event, card)
+}
@@ -0,0 +1,55 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// GroupSearchHandlerStrategy handles "@Miko 搜索 <关键字>" in group chats.
+type GroupSearchHandlerStrategy struct {
+	groupCommandMatcher
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+}
+
+// NewGroupSearchHandlerStrategy creates a new group search strategy.
+func NewGroupSearchHandlerStrategy(
+	articleService service.ArticleService,
+	feishuService service.FeishuMessageService,
+	botIdentity service.FeishuBotIdentityService,
+) service.MessageHandlerStrategy {
+	return &GroupSearchHandlerStrategy{
+		groupCommandMatcher: groupCommandMatcher{botIdentity: botIdentity},
+		articleService:      articleService,
+		feishuService:       feishuService,
+	}
+}
+
+// ShouldHandle checks if the bot is @-mentioned with the "搜索" command.
+func (s *GroupSearchHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
+	_, ok := s.match(ctx, event, "搜索", "search")
+	return ok
+}
+
+// Handle replies with a card listing the matching articles.
+func (s *GroupSearchHandlerStrategy) Handle(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	cmd, _ := s.match(ctx, event)
+	if cmd.Args == "" {
+		return replyGroupText(ctx, s.feishuService, event, "请提供搜索关键字，用法：@我 搜索 <关键字>")
+	}
+
+	articles, err := s.articleService.SearchArticles(ctx, cmd.Args, 0)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to search articles for group command", zap.String("keyword", cmd.Args), zap.Error(err))
+		_ = replyGroupText(ctx, s.feishuService, event, "搜索投稿失败，请稍后再试")
+		return fmt.Errorf("search articles failed: %w", err)
+	}
+
+	card := buildArticleListCard(fmt.Sprintf("搜索：%s", cmd.Args), articles, "没有找到相关投稿")
+	return replyGroupCard(ctx, s.feishuService, event, card)
+}
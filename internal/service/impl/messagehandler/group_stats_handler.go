@@ -0,0 +1,67 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"strings"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// GroupStatsHandlerStrategy handles "@Miko 统计" in group chats.
+type GroupStatsHandlerStrategy struct {
+	groupCommandMatcher
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+}
+
+// NewGroupStatsHandlerStrategy creates a new group stats strategy.
+func NewGroupStatsHandlerStrategy(
+	articleService service.ArticleService,
+	feishuService service.FeishuMessageService,
+	botIdentity service.FeishuBotIdentityService,
+) service.MessageHandlerStrategy {
+	return &GroupStatsHandlerStrategy{
+		groupCommandMatcher: groupCommandMatcher{botIdentity: botIdentity},
+		articleService:      articleService,
+		feishuService:       feishuService,
+	}
+}
+
+// ShouldHandle checks if the bot is @-mentioned with the "统计" command.
+func (s *GroupStatsHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
+	_, ok := s.match(ctx, event, "统计", "stats")
+	return ok
+}
+
+// Handle replies with a card summarizing submission statistics.
+func (s *GroupStatsHandlerStrategy) Handle(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+	stats, err := s.articleService.GetArticleStats(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to get article stats for group command", zap.Error(err))
+		_ = replyGroupText(ctx, s.feishuService, event, "查询投稿统计失败，请稍后再试")
+		return fmt.Errorf("get article stats failed: %w", err)
+	}
+
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("**投稿总数**：%d\n", stats.TotalArticles))
+	md.WriteString(fmt.Sprintf("**投稿作者**：%d 位\n", stats.TotalAuthors))
+	md.WriteString(fmt.Sprintf("**最近 7 天**：%d 篇", stats.RecentArticles))
+	if len(stats.TopAuthors) > 0 {
+		md.WriteString("\n\n**投稿榜**")
+		for i, author := range stats.TopAuthors {
+			md.WriteString(fmt.Sprintf("\n%d. %s（%d 篇）", i+1, author.AuthorName, author.ArticleCount))
+		}
+	}
+	if len(stats.TopCoAuthors) > 0 {
+		md.WriteString("\n\n**合著榜**")
+		for i, author := range stats.TopCoAuthors {
+			md.WriteString(fmt.Sprintf("\n%d. %s（%d 篇）", i+1, author.AuthorName, author.ArticleCount))
+		}
+	}
+
+	return replyGroupCard(ctx, s.feishuService, event, buildMarkdownCard("投稿统计", "green", md.String()))
+}
@@ -0,0 +1,208 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.uber.org/zap"
+)
+
+// messageArchiveServiceImpl implements service.MessageArchiveService.
+// It lives next to the submission strategy because it shares the post parsing and card building code.
+type messageArchiveServiceImpl struct {
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+	publisher      service.Publisher
+	userDirectory  service.UserDirectoryService
+	policyService  service.SubmissionPolicyService
+	moderation     service.ContentModerationService
+	adminCfg       *config.AdminConfig
+}
+
+// NewMessageArchiveService creates a new message archive service.
+func NewMessageArchiveService(
+	articleService service.ArticleService,
+	feishuService service.FeishuMessageService,
+	publisher service.Publisher,
+	userDirectory service.UserDirectoryService,
+	policyService service.SubmissionPolicyService,
+	moderation service.ContentModerationService,
+	adminCfg *config.AdminConfig,
+) service.MessageArchiveService {
+	return &messageArchiveServiceImpl{
+		articleService: articleService,
+		feishuService:  feishuService,
+		publisher:      publisher,
+		userDirectory:  userDirectory,
+		policyService:  policyService,
+		moderation:     moderation,
+		adminCfg:       adminCfg,
+	}
+}
+
+// ArchiveMessage archives the message identified by messageID as an article.
+func (s *messageArchiveServiceImpl) ArchiveMessage(ctx context.Context, messageID, curatorID string) (*service.ArchiveResult, error) {
+	logger.Ctx(ctx).Info("Archiving message on behalf of sender", zap.String("messageID", messageID), zap.String("curatorOpenID", curatorID))
+
+	// 0. The curator must be allowed to submit
+	decision, err := s.policyService.Evaluate(ctx, curatorID)
+	if err != nil {
+		return nil, err
+	}
+	if !decision.Allowed {
+		logger.Ctx(ctx).Info("Archive rejected by policy", zap.String("curatorOpenID", curatorID), zap.String("rule", decision.Rule))
+		return nil, errors.New(decision.Message)
+	}
+
+	// 1. Fetch the original message
+	msg, err := s.feishuService.GetMessage(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("获取原消息失败: %w", err)
+	}
+	if msg.Deleted != nil && *msg.Deleted {
+		return nil, fmt.Errorf("原消息已被撤回")
+	}
+	if msg.Sender == nil || msg.Sender.Id == nil || msg.Sender.SenderType == nil || *msg.Sender.SenderType != "user" {
+		return nil, fmt.Errorf("只能收录成员发送的消息")
+	}
+	if msg.Body == nil || msg.Body.Content == nil || msg.MsgType == nil {
+		return nil, fmt.Errorf("原消息内容为空")
+	}
+
+	// 2. Normalize the content into the post structure used by submissions
+	rawContent, err := messageToPostContent(*msg.MsgType, *msg.Body.Content, msg.Mentions)
+	if err != nil {
+		return nil, err
+	}
+	if rawContent, err = resolvePostMentions(rawContent, mentionsFromMessage(msg.Mentions)); err != nil {
+		return nil, fmt.Errorf("解析原消息内容失败: %w", err)
+	}
+	title, textContent, err := parsePostContentForSubmission(rawContent)
+	if err != nil {
+		return nil, fmt.Errorf("解析原消息内容失败: %w", err)
+	}
+
+	// 3. Run the content filters, then save the article with the original sender as author
+	authorID := *msg.Sender.Id
+	moderation, err := s.moderation.Moderate(ctx, &service.ModerationInput{
+		AuthorID:   authorID,
+		Title:      title,
+		Content:    textContent,
+		RawContent: rawContent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if moderation.Verdict == service.FilterReject {
+		return nil, fmt.Errorf("内容未通过审核：%s", moderation.Reason)
+	}
+
+	// 3.1 Merge duplicates of existing articles instead of archiving them again
+	authorName := resolveAuthorName(ctx, s.userDirectory, authorID)
+	duplicate, err := s.articleService.FindDuplicate(ctx, textContent, rawContent)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate != nil {
+		merged, err := s.articleService.MergeCredits(ctx, duplicate.ID, []*model.ArticleAuthor{
+			{OpenID: authorID, Name: authorName, Role: model.AuthorRoleSource},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logger.Ctx(ctx).Info("Archived message merged into existing article",
+			zap.String("messageID", messageID),
+			zap.Int64("articleID", merged.ID),
+			zap.String("curatorOpenID", curatorID),
+		)
+		return &service.ArchiveResult{Article: merged, Merged: true}, nil
+	}
+
+	article, err := s.articleService.SaveSubmission(ctx, &service.Submission{
+		AuthorID:   authorID,
+		AuthorName: authorName,
+		CuratorID:  curatorID,
+		Title:      title,
+		Content:    textContent,
+		RawContent: rawContent,
+		MessageID:  messageID,
+
+		ReviewRequired: decision.ReviewRequired || moderation.Verdict == service.FilterFlag,
+	})
+	if errors.Is(err, service.ErrSubmissionSaved) {
+		// The message was archived before, e.g. the event was redelivered; it has already been forwarded
+		logger.Ctx(ctx).Info("Message already archived", zap.String("messageID", messageID), zap.Int64("articleID", article.ID))
+		return &service.ArchiveResult{Article: article, AlreadySaved: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Forward to the configured group chats, or wait for an admin to review it
+	if article.Status == model.ArticleStatusPending {
+		notifyReviewers(ctx, s.feishuService, s.adminCfg, article)
+	} else {
+		publishArticle(ctx, s.publisher, article, messageID)
+	}
+
+	logger.Ctx(ctx).Info("Message archived successfully",
+		zap.String("messageID", messageID),
+		zap.Int64("articleID", article.ID),
+		zap.String("authorOpenID", authorID),
+		zap.String("curatorOpenID", curatorID),
+	)
+	return &service.ArchiveResult{Article: article}, nil
+}
+
+// messageToPostContent converts the content of a text or post message into post JSON.
+// Mention placeholders such as "@_user_1" in text messages are replaced by the mentioned names.
+func messageToPostContent(msgType, content string, mentions []*larkim.Mention) (string, error) {
+	switch msgType {
+	case larkim.MsgTypePost:
+		return content, nil
+	case larkim.MsgTypeText:
+		var text textContent
+		if err := json.Unmarshal([]byte(content), &text); err != nil {
+			return "", fmt.Errorf("解析文本消息失败: %w", err)
+		}
+		for _, mention := range mentions {
+			if mention != nil && mention.Key != nil && mention.Name != nil {
+				text.Text = strings.ReplaceAll(text.Text, *mention.Key, "@"+*mention.Name)
+			}
+		}
+		return textToPostContent(text.Text)
+	default:
+		return "", fmt.Errorf("暂不支持收录 %s 类型的消息", msgType)
+	}
+}
+
+// textToPostContent wraps plain text into post JSON, one text element per line.
+func textToPostContent(text string) (string, error) {
+	type postElement struct {
+		Tag  string `json:"tag"`
+		Text string `json:"text"`
+	}
+	post := struct {
+		Title   string          `json:"title"`
+		Content [][]postElement `json:"content"`
+	}{}
+	for _, line := range strings.Split(text, "\n") {
+		post.Content = append(post.Content, []postElement{{Tag: "text", Text: line}})
+	}
+	data, err := json.Marshal(post)
+	if err != nil {
+		return "", fmt.Errorf("序列化消息内容失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// Ensure messageArchiveServiceImpl implements MessageArchiveService
+var _ service.MessageArchiveService = (*messageArchiveServiceImpl)(nil)
@@ -0,0 +1,291 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// fakePolicy allows everyone except the denied open_ids and records who was evaluated.
+type fakePolicy struct {
+	denied         map[string]bool
+	reviewRequired bool
+	evaluated      []string
+}
+
+func (p *fakePolicy) Evaluate(_ context.Context, openID string) (*service.PolicyDecision, error) {
+	p.evaluated = append(p.evaluated, openID)
+	if p.denied[openID] {
+		return &service.PolicyDecision{Rule: "deny", Message: "你暂时不能投稿"}, nil
+	}
+	return &service.PolicyDecision{Allowed: true, ReviewRequired: p.reviewRequired}, nil
+}
+
+// fakeModeration returns a fixed verdict.
+type fakeModeration struct {
+	result service.FilterResult
+}
+
+func (m *fakeModeration) Moderate(context.Context, *service.ModerationInput) (*service.FilterResult, error) {
+	result := m.result
+	return &result, nil
+}
+
+// fakeNames resolves every open_id to a fixed name.
+type fakeNames struct {
+	service.UserDirectoryService
+	names map[string]string
+}
+
+func (d *fakeNames) GetUser(_ context.Context, openID string) (*model.User, error) {
+	return &model.User{OpenID: openID, Name: d.names[openID]}, nil
+}
+
+// fakeArchiveArticles saves submissions in memory and serves a configurable duplicate.
+type fakeArchiveArticles struct {
+	service.ArticleService
+	duplicate *model.Article
+	saved     []*service.Submission
+	byMessage map[string]*model.Article
+	merged    []*model.ArticleAuthor
+}
+
+func (f *fakeArchiveArticles) FindDuplicate(context.Context, string, string) (*model.Article, error) {
+	return f.duplicate, nil
+}
+
+func (f *fakeArchiveArticles) MergeCredits(_ context.Context, id int64, credits []*model.ArticleAuthor) (*model.Article, error) {
+	if f.duplicate == nil || f.duplicate.ID != id {
+		return nil, fmt.Errorf("article %d not found", id)
+	}
+	f.merged = append(f.merged, credits...)
+	return f.duplicate, nil
+}
+
+func (f *fakeArchiveArticles) SaveSubmission(_ context.Context, submission *service.Submission) (*model.Article, error) {
+	if existing, ok := f.byMessage[submission.MessageID]; ok {
+		return existing, service.ErrSubmissionSaved
+	}
+	f.saved = append(f.saved, submission)
+	article := &model.Article{
+		ID:         int64(len(f.saved)),
+		Title:      submission.Title,
+		AuthorID:   submission.AuthorID,
+		AuthorName: submission.AuthorName,
+		CuratorID:  submission.CuratorID,
+		Status:     model.ArticleStatusPublished,
+	}
+	if submission.ReviewRequired {
+		article.Status = model.ArticleStatusPending
+	}
+	if f.byMessage == nil {
+		f.byMessage = make(map[string]*model.Article)
+	}
+	f.byMessage[submission.MessageID] = article
+	return article, nil
+}
+
+// archiveFixture wires the archive service to fakes serving one text message om_parent sent by ou_author.
+type archiveFixture struct {
+	feishu    *fakeFeishu
+	publisher *fakePublisher
+	articles  *fakeArchiveArticles
+	policy    *fakePolicy
+	archive   service.MessageArchiveService
+}
+
+func newArchiveFixture(moderation service.FilterResult) *archiveFixture {
+	content, _ := json.Marshal(textContent{Text: "好文推荐\nhttps://example.com/post"})
+	f := &archiveFixture{
+		feishu: &fakeFeishu{messages: map[string]*larkim.Message{
+			"om_parent": {
+				MessageId: strPtr("om_parent"),
+				MsgType:   strPtr(larkim.MsgTypeText),
+				Sender:    &larkim.Sender{Id: strPtr("ou_author"), SenderType: strPtr("user")},
+				Body:      &larkim.MessageBody{Content: strPtr(string(content))},
+			},
+			"om_bot": {
+				MessageId: strPtr("om_bot"),
+				MsgType:   strPtr(larkim.MsgTypeText),
+				Sender:    &larkim.Sender{Id: strPtr("cli_bot"), SenderType: strPtr("app")},
+				Body:      &larkim.MessageBody{Content: strPtr(string(content))},
+			},
+		}},
+		publisher: &fakePublisher{},
+		articles:  &fakeArchiveArticles{},
+		policy:    &fakePolicy{denied: map[string]bool{"ou_denied": true}},
+	}
+	names := &fakeNames{names: map[string]string{"ou_author": "张三", "ou_curator": "李四"}}
+	f.archive = NewMessageArchiveService(f.articles, f.feishu, f.publisher, names, f.policy,
+		&fakeModeration{result: moderation}, &config.AdminConfig{OpenIDs: []string{"ou_admin"}})
+	return f
+}
+
+func TestArchiveMessage(t *testing.T) {
+	t.Run("archives on behalf of the sender", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{})
+		result, err := f.archive.ArchiveMessage(context.Background(), "om_parent", "ou_curator")
+		if err != nil {
+			t.Fatalf("ArchiveMessage() error = %v", err)
+		}
+		if result.Merged || result.AlreadySaved {
+			t.Errorf("result = %+v, want a new article", result)
+		}
+		if len(f.articles.saved) != 1 {
+			t.Fatalf("saved %d submissions, want 1", len(f.articles.saved))
+		}
+		saved := f.articles.saved[0]
+		if saved.AuthorID != "ou_author" || saved.AuthorName != "张三" || saved.CuratorID != "ou_curator" || saved.MessageID != "om_parent" {
+			t.Errorf("submission = %+v, want the sender as author and the curator recorded", saved)
+		}
+		if saved.Title != "好文推荐" {
+			t.Errorf("title = %q, want the first line", saved.Title)
+		}
+		if len(f.publisher.published) != 1 {
+			t.Errorf("published %v, want the new article", f.publisher.published)
+		}
+	})
+
+	t.Run("policy is evaluated on the curator", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{})
+		_, err := f.archive.ArchiveMessage(context.Background(), "om_parent", "ou_denied")
+		if err == nil || err.Error() != "你暂时不能投稿" {
+			t.Errorf("error = %v, want the policy message", err)
+		}
+		if len(f.policy.evaluated) != 1 || f.policy.evaluated[0] != "ou_denied" {
+			t.Errorf("evaluated %v, want only the curator", f.policy.evaluated)
+		}
+		if len(f.articles.saved) != 0 {
+			t.Error("a denied curator must not archive messages")
+		}
+	})
+
+	t.Run("review required by the curator policy", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{})
+		f.policy.reviewRequired = true
+		result, err := f.archive.ArchiveMessage(context.Background(), "om_parent", "ou_curator")
+		if err != nil {
+			t.Fatalf("ArchiveMessage() error = %v", err)
+		}
+		if result.Article.Status != model.ArticleStatusPending || len(f.publisher.published) != 0 {
+			t.Errorf("status = %q, published %v; want a pending article", result.Article.Status, f.publisher.published)
+		}
+		if len(f.feishu.direct) != 1 || !strings.HasPrefix(f.feishu.direct[0], "ou_admin: 新投稿待审核") {
+			t.Errorf("direct messages = %v, want the admin notified", f.feishu.direct)
+		}
+	})
+
+	t.Run("moderation reject", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{Verdict: service.FilterReject, Filter: "keyword", Reason: "包含敏感词"})
+		_, err := f.archive.ArchiveMessage(context.Background(), "om_parent", "ou_curator")
+		if err == nil || !strings.Contains(err.Error(), "包含敏感词") {
+			t.Errorf("error = %v, want the moderation reason", err)
+		}
+		if len(f.articles.saved) != 0 || len(f.publisher.published) != 0 {
+			t.Error("rejected content must not be saved or published")
+		}
+	})
+
+	t.Run("moderation flag requires review", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{Verdict: service.FilterFlag, Filter: "links"})
+		result, err := f.archive.ArchiveMessage(context.Background(), "om_parent", "ou_curator")
+		if err != nil {
+			t.Fatalf("ArchiveMessage() error = %v", err)
+		}
+		if result.Article.Status != model.ArticleStatusPending {
+			t.Errorf("status = %q, want pending", result.Article.Status)
+		}
+	})
+
+	t.Run("duplicate is merged crediting the sender as source", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{})
+		f.articles.duplicate = &model.Article{ID: 7, Title: "已有文章", Status: model.ArticleStatusPublished}
+		result, err := f.archive.ArchiveMessage(context.Background(), "om_parent", "ou_curator")
+		if err != nil {
+			t.Fatalf("ArchiveMessage() error = %v", err)
+		}
+		if !result.Merged || result.Article.ID != 7 {
+			t.Errorf("result = %+v, want merged into article 7", result)
+		}
+		if len(f.articles.merged) != 1 || f.articles.merged[0].OpenID != "ou_author" || f.articles.merged[0].Role != model.AuthorRoleSource {
+			t.Errorf("merged credits = %+v, want the sender as source", f.articles.merged)
+		}
+		if len(f.articles.saved) != 0 || len(f.publisher.published) != 0 {
+			t.Error("a merged duplicate must not be saved or published again")
+		}
+	})
+
+	t.Run("redelivered archive is not published twice", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{})
+		if _, err := f.archive.ArchiveMessage(context.Background(), "om_parent", "ou_curator"); err != nil {
+			t.Fatalf("first ArchiveMessage() error = %v", err)
+		}
+		result, err := f.archive.ArchiveMessage(context.Background(), "om_parent", "ou_curator")
+		if err != nil {
+			t.Fatalf("second ArchiveMessage() error = %v", err)
+		}
+		if !result.AlreadySaved || len(f.publisher.published) != 1 {
+			t.Errorf("result = %+v, published %v; want already saved and published once", result, f.publisher.published)
+		}
+	})
+
+	t.Run("messages sent by apps are not archived", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{})
+		if _, err := f.archive.ArchiveMessage(context.Background(), "om_bot", "ou_curator"); err == nil {
+			t.Error("archiving a bot message should fail")
+		}
+	})
+}
+
+func TestGroupArchiveHandler(t *testing.T) {
+	reply := func(parentID string) *larkim.P2MessageReceiveV1 {
+		event := groupTextEvent("@_user_1 收录", mention("@_user_1", testBotOpenID, "Miko"))
+		event.Event.Sender = &larkim.EventSender{SenderId: &larkim.UserId{OpenId: strPtr("ou_curator")}}
+		if parentID != "" {
+			event.Event.Message.ParentId = strPtr(parentID)
+		}
+		return event
+	}
+	tests := []struct {
+		name      string
+		event     *larkim.P2MessageReceiveV1
+		wantErr   bool
+		wantReply string
+	}{
+		{"reply to a message", reply("om_parent"), false, "已收录 '好文推荐'，作者：张三 (ID: 1)，感谢推荐！"},
+		{"without a replied message", reply(""), false, "请回复需要收录的消息，并在回复中 @我 收录"},
+		{"archive failure is replied", reply("om_missing"), true, "收录失败：获取原消息失败: message om_missing not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newArchiveFixture(service.FilterResult{})
+			handler := NewGroupArchiveHandlerStrategy(f.archive, f.feishu, &fakeBotIdentity{openID: testBotOpenID})
+			ctx := withGroupCommand(context.Background())
+			if !handler.ShouldHandle(ctx, tt.event) {
+				t.Fatal("ShouldHandle() = false, want the archive command matched")
+			}
+			if err := handler.Handle(ctx, tt.event); (err != nil) != tt.wantErr {
+				t.Errorf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := f.feishu.lastReply(); got != tt.wantReply {
+				t.Errorf("reply = %q, want %q", got, tt.wantReply)
+			}
+		})
+	}
+
+	t.Run("other commands are ignored", func(t *testing.T) {
+		f := newArchiveFixture(service.FilterResult{})
+		handler := NewGroupArchiveHandlerStrategy(f.archive, f.feishu, &fakeBotIdentity{openID: testBotOpenID})
+		event := groupTextEvent("@_user_1 最新", mention("@_user_1", testBotOpenID, "Miko"))
+		if handler.ShouldHandle(withGroupCommand(context.Background()), event) {
+			t.Error("ShouldHandle() = true for another command")
+		}
+	})
+}
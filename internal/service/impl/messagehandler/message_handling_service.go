@@ -2,11 +2,14 @@ package messagehandler
 
 import (
 	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/metrics"
+	"MikoNews/internal/pkg/tracing"
 	"MikoNews/internal/service"
 	"context"
 	"fmt"
 
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -22,37 +25,58 @@ func NewMessageHandlingService(strategies ...service.MessageHandlerStrategy) ser
 	}
 }
 
-func (s *messageHandlingServiceImpl) ProcessReceivedMessage(ctx context.Context, event *larkim.P2MessageReceiveV1) error {
+func (s *messageHandlingServiceImpl) ProcessReceivedMessage(ctx context.Context, event *larkim.P2MessageReceiveV1) (err error) {
 	messageID := "unknown"
 	if event.Event != nil && event.Event.Message != nil && event.Event.Message.MessageId != nil {
 		messageID = *event.Event.Message.MessageId
 	}
-	logger.Info("Processing received message", zap.String("messageID", messageID))
+	ctx, span := tracing.Start(ctx, "message.process", attribute.String("feishu.message_id", messageID))
+	// Group command strategies share one parse of the @-mention per event
+	ctx = withGroupCommand(ctx)
+	defer func() { tracing.End(span, err) }()
+	logger.Ctx(ctx).Info("Processing received message", zap.String("messageID", messageID))
 
+	strategy := s.selectStrategy(ctx, event)
+	if strategy == nil {
+		logger.Ctx(ctx).Warn("No suitable strategy found for message", zap.String("messageID", messageID))
+		// Optionally, implement a default action here if no strategy matches
+		return nil // Or return an error if unhandled messages are considered an error
+	}
+
+	strategyName := fmt.Sprintf("%T", strategy) // Identify the strategy by its type in logs, metrics and traces
+	logger.Ctx(ctx).Info("Found matching strategy",
+		zap.String("messageID", messageID),
+		zap.String("strategy", strategyName),
+	)
+	metrics.StrategyMatched(strategyName)
+	handleCtx, handleSpan := tracing.Start(ctx, "strategy.handle", attribute.String("strategy", strategyName))
+	err = strategy.Handle(handleCtx, event)
+	tracing.End(handleSpan, err)
+	if err != nil {
+		metrics.StrategyFailed(strategyName)
+		logger.Ctx(ctx).Error("Error handling message with strategy",
+			zap.String("messageID", messageID),
+			zap.String("strategy", strategyName),
+			zap.Error(err),
+		)
+		return fmt.Errorf("strategy %T failed: %w", strategy, err)
+	}
+	logger.Ctx(ctx).Info("Message handled successfully by strategy",
+		zap.String("messageID", messageID),
+		zap.String("strategy", strategyName),
+	)
+	return nil // Strategy handled the message, stop processing
+}
+
+// selectStrategy returns the first strategy that should handle the event, or nil when none matches.
+func (s *messageHandlingServiceImpl) selectStrategy(ctx context.Context, event *larkim.P2MessageReceiveV1) service.MessageHandlerStrategy {
+	ctx, span := tracing.Start(ctx, "message.select_strategy")
+	defer span.End()
 	for _, strategy := range s.strategies {
 		if strategy.ShouldHandle(ctx, event) {
-			logger.Info("Found matching strategy",
-				zap.String("messageID", messageID),
-				zap.String("strategy", fmt.Sprintf("%T", strategy)), // Log strategy type
-			)
-			err := strategy.Handle(ctx, event)
-			if err != nil {
-				logger.Error("Error handling message with strategy",
-					zap.String("messageID", messageID),
-					zap.String("strategy", fmt.Sprintf("%T", strategy)),
-					zap.Error(err),
-				)
-				return fmt.Errorf("strategy %T failed: %w", strategy, err)
-			}
-			logger.Info("Message handled successfully by strategy",
-				zap.String("messageID", messageID),
-				zap.String("strategy", fmt.Sprintf("%T", strategy)),
-			)
-			return nil // Strategy handled the message, stop processing
+			span.SetAttributes(attribute.String("strategy", fmt.Sprintf("%T", strategy)))
+			return strategy
 		}
 	}
-
-	logger.Warn("No suitable strategy found for message", zap.String("messageID", messageID))
-	// Optionally, implement a default action here if no strategy matches
-	return nil // Or return an error if unhandled messages are considered an error
+	return nil
 }
@@ -0,0 +1,102 @@
+package messagehandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// mentionAllID 是富文本中 @所有人 使用的 user_id
+const mentionAllID = "all"
+
+// postMention 表示富文本中 @ 的一位成员
+type postMention struct {
+	OpenID string
+	Name   string
+}
+
+// mentionsFromEvent 将消息事件中的 mentions 转换为 key -> 成员的映射
+func mentionsFromEvent(mentions []*larkim.MentionEvent) map[string]postMention {
+	result := make(map[string]postMention, len(mentions))
+	for _, m := range mentions {
+		if m == nil || m.Key == nil || m.Id == nil || m.Id.OpenId == nil {
+			continue
+		}
+		mention := postMention{OpenID: *m.Id.OpenId}
+		if m.Name != nil {
+			mention.Name = *m.Name
+		}
+		result[*m.Key] = mention
+	}
+	return result
+}
+
+// mentionsFromMessage 将获取消息 API 返回的 mentions 转换为 key -> 成员的映射
+func mentionsFromMessage(mentions []*larkim.Mention) map[string]postMention {
+	result := make(map[string]postMention, len(mentions))
+	for _, m := range mentions {
+		if m == nil || m.Key == nil || m.Id == nil {
+			continue
+		}
+		mention := postMention{OpenID: *m.Id}
+		if m.Name != nil {
+			mention.Name = *m.Name
+		}
+		result[*m.Key] = mention
+	}
+	return result
+}
+
+// resolvePostMentions 将富文本 at 元素中的 "@_user_N" 占位符替换为成员真实的 OpenID 和名字。
+// 飞书推送的消息内容中 at 元素只携带占位符，真实身份需通过消息的 mentions 字段对应。
+func resolvePostMentions(rawContent string, mentions map[string]postMention) (string, error) {
+	if len(mentions) == 0 || !strings.Contains(rawContent, `"at"`) {
+		return rawContent, nil
+	}
+
+	var post map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(rawContent), &post); err != nil {
+		return "", fmt.Errorf("failed to unmarshal post content: %w", err)
+	}
+	var lines [][]map[string]interface{}
+	if raw, ok := post["content"]; ok {
+		if err := json.Unmarshal(raw, &lines); err != nil {
+			return "", fmt.Errorf("failed to unmarshal post lines: %w", err)
+		}
+	}
+
+	for _, line := range lines {
+		for _, element := range line {
+			if element["tag"] != "at" {
+				continue
+			}
+			key, _ := element["user_id"].(string)
+			mention, ok := mentions[key]
+			if !ok {
+				continue
+			}
+			element["user_id"] = mention.OpenID
+			if name, _ := element["user_name"].(string); name == "" || strings.HasPrefix(name, "@_user_") {
+				element["user_name"] = mention.Name
+			}
+		}
+	}
+
+	content, err := json.Marshal(lines)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal post lines: %w", err)
+	}
+	post["content"] = content
+	resolved, err := json.Marshal(post)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal post content: %w", err)
+	}
+	return string(resolved), nil
+}
+
+// mentionMarkdown 返回卡片 lark_md 中 @ 成员的标签，被 @ 的成员会收到通知
+func mentionMarkdown(openID string) string {
+	return fmt.Sprintf("<at id=%s></at>", openID)
+}
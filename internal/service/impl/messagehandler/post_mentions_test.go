@@ -0,0 +1,169 @@
+package messagehandler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// postJSON builds rich text content from lines of elements.
+func postJSON(t *testing.T, lines ...[]map[string]interface{}) string {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{"title": "", "content": lines})
+	if err != nil {
+		t.Fatalf("marshal post: %v", err)
+	}
+	return string(raw)
+}
+
+func textElem(text string) map[string]interface{} {
+	return map[string]interface{}{"tag": "text", "text": text}
+}
+
+func atElem(userID, userName string) map[string]interface{} {
+	return map[string]interface{}{"tag": "at", "user_id": userID, "user_name": userName}
+}
+
+// postLines returns the lines of rich text content.
+func postLines(t *testing.T, rawContent string) [][]map[string]interface{} {
+	t.Helper()
+	var post struct {
+		Content [][]map[string]interface{} `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(rawContent), &post); err != nil {
+		t.Fatalf("unmarshal post: %v", err)
+	}
+	return post.Content
+}
+
+// postAts returns the user_id/user_name pairs of all at elements in the post.
+func postAts(t *testing.T, rawContent string) [][2]string {
+	t.Helper()
+	var ats [][2]string
+	for _, line := range postLines(t, rawContent) {
+		for _, element := range line {
+			if element["tag"] == "at" {
+				id, _ := element["user_id"].(string)
+				name, _ := element["user_name"].(string)
+				ats = append(ats, [2]string{id, name})
+			}
+		}
+	}
+	return ats
+}
+
+func TestResolvePostMentions(t *testing.T) {
+	mentions := map[string]postMention{
+		"@_user_1": {OpenID: "ou_b", Name: "李四"},
+		"@_user_2": {OpenID: testBotOpenID, Name: "Miko"},
+		"@_all":    {OpenID: mentionAllID, Name: "所有人"},
+	}
+	tests := []struct {
+		name     string
+		lines    [][]map[string]interface{}
+		mentions map[string]postMention
+		want     [][2]string
+	}{
+		{
+			name:  "placeholder is replaced with open_id and name",
+			lines: [][]map[string]interface{}{{textElem("合著: "), atElem("@_user_1", "")}},
+			want:  [][2]string{{"ou_b", "李四"}},
+		},
+		{
+			name:  "placeholder name is replaced",
+			lines: [][]map[string]interface{}{{atElem("@_user_1", "@_user_1")}},
+			want:  [][2]string{{"ou_b", "李四"}},
+		},
+		{
+			name:  "existing name is kept",
+			lines: [][]map[string]interface{}{{atElem("@_user_1", "小李")}},
+			want:  [][2]string{{"ou_b", "小李"}},
+		},
+		{
+			name:  "bot mention resolves like any member",
+			lines: [][]map[string]interface{}{{atElem("@_user_2", "")}},
+			want:  [][2]string{{testBotOpenID, "Miko"}},
+		},
+		{
+			name:  "duplicate mentions are all resolved",
+			lines: [][]map[string]interface{}{{atElem("@_user_1", ""), atElem("@_user_1", "")}},
+			want:  [][2]string{{"ou_b", "李四"}, {"ou_b", "李四"}},
+		},
+		{
+			name:  "unknown key is left alone",
+			lines: [][]map[string]interface{}{{atElem("@_user_9", "")}},
+			want:  [][2]string{{"@_user_9", ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePostMentions(postJSON(t, tt.lines...), mentions)
+			if err != nil {
+				t.Fatalf("resolvePostMentions() error = %v", err)
+			}
+			ats := postAts(t, got)
+			if len(ats) != len(tt.want) {
+				t.Fatalf("at elements = %v, want %v", ats, tt.want)
+			}
+			for i := range ats {
+				if ats[i] != tt.want[i] {
+					t.Errorf("at[%d] = %v, want %v", i, ats[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolvePostMentionsUnchanged(t *testing.T) {
+	plain := postJSON(t, []map[string]interface{}{textElem("hello")})
+	if got, err := resolvePostMentions(plain, map[string]postMention{"@_user_1": {OpenID: "ou_b"}}); err != nil || got != plain {
+		t.Errorf("post without at elements = %q, %v; want it unchanged", got, err)
+	}
+	withAt := postJSON(t, []map[string]interface{}{atElem("@_user_1", "")})
+	if got, err := resolvePostMentions(withAt, nil); err != nil || got != withAt {
+		t.Errorf("post without mentions = %q, %v; want it unchanged", got, err)
+	}
+}
+
+func TestBuildForwardingCardMentions(t *testing.T) {
+	raw := postJSON(t,
+		[]map[string]interface{}{textElem("标题")},
+		[]map[string]interface{}{textElem("感谢 "), atElem("ou_b", "李四"), textElem(" 和 "), atElem("@_user_9", "王五"), textElem(" "), atElem(mentionAllID, "所有人")},
+	)
+	card, err := buildForwardingCard(raw, "投稿人：张三 · 合著：<at id=ou_c></at>")
+	if err != nil {
+		t.Fatalf("buildForwardingCard() error = %v", err)
+	}
+	body, err := json.Marshal(card)
+	if err != nil {
+		t.Fatalf("marshal card: %v", err)
+	}
+	var rendered struct {
+		Elements []struct {
+			Tag      string              `json:"tag"`
+			Text     map[string]string   `json:"text"`
+			Elements []map[string]string `json:"elements"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(body, &rendered); err != nil {
+		t.Fatalf("unmarshal card: %v", err)
+	}
+	if len(rendered.Elements) != 2 {
+		t.Fatalf("card elements = %d, want content and note", len(rendered.Elements))
+	}
+
+	content := rendered.Elements[0].Text["content"]
+	for _, want := range []string{"<at id=ou_b></at>", "@王五", "<at id=all></at>"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("card content %q does not contain %q", content, want)
+		}
+	}
+	if strings.Contains(content, "@_user_9") {
+		t.Errorf("card content %q leaks an unresolved placeholder", content)
+	}
+
+	note := rendered.Elements[1]
+	if note.Tag != "note" || len(note.Elements) != 1 || note.Elements[0]["content"] != "投稿人：张三 · 合著：<at id=ou_c></at>" {
+		t.Errorf("card note = %+v, want the byline with the mention", note)
+	}
+}
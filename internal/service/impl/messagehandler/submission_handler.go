@@ -2,13 +2,16 @@ package messagehandler
 
 import (
 	"MikoNews/internal/config"
+	"MikoNews/internal/model"
 	"MikoNews/internal/pkg/logger"
 	"MikoNews/internal/service"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
+	"time"
 
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 	"go.uber.org/zap"
@@ -19,36 +22,58 @@ type postContentTitleCheck struct {
 	Title string `json:"title"`
 }
 
+// Post titles that mark a P2P post message as a submission.
+const (
+	submissionTitle          = "投稿"
+	anonymousSubmissionTitle = "匿名投稿" // Dedicated trigger for anonymous submissions
+)
+
 // SubmissionHandlerStrategy handles messages starting with /投稿
 type SubmissionHandlerStrategy struct {
-	articleService       service.ArticleService
-	feishuService        service.FeishuMessageService
-	feishuContactService service.FeishuContactService
-	cfg                  *config.FeishuConfig
+	articleService service.ArticleService
+	feishuService  service.FeishuMessageService
+	publisher      service.Publisher
+	userDirectory  service.UserDirectoryService
+	policyService  service.SubmissionPolicyService
+	rateLimiter    service.SubmissionRateLimiter
+	moderation     service.ContentModerationService
+	adminCfg       *config.AdminConfig
+	serverCfg      *config.ServerConfig
 }
 
 // NewSubmissionHandlerStrategy creates a new submission handler strategy.
 func NewSubmissionHandlerStrategy(
 	articleService service.ArticleService,
 	feishuService service.FeishuMessageService,
-	feishuContactService service.FeishuContactService,
-	cfg *config.FeishuConfig,
+	publisher service.Publisher,
+	userDirectory service.UserDirectoryService,
+	policyService service.SubmissionPolicyService,
+	rateLimiter service.SubmissionRateLimiter,
+	moderation service.ContentModerationService,
+	adminCfg *config.AdminConfig,
+	serverCfg *config.ServerConfig,
 ) service.MessageHandlerStrategy {
 	return &SubmissionHandlerStrategy{
-		articleService:       articleService,
-		feishuService:        feishuService,
-		feishuContactService: feishuContactService,
-		cfg:                  cfg,
+		articleService: articleService,
+		feishuService:  feishuService,
+		publisher:      publisher,
+		userDirectory:  userDirectory,
+		policyService:  policyService,
+		rateLimiter:    rateLimiter,
+		moderation:     moderation,
+		adminCfg:       adminCfg,
+		serverCfg:      serverCfg,
 	}
 }
 
-// ShouldHandle checks if the message is a P2P Post message with title "投稿"
+// ShouldHandle checks if the message is a P2P Post message with title "投稿" or "匿名投稿"
 func (s *SubmissionHandlerStrategy) ShouldHandle(ctx context.Context, event *larkim.P2MessageReceiveV1) bool {
 	// Basic event and message structure checks
 	if event.Event == nil || event.Event.Message == nil || event.Event.Sender == nil || event.Event.Sender.SenderId == nil ||
+		event.Event.Message.ChatType == nil || event.Event.Message.MessageType == nil ||
 		*event.Event.Message.ChatType != "p2p" || *event.Event.Message.MessageType != larkim.MsgTypePost ||
 		event.Event.Message.Content == nil {
-		logger.Debug("SubmissionHandler: Event structure/type mismatch")
+		logger.Ctx(ctx).Debug("SubmissionHandler: Event structure/type mismatch")
 		return false
 	}
 
@@ -57,17 +82,17 @@ func (s *SubmissionHandlerStrategy) ShouldHandle(ctx context.Context, event *lar
 	rawContent := *event.Event.Message.Content
 	if err := json.Unmarshal([]byte(rawContent), &contentCheck); err != nil {
 		// Log the error if needed, but don't handle if parsing fails
-		logger.Warn("SubmissionHandler: Failed to unmarshal post content for title check", zap.Error(err), zap.String("rawContent", rawContent))
+		logger.Ctx(ctx).Warn("SubmissionHandler: Failed to unmarshal post content for title check", zap.Error(err), zap.String("rawContent", rawContent))
 		return false
 	}
 
-	// Check if the title field is exactly "投稿"
-	if contentCheck.Title == "投稿" {
-		logger.Debug("SubmissionHandler: Matched title '投稿'")
+	// Check if the title field is exactly "投稿" or "匿名投稿"
+	if contentCheck.Title == submissionTitle || contentCheck.Title == anonymousSubmissionTitle {
+		logger.Ctx(ctx).Debug("SubmissionHandler: Matched submission title", zap.String("foundTitle", contentCheck.Title))
 		return true
 	}
 
-	logger.Debug("SubmissionHandler: Title did not match '投稿'", zap.String("foundTitle", contentCheck.Title))
+	logger.Ctx(ctx).Debug("SubmissionHandler: Title did not match '投稿'", zap.String("foundTitle", contentCheck.Title))
 	return false
 }
 
@@ -77,87 +102,267 @@ func (s *SubmissionHandlerStrategy) Handle(ctx context.Context, event *larkim.P2
 	senderID := *event.Event.Sender.SenderId.OpenId
 	rawContent := *event.Event.Message.Content
 
-	logger.Info("Handling submission", zap.String("messageID", msgID), zap.String("senderOpenID", senderID))
+	logger.Ctx(ctx).Info("Handling submission", zap.String("messageID", msgID), zap.String("senderOpenID", senderID))
+
+	// 0. Check the submission policy before doing any work
+	decision, err := s.policyService.Evaluate(ctx, senderID)
+	if err == nil && !decision.Allowed {
+		logger.Ctx(ctx).Info("Submission rejected by policy", zap.String("senderOpenID", senderID), zap.String("rule", decision.Rule))
+		if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, decision.Message); replyErr != nil {
+			logger.Ctx(ctx).Error("Failed to send policy rejection reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+		}
+		return nil
+	}
+	if err != nil {
+		if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, "校验投稿权限失败，请稍后再试"); replyErr != nil {
+			logger.Ctx(ctx).Error("Failed to send error reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+		}
+		return err
+	}
+
+	// 0.1 Reject users who are over quota before doing any work; the quota is consumed atomically before saving
+	quota, err := s.rateLimiter.Check(ctx, senderID)
+	if err != nil {
+		// Fail open: a counter backend outage should not block submissions
+		logger.Ctx(ctx).Warn("Submission rate limit check failed, allowing submission", zap.String("senderOpenID", senderID), zap.Error(err))
+	} else if !quota.Allowed {
+		if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, rateLimitReplyText(quota)); replyErr != nil {
+			logger.Ctx(ctx).Error("Failed to send rate limit reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+		}
+		return nil
+	}
 
-	// 1. Parse Post content
-	title, textContent, err := parsePostContentForSubmission(rawContent)
+	// 1. Resolve @-mentions, extract option lines (e.g. "匿名: 是", "合著: @张三") and parse Post content
+	rawContent, err = resolvePostMentions(rawContent, mentionsFromEvent(event.Event.Message.Mentions))
+	var opts *submissionOptions
+	if err == nil {
+		rawContent, opts, err = extractSubmissionOptions(rawContent)
+	}
+	var title, textContent string
+	if err == nil {
+		title, textContent, err = parsePostContentForSubmission(rawContent)
+	}
 	if err != nil {
-		logger.Error("Failed to parse post content", zap.String("messageID", msgID), zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to parse post content", zap.String("messageID", msgID), zap.Error(err))
 		// Reply to user about parsing error
 		replyText := fmt.Sprintf("解析投稿内容失败：%s", err)
 		if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, replyText); replyErr != nil {
-			logger.Error("Failed to send error reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+			logger.Ctx(ctx).Error("Failed to send error reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
 		}
 		return fmt.Errorf("parsing post content failed: %w", err)
 	}
 
-	// 2. Get Author Name using FeishuContactService
-	authorName := senderID // Default to senderID if fetching fails
-	userInfo, err := s.feishuContactService.GetUserInfoByOpenID(ctx, senderID)
+	var contentCheck postContentTitleCheck
+	_ = json.Unmarshal([]byte(rawContent), &contentCheck)
+	anonymous := opts.Anonymous || contentCheck.Title == anonymousSubmissionTitle
+
+	// 1.1 Run the content filters: rejected content is not saved, flagged content goes to review
+	moderation, err := s.moderation.Moderate(ctx, &service.ModerationInput{
+		AuthorID:   senderID,
+		Title:      title,
+		Content:    textContent,
+		RawContent: rawContent,
+	})
 	if err != nil {
-		// Log the error but continue with senderID as author name
-		logger.Warn("Failed to get user info from Feishu API, using OpenID as author name",
-			zap.String("senderOpenID", senderID),
-			zap.Error(err),
-		)
-	} else if userInfo != nil && userInfo.Name != nil && *userInfo.Name != "" {
-		authorName = *userInfo.Name
-		logger.Info("Successfully fetched author name", zap.String("senderOpenID", senderID), zap.String("authorName", authorName))
-	} else {
-		logger.Warn("Fetched user info from Feishu API, but Name field is missing or empty, using OpenID as author name", zap.String("senderOpenID", senderID))
+		logger.Ctx(ctx).Error("Failed to moderate submission", zap.String("messageID", msgID), zap.Error(err))
+		return fmt.Errorf("moderating submission failed: %w", err)
+	}
+	if moderation.Verdict == service.FilterReject {
+		replyText := fmt.Sprintf("投稿未通过内容审核：%s", moderation.Reason)
+		if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, replyText); replyErr != nil {
+			logger.Ctx(ctx).Error("Failed to send moderation rejection reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+		}
+		return nil
+	}
+
+	// 1.2 Warn the author about duplicates of existing articles, unless confirmed with "重复: 忽略"
+	if !opts.AllowDuplicate {
+		duplicate, err := s.articleService.FindDuplicate(ctx, textContent, rawContent)
+		if err != nil {
+			// Duplicate detection is best effort and must not block submissions
+			logger.Ctx(ctx).Warn("Duplicate detection failed", zap.String("messageID", msgID), zap.Error(err))
+		} else if duplicate != nil {
+			logger.Ctx(ctx).Info("Duplicate submission detected", zap.String("messageID", msgID), zap.Int64("duplicateOf", duplicate.ID))
+			if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, duplicateReplyText(duplicate, s.serverCfg)); replyErr != nil {
+				logger.Ctx(ctx).Error("Failed to send duplicate warning to user", zap.String("messageID", msgID), zap.Error(replyErr))
+			}
+			return nil
+		}
 	}
 
-	// 3. Call ArticleService to save the submission
-	createdArticle, err := s.articleService.SaveSubmission(ctx, senderID, authorName, title, textContent)
+	// 2. Get Author Name from the user directory (skipped for anonymous submissions)
+	authorName := model.AnonymousAuthorName
+	if !anonymous {
+		authorName = resolveAuthorName(ctx, s.userDirectory, senderID)
+	}
+
+	// 3. Consume one submission from the quota, then call ArticleService to save the submission
+	acquired, proceed := s.acquireQuota(ctx, senderID, msgID)
+	if !proceed {
+		return nil
+	}
+	createdArticle, err := s.articleService.SaveSubmission(ctx, &service.Submission{
+		AuthorID:       senderID,
+		AuthorName:     authorName,
+		Anonymous:      anonymous,
+		Title:          title,
+		Content:        textContent,
+		RawContent:     rawContent,
+		MessageID:      msgID,
+		Credits:        opts.Credits,
+		ReviewRequired: decision.ReviewRequired || moderation.Verdict == service.FilterFlag,
+	})
+	if errors.Is(err, service.ErrSubmissionSaved) {
+		// A redelivered event or a resumed queue job: the first delivery already replied and forwarded the article
+		s.releaseQuota(ctx, senderID, acquired)
+		logger.Ctx(ctx).Info("Submission already saved, skipping", zap.String("messageID", msgID), zap.Int64("articleID", createdArticle.ID))
+		return nil
+	}
 	if err != nil {
-		logger.Error("Failed to save submission", zap.String("messageID", msgID), zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to save submission", zap.String("messageID", msgID), zap.Error(err))
+		s.releaseQuota(ctx, senderID, acquired)
 		// Reply to user about saving error
 		replyText := fmt.Sprintf("保存投稿失败：%s", err)
 		if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, replyText); replyErr != nil {
-			logger.Error("Failed to send error reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+			logger.Ctx(ctx).Error("Failed to send error reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
 		}
 		return fmt.Errorf("failed to save article: %w", err)
 	}
 
-	// 4. Send confirmation reply to the user
+	// 4. Submissions that require review wait for an admin instead of being forwarded
+	if createdArticle.Status == model.ArticleStatusPending {
+		replyText := fmt.Sprintf("投稿 '%s' 已收到！(ID: %d) 管理员审核通过后会转发到群聊。", createdArticle.Title, createdArticle.ID)
+		if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, replyText); replyErr != nil {
+			logger.Ctx(ctx).Error("Failed to send confirmation reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+		}
+		notifyReviewers(ctx, s.feishuService, s.adminCfg, createdArticle)
+		logger.Ctx(ctx).Info("Submission awaiting review", zap.String("messageID", msgID), zap.Int64("articleID", createdArticle.ID))
+		return nil
+	}
+
+	// 5. Send confirmation reply to the user
 	replyText := fmt.Sprintf("投稿 '%s' 已收到！感谢您的分享！(ID: %d) 正在转发到群聊...", createdArticle.Title, createdArticle.ID)
+	if createdArticle.Anonymous {
+		replyText = fmt.Sprintf("匿名投稿 '%s' 已收到！群聊中不会显示您的身份。(ID: %d) 正在转发到群聊...", createdArticle.Title, createdArticle.ID)
+	}
 	if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, replyText); replyErr != nil {
-		logger.Error("Failed to send confirmation reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+		logger.Ctx(ctx).Error("Failed to send confirmation reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
+	}
+
+	// 6. Build and Forward card to group chat(s)
+	publishArticle(ctx, s.publisher, createdArticle, msgID)
+
+	logger.Ctx(ctx).Info("Submission handled successfully", zap.String("messageID", msgID), zap.String("title", title))
+	return nil
+}
+
+// duplicateReplyText warns the author that the submission duplicates an existing article.
+func duplicateReplyText(duplicate *model.Article, serverCfg *config.ServerConfig) string {
+	earlier := fmt.Sprintf("'%s' (ID: %d，%s)", duplicate.Title, duplicate.ID, duplicate.CreatedAt.Format("01-02 15:04"))
+	if link := serverCfg.ArticleURL(duplicate.TenantKey, duplicate.ID); link != "" {
+		earlier += " " + link
+	}
+	return fmt.Sprintf("这篇投稿和之前的投稿 %s 内容相同或高度相似，本次未转发。\n如确认不是重复内容，请在投稿中加一行「重复: 忽略」后重新发送。", earlier)
+}
+
+// releaseQuota gives back the submission taken by acquireQuota when the article was not saved.
+func (s *SubmissionHandlerStrategy) releaseQuota(ctx context.Context, senderID string, acquired bool) {
+	if !acquired {
+		return
+	}
+	if err := s.rateLimiter.Release(ctx, senderID); err != nil {
+		logger.Ctx(ctx).Warn("Failed to release submission quota", zap.String("senderOpenID", senderID), zap.Error(err))
 	}
+}
 
-	// 5. Build and Forward card to group chat(s)
-	cardContent, err := s.buildForwardingCard(rawContent)
+// acquireQuota consumes one submission from the sender's quota. proceed is false when the quota is exhausted and the
+// user has been told so; acquired is false when the counter backend failed and the submission is let through.
+func (s *SubmissionHandlerStrategy) acquireQuota(ctx context.Context, senderID, msgID string) (acquired, proceed bool) {
+	quota, err := s.rateLimiter.Acquire(ctx, senderID)
 	if err != nil {
-		logger.Error("Failed to build forwarding card content", zap.String("messageID", msgID), zap.Error(err))
-		// Don't return error here, submission is saved, just forwarding failed.
-	} else {
-		// Send to all configured group chats
-		if len(s.cfg.GroupChats) == 0 {
-			logger.Warn("No group chats configured for forwarding", zap.String("messageID", msgID))
-		} else {
-			for _, groupID := range s.cfg.GroupChats {
-				if _, sendErr := s.feishuService.SendCardMessage(ctx, groupID, cardContent); sendErr != nil {
-					logger.Error("Failed to forward card to group chat",
-						zap.String("messageID", msgID),
-						zap.String("groupID", groupID),
-						zap.Error(sendErr),
-					)
-				} else {
-					logger.Info("Successfully forwarded card to group chat",
-						zap.String("messageID", msgID),
-						zap.String("groupID", groupID),
-					)
-				}
-			}
+		// Fail open: a counter backend outage should not block submissions
+		logger.Ctx(ctx).Warn("Submission rate limit acquire failed, allowing submission", zap.String("senderOpenID", senderID), zap.Error(err))
+		return false, true
+	}
+	if !quota.Allowed {
+		if _, replyErr := s.feishuService.ReplyTextMessage(ctx, msgID, rateLimitReplyText(quota)); replyErr != nil {
+			logger.Ctx(ctx).Error("Failed to send rate limit reply to user", zap.String("messageID", msgID), zap.Error(replyErr))
 		}
+		return false, false
 	}
+	return true, true
+}
 
-	logger.Info("Submission handled successfully", zap.String("messageID", msgID), zap.String("title", title))
-	return nil
+// rateLimitReplyText is the friendly reply sent to a user who exceeded the submission quota.
+func rateLimitReplyText(quota *service.RateLimitResult) string {
+	window := "每小时"
+	if quota.Window >= 24*time.Hour {
+		window = "每天"
+	}
+	return fmt.Sprintf("您投稿太频繁啦～%s最多投稿 %d 篇，请在 %s 之后再试，感谢您的热情！",
+		window, quota.Limit, quota.ResetAt.Format("01-02 15:04"))
+}
+
+// resolveAuthorName looks up the display name of a user in the user directory, falling back to the open_id if the lookup fails.
+func resolveAuthorName(ctx context.Context, userDirectory service.UserDirectoryService, openID string) string {
+	user, err := userDirectory.GetUser(ctx, openID)
+	if err != nil {
+		// Log the error but continue with openID as author name
+		logger.Ctx(ctx).Warn("Failed to resolve user from directory, using OpenID as author name",
+			zap.String("senderOpenID", openID),
+			zap.Error(err),
+		)
+		return openID
+	}
+	if user != nil && user.Name != "" {
+		logger.Ctx(ctx).Debug("Resolved author name", zap.String("senderOpenID", openID), zap.String("authorName", user.Name))
+		return user.Name
+	}
+	logger.Ctx(ctx).Warn("Resolved user has no name, using OpenID as author name", zap.String("senderOpenID", openID))
+	return openID
+}
+
+// articleByline returns the lark_md footer shown on the forwarded card. Anonymous submissions never reveal the author.
+// Co-authors and sources are rendered as real mentions so that credited members get notified; members from other
+// sources such as Telegram cannot be mentioned in Feishu and are rendered by name.
+func articleByline(article *model.Article) string {
+	var parts []string
+	switch {
+	case article.Anonymous:
+		parts = append(parts, "匿名投稿")
+	case article.CuratorID != "":
+		parts = append(parts, fmt.Sprintf("作者：%s · 群友推荐收录", article.AuthorName))
+	case article.Source == model.ArticleSourceTelegram:
+		parts = append(parts, fmt.Sprintf("投稿人：%s · 来自 Telegram", article.AuthorName))
+	default:
+		parts = append(parts, fmt.Sprintf("投稿人：%s", article.AuthorName))
+	}
+
+	roleLabels := []struct{ role, label string }{
+		{model.AuthorRoleCoAuthor, "合著"},
+		{model.AuthorRoleSource, "来源"},
+	}
+	for _, rl := range roleLabels {
+		var mentions []string
+		for _, author := range article.Authors {
+			switch {
+			case author.Role != rl.role:
+			case author.IsExternal():
+				mentions = append(mentions, author.Name)
+			default:
+				mentions = append(mentions, mentionMarkdown(author.OpenID))
+			}
+		}
+		if len(mentions) > 0 {
+			parts = append(parts, fmt.Sprintf("%s：%s", rl.label, strings.Join(mentions, " ")))
+		}
+	}
+	return strings.Join(parts, " · ")
 }
 
 // buildForwardingCard constructs the interactive card content for forwarding.
-func (s *SubmissionHandlerStrategy) buildForwardingCard(rawContent string) (*service.MessageCardContent, error) {
+// The byline is rendered as a note at the bottom of the card.
+func buildForwardingCard(rawContent string, byline string) (*service.MessageCardContent, error) {
 	// Define input structure (can reuse/adapt from parsePostContentForSubmission)
 	type PostElement struct {
 		Tag      string   `json:"tag"`
@@ -165,6 +370,8 @@ func (s *SubmissionHandlerStrategy) buildForwardingCard(rawContent string) (*ser
 		Style    []string `json:"style"`
 		ImageKey string   `json:"image_key"` // For img tags
 		Href     string   `json:"href"`      // For a tags
+		UserID   string   `json:"user_id"`   // For at tags, resolved to open_id by resolvePostMentions
+		UserName string   `json:"user_name"` // For at tags
 	}
 	type PostBody struct {
 		Title   string          `json:"title"`
@@ -272,6 +479,14 @@ func (s *SubmissionHandlerStrategy) buildForwardingCard(rawContent string) (*ser
 			case "a":
 				mdContentBuilder.WriteString(fmt.Sprintf("[%s](%s)", element.Text, element.Href))
 				lineHasContent = true
+			case "at":
+				// Render as a real mention so the member gets notified; unresolved placeholders fall back to the name
+				if strings.HasPrefix(element.UserID, "ou_") || element.UserID == mentionAllID {
+					mdContentBuilder.WriteString(mentionMarkdown(element.UserID))
+				} else {
+					mdContentBuilder.WriteString("@" + element.UserName)
+				}
+				lineHasContent = true
 			}
 		}
 		// Add newline after processing each line that had markdown content
@@ -296,6 +511,13 @@ func (s *SubmissionHandlerStrategy) buildForwardingCard(rawContent string) (*ser
 		})
 	}
 
+	if byline != "" {
+		cardElements = append(cardElements, map[string]interface{}{
+			"tag":      "note",
+			"elements": []map[string]string{{"tag": "lark_md", "content": byline}},
+		})
+	}
+
 	// --- Assemble Final Card ---
 	finalCard := &service.MessageCardContent{
 		Config:   CardConfig{WideScreenMode: true},
@@ -310,9 +532,10 @@ func (s *SubmissionHandlerStrategy) buildForwardingCard(rawContent string) (*ser
 func parsePostContentForSubmission(rawContent string) (title string, textContent string, err error) {
 	// Define structs matching the expected nested structure
 	type PostElement struct {
-		Tag   string   `json:"tag"`
-		Text  string   `json:"text"`
-		Style []string `json:"style"` // Add Style field
+		Tag      string   `json:"tag"`
+		Text     string   `json:"text"`
+		Style    []string `json:"style"`     // Add Style field
+		UserName string   `json:"user_name"` // For at tags
 	}
 	type PostBody struct {
 		Title   string          `json:"title"`   // Keep the top-level title as potential fallback
@@ -345,6 +568,9 @@ func parsePostContentForSubmission(rawContent string) (title string, textContent
 						}
 					}
 				}
+			} else if element.Tag == "at" {
+				// Keep mentions readable in the plain text content
+				currentLineText += "@" + element.UserName
 			}
 			// TODO: Potentially handle other tags like 'a' for links if needed
 		}
@@ -0,0 +1,57 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/model"
+	"testing"
+)
+
+func TestArticleByline(t *testing.T) {
+	tests := []struct {
+		name    string
+		article *model.Article
+		want    string
+	}{
+		{
+			name:    "author only",
+			article: &model.Article{AuthorName: "张三"},
+			want:    "投稿人：张三",
+		},
+		{
+			name: "feishu credits are mentioned",
+			article: &model.Article{AuthorName: "张三", Authors: []*model.ArticleAuthor{
+				{OpenID: "ou_a", Name: "张三", Role: model.AuthorRoleAuthor},
+				{OpenID: "ou_b", Name: "李四", Role: model.AuthorRoleCoAuthor},
+				{OpenID: "ou_c", Name: "王五", Role: model.AuthorRoleSource},
+			}},
+			want: "投稿人：张三 · 合著：<at id=ou_b></at> · 来源：<at id=ou_c></at>",
+		},
+		{
+			name: "telegram credits merged into a feishu article are rendered by name",
+			article: &model.Article{AuthorName: "张三", Authors: []*model.ArticleAuthor{
+				{OpenID: "ou_c", Name: "王五", Role: model.AuthorRoleSource},
+				{OpenID: "tg:user:42", Name: "Alice", Role: model.AuthorRoleSource},
+				{OpenID: "tg:channel:7", Name: "Tech News", Role: model.AuthorRoleSource},
+			}},
+			want: "投稿人：张三 · 来源：<at id=ou_c></at> Alice Tech News",
+		},
+		{
+			name:    "telegram article",
+			article: &model.Article{AuthorName: "Alice", Source: model.ArticleSourceTelegram},
+			want:    "投稿人：Alice · 来自 Telegram",
+		},
+		{
+			name: "anonymous",
+			article: &model.Article{Anonymous: true, AuthorName: model.AnonymousAuthorName, Authors: []*model.ArticleAuthor{
+				{OpenID: "ou_b", Name: "李四", Role: model.AuthorRoleCoAuthor},
+			}},
+			want: "匿名投稿 · 合著：<at id=ou_b></at>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := articleByline(tt.article); got != tt.want {
+				t.Errorf("articleByline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/model"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// submissionOptions 是投稿中以 "键: 值" 独占一行声明的选项，例如 "匿名: 是"、"合著: @张三 @李四"
+type submissionOptions struct {
+	Anonymous      bool                   // 匿名投稿
+	Credits        []*model.ArticleAuthor // 通过 "合著"、"来源" 署名的成员
+	AllowDuplicate bool                   // 通过 "重复: 忽略" 确认不是重复内容，跳过查重
+}
+
+// optionLinePattern 匹配选项行，兼容中英文冒号
+var optionLinePattern = regexp.MustCompile(`^\s*([^:：\s]+)\s*[:：]\s*(.*?)\s*$`)
+
+// applyOption 将一行选项写入 opts，返回该行是否为已知选项。
+// mentions 为该行中 @ 的成员，仅署名类选项使用。
+func (opts *submissionOptions) applyOption(key, value string, mentions []postMention) bool {
+	switch strings.ToLower(key) {
+	case "匿名", "anonymous":
+		opts.Anonymous = isAffirmative(value)
+		return true
+	case "重复", "duplicate":
+		opts.AllowDuplicate = isAffirmative(value) || strings.TrimSpace(value) == "忽略" || strings.EqualFold(strings.TrimSpace(value), "ignore")
+		return true
+	case "合著", "合著者", "co-author":
+		return opts.addCredits(mentions, model.AuthorRoleCoAuthor)
+	case "来源", "source":
+		return opts.addCredits(mentions, model.AuthorRoleSource)
+	}
+	return false
+}
+
+// addCredits 记录署名成员。没有 @ 任何成员时（如 "来源: 某网站"）不视为选项，保留在正文中。
+// @所有人、未能解析的占位符以及同一角色下重复 @ 的成员会被忽略。
+func (opts *submissionOptions) addCredits(mentions []postMention, role string) bool {
+	if len(mentions) == 0 {
+		return false
+	}
+	for _, m := range mentions {
+		if !strings.HasPrefix(m.OpenID, "ou_") || opts.hasCredit(m.OpenID, role) {
+			continue
+		}
+		opts.Credits = append(opts.Credits, &model.ArticleAuthor{OpenID: m.OpenID, Name: m.Name, Role: role})
+	}
+	return true
+}
+
+// hasCredit 判断 openID 是否已以 role 署名
+func (opts *submissionOptions) hasCredit(openID, role string) bool {
+	for _, credit := range opts.Credits {
+		if credit.OpenID == openID && credit.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isAffirmative 判断选项值是否表示“是”
+func isAffirmative(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "是", "yes", "y", "true", "1":
+		return true
+	}
+	return false
+}
+
+// extractSubmissionOptions 从富文本内容中提取选项行，并返回去除这些行之后的富文本 JSON。
+// 只有整行由文本和 @ 组成、以文本开头且键为已知选项的行才会被识别，其余内容保持原样。
+// 调用前应先通过 resolvePostMentions 将 @ 占位符替换为真实 OpenID。
+func extractSubmissionOptions(rawContent string) (string, *submissionOptions, error) {
+	var post map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(rawContent), &post); err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal post content: %w", err)
+	}
+
+	var lines [][]map[string]interface{}
+	if raw, ok := post["content"]; ok {
+		if err := json.Unmarshal(raw, &lines); err != nil {
+			return "", nil, fmt.Errorf("failed to unmarshal post lines: %w", err)
+		}
+	}
+
+	opts := &submissionOptions{}
+	kept := make([][]map[string]interface{}, 0, len(lines))
+	for _, line := range lines {
+		if text, mentions, ok := optionLineText(line); ok {
+			if m := optionLinePattern.FindStringSubmatch(text); m != nil && opts.applyOption(m[1], m[2], mentions) {
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+
+	if len(kept) == len(lines) {
+		return rawContent, opts, nil
+	}
+
+	content, err := json.Marshal(kept)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal post lines: %w", err)
+	}
+	post["content"] = content
+	cleaned, err := json.Marshal(post)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal post content: %w", err)
+	}
+	return string(cleaned), opts, nil
+}
+
+// optionLineText 在一行以文本开头且仅由 text、at 元素组成时，返回拼接后的文本及其中 @ 的成员
+func optionLineText(line []map[string]interface{}) (string, []postMention, bool) {
+	if len(line) == 0 || line[0]["tag"] != "text" {
+		return "", nil, false
+	}
+	var b strings.Builder
+	var mentions []postMention
+	for _, element := range line {
+		switch element["tag"] {
+		case "text":
+			text, _ := element["text"].(string)
+			b.WriteString(text)
+		case "at":
+			openID, _ := element["user_id"].(string)
+			name, _ := element["user_name"].(string)
+			mentions = append(mentions, postMention{OpenID: openID, Name: name})
+			b.WriteString("@" + name)
+		default:
+			return "", nil, false
+		}
+	}
+	return b.String(), mentions, true
+}
@@ -0,0 +1,115 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/model"
+	"testing"
+)
+
+func TestExtractSubmissionOptions(t *testing.T) {
+	type credit struct{ openID, role string }
+	body := []map[string]interface{}{textElem("正文")}
+	tests := []struct {
+		name          string
+		lines         [][]map[string]interface{}
+		wantAnonymous bool
+		wantDuplicate bool
+		wantCredits   []credit
+		wantLines     int
+	}{
+		{
+			name:        "co-author role",
+			lines:       [][]map[string]interface{}{body, {textElem("合著: "), atElem("ou_b", "李四"), textElem(" "), atElem("ou_c", "王五")}},
+			wantCredits: []credit{{"ou_b", model.AuthorRoleCoAuthor}, {"ou_c", model.AuthorRoleCoAuthor}},
+			wantLines:   1,
+		},
+		{
+			name:        "source role with a full-width colon",
+			lines:       [][]map[string]interface{}{body, {textElem("来源："), atElem("ou_c", "王五")}},
+			wantCredits: []credit{{"ou_c", model.AuthorRoleSource}},
+			wantLines:   1,
+		},
+		{
+			name:        "same member as co-author and source",
+			lines:       [][]map[string]interface{}{body, {textElem("co-author: "), atElem("ou_b", "李四")}, {textElem("source: "), atElem("ou_b", "李四")}},
+			wantCredits: []credit{{"ou_b", model.AuthorRoleCoAuthor}, {"ou_b", model.AuthorRoleSource}},
+			wantLines:   1,
+		},
+		{
+			name:        "duplicate mentions are credited once",
+			lines:       [][]map[string]interface{}{body, {textElem("合著: "), atElem("ou_b", "李四"), atElem("ou_b", "李四")}, {textElem("合著者: "), atElem("ou_b", "李四")}},
+			wantCredits: []credit{{"ou_b", model.AuthorRoleCoAuthor}},
+			wantLines:   1,
+		},
+		{
+			// 作者本人的合著署名在 buildArticleAuthors 中去除，这里按原样保留
+			name:        "self mention is passed through",
+			lines:       [][]map[string]interface{}{body, {textElem("合著: "), atElem("ou_a", "张三")}},
+			wantCredits: []credit{{"ou_a", model.AuthorRoleCoAuthor}},
+			wantLines:   1,
+		},
+		{
+			name:        "bot mention is credited like any member",
+			lines:       [][]map[string]interface{}{body, {textElem("来源: "), atElem(testBotOpenID, "Miko")}},
+			wantCredits: []credit{{testBotOpenID, model.AuthorRoleSource}},
+			wantLines:   1,
+		},
+		{
+			name:      "mention all and unresolved placeholders are skipped",
+			lines:     [][]map[string]interface{}{body, {textElem("合著: "), atElem(mentionAllID, "所有人"), atElem("@_user_9", "")}},
+			wantLines: 1,
+		},
+		{
+			name:      "source without a mention stays in the body",
+			lines:     [][]map[string]interface{}{body, {textElem("来源: 某网站")}},
+			wantLines: 2,
+		},
+		{
+			name:      "option line must start with text",
+			lines:     [][]map[string]interface{}{body, {atElem("ou_b", "李四"), textElem(" 合著: 是")}},
+			wantLines: 2,
+		},
+		{
+			name:          "anonymous and duplicate flags",
+			lines:         [][]map[string]interface{}{{textElem("匿名: 是")}, body, {textElem("重复：忽略")}},
+			wantAnonymous: true,
+			wantDuplicate: true,
+			wantLines:     1,
+		},
+		{
+			name:      "negative anonymous is consumed but not set",
+			lines:     [][]map[string]interface{}{body, {textElem("anonymous: no")}},
+			wantLines: 1,
+		},
+		{
+			name:      "unknown key stays in the body",
+			lines:     [][]map[string]interface{}{body, {textElem("备注: 无")}},
+			wantLines: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := postJSON(t, tt.lines...)
+			cleaned, opts, err := extractSubmissionOptions(raw)
+			if err != nil {
+				t.Fatalf("extractSubmissionOptions() error = %v", err)
+			}
+			if opts.Anonymous != tt.wantAnonymous || opts.AllowDuplicate != tt.wantDuplicate {
+				t.Errorf("Anonymous = %v, AllowDuplicate = %v; want %v, %v", opts.Anonymous, opts.AllowDuplicate, tt.wantAnonymous, tt.wantDuplicate)
+			}
+			if len(opts.Credits) != len(tt.wantCredits) {
+				t.Fatalf("credits = %d, want %d", len(opts.Credits), len(tt.wantCredits))
+			}
+			for i, want := range tt.wantCredits {
+				if got := opts.Credits[i]; got.OpenID != want.openID || got.Role != want.role {
+					t.Errorf("credit[%d] = %s/%s, want %s/%s", i, got.OpenID, got.Role, want.openID, want.role)
+				}
+			}
+			if tt.wantLines == len(tt.lines) && cleaned != raw {
+				t.Errorf("content without options = %q, want it unchanged", cleaned)
+			}
+			if got := len(postLines(t, cleaned)); got != tt.wantLines {
+				t.Errorf("kept lines = %d, want %d", got, tt.wantLines)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package messagehandler
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// publishArticle posts a published article to every outbound destination.
+// Failures are only logged: the article is saved and the submitter has been answered already.
+func publishArticle(ctx context.Context, publisher service.Publisher, article *model.Article, sourceMsgID string) {
+	if err := publisher.Publish(ctx, article); err != nil {
+		logger.Ctx(ctx).Error("Failed to publish article",
+			zap.Int64("articleID", article.ID),
+			zap.String("messageID", sourceMsgID),
+			zap.String("publisher", publisher.Name()),
+			zap.Error(err),
+		)
+	}
+}
+
+// notifyReviewers tells every admin that a submission is waiting for review.
+func notifyReviewers(ctx context.Context, feishuService service.FeishuMessageService, adminCfg *config.AdminConfig, article *model.Article) {
+	text := fmt.Sprintf("新投稿待审核：'%s'，作者：%s (ID: %d)\n发送 \"%s %d\" 通过，或 \"%s %d\" 驳回。",
+		article.Title, article.AuthorName, article.ID,
+		approveCommand, article.ID, rejectCommand, article.ID)
+	for _, adminID := range adminCfg.OpenIDs {
+		if _, err := feishuService.SendTextMessageToUser(ctx, adminID, text); err != nil {
+			logger.Ctx(ctx).Error("Failed to notify reviewer", zap.String("adminOpenID", adminID), zap.Int64("articleID", article.ID), zap.Error(err))
+		}
+	}
+	if len(adminCfg.OpenIDs) == 0 {
+		logger.Ctx(ctx).Warn("Submission requires review but no admins are configured", zap.Int64("articleID", article.ID))
+	}
+}
+
+// ArchiveResultText is the reply sent after a message was archived on behalf of its sender.
+func ArchiveResultText(result *service.ArchiveResult) string {
+	article := result.Article
+	if result.AlreadySaved {
+		return fmt.Sprintf("该消息已收录（'%s'，ID: %d），无需重复收录。", article.Title, article.ID)
+	}
+	if result.Merged {
+		return fmt.Sprintf("该内容已收录过（'%s'，ID: %d），已将原发送者署名为来源，感谢推荐！", article.Title, article.ID)
+	}
+	if article.Status == model.ArticleStatusPending {
+		return fmt.Sprintf("已收录 '%s'，作者：%s (ID: %d)，等待管理员审核后转发，感谢推荐！", article.Title, article.AuthorName, article.ID)
+	}
+	return fmt.Sprintf("已收录 '%s'，作者：%s (ID: %d)，感谢推荐！", article.Title, article.AuthorName, article.ID)
+}
@@ -0,0 +1,37 @@
+package publisher
+
+import (
+	"MikoNews/internal/model"
+	"fmt"
+	"strings"
+)
+
+// articleByline returns the plain-text footer shown below cross-posted articles. Anonymous submissions never reveal the author.
+func articleByline(article *model.Article) string {
+	var parts []string
+	switch {
+	case article.Anonymous:
+		parts = append(parts, "匿名投稿")
+	case article.CuratorID != "":
+		parts = append(parts, fmt.Sprintf("作者：%s · 群友推荐收录", article.AuthorName))
+	default:
+		parts = append(parts, fmt.Sprintf("投稿人：%s", article.AuthorName))
+	}
+
+	roleLabels := []struct{ role, label string }{
+		{model.AuthorRoleCoAuthor, "合著"},
+		{model.AuthorRoleSource, "来源"},
+	}
+	for _, rl := range roleLabels {
+		var names []string
+		for _, author := range article.Authors {
+			if author.Role == rl.role && author.Name != "" {
+				names = append(names, author.Name)
+			}
+		}
+		if len(names) > 0 {
+			parts = append(parts, fmt.Sprintf("%s：%s", rl.label, strings.Join(names, "、")))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,163 @@
+package publisher
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	larkwiki "github.com/larksuite/oapi-sdk-go/v3/service/wiki/v2"
+	"go.uber.org/zap"
+)
+
+// DocTokenRecorder stores the document or wiki node an article was archived to, e.g. the article service.
+type DocTokenRecorder interface {
+	RecordDocToken(ctx context.Context, id int64, docToken string) error
+}
+
+// feishuDocPublisher archives published articles to Feishu Docs or a Feishu Wiki space.
+type feishuDocPublisher struct {
+	conf     *config.FeishuDocsConfig
+	docs     service.FeishuDocService
+	images   ImageDownloader
+	recorder DocTokenRecorder
+	now      func() time.Time
+
+	mu         sync.Mutex                // serializes appends so articles keep their order in the monthly node
+	monthNodes map[string]*larkwiki.Node // wiki nodes by title, to avoid looking them up for every article
+}
+
+var _ service.Publisher = (*feishuDocPublisher)(nil)
+
+// NewFeishuDocPublisher creates a publisher archiving articles according to conf.Mode:
+// one docx document per article, or appended to a wiki node per month.
+// The resulting document_id (docx) or node_token (wiki) is stored on the article through recorder.
+func NewFeishuDocPublisher(conf *config.FeishuDocsConfig, docs service.FeishuDocService, images ImageDownloader, recorder DocTokenRecorder) service.Publisher {
+	return &feishuDocPublisher{
+		conf:       conf,
+		docs:       docs,
+		images:     images,
+		recorder:   recorder,
+		now:        time.Now,
+		monthNodes: make(map[string]*larkwiki.Node),
+	}
+}
+
+// Name identifies the publisher in logs.
+func (p *feishuDocPublisher) Name() string {
+	return "feishu_" + p.conf.Mode
+}
+
+// Publish writes the article to a new document or to the wiki node of the current month.
+// Articles that were already archived are skipped, so publishing again does not duplicate them.
+func (p *feishuDocPublisher) Publish(ctx context.Context, article *model.Article) error {
+	if article.DocToken != "" {
+		logger.Ctx(ctx).Debug("Article already archived to Feishu Docs", zap.Int64("articleID", article.ID), zap.String("docToken", article.DocToken))
+		return nil
+	}
+
+	body, images, err := renderDocxBlocks(article.RawContent)
+	if err != nil {
+		return err
+	}
+	byline := textBlock(docxBlockText, []*larkdocx.TextElement{textRun(articleByline(article), []string{"italic"}, "")})
+
+	var documentID, docToken string
+	var blocks []*larkdocx.Block
+	offset := 0 // index of the first body block, to locate the image blocks once created
+	switch p.conf.Mode {
+	case config.DocsModeWiki:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		node, err := p.monthNode(ctx)
+		if err != nil {
+			return err
+		}
+		documentID, docToken = *node.ObjToken, *node.NodeToken
+		heading := textBlock(docxBlockHeading1+1, []*larkdocx.TextElement{textRun(documentTitle(article), nil, "")})
+		blocks = append(append([]*larkdocx.Block{heading}, body...), byline, dividerBlock())
+		offset = 1
+	default:
+		documentID, err = p.docs.CreateDocument(ctx, p.conf.FolderToken, documentTitle(article))
+		if err != nil {
+			return err
+		}
+		docToken = documentID
+		blocks = append(body, byline)
+	}
+
+	created, err := p.docs.AppendBlocks(ctx, documentID, blocks)
+	if err != nil {
+		return err
+	}
+	p.fillImages(ctx, article.ID, documentID, created, images, offset)
+
+	if err := p.recorder.RecordDocToken(ctx, article.ID, docToken); err != nil {
+		return err
+	}
+	article.DocToken = docToken
+	logger.Ctx(ctx).Info("Article archived to Feishu Docs",
+		zap.Int64("articleID", article.ID),
+		zap.String("mode", p.conf.Mode),
+		zap.String("docToken", docToken),
+	)
+	return nil
+}
+
+// monthNode returns the wiki node of the current month, creating it on first use. Callers must hold p.mu.
+func (p *feishuDocPublisher) monthNode(ctx context.Context) (*larkwiki.Node, error) {
+	title := p.now().Format("2006-01") + " 投稿"
+	if node, ok := p.monthNodes[title]; ok {
+		return node, nil
+	}
+
+	node, err := p.docs.FindWikiNode(ctx, p.conf.WikiSpaceID, p.conf.WikiParentNode, title)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		node, err = p.docs.CreateWikiNode(ctx, p.conf.WikiSpaceID, p.conf.WikiParentNode, title)
+		if err != nil {
+			return nil, err
+		}
+		logger.Ctx(ctx).Info("Created monthly wiki node", zap.String("title", title), zap.String("nodeToken", *node.NodeToken))
+	}
+	if node.ObjToken == nil || node.NodeToken == nil {
+		return nil, fmt.Errorf("知识库节点缺少 token: %s", title)
+	}
+	p.monthNodes[title] = node
+	return node, nil
+}
+
+// fillImages downloads the article images and uploads them into the created image blocks.
+// A failed image leaves an empty block behind but does not fail the article.
+func (p *feishuDocPublisher) fillImages(ctx context.Context, articleID int64, documentID string, created []*larkdocx.Block, images []docxImage, offset int) {
+	for _, image := range images {
+		index := offset + image.index
+		if index >= len(created) || created[index].BlockId == nil {
+			logger.Ctx(ctx).Warn("Image block missing from created blocks", zap.Int64("articleID", articleID), zap.Int("index", index))
+			continue
+		}
+		data, err := p.images.DownloadImage(ctx, image.imageKey)
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to download image for Feishu Docs", zap.Int64("articleID", articleID), zap.String("imageKey", image.imageKey), zap.Error(err))
+			continue
+		}
+		if err := p.docs.ReplaceImage(ctx, documentID, *created[index].BlockId, data); err != nil {
+			logger.Ctx(ctx).Error("Failed to upload image to Feishu Docs", zap.Int64("articleID", articleID), zap.String("imageKey", image.imageKey), zap.Error(err))
+		}
+	}
+}
+
+// documentTitle returns the title of the archived article, falling back to its ID for untitled posts.
+func documentTitle(article *model.Article) string {
+	if article.Title != "" {
+		return article.Title
+	}
+	return fmt.Sprintf("投稿 #%d", article.ID)
+}
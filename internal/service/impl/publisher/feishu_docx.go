@@ -0,0 +1,213 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+)
+
+// Block types of the Feishu docx API.
+const (
+	docxBlockText     = 2
+	docxBlockHeading1 = 3
+	docxBlockCode     = 14
+	docxBlockDivider  = 22
+	docxBlockImage    = 27
+)
+
+// docxImage is an image block waiting for its content, identified by its index in the rendered blocks.
+type docxImage struct {
+	index    int
+	imageKey string
+}
+
+// docxBlocks accumulates the blocks of a document while the post content is converted.
+type docxBlocks struct {
+	blocks []*larkdocx.Block
+	images []docxImage
+	runs   []*larkdocx.TextElement // text runs of the current paragraph
+}
+
+// renderDocxBlocks converts the post content of an article into docx blocks.
+// Each post line becomes a paragraph; images, code blocks and markdown headings become blocks of their own.
+// Image blocks are created empty and must be filled with ReplaceImage once their block IDs are known.
+func renderDocxBlocks(rawContent string) ([]*larkdocx.Block, []docxImage, error) {
+	var post postBody
+	if err := json.Unmarshal([]byte(rawContent), &post); err != nil {
+		return nil, nil, fmt.Errorf("解析文章内容失败: %w", err)
+	}
+
+	var d docxBlocks
+	for _, line := range post.Content {
+		for _, element := range line {
+			switch element.Tag {
+			case "text":
+				d.addRun(element.Text, element.Style, "")
+			case "a":
+				text := element.Text
+				if text == "" {
+					text = element.Href
+				}
+				d.addRun(text, element.Style, element.Href)
+			case "at":
+				d.addRun("@"+element.UserName, nil, "")
+			case "md":
+				d.addMarkdown(element.Text)
+			case "code_block":
+				d.flush()
+				d.addTextBlock(docxBlockCode, []*larkdocx.TextElement{textRun(strings.TrimRight(element.Text, "\n"), nil, "")})
+			case "hr":
+				d.flush()
+				d.blocks = append(d.blocks, dividerBlock())
+			case "img":
+				d.flush()
+				d.images = append(d.images, docxImage{index: len(d.blocks), imageKey: element.ImageKey})
+				d.blocks = append(d.blocks, larkdocx.NewBlockBuilder().
+					BlockType(docxBlockImage).
+					Image(larkdocx.NewImageBuilder().Build()).
+					Build())
+			}
+		}
+		d.flush()
+	}
+	return d.blocks, d.images, nil
+}
+
+// addRun appends a text run to the current paragraph.
+func (d *docxBlocks) addRun(text string, styles []string, href string) {
+	if text == "" {
+		return
+	}
+	d.runs = append(d.runs, textRun(text, styles, href))
+}
+
+// flush ends the current paragraph.
+func (d *docxBlocks) flush() {
+	if len(d.runs) == 0 {
+		return
+	}
+	d.addTextBlock(docxBlockText, d.runs)
+	d.runs = nil
+}
+
+// addTextBlock appends a text-like block (text, heading or code) with the given runs.
+func (d *docxBlocks) addTextBlock(blockType int, runs []*larkdocx.TextElement) {
+	d.blocks = append(d.blocks, textBlock(blockType, runs))
+}
+
+// markdownHeading matches a markdown heading line.
+var markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+
+// markdownInline matches the inline markdown supported by Feishu (lark_md): code, links, bold, strikethrough and italic.
+var markdownInline = regexp.MustCompile("`([^`\n]+)`" + `|\[([^\]\n]+)\]\(([^)\s]+)\)|\*\*([^*\n]+)\*\*|~~([^~\n]+)~~|\*([^*\n]+)\*`)
+
+// addMarkdown converts Feishu markdown into paragraphs and headings.
+func (d *docxBlocks) addMarkdown(markdown string) {
+	for i, line := range strings.Split(markdown, "\n") {
+		if i > 0 {
+			d.flush()
+		}
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			d.flush()
+			d.addTextBlock(docxBlockHeading1+len(m[1])-1, markdownRuns(m[2]))
+			continue
+		}
+		d.runs = append(d.runs, markdownRuns(line)...)
+	}
+}
+
+// markdownRuns converts a line of inline markdown into styled text runs.
+func markdownRuns(line string) []*larkdocx.TextElement {
+	var runs []*larkdocx.TextElement
+	plain := func(text string) {
+		if text != "" {
+			runs = append(runs, textRun(text, nil, ""))
+		}
+	}
+	last := 0
+	for _, m := range markdownInline.FindAllStringSubmatchIndex(line, -1) {
+		plain(line[last:m[0]])
+		last = m[1]
+		group := func(n int) string { return line[m[2*n]:m[2*n+1]] }
+		switch {
+		case m[2] >= 0:
+			runs = append(runs, textRun(group(1), []string{"inlineCode"}, ""))
+		case m[4] >= 0:
+			runs = append(runs, textRun(group(2), nil, group(3)))
+		case m[8] >= 0:
+			runs = append(runs, textRun(group(4), []string{"bold"}, ""))
+		case m[10] >= 0:
+			runs = append(runs, textRun(group(5), []string{"lineThrough"}, ""))
+		case m[12] >= 0:
+			runs = append(runs, textRun(group(6), []string{"italic"}, ""))
+		}
+	}
+	plain(line[last:])
+	return runs
+}
+
+// textRun builds a text run with the Feishu post styles and an optional link.
+func textRun(text string, styles []string, href string) *larkdocx.TextElement {
+	style := larkdocx.NewTextElementStyleBuilder()
+	for _, s := range styles {
+		switch s {
+		case "bold":
+			style.Bold(true)
+		case "italic":
+			style.Italic(true)
+		case "underline":
+			style.Underline(true)
+		case "lineThrough", "strikethrough":
+			style.Strikethrough(true)
+		case "inlineCode":
+			style.InlineCode(true)
+		}
+	}
+	if href != "" {
+		// the docx API expects the link URL to be URL-encoded
+		style.Link(larkdocx.NewLinkBuilder().Url(url.QueryEscape(href)).Build())
+	}
+	return larkdocx.NewTextElementBuilder().
+		TextRun(larkdocx.NewTextRunBuilder().
+			Content(text).
+			TextElementStyle(style.Build()).
+			Build()).
+		Build()
+}
+
+// textBlock builds a text, heading or code block.
+func textBlock(blockType int, runs []*larkdocx.TextElement) *larkdocx.Block {
+	text := larkdocx.NewTextBuilder().Elements(runs).Build()
+	block := larkdocx.NewBlockBuilder().BlockType(blockType).Build()
+	switch blockType {
+	case docxBlockText:
+		block.Text = text
+	case docxBlockCode:
+		block.Code = text
+	case docxBlockHeading1:
+		block.Heading1 = text
+	case docxBlockHeading1 + 1:
+		block.Heading2 = text
+	case docxBlockHeading1 + 2:
+		block.Heading3 = text
+	case docxBlockHeading1 + 3:
+		block.Heading4 = text
+	case docxBlockHeading1 + 4:
+		block.Heading5 = text
+	default:
+		block.Heading6 = text
+	}
+	return block
+}
+
+// dividerBlock builds a horizontal rule.
+func dividerBlock() *larkdocx.Block {
+	return larkdocx.NewBlockBuilder().
+		BlockType(docxBlockDivider).
+		Divider(larkdocx.NewDividerBuilder().Build()).
+		Build()
+}
@@ -0,0 +1,85 @@
+package publisher
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+)
+
+// describeBlock summarizes a block as its type followed by its runs, e.g. "2:plain|b:粗".
+func describeBlock(block *larkdocx.Block) string {
+	text := block.Text
+	for _, t := range []*larkdocx.Text{block.Heading1, block.Heading2, block.Heading3, block.Code} {
+		if t != nil {
+			text = t
+		}
+	}
+	desc := fmt.Sprintf("%02d", *block.BlockType)
+	if text == nil {
+		return desc
+	}
+	for _, element := range text.Elements {
+		run := element.TextRun
+		style := run.TextElementStyle
+		desc += "|"
+		if style.Bold != nil {
+			desc += "b:"
+		}
+		if style.Italic != nil {
+			desc += "i:"
+		}
+		if style.InlineCode != nil {
+			desc += "c:"
+		}
+		if style.Link != nil {
+			desc += "a(" + *style.Link.Url + "):"
+		}
+		desc += *run.Content
+	}
+	return desc
+}
+
+func TestRenderDocxBlocks(t *testing.T) {
+	raw := `{"title":"","content":[
+		[{"tag":"text","text":"正文 "},{"tag":"text","text":"加粗","style":["bold"]}],
+		[{"tag":"a","text":"链接","href":"https://example.com/?a=1"},{"tag":"at","user_id":"ou_1","user_name":"李四"}],
+		[{"tag":"text","text":"图前"},{"tag":"img","image_key":"img_1"},{"tag":"text","text":"图后"}],
+		[{"tag":"md","text":"## 小标题\n*斜* ` + "`code`" + `"}],
+		[{"tag":"code_block","language":"go","text":"fmt.Println()\n"}],
+		[{"tag":"hr"}]
+	]}`
+
+	blocks, images, err := renderDocxBlocks(raw)
+	if err != nil {
+		t.Fatalf("renderDocxBlocks() error = %v", err)
+	}
+	var got []string
+	for _, block := range blocks {
+		got = append(got, describeBlock(block))
+	}
+	want := []string{
+		"02|正文 |b:加粗",
+		"02|a(https%3A%2F%2Fexample.com%2F%3Fa%3D1):链接|@李四",
+		"02|图前",
+		"27",
+		"02|图后",
+		"04|小标题",
+		"02|i:斜| |c:code",
+		"14|fmt.Println()",
+		"22",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderDocxBlocks() =\n%q\nwant\n%q", got, want)
+	}
+	if !reflect.DeepEqual(images, []docxImage{{index: 3, imageKey: "img_1"}}) {
+		t.Errorf("images = %+v, want img_1 at index 3", images)
+	}
+}
+
+func TestRenderDocxBlocksInvalidContent(t *testing.T) {
+	if _, _, err := renderDocxBlocks("not json"); err == nil {
+		t.Error("renderDocxBlocks() error = nil, want an error for invalid content")
+	}
+}
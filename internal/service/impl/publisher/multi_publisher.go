@@ -0,0 +1,46 @@
+package publisher
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// multiPublisher publishes every article to all destinations in order.
+type multiPublisher struct {
+	publishers []service.Publisher
+}
+
+var _ service.Publisher = (*multiPublisher)(nil)
+
+// NewMultiPublisher creates a publisher fanning out to all given publishers.
+// A failing destination does not keep the article from reaching the others.
+func NewMultiPublisher(publishers ...service.Publisher) service.Publisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+// Name identifies the publisher in logs.
+func (p *multiPublisher) Name() string {
+	return "multi"
+}
+
+// Publish posts the article to every destination and joins their errors.
+func (p *multiPublisher) Publish(ctx context.Context, article *model.Article) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, article); err != nil {
+			logger.Ctx(ctx).Error("Publisher failed",
+				zap.String("publisher", publisher.Name()),
+				zap.Int64("articleID", article.ID),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", publisher.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,130 @@
+package publisher
+
+import (
+	"MikoNews/internal/model"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// postElement is an element of the Feishu post content stored with articles.
+type postElement struct {
+	Tag      string   `json:"tag"`
+	Text     string   `json:"text"`
+	Style    []string `json:"style"`
+	Href     string   `json:"href"`
+	ImageKey string   `json:"image_key"`
+	UserName string   `json:"user_name"`
+	Language string   `json:"language"`
+}
+
+// postBody is the Feishu post content stored with articles.
+type postBody struct {
+	Title   string          `json:"title"`
+	Content [][]postElement `json:"content"`
+}
+
+// telegramStyleTags maps Feishu text styles to Telegram HTML tags.
+var telegramStyleTags = map[string]string{
+	"bold":          "b",
+	"italic":        "i",
+	"underline":     "u",
+	"lineThrough":   "s",
+	"strikethrough": "s",
+}
+
+// renderTelegramHTML converts the post content of an article into Telegram HTML and collects its image keys.
+// Only the tags supported by Telegram (b, i, u, s, a, code, pre) are produced; all text is escaped.
+func renderTelegramHTML(rawContent string) (string, []string, error) {
+	var post postBody
+	if err := json.Unmarshal([]byte(rawContent), &post); err != nil {
+		return "", nil, fmt.Errorf("解析文章内容失败: %w", err)
+	}
+
+	var lines []string
+	var imageKeys []string
+	for _, line := range post.Content {
+		var b strings.Builder
+		for _, element := range line {
+			switch element.Tag {
+			case "text":
+				b.WriteString(styled(html.EscapeString(element.Text), element.Style))
+			case "a":
+				text := element.Text
+				if text == "" {
+					text = element.Href
+				}
+				fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(element.Href), styled(html.EscapeString(text), element.Style))
+			case "at":
+				// Feishu members cannot be mentioned on Telegram, keep the name only
+				b.WriteString(html.EscapeString("@" + element.UserName))
+			case "md":
+				b.WriteString(markdownToTelegramHTML(element.Text))
+			case "code_block":
+				fmt.Fprintf(&b, "<pre>%s</pre>", html.EscapeString(element.Text))
+			case "img":
+				imageKeys = append(imageKeys, element.ImageKey)
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), imageKeys, nil
+}
+
+// styled wraps escaped text in the HTML tags matching the Feishu text styles.
+func styled(text string, styles []string) string {
+	if text == "" {
+		return text
+	}
+	for _, style := range styles {
+		if tag, ok := telegramStyleTags[style]; ok {
+			text = fmt.Sprintf("<%s>%s</%s>", tag, text, tag)
+		}
+	}
+	return text
+}
+
+// markdownCodeSpan matches inline code, whose content is kept verbatim.
+var markdownCodeSpan = regexp.MustCompile("`([^`\n]+)`")
+
+// markdownRules converts the markdown subset used by Feishu (lark_md) into Telegram HTML.
+// The rules run on escaped text outside code spans, so the captured groups are safe to embed.
+var markdownRules = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	{regexp.MustCompile(`\[([^\]\n]+)\]\(([^)\s]+)\)`), `<a href="$2">$1</a>`},
+	{regexp.MustCompile(`\*\*([^*\n]+)\*\*`), "<b>$1</b>"},
+	{regexp.MustCompile(`~~([^~\n]+)~~`), "<s>$1</s>"},
+	{regexp.MustCompile(`\*([^*\n]+)\*`), "<i>$1</i>"},
+}
+
+// markdownToTelegramHTML converts Feishu markdown into Telegram HTML.
+// Code spans are split out first so that the other rules do not rewrite their content.
+func markdownToTelegramHTML(markdown string) string {
+	text := html.EscapeString(markdown)
+	var b strings.Builder
+	last := 0
+	for _, span := range markdownCodeSpan.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(applyMarkdownRules(text[last:span[0]]))
+		fmt.Fprintf(&b, "<code>%s</code>", text[span[2]:span[3]])
+		last = span[1]
+	}
+	b.WriteString(applyMarkdownRules(text[last:]))
+	return b.String()
+}
+
+// applyMarkdownRules applies the markdown rules to escaped text without code spans.
+func applyMarkdownRules(text string) string {
+	for _, rule := range markdownRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replace)
+	}
+	return text
+}
+
+// telegramByline returns the escaped footer of the Telegram post.
+func telegramByline(article *model.Article) string {
+	return html.EscapeString(articleByline(article))
+}
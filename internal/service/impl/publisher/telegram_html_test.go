@@ -0,0 +1,95 @@
+package publisher
+
+import (
+	"MikoNews/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestRenderTelegramHTML(t *testing.T) {
+	raw := `{"title":"","content":[
+		[{"tag":"text","text":"标题","style":["bold"]}],
+		[{"tag":"text","text":"a < b & c","style":["italic","underline"]},{"tag":"text","text":" 删除","style":["lineThrough"]}],
+		[{"tag":"a","text":"链接","href":"https://example.com/?a=1&b=2"},{"tag":"at","user_id":"ou_1","user_name":"李四"}],
+		[{"tag":"img","image_key":"img_1"},{"tag":"img","image_key":"img_2"}],
+		[{"tag":"md","text":"**粗** *斜* ~~删~~ ` + "`code`" + ` [文档](https://example.com/doc)"}]
+	]}`
+
+	got, images, err := renderTelegramHTML(raw)
+	if err != nil {
+		t.Fatalf("renderTelegramHTML() error = %v", err)
+	}
+	want := "<b>标题</b>\n" +
+		"<u><i>a &lt; b &amp; c</i></u><s> 删除</s>\n" +
+		`<a href="https://example.com/?a=1&amp;b=2">链接</a>@李四` + "\n" +
+		"\n" +
+		`<b>粗</b> <i>斜</i> <s>删</s> <code>code</code> <a href="https://example.com/doc">文档</a>`
+	if got != want {
+		t.Errorf("renderTelegramHTML() =\n%q\nwant\n%q", got, want)
+	}
+	if !reflect.DeepEqual(images, []string{"img_1", "img_2"}) {
+		t.Errorf("images = %v, want [img_1 img_2]", images)
+	}
+}
+
+func TestMarkdownToTelegramHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"code span is kept verbatim", "`**x**`", "<code>**x**</code>"},
+		{"rules apply around code spans", "**a** `*b*` ~~c~~", "<b>a</b> <code>*b*</code> <s>c</s>"},
+		{"link inside code span", "`[a](https://example.com)`", "<code>[a](https://example.com)</code>"},
+		{"code span is escaped", "`a < b`", "<code>a &lt; b</code>"},
+		{"unclosed backtick", "`**x**", "`<b>x</b>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToTelegramHTML(tt.markdown); got != tt.want {
+				t.Errorf("markdownToTelegramHTML(%q) = %q, want %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTelegramHTMLInvalidContent(t *testing.T) {
+	if _, _, err := renderTelegramHTML("not json"); err == nil {
+		t.Error("renderTelegramHTML() error = nil, want an error for invalid content")
+	}
+}
+
+func TestTelegramByline(t *testing.T) {
+	tests := []struct {
+		name    string
+		article *model.Article
+		want    string
+	}{
+		{
+			name:    "anonymous",
+			article: &model.Article{Anonymous: true, AuthorName: model.AnonymousAuthorName},
+			want:    "匿名投稿",
+		},
+		{
+			name: "credits",
+			article: &model.Article{AuthorName: "张三", Authors: []*model.ArticleAuthor{
+				{Name: "张三", Role: model.AuthorRoleAuthor},
+				{Name: "李四", Role: model.AuthorRoleCoAuthor},
+				{Name: "王<五>", Role: model.AuthorRoleSource},
+			}},
+			want: "投稿人：张三 · 合著：李四 · 来源：王&lt;五&gt;",
+		},
+		{
+			name:    "curated",
+			article: &model.Article{AuthorName: "张三", CuratorID: "ou_2"},
+			want:    "作者：张三 · 群友推荐收录",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := telegramByline(tt.article); got != tt.want {
+				t.Errorf("telegramByline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package publisher
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// telegramMessageLimit is the maximum length of a Telegram message text in characters.
+const telegramMessageLimit = 4096
+
+// ImageDownloader downloads the images referenced by image_key in the article content, e.g. from Feishu.
+type ImageDownloader interface {
+	DownloadImage(ctx context.Context, imageKey string) ([]byte, error)
+}
+
+// telegramResponse is the envelope returned by every Telegram Bot API method.
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+// telegramPublisher posts articles to Telegram channels through the Bot API.
+type telegramPublisher struct {
+	conf   *config.TelegramPublishConfig
+	images ImageDownloader
+	client *http.Client
+}
+
+var _ service.Publisher = (*telegramPublisher)(nil)
+
+// NewTelegramPublisher creates a publisher posting articles to the configured Telegram channels.
+// Images are downloaded with images and re-uploaded to Telegram.
+func NewTelegramPublisher(conf *config.TelegramPublishConfig, images ImageDownloader) service.Publisher {
+	return &telegramPublisher{
+		conf:   conf,
+		images: images,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name identifies the publisher in logs.
+func (p *telegramPublisher) Name() string {
+	return "telegram"
+}
+
+// Publish posts the article text to every channel, followed by its images.
+// Articles that came from Telegram are not posted back to avoid echoing them.
+func (p *telegramPublisher) Publish(ctx context.Context, article *model.Article) error {
+	if article.Source == model.ArticleSourceTelegram {
+		logger.Ctx(ctx).Debug("Skipping Telegram cross-post for article from Telegram", zap.Int64("articleID", article.ID))
+		return nil
+	}
+
+	body, imageKeys, err := renderTelegramHTML(article.RawContent)
+	if err != nil {
+		return err
+	}
+	text, plain := body+"\n\n<i>"+telegramByline(article)+"</i>", false
+	if utf8.RuneCountInString(text) > telegramMessageLimit {
+		// Cutting HTML could leave tags unbalanced, so long articles are sent as truncated plain text
+		text, plain = truncateRunes(article.Content, telegramMessageLimit-1)+"…", true
+	}
+	images := p.downloadImages(ctx, article.ID, imageKeys)
+
+	var errs []error
+	for _, channel := range p.conf.Channels {
+		if err := p.sendMessage(ctx, channel, text, plain); err != nil {
+			errs = append(errs, fmt.Errorf("发送到 Telegram 频道 %s 失败: %w", channel, err))
+			continue
+		}
+		for i, image := range images {
+			if err := p.sendPhoto(ctx, channel, fmt.Sprintf("image_%d.jpg", i+1), image); err != nil {
+				errs = append(errs, fmt.Errorf("发送图片到 Telegram 频道 %s 失败: %w", channel, err))
+			}
+		}
+		logger.Ctx(ctx).Info("Article cross-posted to Telegram", zap.Int64("articleID", article.ID), zap.String("channel", channel))
+	}
+	return errors.Join(errs...)
+}
+
+// downloadImages fetches the article images, skipping the ones that cannot be downloaded.
+func (p *telegramPublisher) downloadImages(ctx context.Context, articleID int64, imageKeys []string) [][]byte {
+	var images [][]byte
+	for _, key := range imageKeys {
+		data, err := p.images.DownloadImage(ctx, key)
+		if err != nil {
+			logger.Ctx(ctx).Warn("Failed to download image for Telegram", zap.Int64("articleID", articleID), zap.String("imageKey", key), zap.Error(err))
+			continue
+		}
+		images = append(images, data)
+	}
+	return images
+}
+
+// sendMessage calls sendMessage, formatting the text as HTML unless plain is set.
+func (p *telegramPublisher) sendMessage(ctx context.Context, chatID, text string, plain bool) error {
+	form := url.Values{}
+	form.Set("chat_id", chatID)
+	form.Set("text", text)
+	if !plain {
+		form.Set("parse_mode", "HTML")
+	}
+	form.Set("disable_web_page_preview", "true")
+	return p.call(ctx, "sendMessage", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+}
+
+// sendPhoto calls sendPhoto, uploading the image as multipart form data.
+func (p *telegramPublisher) sendPhoto(ctx context.Context, chatID, filename string, image []byte) error {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.WriteField("chat_id", chatID); err != nil {
+		return err
+	}
+	part, err := writer.CreateFormFile("photo", filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(image); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return p.call(ctx, "sendPhoto", writer.FormDataContentType(), &buf)
+}
+
+// call invokes a Bot API method and checks the ok flag of its response.
+func (p *telegramPublisher) call(ctx context.Context, method, contentType string, body io.Reader) error {
+	endpoint := fmt.Sprintf("%s/bot%s/%s", strings.TrimRight(p.conf.APIURL, "/"), p.conf.BotToken, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("创建 Telegram 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// The error contains the URL and thus the bot token, keep it out of the logs
+		return fmt.Errorf("请求 Telegram %s 失败: %w", method, errors.Unwrap(err))
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return fmt.Errorf("解析 Telegram %s 响应失败 (HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram %s 返回错误 %d: %s", method, result.ErrorCode, result.Description)
+	}
+	return nil
+}
+
+// truncateRunes returns at most n characters of s.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,192 @@
+package publisher
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// botAPIRequest is a request received by the fake Telegram Bot API server.
+type botAPIRequest struct {
+	Token  string
+	Method string
+	Fields map[string]string
+	Photo  []byte
+}
+
+// fakeBotAPI is a minimal Telegram Bot API server recording the requests it receives.
+type fakeBotAPI struct {
+	mu       sync.Mutex
+	requests []botAPIRequest
+	failChat string // requests for this chat_id are answered with ok=false
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Paths look like /bot<token>/<method>
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/bot"), "/", 2)
+	req := botAPIRequest{Token: parts[0], Method: parts[1], Fields: map[string]string{}}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for key, values := range r.MultipartForm.Value {
+			req.Fields[key] = values[0]
+		}
+		if file, _, err := r.FormFile("photo"); err == nil {
+			req.Photo, _ = io.ReadAll(file)
+			file.Close()
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for key := range r.PostForm {
+			req.Fields[key] = r.PostForm.Get(key)
+		}
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	messageID := len(f.requests)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if req.Fields["chat_id"] == f.failChat {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error_code": 400, "description": "Bad Request: chat not found"})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": map[string]interface{}{"message_id": messageID}})
+}
+
+// fakeImages serves image bytes by image_key.
+type fakeImages map[string][]byte
+
+func (f fakeImages) DownloadImage(_ context.Context, imageKey string) ([]byte, error) {
+	data, ok := f[imageKey]
+	if !ok {
+		return nil, fmt.Errorf("image %s not found", imageKey)
+	}
+	return data, nil
+}
+
+func newTestPublisher(t *testing.T, api *fakeBotAPI, channels ...string) *telegramPublisher {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	conf := &config.TelegramPublishConfig{BotToken: "123:secret", Channels: channels, APIURL: server.URL}
+	return NewTelegramPublisher(conf, fakeImages{"img_1": []byte("png-bytes")}).(*telegramPublisher)
+}
+
+func testArticle() *model.Article {
+	return &model.Article{
+		ID:         42,
+		Title:      "周报",
+		Content:    "周报\n本周上线 <新功能>",
+		AuthorName: "张三",
+		Source:     model.ArticleSourceFeishu,
+		RawContent: `{"title":"","content":[[{"tag":"text","text":"周报","style":["bold"]}],[{"tag":"text","text":"本周上线 <新功能>"}],[{"tag":"img","image_key":"img_1"}]]}`,
+	}
+}
+
+func TestTelegramPublisherPostsTextAndImages(t *testing.T) {
+	api := &fakeBotAPI{}
+	publisher := newTestPublisher(t, api, "@miko_news")
+
+	if err := publisher.Publish(context.Background(), testArticle()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(api.requests) != 2 {
+		t.Fatalf("got %d requests, want sendMessage and sendPhoto", len(api.requests))
+	}
+	msg := api.requests[0]
+	if msg.Method != "sendMessage" || msg.Token != "123:secret" {
+		t.Errorf("first request = %s with token %q, want sendMessage with the bot token", msg.Method, msg.Token)
+	}
+	if msg.Fields["chat_id"] != "@miko_news" || msg.Fields["parse_mode"] != "HTML" {
+		t.Errorf("sendMessage fields = %v", msg.Fields)
+	}
+	wantText := "<b>周报</b>\n本周上线 &lt;新功能&gt;\n\n<i>投稿人：张三</i>"
+	if msg.Fields["text"] != wantText {
+		t.Errorf("text = %q, want %q", msg.Fields["text"], wantText)
+	}
+
+	photo := api.requests[1]
+	if photo.Method != "sendPhoto" || photo.Fields["chat_id"] != "@miko_news" {
+		t.Errorf("second request = %s to %q, want sendPhoto to the channel", photo.Method, photo.Fields["chat_id"])
+	}
+	if string(photo.Photo) != "png-bytes" {
+		t.Errorf("uploaded photo = %q, want the downloaded image", photo.Photo)
+	}
+}
+
+func TestTelegramPublisherReportsFailedChannels(t *testing.T) {
+	api := &fakeBotAPI{failChat: "@broken"}
+	publisher := newTestPublisher(t, api, "@broken", "@miko_news")
+
+	err := publisher.Publish(context.Background(), testArticle())
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Fatalf("Publish() error = %v, want the Bot API description", err)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error %q leaks the bot token", err)
+	}
+
+	// The healthy channel still gets the message and the image
+	var delivered int
+	for _, req := range api.requests {
+		if req.Fields["chat_id"] == "@miko_news" {
+			delivered++
+		}
+	}
+	if delivered != 2 {
+		t.Errorf("delivered %d requests to the healthy channel, want 2", delivered)
+	}
+}
+
+func TestTelegramPublisherSkipsArticlesFromTelegram(t *testing.T) {
+	api := &fakeBotAPI{}
+	publisher := newTestPublisher(t, api, "@miko_news")
+
+	article := testArticle()
+	article.Source = model.ArticleSourceTelegram
+	if err := publisher.Publish(context.Background(), article); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(api.requests) != 0 {
+		t.Errorf("got %d requests, want none for an article from Telegram", len(api.requests))
+	}
+}
+
+func TestTelegramPublisherSendsLongArticlesAsPlainText(t *testing.T) {
+	api := &fakeBotAPI{}
+	publisher := newTestPublisher(t, api, "@miko_news")
+
+	article := testArticle()
+	long := strings.Repeat("长", telegramMessageLimit+10)
+	article.Content = long
+	article.RawContent = fmt.Sprintf(`{"content":[[{"tag":"text","text":%q}]]}`, long)
+	if err := publisher.Publish(context.Background(), article); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	msg := api.requests[0]
+	if _, ok := msg.Fields["parse_mode"]; ok {
+		t.Errorf("parse_mode = %q, want plain text for truncated articles", msg.Fields["parse_mode"])
+	}
+	if n := len([]rune(msg.Fields["text"])); n != telegramMessageLimit {
+		t.Errorf("text length = %d, want %d", n, telegramMessageLimit)
+	}
+}
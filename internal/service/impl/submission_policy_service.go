@@ -0,0 +1,130 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/cache"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	chatMemberCacheSize = 64              // 缓存成员列表的群聊数
+	chatMemberCacheTTL  = 5 * time.Minute // 群成员列表的缓存时间
+)
+
+// submissionPolicyServiceImpl implements the SubmissionPolicyService interface.
+type submissionPolicyServiceImpl struct {
+	conf          *config.SubmissionPolicyConfig
+	userDirectory service.UserDirectoryService
+	feishuService service.FeishuMessageService
+	chatMembers   *cache.LRU[string, map[string]bool]
+}
+
+// NewSubmissionPolicyService creates a new submission policy service.
+func NewSubmissionPolicyService(
+	conf *config.SubmissionPolicyConfig,
+	userDirectory service.UserDirectoryService,
+	feishuService service.FeishuMessageService,
+) service.SubmissionPolicyService {
+	return &submissionPolicyServiceImpl{
+		conf:          conf,
+		userDirectory: userDirectory,
+		feishuService: feishuService,
+		chatMembers:   cache.NewLRU[string, map[string]bool](chatMemberCacheSize, chatMemberCacheTTL),
+	}
+}
+
+// Evaluate matches the rules in order; the first matching rule wins, otherwise the default applies.
+func (s *submissionPolicyServiceImpl) Evaluate(ctx context.Context, openID string) (*service.PolicyDecision, error) {
+	for i := range s.conf.Rules {
+		rule := &s.conf.Rules[i]
+		matched, err := s.matches(ctx, rule, openID)
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to evaluate submission policy rule", zap.String("rule", rule.Name), zap.String("openID", openID), zap.Error(err))
+			return nil, fmt.Errorf("校验投稿权限失败: %w", err)
+		}
+		if matched {
+			decision := s.decide(rule.Effect, rule.Review, rule.Message)
+			decision.Rule = rule.Name
+			logger.Ctx(ctx).Info("Submission policy rule matched",
+				zap.String("rule", rule.Name),
+				zap.String("openID", openID),
+				zap.Bool("allowed", decision.Allowed),
+				zap.Bool("reviewRequired", decision.ReviewRequired),
+			)
+			return decision, nil
+		}
+	}
+	return s.decide(s.conf.DefaultEffect, s.conf.DefaultReview, ""), nil
+}
+
+// decide builds the decision for the given effect.
+func (s *submissionPolicyServiceImpl) decide(effect string, review bool, message string) *service.PolicyDecision {
+	if effect == config.PolicyEffectDeny {
+		if message == "" {
+			message = s.conf.DenyMessage
+		}
+		return &service.PolicyDecision{Allowed: false, Message: message}
+	}
+	return &service.PolicyDecision{Allowed: true, ReviewRequired: review}
+}
+
+// matches reports whether the user matches any of the rule's open_ids, departments or chats.
+// Departments and chat memberships are only looked up when the cheaper checks did not match.
+func (s *submissionPolicyServiceImpl) matches(ctx context.Context, rule *config.SubmissionPolicyRule, openID string) (bool, error) {
+	for _, id := range rule.OpenIDs {
+		if id == openID {
+			return true, nil
+		}
+	}
+
+	if len(rule.Departments) > 0 {
+		user, err := s.userDirectory.GetUser(ctx, openID)
+		if err != nil {
+			return false, err
+		}
+		for _, userDept := range user.Departments() {
+			for _, dept := range rule.Departments {
+				if dept == userDept {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	for _, chatID := range rule.Chats {
+		members, err := s.members(ctx, chatID)
+		if err != nil {
+			return false, err
+		}
+		if members[openID] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// members returns the member set of a chat, cached for chatMemberCacheTTL.
+func (s *submissionPolicyServiceImpl) members(ctx context.Context, chatID string) (map[string]bool, error) {
+	if members, ok := s.chatMembers.Get(chatID); ok {
+		return members, nil
+	}
+	ids, err := s.feishuService.ListChatMemberIDs(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		members[id] = true
+	}
+	s.chatMembers.Set(chatID, members)
+	return members, nil
+}
+
+// Ensure submissionPolicyServiceImpl implements SubmissionPolicyService
+var _ service.SubmissionPolicyService = (*submissionPolicyServiceImpl)(nil)
@@ -0,0 +1,143 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeUserDirectory serves users from a map.
+type fakeUserDirectory struct {
+	service.UserDirectoryService
+	users map[string]*model.User
+}
+
+func (f *fakeUserDirectory) GetUser(_ context.Context, openID string) (*model.User, error) {
+	user, ok := f.users[openID]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// fakeChatMembers lists chat members from a map and counts the lookups per chat.
+type fakeChatMembers struct {
+	service.FeishuMessageService
+	members map[string][]string
+	lookups map[string]int
+}
+
+func (f *fakeChatMembers) ListChatMemberIDs(_ context.Context, chatID string) ([]string, error) {
+	f.lookups[chatID]++
+	members, ok := f.members[chatID]
+	if !ok {
+		return nil, errors.New("chat not found")
+	}
+	return members, nil
+}
+
+func userInDepartments(openID string, departments ...string) *model.User {
+	user := &model.User{OpenID: openID}
+	user.SetDepartments(departments)
+	return user
+}
+
+func newTestPolicy(conf *config.SubmissionPolicyConfig) (*submissionPolicyServiceImpl, *fakeChatMembers) {
+	directory := &fakeUserDirectory{users: map[string]*model.User{
+		"ou_intern": userInDepartments("ou_intern", "od_interns"),
+		"ou_editor": userInDepartments("ou_editor", "od_editors"),
+		"ou_other":  userInDepartments("ou_other"),
+		"ou_banned": userInDepartments("ou_banned", "od_interns"),
+	}}
+	chats := &fakeChatMembers{
+		members: map[string][]string{"oc_editors": {"ou_editor"}},
+		lookups: map[string]int{},
+	}
+	return NewSubmissionPolicyService(conf, directory, chats).(*submissionPolicyServiceImpl), chats
+}
+
+func TestSubmissionPolicyEvaluate(t *testing.T) {
+	conf := &config.SubmissionPolicyConfig{
+		DefaultEffect: config.PolicyEffectDeny,
+		DenyMessage:   "no permission",
+		Rules: []config.SubmissionPolicyRule{
+			{Name: "banned", Effect: config.PolicyEffectDeny, OpenIDs: []string{"ou_banned"}, Message: "suspended"},
+			{Name: "editors", Effect: config.PolicyEffectAllow, Chats: []string{"oc_editors"}},
+			{Name: "interns", Effect: config.PolicyEffectAllow, Departments: []string{"od_interns"}, Review: true},
+			{Name: "blocked", Effect: config.PolicyEffectDeny, Departments: []string{"od_editors"}},
+		},
+	}
+	tests := []struct {
+		name        string
+		openID      string
+		wantAllowed bool
+		wantReview  bool
+		wantRule    string
+		wantMessage string
+	}{
+		{"open_id rule matched before the department rule", "ou_banned", false, false, "banned", "suspended"},
+		{"chat rule matched before a later deny rule", "ou_editor", true, false, "editors", ""},
+		{"department rule with review", "ou_intern", true, true, "interns", ""},
+		{"default effect with the default deny message", "ou_other", false, false, "", "no permission"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, _ := newTestPolicy(conf)
+			decision, err := policy.Evaluate(context.Background(), tt.openID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decision.Allowed != tt.wantAllowed || decision.ReviewRequired != tt.wantReview ||
+				decision.Rule != tt.wantRule || decision.Message != tt.wantMessage {
+				t.Errorf("Evaluate(%s) = %+v, want allowed=%v review=%v rule=%q message=%q",
+					tt.openID, decision, tt.wantAllowed, tt.wantReview, tt.wantRule, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestSubmissionPolicyDefaultReview(t *testing.T) {
+	policy, _ := newTestPolicy(&config.SubmissionPolicyConfig{DefaultEffect: config.PolicyEffectAllow, DefaultReview: true})
+	decision, err := policy.Evaluate(context.Background(), "ou_other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decision.Allowed || !decision.ReviewRequired || decision.Rule != "" {
+		t.Errorf("Evaluate() = %+v, want allowed with review by default", decision)
+	}
+}
+
+func TestSubmissionPolicyCachesChatMembers(t *testing.T) {
+	policy, chats := newTestPolicy(&config.SubmissionPolicyConfig{
+		DefaultEffect: config.PolicyEffectDeny,
+		Rules:         []config.SubmissionPolicyRule{{Name: "editors", Effect: config.PolicyEffectAllow, Chats: []string{"oc_editors"}}},
+	})
+	for _, openID := range []string{"ou_editor", "ou_other", "ou_editor"} {
+		if _, err := policy.Evaluate(context.Background(), openID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := chats.lookups["oc_editors"]; got != 1 {
+		t.Errorf("listed the chat members %d times, want 1", got)
+	}
+}
+
+func TestSubmissionPolicyLookupFailure(t *testing.T) {
+	policy, _ := newTestPolicy(&config.SubmissionPolicyConfig{
+		DefaultEffect: config.PolicyEffectAllow,
+		Rules: []config.SubmissionPolicyRule{
+			{Name: "open_ids", Effect: config.PolicyEffectAllow, OpenIDs: []string{"ou_editor"}},
+			{Name: "unknown chat", Effect: config.PolicyEffectDeny, Chats: []string{"oc_unknown"}},
+		},
+	})
+	// The open_id rule matches first, so the chat is never looked up
+	if decision, err := policy.Evaluate(context.Background(), "ou_editor"); err != nil || !decision.Allowed {
+		t.Errorf("Evaluate(ou_editor) = %+v, %v; want allowed", decision, err)
+	}
+	if _, err := policy.Evaluate(context.Background(), "ou_other"); err == nil {
+		t.Error("Evaluate() should fail when the chat members cannot be listed")
+	}
+}
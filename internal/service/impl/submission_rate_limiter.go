@@ -0,0 +1,174 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/service"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// cleanupInterval 是清理过期限流计数的最小间隔
+const cleanupInterval = time.Hour
+
+// rateLimitWindow 描述一个固定时间窗口及其配额
+type rateLimitWindow struct {
+	name     string // 计数键中的窗口名
+	duration time.Duration
+	limit    int
+}
+
+// submissionRateLimiterImpl implements the SubmissionRateLimiter interface with fixed hourly and daily windows.
+type submissionRateLimiterImpl struct {
+	repo     repository.RateLimitRepository
+	conf     *config.RateLimitConfig
+	adminCfg *config.AdminConfig
+	now      func() time.Time
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+// NewSubmissionRateLimiter creates a new submission rate limiter backed by the given counter repository.
+func NewSubmissionRateLimiter(
+	repo repository.RateLimitRepository,
+	conf *config.RateLimitConfig,
+	adminCfg *config.AdminConfig,
+) service.SubmissionRateLimiter {
+	return &submissionRateLimiterImpl{
+		repo:     repo,
+		conf:     conf,
+		adminCfg: adminCfg,
+		now:      time.Now,
+	}
+}
+
+// Check reports whether the user is within both the hourly and the daily quota.
+func (l *submissionRateLimiterImpl) Check(ctx context.Context, openID string) (*service.RateLimitResult, error) {
+	now := l.now()
+	for _, window := range l.windows(openID) {
+		start := window.start(now)
+		count, err := l.repo.Count(ctx, counterKey(window, openID), start)
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to read submission rate limit counter", zap.String("openID", openID), zap.Error(err))
+			return nil, fmt.Errorf("读取投稿配额失败: %w", err)
+		}
+		if count >= int64(window.limit) {
+			return exceeded(ctx, openID, window, start), nil
+		}
+	}
+	return &service.RateLimitResult{Allowed: true}, nil
+}
+
+// Acquire increments the user's counters of every limited window and compares the new counts with the limits.
+// The increment is atomic in the repository, so of several concurrent submissions only those within quota are
+// allowed; counters incremented for a rejected submission are decremented again.
+func (l *submissionRateLimiterImpl) Acquire(ctx context.Context, openID string) (*service.RateLimitResult, error) {
+	now := l.now()
+	var acquired []rateLimitWindow
+	rollback := func() {
+		for _, window := range acquired {
+			if err := l.repo.Decrement(ctx, counterKey(window, openID), window.start(now)); err != nil {
+				logger.Ctx(ctx).Warn("Failed to roll back submission rate limit counter", zap.String("openID", openID), zap.Error(err))
+			}
+		}
+	}
+
+	for _, window := range l.windows(openID) {
+		start := window.start(now)
+		count, err := l.repo.Increment(ctx, counterKey(window, openID), start, start.Add(window.duration))
+		if err != nil {
+			rollback()
+			logger.Ctx(ctx).Error("Failed to increment submission rate limit counter", zap.String("openID", openID), zap.Error(err))
+			return nil, fmt.Errorf("更新投稿配额失败: %w", err)
+		}
+		acquired = append(acquired, window)
+		if count > int64(window.limit) {
+			rollback()
+			return exceeded(ctx, openID, window, start), nil
+		}
+	}
+	l.cleanup(ctx, now)
+	return &service.RateLimitResult{Allowed: true}, nil
+}
+
+// Release decrements the user's counters of every limited window.
+func (l *submissionRateLimiterImpl) Release(ctx context.Context, openID string) error {
+	now := l.now()
+	for _, window := range l.windows(openID) {
+		if err := l.repo.Decrement(ctx, counterKey(window, openID), window.start(now)); err != nil {
+			logger.Ctx(ctx).Error("Failed to decrement submission rate limit counter", zap.String("openID", openID), zap.Error(err))
+			return fmt.Errorf("归还投稿配额失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// exceeded builds the result of a submission rejected by the window's limit.
+func exceeded(ctx context.Context, openID string, window rateLimitWindow, start time.Time) *service.RateLimitResult {
+	logger.Ctx(ctx).Info("Submission rate limit exceeded",
+		zap.String("openID", openID),
+		zap.String("window", window.name),
+		zap.Int("limit", window.limit),
+	)
+	return &service.RateLimitResult{
+		Allowed: false,
+		Limit:   window.limit,
+		Window:  window.duration,
+		ResetAt: start.Add(window.duration),
+	}
+}
+
+// windows returns the limited windows for the user's role; unlimited (0) windows are skipped.
+func (l *submissionRateLimiterImpl) windows(openID string) []rateLimitWindow {
+	quota := l.conf.Default
+	if l.adminCfg.IsAdmin(openID) {
+		quota = l.conf.Admin
+	}
+
+	windows := make([]rateLimitWindow, 0, 2)
+	if quota.PerHour > 0 {
+		windows = append(windows, rateLimitWindow{name: "hour", duration: time.Hour, limit: quota.PerHour})
+	}
+	if quota.PerDay > 0 {
+		windows = append(windows, rateLimitWindow{name: "day", duration: 24 * time.Hour, limit: quota.PerDay})
+	}
+	return windows
+}
+
+// cleanup removes expired counters at most once per cleanupInterval.
+func (l *submissionRateLimiterImpl) cleanup(ctx context.Context, now time.Time) {
+	l.mu.Lock()
+	if now.Sub(l.lastCleanup) < cleanupInterval {
+		l.mu.Unlock()
+		return
+	}
+	l.lastCleanup = now
+	l.mu.Unlock()
+
+	if err := l.repo.DeleteExpired(ctx, now); err != nil {
+		logger.Ctx(ctx).Warn("Failed to delete expired rate limit counters", zap.Error(err))
+	}
+}
+
+// start returns the beginning of the window containing now; daily windows start at local midnight.
+func (w rateLimitWindow) start(now time.Time) time.Time {
+	if w.duration == 24*time.Hour {
+		year, month, day := now.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	}
+	return now.Truncate(w.duration)
+}
+
+// counterKey builds the counter key of a user in a window.
+func counterKey(window rateLimitWindow, openID string) string {
+	return "submission:" + window.name + ":" + openID
+}
+
+// Ensure submissionRateLimiterImpl implements SubmissionRateLimiter
+var _ service.SubmissionRateLimiter = (*submissionRateLimiterImpl)(nil)
@@ -0,0 +1,117 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/repository/impl/memory"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestRateLimiter(perHour, perDay int, now *time.Time) *submissionRateLimiterImpl {
+	limiter := NewSubmissionRateLimiter(memory.NewRateLimitRepository(), &config.RateLimitConfig{
+		Default: config.RateLimitQuota{PerHour: perHour, PerDay: perDay},
+		Admin:   config.RateLimitQuota{},
+	}, &config.AdminConfig{OpenIDs: []string{"ou_admin"}}).(*submissionRateLimiterImpl)
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestRateLimiterAcquire(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.Local)
+	limiter := newTestRateLimiter(2, 3, &now)
+
+	for i := 0; i < 2; i++ {
+		if result, err := limiter.Acquire(ctx, "ou_user"); err != nil || !result.Allowed {
+			t.Fatalf("acquire %d: got %+v, %v; want allowed", i, result, err)
+		}
+	}
+	result, err := limiter.Acquire(ctx, "ou_user")
+	if err != nil || result.Allowed || result.Limit != 2 || result.Window != time.Hour {
+		t.Fatalf("acquire over the hourly quota: got %+v, %v", result, err)
+	}
+	if want := time.Date(2024, 5, 1, 11, 0, 0, 0, time.Local); !result.ResetAt.Equal(want) {
+		t.Errorf("ResetAt = %v, want %v", result.ResetAt, want)
+	}
+	if result, _ := limiter.Check(ctx, "ou_user"); result.Allowed {
+		t.Error("Check should reject a user over quota")
+	}
+
+	// The rejected attempt is not counted against the daily quota: one submission is left for today
+	now = now.Add(time.Hour)
+	if result, _ := limiter.Acquire(ctx, "ou_user"); !result.Allowed {
+		t.Fatal("the next hour should allow a submission")
+	}
+	result, _ = limiter.Acquire(ctx, "ou_user")
+	if result.Allowed || result.Window != 24*time.Hour {
+		t.Fatalf("acquire over the daily quota: got %+v", result)
+	}
+
+	// Admins use the admin quota, which is unlimited here
+	for i := 0; i < 5; i++ {
+		if result, _ := limiter.Acquire(ctx, "ou_admin"); !result.Allowed {
+			t.Fatal("admins should not be limited")
+		}
+	}
+}
+
+func TestRateLimiterCheckDoesNotConsume(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	limiter := newTestRateLimiter(1, 0, &now)
+
+	for i := 0; i < 3; i++ {
+		if result, _ := limiter.Check(ctx, "ou_user"); !result.Allowed {
+			t.Fatal("Check must not consume quota")
+		}
+	}
+	if result, _ := limiter.Acquire(ctx, "ou_user"); !result.Allowed {
+		t.Fatal("acquire should be allowed")
+	}
+}
+
+func TestRateLimiterRelease(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	limiter := newTestRateLimiter(1, 1, &now)
+
+	if result, _ := limiter.Acquire(ctx, "ou_user"); !result.Allowed {
+		t.Fatal("acquire should be allowed")
+	}
+	if err := limiter.Release(ctx, "ou_user"); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := limiter.Acquire(ctx, "ou_user"); !result.Allowed {
+		t.Fatal("released quota should be available again")
+	}
+}
+
+func TestRateLimiterConcurrentAcquire(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	limiter := newTestRateLimiter(5, 0, &now)
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := limiter.Acquire(ctx, "ou_user")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if result.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := allowed.Load(); got != 5 {
+		t.Errorf("concurrent submissions allowed = %d, want 5", got)
+	}
+}
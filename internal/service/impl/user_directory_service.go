@@ -0,0 +1,256 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/cache"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/pkg/tracing"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// userDirectoryServiceImpl implements the UserDirectoryService interface.
+type userDirectoryServiceImpl struct {
+	contactService service.FeishuContactService
+	userRepo       repository.UserRepository
+	articleRepo    repository.ArticleRepository
+	cache          *cache.LRU[string, *model.User]
+
+	stopping chan struct{} // StopPeriodicSync 时关闭
+	stopOnce sync.Once
+	started  atomic.Bool
+	done     chan struct{} // RunPeriodicSync 返回前关闭
+}
+
+// NewUserDirectoryService creates a new user directory service backed by the users table and an LRU cache.
+func NewUserDirectoryService(
+	contactService service.FeishuContactService,
+	userRepo repository.UserRepository,
+	articleRepo repository.ArticleRepository,
+	conf *config.ContactConfig,
+) service.UserDirectoryService {
+	return &userDirectoryServiceImpl{
+		contactService: contactService,
+		userRepo:       userRepo,
+		articleRepo:    articleRepo,
+		cache:          cache.NewLRU[string, *model.User](conf.CacheSize, conf.CacheTTL),
+		stopping:       make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+}
+
+// GetUser resolves a user: cache -> users table -> Contact API.
+// The span records where the user was found as user_directory.source: cache, database or contact.
+func (s *userDirectoryServiceImpl) GetUser(ctx context.Context, openID string) (user *model.User, err error) {
+	ctx, span := tracing.Start(ctx, "user_directory.get_user", attribute.String("feishu.open_id", openID))
+	defer func() { tracing.End(span, err) }()
+
+	if user, ok := s.cache.Get(openID); ok {
+		span.SetAttributes(attribute.String("user_directory.source", "cache"))
+		return user, nil
+	}
+
+	user, err = s.userRepo.FindByOpenID(ctx, openID)
+	if err == nil {
+		span.SetAttributes(attribute.String("user_directory.source", "database"))
+		s.cache.Set(openID, user)
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Ctx(ctx).Warn("Failed to query local user directory, falling back to Contact API", zap.String("openID", openID), zap.Error(err))
+	}
+
+	// Not synced yet: fetch from the Contact API once and persist it
+	span.SetAttributes(attribute.String("user_directory.source", "contact"))
+	contactUser, err := s.contactService.GetUserInfoByOpenID(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+	user = userFromContact(contactUser)
+	if err := s.userRepo.Upsert(ctx, user); err != nil {
+		logger.Ctx(ctx).Warn("Failed to persist user fetched from Contact API", zap.String("openID", openID), zap.Error(err))
+	}
+	s.cache.Set(openID, user)
+	return user, nil
+}
+
+// SyncUser applies an incremental change from a contact event.
+func (s *userDirectoryServiceImpl) SyncUser(ctx context.Context, event *larkcontact.UserEvent) error {
+	if event == nil || event.OpenId == nil {
+		return fmt.Errorf("用户事件缺少 open_id")
+	}
+	user := userFromEvent(event)
+	return s.save(ctx, user)
+}
+
+// RemoveUser marks the user as resigned and evicts it from the cache.
+func (s *userDirectoryServiceImpl) RemoveUser(ctx context.Context, openID string) error {
+	s.cache.Delete(openID)
+	if err := s.userRepo.UpdateStatus(ctx, openID, model.UserStatusResigned); err != nil {
+		logger.Ctx(ctx).Error("Failed to mark user as resigned", zap.String("openID", openID), zap.Error(err))
+		return fmt.Errorf("更新用户状态失败: %w", err)
+	}
+	logger.Ctx(ctx).Info("User marked as resigned", zap.String("openID", openID))
+	return nil
+}
+
+// SyncAll performs a full sync from the Contact API.
+func (s *userDirectoryServiceImpl) SyncAll(ctx context.Context) (int, error) {
+	start := time.Now()
+	contactUsers, err := s.contactService.ListAllUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("全量同步用户失败: %w", err)
+	}
+
+	synced := 0
+	for _, contactUser := range contactUsers {
+		if err := s.save(ctx, userFromContact(contactUser)); err != nil {
+			logger.Ctx(ctx).Warn("Failed to sync user", zap.Stringp("openID", contactUser.OpenId), zap.Error(err))
+			continue
+		}
+		synced++
+	}
+
+	logger.Ctx(ctx).Info("Full user sync finished",
+		zap.Int("total", len(contactUsers)),
+		zap.Int("synced", synced),
+		zap.Duration("elapsed", time.Since(start)),
+	)
+	return synced, nil
+}
+
+// RunPeriodicSync runs SyncAll immediately and then every interval until ctx is cancelled or StopPeriodicSync is called.
+func (s *userDirectoryServiceImpl) RunPeriodicSync(ctx context.Context, interval time.Duration) {
+	s.started.Store(true)
+	defer close(s.done)
+	if interval <= 0 {
+		return
+	}
+	select {
+	case <-s.stopping:
+		// StopPeriodicSync ran before this goroutine started and did not wait for it; skip the full sync
+		return
+	default:
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SyncAll(ctx); err != nil {
+			logger.Ctx(ctx).Warn("Periodic user sync failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.stopping:
+		case <-ticker.C:
+			continue
+		}
+		logger.Ctx(ctx).Info("Periodic user sync stopped")
+		return
+	}
+}
+
+// StopPeriodicSync stops scheduling full syncs and waits until the running one finishes or ctx ends.
+func (s *userDirectoryServiceImpl) StopPeriodicSync(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopping) })
+	if !s.started.Load() {
+		return nil
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待通讯录同步完成超时: %w", ctx.Err())
+	}
+}
+
+// save persists the user, propagating renames to existing articles and refreshing the cache.
+func (s *userDirectoryServiceImpl) save(ctx context.Context, user *model.User) error {
+	previous, err := s.userRepo.FindByOpenID(ctx, user.OpenID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("查询本地用户失败: %w", err)
+	}
+
+	if err := s.userRepo.Upsert(ctx, user); err != nil {
+		return fmt.Errorf("保存用户失败: %w", err)
+	}
+	s.cache.Set(user.OpenID, user)
+
+	if previous != nil && user.Name != "" && previous.Name != user.Name {
+		if err := s.articleRepo.UpdateAuthorName(ctx, user.OpenID, user.Name); err != nil {
+			logger.Ctx(ctx).Error("Failed to propagate renamed user to articles", zap.String("openID", user.OpenID), zap.Error(err))
+			return fmt.Errorf("更新文章作者名失败: %w", err)
+		}
+		logger.Ctx(ctx).Info("User renamed, author names updated",
+			zap.String("openID", user.OpenID),
+			zap.String("oldName", previous.Name),
+			zap.String("newName", user.Name),
+		)
+	}
+	return nil
+}
+
+// userFromContact converts a Contact API user to the local model.
+func userFromContact(u *larkcontact.User) *model.User {
+	user := &model.User{
+		OpenID:   deref(u.OpenId),
+		UnionID:  deref(u.UnionId),
+		Name:     deref(u.Name),
+		EnName:   deref(u.EnName),
+		Status:   userStatus(u.Status),
+		SyncedAt: time.Now(),
+	}
+	if u.Avatar != nil {
+		user.AvatarURL = deref(u.Avatar.Avatar240)
+	}
+	user.SetDepartments(u.DepartmentIds)
+	return user
+}
+
+// userFromEvent converts a contact event user to the local model.
+func userFromEvent(u *larkcontact.UserEvent) *model.User {
+	user := &model.User{
+		OpenID:   deref(u.OpenId),
+		UnionID:  deref(u.UnionId),
+		Name:     deref(u.Name),
+		EnName:   deref(u.EnName),
+		Status:   userStatus(u.Status),
+		SyncedAt: time.Now(),
+	}
+	if u.Avatar != nil {
+		user.AvatarURL = deref(u.Avatar.Avatar240)
+	}
+	user.SetDepartments(u.DepartmentIds)
+	return user
+}
+
+// userStatus maps the Feishu user status to the local status.
+func userStatus(status *larkcontact.UserStatus) string {
+	if status != nil && ((status.IsResigned != nil && *status.IsResigned) || (status.IsExited != nil && *status.IsExited)) {
+		return model.UserStatusResigned
+	}
+	return model.UserStatusActive
+}
+
+// deref returns the value of a string pointer, or "" if nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Ensure userDirectoryServiceImpl implements UserDirectoryService
+var _ service.UserDirectoryService = (*userDirectoryServiceImpl)(nil)
@@ -0,0 +1,178 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/repository"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+	"gorm.io/gorm"
+)
+
+// fakeUserRepo is an in-memory users table counting lookups.
+type fakeUserRepo struct {
+	mu      sync.Mutex
+	users   map[string]*model.User
+	lookups int
+}
+
+func (r *fakeUserRepo) Upsert(_ context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *user
+	r.users[user.OpenID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) FindByOpenID(_ context.Context, openID string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	user, ok := r.users[openID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) UpdateStatus(_ context.Context, openID, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.users[openID]; ok {
+		user.Status = status
+	}
+	return nil
+}
+
+// fakeContactService serves users from a map and counts the API calls.
+type fakeContactService struct {
+	mu    sync.Mutex
+	users map[string]*larkcontact.User
+	calls int
+}
+
+func (f *fakeContactService) GetUserInfoByOpenID(_ context.Context, openID string) (*larkcontact.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	user, ok := f.users[openID]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (f *fakeContactService) ListAllUsers(context.Context) ([]*larkcontact.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	users := make([]*larkcontact.User, 0, len(f.users))
+	for _, user := range f.users {
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// renameRecorder records the author renames propagated to articles.
+type renameRecorder struct {
+	repository.ArticleRepository
+	renames []string // open_id=name
+}
+
+func (r *renameRecorder) UpdateAuthorName(_ context.Context, openID, name string) error {
+	r.renames = append(r.renames, openID+"="+name)
+	return nil
+}
+
+func contactUser(openID, name string) *larkcontact.User {
+	return &larkcontact.User{OpenId: &openID, Name: &name}
+}
+
+func newTestUserDirectory(contact *fakeContactService, users *fakeUserRepo, articles *renameRecorder) *userDirectoryServiceImpl {
+	conf := &config.ContactConfig{CacheSize: 10, CacheTTL: time.Minute}
+	return NewUserDirectoryService(contact, users, articles, conf).(*userDirectoryServiceImpl)
+}
+
+func TestGetUserFallsBackFromCacheToDatabaseToContact(t *testing.T) {
+	ctx := context.Background()
+	contact := &fakeContactService{users: map[string]*larkcontact.User{"ou_bob": contactUser("ou_bob", "Bob")}}
+	users := &fakeUserRepo{users: map[string]*model.User{"ou_alice": {OpenID: "ou_alice", Name: "Alice"}}}
+	directory := newTestUserDirectory(contact, users, &renameRecorder{})
+
+	// Synced users come from the database, then from the cache
+	for i := 0; i < 2; i++ {
+		user, err := directory.GetUser(ctx, "ou_alice")
+		if err != nil || user.Name != "Alice" {
+			t.Fatalf("GetUser(ou_alice) = %+v, %v; want Alice", user, err)
+		}
+	}
+	if users.lookups != 1 || contact.calls != 0 {
+		t.Errorf("got %d database lookups and %d contact calls, want 1 and 0", users.lookups, contact.calls)
+	}
+
+	// Users not synced yet are fetched from the Contact API once and persisted
+	for i := 0; i < 2; i++ {
+		user, err := directory.GetUser(ctx, "ou_bob")
+		if err != nil || user.Name != "Bob" {
+			t.Fatalf("GetUser(ou_bob) = %+v, %v; want Bob", user, err)
+		}
+	}
+	if contact.calls != 1 {
+		t.Errorf("got %d contact calls, want 1", contact.calls)
+	}
+	if saved, ok := users.users["ou_bob"]; !ok || saved.Name != "Bob" {
+		t.Errorf("the user fetched from the Contact API should be saved, got %+v", saved)
+	}
+
+	if _, err := directory.GetUser(ctx, "ou_unknown"); err == nil {
+		t.Error("GetUser() should fail when the Contact API does not know the user")
+	}
+}
+
+func TestSavePropagatesRenames(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeUserRepo{users: map[string]*model.User{"ou_alice": {OpenID: "ou_alice", Name: "Alice"}}}
+	articles := &renameRecorder{}
+	directory := newTestUserDirectory(&fakeContactService{}, users, articles)
+
+	steps := []struct {
+		name string
+		user *model.User
+	}{
+		{"unchanged name", &model.User{OpenID: "ou_alice", Name: "Alice", EnName: "alice"}},
+		{"renamed", &model.User{OpenID: "ou_alice", Name: "Alice Smith"}},
+		{"empty name", &model.User{OpenID: "ou_alice"}},
+		{"new user", &model.User{OpenID: "ou_bob", Name: "Bob"}},
+	}
+	for _, step := range steps {
+		if err := directory.save(ctx, step.user); err != nil {
+			t.Fatalf("%s: save() error = %v", step.name, err)
+		}
+	}
+	if len(articles.renames) != 1 || articles.renames[0] != "ou_alice=Alice Smith" {
+		t.Errorf("renames = %v, want only ou_alice=Alice Smith", articles.renames)
+	}
+	if user, err := directory.GetUser(ctx, "ou_bob"); err != nil || user.Name != "Bob" {
+		t.Errorf("saved users should be cached, got %+v, %v", user, err)
+	}
+}
+
+func TestStopPeriodicSyncBeforeStart(t *testing.T) {
+	contact := &fakeContactService{users: map[string]*larkcontact.User{"ou_alice": contactUser("ou_alice", "Alice")}}
+	users := &fakeUserRepo{users: map[string]*model.User{}}
+	directory := newTestUserDirectory(contact, users, &renameRecorder{})
+
+	// Shutdown can stop the component before its goroutine has started
+	if err := directory.StopPeriodicSync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	directory.RunPeriodicSync(context.Background(), time.Hour)
+	if len(users.users) != 0 {
+		t.Errorf("a stopped directory should not run a full sync, synced %d users", len(users.users))
+	}
+}
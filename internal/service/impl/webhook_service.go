@@ -0,0 +1,442 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	"MikoNews/internal/pkg/crypto"
+	apperrors "MikoNews/internal/pkg/errors"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/service"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// webhook 请求头
+const (
+	WebhookHeaderSignature = "X-MikoNews-Signature" // sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+	WebhookHeaderTimestamp = "X-MikoNews-Timestamp" // 签名时的 Unix 秒级时间戳
+	WebhookHeaderEvent     = "X-MikoNews-Event"     // 事件类型，如 article.published
+	WebhookHeaderDelivery  = "X-MikoNews-Delivery"  // 投递记录ID，重新投递时不变
+)
+
+const (
+	webhookPollInterval = 5 * time.Second  // 扫描到期投递的间隔
+	webhookBatchSize    = 20               // 每次扫描最多处理的投递数
+	webhookRetryBase    = 30 * time.Second // 第一次重试的等待时间，之后每次翻倍
+	webhookRetryMax     = time.Hour        // 重试等待时间上限
+	webhookMaxErrorLen  = 512              // last_error 列的长度
+)
+
+// WebhookPayload 是投递给端点的 JSON 内容
+type WebhookPayload struct {
+	EventID    string         `json:"event_id"`
+	Event      string         `json:"event"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	TenantKey  string         `json:"tenant_key"`
+	Article    *model.Article `json:"article"`
+	URL        string         `json:"url,omitempty"` // 文章的公开访问地址，未配置 server.public_url 时为空
+}
+
+// webhookService 实现了 WebhookService 接口
+type webhookService struct {
+	repo      repository.WebhookRepository
+	conf      *config.WebhooksConfig
+	serverCfg *config.ServerConfig
+	client    *http.Client
+	now       func() time.Time
+}
+
+var _ service.WebhookService = (*webhookService)(nil)
+
+// NewWebhookService 创建一个新的 webhookService 实例
+func NewWebhookService(repo repository.WebhookRepository, conf *config.WebhooksConfig, serverCfg *config.ServerConfig) service.WebhookService {
+	return &webhookService{
+		repo:      repo,
+		conf:      conf,
+		serverCfg: serverCfg,
+		client:    &http.Client{Timeout: conf.Timeout},
+		now:       time.Now,
+	}
+}
+
+// OnArticleEvent 为订阅了该事件的端点写入投递记录，由 Run 异步发送
+func (s *webhookService) OnArticleEvent(ctx context.Context, event *service.ArticleEvent) {
+	endpoints, err := s.repo.ListEndpoints(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to list webhook endpoints", zap.Error(err))
+		return
+	}
+
+	payload := &WebhookPayload{
+		EventID:    crypto.RandomToken(16),
+		Event:      string(event.Type),
+		OccurredAt: event.OccurredAt,
+		TenantKey:  event.Article.TenantKey,
+		Article:    event.Article,
+		URL:        s.serverCfg.ArticleURL(event.Article.TenantKey, event.Article.ID),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to marshal webhook payload", zap.String("event", payload.Event), zap.Error(err))
+		return
+	}
+
+	var deliveries []*model.WebhookDelivery
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(payload.Event, payload.TenantKey) {
+			continue
+		}
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       payload.EventID,
+			Event:         payload.Event,
+			Payload:       string(body),
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: s.now(),
+		})
+	}
+	if err := s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		logger.Ctx(ctx).Error("Failed to queue webhook deliveries",
+			zap.String("event", payload.Event),
+			zap.Int64("articleID", event.Article.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	if len(deliveries) > 0 {
+		logger.Ctx(ctx).Info("Webhook deliveries queued",
+			zap.String("event", payload.Event),
+			zap.Int64("articleID", event.Article.ID),
+			zap.Int("count", len(deliveries)),
+		)
+	}
+}
+
+// SyncConfigEndpoints 将配置文件中的端点按名称同步到数据库
+func (s *webhookService) SyncConfigEndpoints(ctx context.Context) error {
+	for _, conf := range s.conf.Endpoints {
+		if conf.Name == "" || conf.URL == "" || conf.Secret == "" {
+			return fmt.Errorf("webhook 端点配置不完整，name、url、secret 均为必填 (name: %q)", conf.Name)
+		}
+		if err := validateWebhookURL(conf.URL); err != nil {
+			return fmt.Errorf("webhook 端点 %s 配置无效: %w", conf.Name, err)
+		}
+		if err := validateWebhookEvents(conf.Events); err != nil {
+			return fmt.Errorf("webhook 端点 %s 配置无效: %w", conf.Name, err)
+		}
+		endpoint := &model.WebhookEndpoint{
+			Name:      conf.Name,
+			URL:       conf.URL,
+			Secret:    conf.Secret,
+			Events:    strings.Join(conf.Events, ","),
+			TenantKey: conf.TenantKey,
+			Source:    model.WebhookSourceConfig,
+			Enabled:   true,
+		}
+		if err := s.repo.UpsertEndpointByName(ctx, endpoint); err != nil {
+			return fmt.Errorf("同步 webhook 端点 %s 失败: %w", conf.Name, err)
+		}
+	}
+	if len(s.conf.Endpoints) > 0 {
+		logger.Ctx(ctx).Info("Webhook endpoints synced from config", zap.Int("count", len(s.conf.Endpoints)))
+	}
+	return nil
+}
+
+// Run 定期扫描到期的投递记录并发送，直到 ctx 被取消
+func (s *webhookService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		s.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue 发送一批到期的投递记录
+func (s *webhookService) deliverDue(ctx context.Context) {
+	now := s.now()
+	deliveries, err := s.repo.ListDueDeliveries(ctx, now, webhookBatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Ctx(ctx).Error("Failed to list due webhook deliveries", zap.Error(err))
+		}
+		return
+	}
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		// 先把下次尝试时间推迟到请求超时之后，抢占失败说明其他实例正在投递
+		claimed, err := s.repo.ClaimDelivery(ctx, delivery, now.Add(s.conf.Timeout+webhookRetryBase))
+		if err != nil {
+			logger.Ctx(ctx).Error("Failed to claim webhook delivery", zap.Int64("deliveryID", delivery.ID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.attempt(ctx, delivery)
+	}
+}
+
+// attempt 发送一次投递并保存结果，失败时按指数退避安排重试
+func (s *webhookService) attempt(ctx context.Context, delivery *model.WebhookDelivery) {
+	endpoint, err := s.repo.FindEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// 端点已被删除，不再重试
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.LastError = "endpoint deleted"
+			s.saveDelivery(ctx, delivery)
+			return
+		}
+		logger.Ctx(ctx).Error("Failed to find webhook endpoint", zap.Int64("endpointID", delivery.EndpointID), zap.Error(err))
+		return
+	}
+
+	delivery.Attempts++
+	statusCode, sendErr := s.send(ctx, endpoint, delivery)
+	delivery.ResponseStatus = statusCode
+	if sendErr == nil {
+		deliveredAt := s.now()
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &deliveredAt
+		logger.Ctx(ctx).Info("Webhook delivered",
+			zap.Int64("deliveryID", delivery.ID),
+			zap.String("endpoint", endpoint.Name),
+			zap.String("event", delivery.Event),
+			zap.Int("attempts", delivery.Attempts),
+		)
+		s.saveDelivery(ctx, delivery)
+		return
+	}
+
+	delivery.LastError = truncateError(sendErr.Error())
+	if delivery.Attempts >= s.conf.MaxAttempts {
+		delivery.Status = model.WebhookDeliveryFailed
+		logger.Ctx(ctx).Error("Webhook delivery failed, giving up",
+			zap.Int64("deliveryID", delivery.ID),
+			zap.String("endpoint", endpoint.Name),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(sendErr),
+		)
+	} else {
+		delivery.NextAttemptAt = s.now().Add(webhookBackoff(delivery.Attempts))
+		logger.Ctx(ctx).Warn("Webhook delivery failed, will retry",
+			zap.Int64("deliveryID", delivery.ID),
+			zap.String("endpoint", endpoint.Name),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Time("nextAttemptAt", delivery.NextAttemptAt),
+			zap.Error(sendErr),
+		)
+	}
+	s.saveDelivery(ctx, delivery)
+}
+
+// send 签名并发送投递内容，返回响应状态码；非 2xx 视为失败
+func (s *webhookService) send(ctx context.Context, endpoint *model.WebhookEndpoint, delivery *model.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return 0, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MikoNews-Webhook/1.0")
+	req.Header.Set(WebhookHeaderEvent, delivery.Event)
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookHeaderTimestamp, timestamp)
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(endpoint.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("响应状态码 %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return resp.StatusCode, nil
+}
+
+// saveDelivery 保存投递结果，失败只记录日志
+func (s *webhookService) saveDelivery(ctx context.Context, delivery *model.WebhookDelivery) {
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		logger.Ctx(ctx).Error("Failed to save webhook delivery", zap.Int64("deliveryID", delivery.ID), zap.Error(err))
+	}
+}
+
+// ListEndpoints 返回所有端点
+func (s *webhookService) ListEndpoints(ctx context.Context) ([]*model.WebhookEndpoint, error) {
+	endpoints, err := s.repo.ListEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询 webhook 端点失败: %w", err)
+	}
+	return endpoints, nil
+}
+
+// CreateEndpoint 通过管理接口注册端点，未指定密钥时自动生成
+func (s *webhookService) CreateEndpoint(ctx context.Context, input *service.WebhookEndpointInput) (*model.WebhookEndpoint, string, error) {
+	if err := validateWebhookURL(input.URL); err != nil {
+		return nil, "", apperrors.NewInvalidRequestError(err.Error(), nil)
+	}
+	if err := validateWebhookEvents(input.Events); err != nil {
+		return nil, "", apperrors.NewInvalidRequestError(err.Error(), nil)
+	}
+	secret := input.Secret
+	if secret == "" {
+		secret = crypto.RandomToken(32)
+	}
+	endpoint := &model.WebhookEndpoint{
+		Name:      input.Name,
+		URL:       input.URL,
+		Secret:    secret,
+		Events:    strings.Join(input.Events, ","),
+		TenantKey: input.TenantKey,
+		Source:    model.WebhookSourceAPI,
+		Enabled:   true,
+	}
+	if err := s.repo.CreateEndpoint(ctx, endpoint); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, "", apperrors.NewInvalidRequestError(fmt.Sprintf("webhook 端点名称已存在: %s", input.Name), err)
+		}
+		return nil, "", fmt.Errorf("创建 webhook 端点失败: %w", err)
+	}
+	logger.Ctx(ctx).Info("Webhook endpoint created", zap.Int64("id", endpoint.ID), zap.String("name", endpoint.Name))
+	return endpoint, secret, nil
+}
+
+// DeleteEndpoint 删除通过管理接口注册的端点
+func (s *webhookService) DeleteEndpoint(ctx context.Context, id int64) error {
+	endpoint, err := s.findEndpoint(ctx, id)
+	if err != nil {
+		return err
+	}
+	if endpoint.Source == model.WebhookSourceConfig {
+		return apperrors.NewInvalidRequestError("配置文件中的端点不能通过接口删除，请修改配置文件", nil)
+	}
+	if err := s.repo.DeleteEndpoint(ctx, id); err != nil {
+		return fmt.Errorf("删除 webhook 端点失败: %w", err)
+	}
+	logger.Ctx(ctx).Info("Webhook endpoint deleted", zap.Int64("id", id), zap.String("name", endpoint.Name))
+	return nil
+}
+
+// ListDeliveries 返回端点最近的投递记录
+func (s *webhookService) ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]*model.WebhookDelivery, error) {
+	if _, err := s.findEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, endpointID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询 webhook 投递记录失败: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Redeliver 将投递记录重新置为待投递，并重置尝试次数
+func (s *webhookService) Redeliver(ctx context.Context, deliveryID int64) (*model.WebhookDelivery, error) {
+	delivery, err := s.repo.FindDelivery(ctx, deliveryID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperrors.NewNotFoundError(fmt.Sprintf("投递记录不存在 (ID: %d)", deliveryID), nil)
+		}
+		return nil, fmt.Errorf("查询 webhook 投递记录失败: %w", err)
+	}
+	delivery.Status = model.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = s.now()
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("重新投递失败: %w", err)
+	}
+	logger.Ctx(ctx).Info("Webhook delivery requeued", zap.Int64("deliveryID", deliveryID))
+	return delivery, nil
+}
+
+// findEndpoint 查找端点，不存在时返回 NotFound 错误
+func (s *webhookService) findEndpoint(ctx context.Context, id int64) (*model.WebhookEndpoint, error) {
+	endpoint, err := s.repo.FindEndpoint(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperrors.NewNotFoundError(fmt.Sprintf("webhook 端点不存在 (ID: %d)", id), nil)
+		}
+		return nil, fmt.Errorf("查询 webhook 端点失败: %w", err)
+	}
+	return endpoint, nil
+}
+
+// SignWebhookPayload 计算 webhook 签名：sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+// 接收方应使用同样的方式计算并以常量时间比较，同时校验时间戳以防重放
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff 返回第 attempts 次失败后的等待时间：30s、1m、2m……最长 1h
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookRetryBase
+	for i := 1; i < attempts && backoff < webhookRetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookRetryMax)
+}
+
+// validateWebhookURL 校验端点地址为 http(s) 绝对地址
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("无效的 webhook 地址: %s", raw)
+	}
+	return nil
+}
+
+// validateWebhookEvents 校验订阅的事件类型
+func validateWebhookEvents(events []string) error {
+	for _, event := range events {
+		known := false
+		for _, eventType := range service.ArticleEventTypes() {
+			if event == string(eventType) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("未知的 webhook 事件: %s", event)
+		}
+	}
+	return nil
+}
+
+// truncateError 截断错误信息以适应 last_error 列
+func truncateError(msg string) string {
+	runes := []rune(msg)
+	if len(runes) <= webhookMaxErrorLen {
+		return msg
+	}
+	return string(runes[:webhookMaxErrorLen])
+}
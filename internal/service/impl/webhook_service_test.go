@@ -0,0 +1,313 @@
+package impl
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/model"
+	apperrors "MikoNews/internal/pkg/errors"
+	"MikoNews/internal/repository"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeWebhookRepo keeps endpoints and deliveries in memory.
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+	endpoints  map[int64]*model.WebhookEndpoint
+	deliveries map[int64]*model.WebhookDelivery
+	nextID     int64
+}
+
+func newFakeWebhookRepo(endpoints ...*model.WebhookEndpoint) *fakeWebhookRepo {
+	repo := &fakeWebhookRepo{endpoints: map[int64]*model.WebhookEndpoint{}, deliveries: map[int64]*model.WebhookDelivery{}}
+	for _, endpoint := range endpoints {
+		repo.endpoints[endpoint.ID] = endpoint
+	}
+	return repo
+}
+
+func (r *fakeWebhookRepo) ListEndpoints(context.Context) ([]*model.WebhookEndpoint, error) {
+	var endpoints []*model.WebhookEndpoint
+	for _, endpoint := range r.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+func (r *fakeWebhookRepo) FindEndpoint(_ context.Context, id int64) (*model.WebhookEndpoint, error) {
+	if endpoint, ok := r.endpoints[id]; ok {
+		return endpoint, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeWebhookRepo) CreateEndpoint(_ context.Context, endpoint *model.WebhookEndpoint) error {
+	for _, existing := range r.endpoints {
+		if existing.Name == endpoint.Name {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	r.nextID++
+	endpoint.ID = r.nextID
+	r.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+func (r *fakeWebhookRepo) CreateDeliveries(_ context.Context, deliveries []*model.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		r.nextID++
+		delivery.ID = r.nextID
+		r.deliveries[delivery.ID] = delivery
+	}
+	return nil
+}
+
+func (r *fakeWebhookRepo) FindDelivery(_ context.Context, id int64) (*model.WebhookDelivery, error) {
+	if delivery, ok := r.deliveries[id]; ok {
+		copied := *delivery
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeWebhookRepo) UpdateDelivery(_ context.Context, delivery *model.WebhookDelivery) error {
+	copied := *delivery
+	r.deliveries[delivery.ID] = &copied
+	return nil
+}
+
+func (r *fakeWebhookRepo) ListDueDeliveries(_ context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	var due []*model.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == model.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			copied := *delivery
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeWebhookRepo) ClaimDelivery(_ context.Context, delivery *model.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	stored := r.deliveries[delivery.ID]
+	if !stored.NextAttemptAt.Equal(delivery.NextAttemptAt) {
+		return false, nil
+	}
+	stored.NextAttemptAt = leaseUntil
+	delivery.NextAttemptAt = leaseUntil
+	return true, nil
+}
+
+// webhookReceiver is an httptest endpoint that answers with the queued status codes and records the requests.
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   []string
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	receiver := &webhookReceiver{statuses: statuses}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, r)
+		receiver.bodies = append(receiver.bodies, string(body))
+		status := http.StatusOK
+		if len(receiver.statuses) > 0 {
+			status, receiver.statuses = receiver.statuses[0], receiver.statuses[1:]
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("receiver says " + strconv.Itoa(status)))
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func newTestWebhookService(repo repository.WebhookRepository, maxAttempts int, now *time.Time) *webhookService {
+	s := NewWebhookService(repo, &config.WebhooksConfig{MaxAttempts: maxAttempts, Timeout: time.Second}, &config.ServerConfig{PublicURL: "https://news.example.com"}).(*webhookService)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	got := SignWebhookPayload("secret", "1700000000", []byte(`{"a":1}`))
+	if want := "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686"; got != want {
+		t.Errorf("SignWebhookPayload() = %s, want %s", got, want)
+	}
+	if SignWebhookPayload("other", "1700000000", []byte(`{"a":1}`)) == got {
+		t.Error("the signature should depend on the secret")
+	}
+	if SignWebhookPayload("secret", "1700000001", []byte(`{"a":1}`)) == got {
+		t.Error("the signature should depend on the timestamp")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour}, // 64m is capped
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	receiver := newWebhookReceiver(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusNoContent)
+	endpoint := &model.WebhookEndpoint{ID: 1, Name: "receiver", URL: receiver.URL, Secret: "secret", Enabled: true}
+	repo := newFakeWebhookRepo(endpoint)
+	s := newTestWebhookService(repo, 5, &now)
+
+	s.OnArticleEvent(ctx, &service.ArticleEvent{
+		Type:       service.ArticleEventPublished,
+		Article:    &model.Article{ID: 7, TenantKey: "tenant_a", Title: "周报"},
+		OccurredAt: now,
+	})
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("queued %d deliveries, want 1", len(repo.deliveries))
+	}
+	var id int64
+	for id = range repo.deliveries {
+	}
+
+	// The first attempt fails and is retried after the base backoff
+	s.deliverDue(ctx)
+	delivery := repo.deliveries[id]
+	if delivery.Status != model.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusInternalServerError {
+		t.Fatalf("after a failed attempt: %+v", delivery)
+	}
+	if want := now.Add(30 * time.Second); !delivery.NextAttemptAt.Equal(want) {
+		t.Errorf("NextAttemptAt = %v, want %v", delivery.NextAttemptAt, want)
+	}
+	if !strings.Contains(delivery.LastError, "500") || !strings.Contains(delivery.LastError, "receiver says 500") {
+		t.Errorf("LastError = %q, want the status and response body", delivery.LastError)
+	}
+
+	// Not due yet
+	s.deliverDue(ctx)
+	if len(receiver.requests) != 1 {
+		t.Fatalf("a delivery that is not due should not be sent, got %d requests", len(receiver.requests))
+	}
+
+	now = now.Add(30 * time.Second)
+	s.deliverDue(ctx)
+	if delivery = repo.deliveries[id]; delivery.Attempts != 2 || !delivery.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("after the second failure: %+v", delivery)
+	}
+
+	now = now.Add(time.Minute)
+	s.deliverDue(ctx)
+	delivery = repo.deliveries[id]
+	if delivery.Status != model.WebhookDeliverySucceeded || delivery.Attempts != 3 || delivery.LastError != "" ||
+		delivery.DeliveredAt == nil || !delivery.DeliveredAt.Equal(now) {
+		t.Fatalf("after a successful attempt: %+v", delivery)
+	}
+
+	// Every request is signed and carries the same delivery ID
+	for i, r := range receiver.requests {
+		timestamp := r.Header.Get(WebhookHeaderTimestamp)
+		if got, want := r.Header.Get(WebhookHeaderSignature), SignWebhookPayload("secret", timestamp, []byte(receiver.bodies[i])); got != want {
+			t.Errorf("request %d: signature %s, want %s", i, got, want)
+		}
+		if r.Header.Get(WebhookHeaderEvent) != "article.published" || r.Header.Get(WebhookHeaderDelivery) != strconv.FormatInt(id, 10) {
+			t.Errorf("request %d: headers %v", i, r.Header)
+		}
+	}
+	if body := receiver.bodies[0]; !strings.Contains(body, `"tenant_key":"tenant_a"`) ||
+		!strings.Contains(body, `"url":"https://news.example.com/api/v1/articles/7?tenant=tenant_a"`) {
+		t.Errorf("payload = %s", body)
+	}
+}
+
+func TestWebhookDeliveryGivesUp(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	receiver := newWebhookReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	repo := newFakeWebhookRepo(&model.WebhookEndpoint{ID: 1, Name: "receiver", URL: receiver.URL, Secret: "secret", Enabled: true})
+	s := newTestWebhookService(repo, 2, &now)
+	delivery := &model.WebhookDelivery{ID: 10, EndpointID: 1, Event: "article.published", Payload: "{}", Status: model.WebhookDeliveryPending, NextAttemptAt: now}
+	repo.deliveries[delivery.ID] = delivery
+
+	s.attempt(ctx, delivery)
+	if got := repo.deliveries[10]; got.Status != model.WebhookDeliveryPending || got.Attempts != 1 {
+		t.Fatalf("after the first failure: %+v", got)
+	}
+	s.attempt(ctx, delivery)
+	if got := repo.deliveries[10]; got.Status != model.WebhookDeliveryFailed || got.Attempts != 2 || got.DeliveredAt != nil {
+		t.Fatalf("the delivery should fail after max attempts: %+v", got)
+	}
+
+	// A delivery whose endpoint was deleted fails without being sent
+	orphan := &model.WebhookDelivery{ID: 11, EndpointID: 99, Status: model.WebhookDeliveryPending, NextAttemptAt: now}
+	s.attempt(ctx, orphan)
+	if got := repo.deliveries[11]; got.Status != model.WebhookDeliveryFailed || got.LastError != "endpoint deleted" || got.Attempts != 0 {
+		t.Errorf("delivery to a deleted endpoint: %+v", got)
+	}
+	if len(receiver.requests) != 2 {
+		t.Errorf("receiver got %d requests, want 2", len(receiver.requests))
+	}
+}
+
+func TestWebhookRedeliver(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newFakeWebhookRepo()
+	s := newTestWebhookService(repo, 3, &now)
+	repo.deliveries[5] = &model.WebhookDelivery{ID: 5, EndpointID: 1, Status: model.WebhookDeliveryFailed, Attempts: 3, LastError: "boom", NextAttemptAt: now.Add(-time.Hour)}
+
+	now = now.Add(time.Hour)
+	delivery, err := s.Redeliver(ctx, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := repo.deliveries[5]
+	for _, d := range []*model.WebhookDelivery{delivery, stored} {
+		if d.Status != model.WebhookDeliveryPending || d.Attempts != 0 || !d.NextAttemptAt.Equal(now) {
+			t.Errorf("redelivered: %+v", d)
+		}
+	}
+
+	var appErr *apperrors.AppError
+	if _, err := s.Redeliver(ctx, 404); !errors.As(err, &appErr) || appErr.Code != apperrors.ErrCodeNotFound {
+		t.Errorf("Redeliver() of a missing delivery = %v, want a not found error", err)
+	}
+}
+
+func TestCreateWebhookEndpointDuplicateName(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s := newTestWebhookService(newFakeWebhookRepo(), 3, &now)
+	input := &service.WebhookEndpointInput{Name: "ci", URL: "https://ci.example.com/hook"}
+	endpoint, secret, err := s.CreateEndpoint(ctx, input)
+	if err != nil || endpoint.Secret != secret || len(secret) != 64 {
+		t.Fatalf("CreateEndpoint() = %+v, %q, %v", endpoint, secret, err)
+	}
+
+	var appErr *apperrors.AppError
+	if _, _, err := s.CreateEndpoint(ctx, input); !errors.As(err, &appErr) || appErr.Code != apperrors.ErrCodeInvalidRequest {
+		t.Errorf("CreateEndpoint() with a duplicate name = %v, want an invalid request error", err)
+	}
+}
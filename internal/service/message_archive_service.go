@@ -0,0 +1,21 @@
+package service
+
+import (
+	"MikoNews/internal/model"
+	"context"
+)
+
+// ArchiveResult is the outcome of archiving a message.
+type ArchiveResult struct {
+	Article      *model.Article // the new article, or the existing one the message was merged into or saved as
+	Merged       bool           // whether the message duplicated an existing article and was merged into it
+	AlreadySaved bool           // whether the message itself had already been archived, e.g. by a redelivered event
+}
+
+// MessageArchiveService archives an existing Feishu message as an article on behalf of its sender.
+type MessageArchiveService interface {
+	// ArchiveMessage fetches the message, saves it as an article authored by the original sender
+	// with curatorID recorded as the curator, and forwards it to the configured group chats.
+	// A message duplicating an existing article is merged into it instead, crediting the sender as a source.
+	ArchiveMessage(ctx context.Context, messageID, curatorID string) (*ArchiveResult, error)
+}
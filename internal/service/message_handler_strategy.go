@@ -6,7 +6,8 @@ import (
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 )
 
-// MessageHandlerStrategy defines the interface for handling different P2P message types based on content.
+// MessageHandlerStrategy defines the interface for handling different message types based on content,
+// covering both P2P messages and @-mention commands in group chats.
 type MessageHandlerStrategy interface {
 	// ShouldHandle determines if this strategy is applicable to the given message event.
 	// It should check message type, content patterns, etc.
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"MikoNews/internal/model"
+	"context"
+)
+
+// Publisher posts published articles to an outbound destination, such as Feishu group chats or Telegram channels.
+type Publisher interface {
+	// Name identifies the destination in logs.
+	Name() string
+
+	// Publish posts the article. Implementations deliver to as many targets as possible
+	// and return an error describing the targets that failed.
+	Publish(ctx context.Context, article *model.Article) error
+}
@@ -0,0 +1,17 @@
+package service
+
+import "context"
+
+// PolicyDecision is the outcome of evaluating the submission policy for a user.
+type PolicyDecision struct {
+	Allowed        bool   // whether the user may submit
+	ReviewRequired bool   // whether the submission must be approved by an admin before it is forwarded
+	Rule           string // name of the matched rule, empty if the default applied
+	Message        string // explanation replied to the user when the submission is rejected
+}
+
+// SubmissionPolicyService decides who may submit articles, based on the allow/deny rules in the config.
+type SubmissionPolicyService interface {
+	// Evaluate returns the policy decision for the user identified by openID.
+	Evaluate(ctx context.Context, openID string) (*PolicyDecision, error)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitResult is the outcome of a submission quota check.
+type RateLimitResult struct {
+	Allowed bool          // whether the user may submit now
+	Limit   int           // the exceeded limit, set when not allowed
+	Window  time.Duration // the window of the exceeded limit, e.g. one hour or one day
+	ResetAt time.Time     // when the exceeded window resets
+}
+
+// SubmissionRateLimiter enforces per-user submission quotas (N per hour, M per day), configurable by role.
+type SubmissionRateLimiter interface {
+	// Check reports whether the user identified by openID is within quota. It does not consume quota, so it only
+	// rejects early; concurrent submissions may all pass it, and Acquire makes the final decision.
+	Check(ctx context.Context, openID string) (*RateLimitResult, error)
+
+	// Acquire atomically consumes one submission from the user's quota. When a window is exhausted nothing is
+	// consumed and the result is not allowed, so concurrent submissions never exceed the quota.
+	Acquire(ctx context.Context, openID string) (*RateLimitResult, error)
+
+	// Release returns a submission acquired by Acquire, e.g. when saving the submission failed.
+	Release(ctx context.Context, openID string) error
+}
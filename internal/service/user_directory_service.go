@@ -0,0 +1,33 @@
+package service
+
+import (
+	"MikoNews/internal/model"
+	"context"
+	"time"
+
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+)
+
+// UserDirectoryService resolves Feishu users from a local directory kept in sync with the contact API,
+// so that author names, avatars and departments do not require a Contact API call per message.
+type UserDirectoryService interface {
+	// GetUser returns the user from the in-memory cache or the local users table,
+	// falling back to the Contact API (and persisting the result) for users not synced yet.
+	GetUser(ctx context.Context, openID string) (*model.User, error)
+
+	// SyncUser applies an incremental change from a contact user created/updated event.
+	// Renames are propagated to the author names of existing articles.
+	SyncUser(ctx context.Context, user *larkcontact.UserEvent) error
+
+	// RemoveUser marks a user as resigned after a contact user deleted event.
+	RemoveUser(ctx context.Context, openID string) error
+
+	// SyncAll performs a full sync of every user visible to the app and returns the number of users synced.
+	SyncAll(ctx context.Context) (int, error)
+
+	// RunPeriodicSync performs a full sync every interval until ctx is cancelled or StopPeriodicSync is called.
+	RunPeriodicSync(ctx context.Context, interval time.Duration)
+
+	// StopPeriodicSync stops scheduling full syncs and waits until the running one finishes or ctx ends.
+	StopPeriodicSync(ctx context.Context) error
+}
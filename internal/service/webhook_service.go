@@ -0,0 +1,42 @@
+package service
+
+import (
+	"MikoNews/internal/model"
+	"context"
+)
+
+// WebhookEndpointInput describes an endpoint registered through the admin API.
+type WebhookEndpointInput struct {
+	Name      string   `json:"name" binding:"required"`
+	URL       string   `json:"url" binding:"required"`
+	Secret    string   `json:"secret"`     // generated when empty
+	Events    []string `json:"events"`     // empty subscribes to all events
+	TenantKey string   `json:"tenant_key"` // empty subscribes to all tenants
+}
+
+// WebhookService delivers signed article lifecycle events to registered endpoints.
+// It is an ArticleEventListener: events are recorded in the delivery log and sent by Run, with retries.
+type WebhookService interface {
+	ArticleEventListener
+
+	// SyncConfigEndpoints upserts the endpoints declared in the config file, matched by name.
+	SyncConfigEndpoints(ctx context.Context) error
+
+	// Run delivers due deliveries until ctx is cancelled.
+	Run(ctx context.Context) error
+
+	// ListEndpoints returns all registered endpoints.
+	ListEndpoints(ctx context.Context) ([]*model.WebhookEndpoint, error)
+
+	// CreateEndpoint registers an endpoint. The returned endpoint carries the secret, which is only shown once.
+	CreateEndpoint(ctx context.Context, input *WebhookEndpointInput) (*model.WebhookEndpoint, string, error)
+
+	// DeleteEndpoint removes an endpoint registered through the API. Config endpoints must be removed from the config file.
+	DeleteEndpoint(ctx context.Context, id int64) error
+
+	// ListDeliveries returns the most recent deliveries of an endpoint.
+	ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]*model.WebhookDelivery, error)
+
+	// Redeliver queues a delivery to be sent again immediately, regardless of its current status.
+	Redeliver(ctx context.Context, deliveryID int64) (*model.WebhookDelivery, error)
+}
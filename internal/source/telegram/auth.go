@@ -0,0 +1,26 @@
+package telegram
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gotd/td/tg"
+	"github.com/mattn/go-isatty"
+)
+
+// promptCode 在终端提示输入登录验证码，仅在首次以用户身份登录或会话失效时需要。
+// 标准输入不是终端（如后台运行的容器）时无法输入验证码，直接返回错误而不是一直等待
+func promptCode(_ context.Context, _ *tg.AuthSentCode) (string, error) {
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("标准输入不是终端，无法输入 telegram 登录验证码")
+	}
+	fmt.Print("请输入 Telegram 登录验证码: ")
+	code, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("读取验证码失败: %w", err)
+	}
+	return strings.TrimSpace(code), nil
+}
@@ -0,0 +1,24 @@
+package telegram
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestPromptCodeWithoutTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("创建管道失败: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	// 标准输入不是终端时立即返回错误，而不是等待输入
+	if _, err := promptCode(context.Background(), nil); err == nil {
+		t.Error("标准输入不是终端时应返回错误")
+	}
+}
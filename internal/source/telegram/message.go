@@ -0,0 +1,83 @@
+package telegram
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/service"
+	"fmt"
+	"strings"
+
+	"github.com/gotd/td/tg"
+)
+
+// channelIDOffset 用于将频道和超级群组的 ID 转换为 Bot API 格式的 Chat ID (-100xxxxxxxxxx)
+const channelIDOffset = -1000000000000
+
+// chatIDFromPeer 将消息所在的会话转换为 Bot API 格式的 Chat ID，私聊消息返回 false
+func chatIDFromPeer(peer tg.PeerClass) (int64, bool) {
+	switch p := peer.(type) {
+	case *tg.PeerChannel:
+		return channelIDOffset - p.ChannelID, true
+	case *tg.PeerChat:
+		return -p.ChatID, true
+	default:
+		return 0, false
+	}
+}
+
+// toSubmission 将 Telegram 消息转换为投稿，没有文本的消息（如纯图片）返回 nil
+func toSubmission(e tg.Entities, chatID int64, msg *tg.Message) *service.ExternalSubmission {
+	text := strings.TrimSpace(msg.Message)
+	if text == "" {
+		return nil
+	}
+	authorID, authorName := sender(e, msg)
+	return &service.ExternalSubmission{
+		Source:     model.ArticleSourceTelegram,
+		AuthorID:   authorID,
+		AuthorName: authorName,
+		Text:       text,
+		Reference:  fmt.Sprintf("telegram:%d/%d", chatID, msg.ID),
+	}
+}
+
+// sender 返回消息发送者的标识和名字；频道消息以频道作为发送者
+func sender(e tg.Entities, msg *tg.Message) (string, string) {
+	from := msg.FromID
+	if from == nil {
+		from = msg.PeerID
+	}
+	switch p := from.(type) {
+	case *tg.PeerUser:
+		id := fmt.Sprintf("tg:user:%d", p.UserID)
+		if user, ok := e.Users[p.UserID]; ok {
+			return id, userName(user)
+		}
+		return id, fmt.Sprintf("Telegram 用户 %d", p.UserID)
+	case *tg.PeerChannel:
+		id := fmt.Sprintf("tg:channel:%d", p.ChannelID)
+		if channel, ok := e.Channels[p.ChannelID]; ok && channel.Title != "" {
+			return id, channel.Title
+		}
+		return id, fmt.Sprintf("Telegram 频道 %d", p.ChannelID)
+	case *tg.PeerChat:
+		id := fmt.Sprintf("tg:chat:%d", p.ChatID)
+		if chat, ok := e.Chats[p.ChatID]; ok && chat.Title != "" {
+			return id, chat.Title
+		}
+		return id, fmt.Sprintf("Telegram 群组 %d", p.ChatID)
+	default:
+		return "tg:unknown", "Telegram 用户"
+	}
+}
+
+// userName 返回 Telegram 用户的显示名，没有姓名时使用用户名
+func userName(user *tg.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name != "" {
+		return name
+	}
+	if user.Username != "" {
+		return "@" + user.Username
+	}
+	return fmt.Sprintf("Telegram 用户 %d", user.ID)
+}
@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"MikoNews/internal/model"
+	"MikoNews/internal/service"
+	"reflect"
+	"testing"
+
+	"github.com/gotd/td/tg"
+)
+
+func TestChatIDFromPeer(t *testing.T) {
+	tests := []struct {
+		name   string
+		peer   tg.PeerClass
+		want   int64
+		wantOK bool
+	}{
+		{"频道", &tg.PeerChannel{ChannelID: 1234567890}, -1001234567890, true},
+		{"普通群组", &tg.PeerChat{ChatID: 42}, -42, true},
+		{"私聊", &tg.PeerUser{UserID: 42}, 0, false},
+		{"空会话", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := chatIDFromPeer(tt.peer)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("chatIDFromPeer() = %d, %v，期望 %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestToSubmission(t *testing.T) {
+	e := tg.Entities{Users: map[int64]*tg.User{7: {ID: 7, FirstName: "Alice"}}}
+	msg := &tg.Message{
+		ID:      15,
+		FromID:  &tg.PeerUser{UserID: 7},
+		PeerID:  &tg.PeerChannel{ChannelID: 99},
+		Message: "  分享一篇文章 https://example.com  ",
+	}
+	want := &service.ExternalSubmission{
+		Source:     model.ArticleSourceTelegram,
+		AuthorID:   "tg:user:7",
+		AuthorName: "Alice",
+		Text:       "分享一篇文章 https://example.com",
+		Reference:  "telegram:-1000000000099/15",
+	}
+	if got := toSubmission(e, -1000000000099, msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("toSubmission() = %+v，期望 %+v", got, want)
+	}
+
+	// 纯图片等没有文本的消息不作为投稿
+	if got := toSubmission(e, -1000000000099, &tg.Message{ID: 16, Message: " \n "}); got != nil {
+		t.Errorf("没有文本的消息应返回 nil，实际 %+v", got)
+	}
+}
+
+func TestSender(t *testing.T) {
+	e := tg.Entities{
+		Users: map[int64]*tg.User{
+			1: {ID: 1, FirstName: "Alice", LastName: "Smith"},
+			2: {ID: 2, Username: "bob"},
+			3: {ID: 3},
+		},
+		Channels: map[int64]*tg.Channel{10: {ID: 10, Title: "Tech News"}},
+		Chats:    map[int64]*tg.Chat{20: {ID: 20, Title: "讨论组"}},
+	}
+	tests := []struct {
+		name     string
+		msg      *tg.Message
+		wantID   string
+		wantName string
+	}{
+		{"用户全名", &tg.Message{FromID: &tg.PeerUser{UserID: 1}}, "tg:user:1", "Alice Smith"},
+		{"只有用户名", &tg.Message{FromID: &tg.PeerUser{UserID: 2}}, "tg:user:2", "@bob"},
+		{"没有名字的用户", &tg.Message{FromID: &tg.PeerUser{UserID: 3}}, "tg:user:3", "Telegram 用户 3"},
+		{"未知用户", &tg.Message{FromID: &tg.PeerUser{UserID: 4}}, "tg:user:4", "Telegram 用户 4"},
+		{"频道消息以频道作为发送者", &tg.Message{PeerID: &tg.PeerChannel{ChannelID: 10}}, "tg:channel:10", "Tech News"},
+		{"未知频道", &tg.Message{PeerID: &tg.PeerChannel{ChannelID: 11}}, "tg:channel:11", "Telegram 频道 11"},
+		{"以群组身份发言", &tg.Message{FromID: &tg.PeerChat{ChatID: 20}, PeerID: &tg.PeerChannel{ChannelID: 10}}, "tg:chat:20", "讨论组"},
+		{"未知群组", &tg.Message{FromID: &tg.PeerChat{ChatID: 21}}, "tg:chat:21", "Telegram 群组 21"},
+		{"没有发送者", &tg.Message{}, "tg:unknown", "Telegram 用户"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, name := sender(e, tt.msg)
+			if id != tt.wantID || name != tt.wantName {
+				t.Errorf("sender() = %q, %q，期望 %q, %q", id, name, tt.wantID, tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package telegram 监听 Telegram 群组或频道中的消息，并将其作为投稿收录到 MikoNews
+package telegram
+
+import (
+	"MikoNews/internal/config"
+	"MikoNews/internal/pkg/logger"
+	"MikoNews/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gotd/td/session"
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/telegram/auth"
+	"github.com/gotd/td/telegram/updates"
+	"github.com/gotd/td/tg"
+	"go.uber.org/zap"
+)
+
+// errNotLoggedIn 表示以用户身份登录的会话不存在或已失效，需要先通过 telegram-login 命令交互式登录
+var errNotLoggedIn = errors.New("telegram 账号未登录，请先执行 `miko_news telegram-login` 输入验证码完成登录")
+
+// Source 是 Telegram 投稿来源，登录后监听配置的群组和频道
+type Source struct {
+	conf        *config.TelegramConfig
+	submissions service.ExternalSubmissionService
+	chats       map[int64]bool
+}
+
+// New 创建 Telegram 投稿来源，消息通过 submissions 保存为文章并转发到飞书群
+func New(conf *config.TelegramConfig, submissions service.ExternalSubmissionService) *Source {
+	chats := make(map[int64]bool, len(conf.Chats))
+	for _, id := range conf.Chats {
+		chats[id] = true
+	}
+	return &Source{
+		conf:        conf,
+		submissions: submissions,
+		chats:       chats,
+	}
+}
+
+// Run 登录 Telegram 并持续接收消息，直到 ctx 被取消
+// 登录会话保存在 session_file 中。以用户身份登录时不会在此提示输入验证码，会话不存在或失效时返回错误
+func (s *Source) Run(ctx context.Context) error {
+	if err := s.checkConfig(); err != nil {
+		return err
+	}
+
+	dispatcher := tg.NewUpdateDispatcher()
+	dispatcher.OnNewMessage(func(ctx context.Context, e tg.Entities, update *tg.UpdateNewMessage) error {
+		s.handleMessage(ctx, e, update.Message)
+		return nil
+	})
+	dispatcher.OnNewChannelMessage(func(ctx context.Context, e tg.Entities, update *tg.UpdateNewChannelMessage) error {
+		s.handleMessage(ctx, e, update.Message)
+		return nil
+	})
+	gaps := updates.New(updates.Config{Handler: dispatcher})
+	client := s.newClient(gaps)
+
+	return client.Run(ctx, func(ctx context.Context) error {
+		if err := s.login(ctx, client); err != nil {
+			return err
+		}
+		self, err := client.Self(ctx)
+		if err != nil {
+			return fmt.Errorf("获取 telegram 账号信息失败: %w", err)
+		}
+
+		logger.Ctx(ctx).Info("Telegram source started", zap.Int64("selfID", self.ID), zap.Int64s("chats", s.conf.Chats))
+		return gaps.Run(ctx, client.API(), self.ID, updates.AuthOptions{IsBot: self.Bot})
+	})
+}
+
+// Login 在终端交互式登录并保存会话，供 telegram-login 命令使用。以用户身份登录时需要输入验证码
+func (s *Source) Login(ctx context.Context) error {
+	if err := s.checkConfig(); err != nil {
+		return err
+	}
+	client := s.newClient(nil)
+	return client.Run(ctx, func(ctx context.Context) error {
+		if s.conf.BotToken != "" {
+			return s.login(ctx, client)
+		}
+		flow := auth.NewFlow(
+			auth.Constant(s.conf.Phone, s.conf.Password, auth.CodeAuthenticatorFunc(promptCode)),
+			auth.SendCodeOptions{},
+		)
+		if err := client.Auth().IfNecessary(ctx, flow); err != nil {
+			return fmt.Errorf("telegram 登录失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkConfig 校验登录所需的配置并创建会话目录
+func (s *Source) checkConfig() error {
+	if s.conf.AppID == 0 || s.conf.AppHash == "" {
+		return fmt.Errorf("telegram 缺少 app_id 或 app_hash")
+	}
+	if s.conf.BotToken == "" && s.conf.Phone == "" {
+		return fmt.Errorf("telegram 需要配置 bot_token 或 phone")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.conf.SessionFile), 0o700); err != nil {
+		return fmt.Errorf("创建 telegram 会话目录失败: %w", err)
+	}
+	return nil
+}
+
+// newClient 创建使用 session_file 保存会话的客户端，handler 为 nil 时不处理更新
+func (s *Source) newClient(handler telegram.UpdateHandler) *telegram.Client {
+	return telegram.NewClient(s.conf.AppID, s.conf.AppHash, telegram.Options{
+		SessionStorage: &session.FileStorage{Path: s.conf.SessionFile},
+		UpdateHandler:  handler,
+	})
+}
+
+// login 在会话失效时以机器人令牌重新登录；以用户身份登录需要验证码，只检查会话是否有效
+func (s *Source) login(ctx context.Context, client *telegram.Client) error {
+	status, err := client.Auth().Status(ctx)
+	if err != nil {
+		return fmt.Errorf("获取 telegram 登录状态失败: %w", err)
+	}
+	if status.Authorized {
+		return nil
+	}
+	if s.conf.BotToken == "" {
+		return errNotLoggedIn
+	}
+	if _, err := client.Auth().Bot(ctx, s.conf.BotToken); err != nil {
+		return fmt.Errorf("telegram 机器人登录失败: %w", err)
+	}
+	return nil
+}
+
+// handleMessage 将来自监听群组的文本消息提交为投稿，失败只记录日志，不影响后续消息
+func (s *Source) handleMessage(ctx context.Context, e tg.Entities, message tg.MessageClass) {
+	msg, ok := message.(*tg.Message)
+	if !ok {
+		return
+	}
+	chatID, ok := chatIDFromPeer(msg.PeerID)
+	if !ok || !s.chats[chatID] {
+		return
+	}
+	submission := toSubmission(e, chatID, msg)
+	if submission == nil {
+		return
+	}
+
+	result, err := s.submissions.Submit(ctx, submission)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to submit telegram message",
+			zap.String("reference", submission.Reference),
+			zap.Error(err),
+		)
+		return
+	}
+	logger.Ctx(ctx).Info("Telegram message archived",
+		zap.String("reference", submission.Reference),
+		zap.Int64("articleID", result.Article.ID),
+		zap.Bool("merged", result.Merged),
+	)
+}
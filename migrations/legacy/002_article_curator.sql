@@ -0,0 +1,5 @@
+-- 为已有部署添加代为收录者字段（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+ALTER TABLE articles
+    ADD COLUMN curator_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '代为收录者飞书OpenID，本人投稿时为空' AFTER author_name;
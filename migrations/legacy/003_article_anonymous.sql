@@ -0,0 +1,6 @@
+-- 为已有部署添加匿名投稿字段（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+ALTER TABLE articles
+    ADD COLUMN is_anonymous TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否匿名投稿' AFTER curator_id,
+    ADD COLUMN author_id_encrypted VARCHAR(255) NOT NULL DEFAULT '' COMMENT '匿名投稿时加密保存的真实作者OpenID' AFTER is_anonymous;
@@ -0,0 +1,18 @@
+-- 为已有部署添加文章署名表（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+CREATE TABLE IF NOT EXISTS article_authors (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id BIGINT NOT NULL COMMENT '文章ID',
+    open_id VARCHAR(64) NOT NULL COMMENT '成员飞书OpenID',
+    name VARCHAR(64) NOT NULL DEFAULT '' COMMENT '成员名字',
+    role VARCHAR(16) NOT NULL COMMENT '署名角色: author/co-author/source',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY uk_article_member_role (article_id, open_id, role),
+    INDEX idx_open_id (open_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='文章署名表';
+
+-- 回填已有文章的作者署名（匿名投稿除外）
+INSERT IGNORE INTO article_authors (article_id, open_id, name, role, created_at)
+SELECT id, author_id, author_name, 'author', created_at FROM articles
+WHERE is_anonymous = 0 AND author_id <> '';
@@ -0,0 +1,16 @@
+-- 为已有部署添加用户目录表（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+CREATE TABLE IF NOT EXISTS users (
+    open_id VARCHAR(64) PRIMARY KEY COMMENT '用户飞书OpenID',
+    union_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '用户UnionID',
+    name VARCHAR(64) NOT NULL DEFAULT '' COMMENT '用户名',
+    en_name VARCHAR(64) NOT NULL DEFAULT '' COMMENT '英文名',
+    avatar_url VARCHAR(512) NOT NULL DEFAULT '' COMMENT '头像链接',
+    department_ids VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '所属部门open_department_id，逗号分隔',
+    status VARCHAR(16) NOT NULL DEFAULT 'active' COMMENT '用户状态: active/resigned',
+    synced_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '最近一次从飞书同步的时间',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_union_id (union_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='飞书通讯录用户表';
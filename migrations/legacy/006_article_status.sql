@@ -0,0 +1,7 @@
+-- 为已有部署添加文章审核状态（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+ALTER TABLE articles
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published' COMMENT '文章状态: pending/published/rejected' AFTER author_id_encrypted,
+    ADD COLUMN raw_content MEDIUMTEXT NULL COMMENT '原始富文本JSON，审核通过后据此转发' AFTER status,
+    ADD INDEX idx_status (status);
@@ -0,0 +1,11 @@
+-- 为已有部署添加投稿限流计数表（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+CREATE TABLE IF NOT EXISTS rate_limit_counters (
+    counter_key VARCHAR(128) NOT NULL COMMENT '计数键，如 submission:hour:ou_xxx',
+    window_start TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '窗口开始时间',
+    count INT NOT NULL DEFAULT 0 COMMENT '窗口内的计数',
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '窗口结束后可清理的时间',
+    PRIMARY KEY (counter_key, window_start),
+    INDEX idx_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='投稿限流计数表';
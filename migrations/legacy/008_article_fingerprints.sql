@@ -0,0 +1,16 @@
+-- 为已有部署添加查重指纹（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+-- 已有文章没有指纹，只有升级后的新投稿参与查重
+USE miko_news;
+
+ALTER TABLE articles
+    ADD COLUMN simhash BIGINT NOT NULL DEFAULT 0 COMMENT '正文SimHash，用于近似查重' AFTER raw_content;
+
+CREATE TABLE IF NOT EXISTS article_fingerprints (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id BIGINT NOT NULL COMMENT '文章ID',
+    kind VARCHAR(16) NOT NULL COMMENT '指纹类型: url/simhash',
+    value VARCHAR(255) NOT NULL COMMENT '指纹值: 规范化链接或SimHash分段',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_article (article_id),
+    INDEX idx_kind_value (kind, value)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='文章查重指纹表';
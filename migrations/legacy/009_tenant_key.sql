@@ -0,0 +1,11 @@
+-- 为已有部署添加租户标识（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+-- 已有数据归属默认租户（tenant_key 为空），单应用部署无需修改配置
+USE miko_news;
+
+ALTER TABLE articles
+    ADD COLUMN tenant_key VARCHAR(64) NOT NULL DEFAULT '' COMMENT '所属飞书租户标识' AFTER id,
+    ADD INDEX idx_tenant (tenant_key);
+
+ALTER TABLE users
+    ADD COLUMN tenant_key VARCHAR(64) NOT NULL DEFAULT '' COMMENT '所属飞书租户标识' AFTER open_id,
+    ADD INDEX idx_tenant (tenant_key);
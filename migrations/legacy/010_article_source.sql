@@ -0,0 +1,5 @@
+-- 为已有部署添加投稿来源（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+ALTER TABLE articles
+    ADD COLUMN source VARCHAR(32) NOT NULL DEFAULT 'feishu' COMMENT '投稿来源: feishu/telegram' AFTER author_id_encrypted;
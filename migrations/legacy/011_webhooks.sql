@@ -0,0 +1,38 @@
+-- 为已有部署添加 webhook 端点和投递记录表（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+ALTER TABLE articles
+    MODIFY COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published' COMMENT '文章状态: pending/published/rejected/withdrawn';
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(64) NOT NULL COMMENT '端点名称',
+    url VARCHAR(512) NOT NULL COMMENT '接收事件的地址',
+    secret VARCHAR(128) NOT NULL COMMENT 'HMAC-SHA256 签名密钥',
+    events VARCHAR(255) NOT NULL DEFAULT '' COMMENT '订阅的事件，逗号分隔，为空表示全部',
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '' COMMENT '只接收该租户的事件，为空表示全部租户',
+    source VARCHAR(16) NOT NULL DEFAULT 'api' COMMENT '端点来源: config/api',
+    enabled TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否启用',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='webhook 端点表';
+
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    endpoint_id BIGINT NOT NULL COMMENT '端点ID',
+    event_id VARCHAR(32) NOT NULL COMMENT '事件ID',
+    event VARCHAR(32) NOT NULL COMMENT '事件类型',
+    payload MEDIUMTEXT NOT NULL COMMENT '投递的 JSON 内容',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' COMMENT '投递状态: pending/succeeded/failed',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '已尝试次数',
+    response_status INT NOT NULL DEFAULT 0 COMMENT '最近一次响应的 HTTP 状态码',
+    last_error VARCHAR(512) NOT NULL DEFAULT '' COMMENT '最近一次失败原因',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次尝试时间',
+    delivered_at TIMESTAMP NULL DEFAULT NULL COMMENT '投递成功时间',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_endpoint (endpoint_id),
+    INDEX idx_status_next (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='webhook 投递记录表';
@@ -0,0 +1,5 @@
+-- 为已有部署添加文章归档标识（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+ALTER TABLE articles
+    ADD COLUMN doc_token VARCHAR(64) NOT NULL DEFAULT '' COMMENT '归档到飞书云文档 (document_id) 或知识库 (node_token) 后的标识' AFTER simhash;
@@ -0,0 +1,5 @@
+-- 为已有部署添加文章转发次数（仅用于尚未执行到 013 的旧部署，新部署由 migrate up 建表）
+USE miko_news;
+
+ALTER TABLE articles
+    ADD COLUMN forward_count INT NOT NULL DEFAULT 0 COMMENT '卡片成功转发到群聊的次数' AFTER doc_token;
@@ -0,0 +1,25 @@
+// Package migrations 内嵌各数据库驱动的版本化迁移脚本
+//
+// 每个驱动一个目录，文件名格式为 <版本号>_<名称>.up.sql / .down.sql，版本号递增且各驱动保持一致。
+// 脚本按以分号结尾的行拆分为单条语句执行。legacy/ 下是引入迁移前需要手动执行的 MySQL 脚本，不会被内嵌。
+package migrations
+
+import (
+	"MikoNews/internal/config"
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed mysql/*.sql sqlite/*.sql postgres/*.sql
+var files embed.FS
+
+// FS 返回 driver 对应的迁移脚本目录
+func FS(driver string) (fs.FS, error) {
+	switch driver {
+	case config.DatabaseDriverMySQL, config.DatabaseDriverSQLite, config.DatabaseDriverPostgres:
+		return fs.Sub(files, driver)
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
+}
@@ -0,0 +1,108 @@
+-- 初始表结构，对应 legacy/013_article_forward_count.sql 之后的 schema
+-- 数据库需事先创建: CREATE DATABASE miko_news DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
+
+-- 创建文章表
+CREATE TABLE IF NOT EXISTS articles (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '' COMMENT '所属飞书租户标识',
+    title VARCHAR(255) NOT NULL DEFAULT '' COMMENT '文章标题',
+    content TEXT NOT NULL COMMENT '文章内容',
+    author_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '作者飞书OpenID',
+    author_name VARCHAR(64) NOT NULL DEFAULT '匿名用户' COMMENT '作者名字',
+    curator_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '代为收录者飞书OpenID，本人投稿时为空',
+    is_anonymous TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否匿名投稿',
+    author_id_encrypted VARCHAR(255) NOT NULL DEFAULT '' COMMENT '匿名投稿时加密保存的真实作者OpenID',
+    source VARCHAR(32) NOT NULL DEFAULT 'feishu' COMMENT '投稿来源: feishu/telegram',
+    status VARCHAR(16) NOT NULL DEFAULT 'published' COMMENT '文章状态: pending/published/rejected/withdrawn',
+    raw_content MEDIUMTEXT NULL COMMENT '原始富文本JSON，审核通过后据此转发',
+    simhash BIGINT NOT NULL DEFAULT 0 COMMENT '正文SimHash，用于近似查重',
+    doc_token VARCHAR(64) NOT NULL DEFAULT '' COMMENT '归档到飞书云文档 (document_id) 或知识库 (node_token) 后的标识',
+    forward_count INT NOT NULL DEFAULT 0 COMMENT '卡片成功转发到群聊的次数',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_tenant (tenant_key),
+    INDEX idx_author (author_id),
+    INDEX idx_status (status),
+    INDEX idx_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户投稿文章表';
+
+-- 创建文章署名表（作者、合著者、来源）
+CREATE TABLE IF NOT EXISTS article_authors (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id BIGINT NOT NULL COMMENT '文章ID',
+    open_id VARCHAR(64) NOT NULL COMMENT '成员飞书OpenID',
+    name VARCHAR(64) NOT NULL DEFAULT '' COMMENT '成员名字',
+    role VARCHAR(16) NOT NULL COMMENT '署名角色: author/co-author/source',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY uk_article_member_role (article_id, open_id, role),
+    INDEX idx_open_id (open_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='文章署名表';
+-- 创建文章查重指纹表
+CREATE TABLE IF NOT EXISTS article_fingerprints (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id BIGINT NOT NULL COMMENT '文章ID',
+    kind VARCHAR(16) NOT NULL COMMENT '指纹类型: url/simhash',
+    value VARCHAR(255) NOT NULL COMMENT '指纹值: 规范化链接或SimHash分段',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_article (article_id),
+    INDEX idx_kind_value (kind, value)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='文章查重指纹表';
+
+-- 创建用户目录表（从飞书通讯录同步）
+CREATE TABLE IF NOT EXISTS users (
+    open_id VARCHAR(64) PRIMARY KEY COMMENT '用户飞书OpenID',
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '' COMMENT '所属飞书租户标识',
+    union_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '用户UnionID',
+    name VARCHAR(64) NOT NULL DEFAULT '' COMMENT '用户名',
+    en_name VARCHAR(64) NOT NULL DEFAULT '' COMMENT '英文名',
+    avatar_url VARCHAR(512) NOT NULL DEFAULT '' COMMENT '头像链接',
+    department_ids VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '所属部门open_department_id，逗号分隔',
+    status VARCHAR(16) NOT NULL DEFAULT 'active' COMMENT '用户状态: active/resigned',
+    synced_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '最近一次从飞书同步的时间',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_tenant (tenant_key),
+    INDEX idx_union_id (union_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='飞书通讯录用户表';
+
+-- 创建投稿限流计数表
+CREATE TABLE IF NOT EXISTS rate_limit_counters (
+    counter_key VARCHAR(128) NOT NULL COMMENT '计数键，如 submission:hour:ou_xxx',
+    window_start TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '窗口开始时间',
+    count INT NOT NULL DEFAULT 0 COMMENT '窗口内的计数',
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '窗口结束后可清理的时间',
+    PRIMARY KEY (counter_key, window_start),
+    INDEX idx_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='投稿限流计数表';
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(64) NOT NULL COMMENT '端点名称',
+    url VARCHAR(512) NOT NULL COMMENT '接收事件的地址',
+    secret VARCHAR(128) NOT NULL COMMENT 'HMAC-SHA256 签名密钥',
+    events VARCHAR(255) NOT NULL DEFAULT '' COMMENT '订阅的事件，逗号分隔，为空表示全部',
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '' COMMENT '只接收该租户的事件，为空表示全部租户',
+    source VARCHAR(16) NOT NULL DEFAULT 'api' COMMENT '端点来源: config/api',
+    enabled TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否启用',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='webhook 端点表';
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    endpoint_id BIGINT NOT NULL COMMENT '端点ID',
+    event_id VARCHAR(32) NOT NULL COMMENT '事件ID',
+    event VARCHAR(32) NOT NULL COMMENT '事件类型',
+    payload MEDIUMTEXT NOT NULL COMMENT '投递的 JSON 内容',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' COMMENT '投递状态: pending/succeeded/failed',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '已尝试次数',
+    response_status INT NOT NULL DEFAULT 0 COMMENT '最近一次响应的 HTTP 状态码',
+    last_error VARCHAR(512) NOT NULL DEFAULT '' COMMENT '最近一次失败原因',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次尝试时间',
+    delivered_at TIMESTAMP NULL DEFAULT NULL COMMENT '投递成功时间',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_endpoint (endpoint_id),
+    INDEX idx_status_next (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='webhook 投递记录表';
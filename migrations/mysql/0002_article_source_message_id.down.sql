@@ -0,0 +1,3 @@
+DROP INDEX idx_tenant_source_message ON articles;
+
+ALTER TABLE articles DROP COLUMN source_message_id;
@@ -0,0 +1,5 @@
+-- 记录文章对应的来源消息ID，导入历史消息时据此去重
+ALTER TABLE articles
+    ADD COLUMN source_message_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '来源消息ID（飞书 message_id 等），用于导入去重' AFTER source;
+
+CREATE INDEX idx_tenant_source_message ON articles (tenant_key, source_message_id);
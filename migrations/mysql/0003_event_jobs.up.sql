@@ -0,0 +1,17 @@
+-- 飞书消息事件的异步处理队列
+CREATE TABLE IF NOT EXISTS event_jobs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '' COMMENT '所属飞书租户标识',
+    event_id VARCHAR(64) NOT NULL COMMENT '飞书事件ID',
+    event_type VARCHAR(64) NOT NULL COMMENT '事件类型',
+    chat_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '事件所属的会话，同一会话的事件按顺序处理',
+    payload MEDIUMTEXT NOT NULL COMMENT '事件内容',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' COMMENT '任务状态: pending/succeeded/failed',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '已尝试次数',
+    last_error VARCHAR(512) NOT NULL DEFAULT '' COMMENT '处理失败的原因',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '可被处理的时间',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '入队时间',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_tenant_event (tenant_key, event_id),
+    INDEX idx_tenant_status_next (tenant_key, status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='飞书事件处理队列表';
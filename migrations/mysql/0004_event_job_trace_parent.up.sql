@@ -0,0 +1,3 @@
+-- 保存入队时的链路追踪上下文，处理事件时延续同一条链路
+ALTER TABLE event_jobs
+    ADD COLUMN trace_parent VARCHAR(64) NOT NULL DEFAULT '' COMMENT '入队时的 W3C traceparent' AFTER payload;
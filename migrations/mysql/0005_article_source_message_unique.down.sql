@@ -0,0 +1,8 @@
+DROP INDEX uk_tenant_source_message ON articles;
+
+UPDATE articles SET source_message_id = '' WHERE source_message_id IS NULL;
+
+ALTER TABLE articles
+    MODIFY COLUMN source_message_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '来源消息ID（飞书 message_id 等），用于导入去重';
+
+CREATE INDEX idx_tenant_source_message ON articles (tenant_key, source_message_id);
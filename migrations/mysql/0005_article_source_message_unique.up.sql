@@ -0,0 +1,20 @@
+-- 来源消息ID改为唯一索引，避免并发导入同一条消息时重复建文章；未知的来源消息ID保存为 NULL，不参与唯一约束
+ALTER TABLE articles
+    MODIFY COLUMN source_message_id VARCHAR(64) NULL DEFAULT NULL COMMENT '来源消息ID（飞书 message_id 等），用于导入去重，未知时为 NULL';
+
+UPDATE articles SET source_message_id = NULL WHERE source_message_id = '';
+
+-- 已重复导入的文章只保留最早一篇的来源消息ID
+UPDATE articles a
+    JOIN (
+        SELECT tenant_key, source_message_id, MIN(id) AS keep_id
+        FROM articles
+        WHERE source_message_id IS NOT NULL
+        GROUP BY tenant_key, source_message_id
+        HAVING COUNT(*) > 1
+    ) d ON a.tenant_key = d.tenant_key AND a.source_message_id = d.source_message_id AND a.id <> d.keep_id
+SET a.source_message_id = NULL;
+
+DROP INDEX idx_tenant_source_message ON articles;
+
+CREATE UNIQUE INDEX uk_tenant_source_message ON articles (tenant_key, source_message_id);
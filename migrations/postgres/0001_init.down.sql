@@ -0,0 +1,8 @@
+-- 回滚初始表结构，会删除全部数据
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
+DROP TABLE IF EXISTS rate_limit_counters;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS article_fingerprints;
+DROP TABLE IF EXISTS article_authors;
+DROP TABLE IF EXISTS articles;
@@ -0,0 +1,117 @@
+-- PostgreSQL 初始表结构，与 mysql/0001_init.up.sql 保持一致
+
+-- 用户投稿文章表
+CREATE TABLE IF NOT EXISTS articles (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '',
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    author_id VARCHAR(64) NOT NULL DEFAULT '',
+    author_name VARCHAR(64) NOT NULL DEFAULT '匿名用户',
+    curator_id VARCHAR(64) NOT NULL DEFAULT '',
+    is_anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    author_id_encrypted VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(32) NOT NULL DEFAULT 'feishu',
+    status VARCHAR(16) NOT NULL DEFAULT 'published',
+    raw_content TEXT NULL,
+    simhash BIGINT NOT NULL DEFAULT 0,
+    doc_token VARCHAR(64) NOT NULL DEFAULT '',
+    forward_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_articles_tenant ON articles (tenant_key);
+CREATE INDEX IF NOT EXISTS idx_articles_author ON articles (author_id);
+CREATE INDEX IF NOT EXISTS idx_articles_status ON articles (status);
+CREATE INDEX IF NOT EXISTS idx_articles_created ON articles (created_at);
+COMMENT ON TABLE articles IS '用户投稿文章表';
+
+-- 文章署名表（作者、合著者、来源）
+CREATE TABLE IF NOT EXISTS article_authors (
+    id BIGSERIAL PRIMARY KEY,
+    article_id BIGINT NOT NULL,
+    open_id VARCHAR(64) NOT NULL,
+    name VARCHAR(64) NOT NULL DEFAULT '',
+    role VARCHAR(16) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uk_article_member_role UNIQUE (article_id, open_id, role)
+);
+CREATE INDEX IF NOT EXISTS idx_article_authors_open_id ON article_authors (open_id);
+COMMENT ON TABLE article_authors IS '文章署名表';
+
+-- 文章查重指纹表
+CREATE TABLE IF NOT EXISTS article_fingerprints (
+    id BIGSERIAL PRIMARY KEY,
+    article_id BIGINT NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_article_fingerprints_article ON article_fingerprints (article_id);
+CREATE INDEX IF NOT EXISTS idx_article_fingerprints_kind_value ON article_fingerprints (kind, value);
+COMMENT ON TABLE article_fingerprints IS '文章查重指纹表';
+
+-- 飞书通讯录用户表
+CREATE TABLE IF NOT EXISTS users (
+    open_id VARCHAR(64) PRIMARY KEY,
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '',
+    union_id VARCHAR(64) NOT NULL DEFAULT '',
+    name VARCHAR(64) NOT NULL DEFAULT '',
+    en_name VARCHAR(64) NOT NULL DEFAULT '',
+    avatar_url VARCHAR(512) NOT NULL DEFAULT '',
+    department_ids VARCHAR(1024) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    synced_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_users_tenant ON users (tenant_key);
+CREATE INDEX IF NOT EXISTS idx_users_union_id ON users (union_id);
+COMMENT ON TABLE users IS '飞书通讯录用户表';
+
+-- 投稿限流计数表
+CREATE TABLE IF NOT EXISTS rate_limit_counters (
+    counter_key VARCHAR(128) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (counter_key, window_start)
+);
+CREATE INDEX IF NOT EXISTS idx_rate_limit_counters_expires ON rate_limit_counters (expires_at);
+COMMENT ON TABLE rate_limit_counters IS '投稿限流计数表';
+
+-- webhook 端点表
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    url VARCHAR(512) NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    events VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '',
+    source VARCHAR(16) NOT NULL DEFAULT 'api',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uk_name UNIQUE (name)
+);
+COMMENT ON TABLE webhook_endpoints IS 'webhook 端点表';
+
+-- webhook 投递记录表
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    endpoint_id BIGINT NOT NULL,
+    event_id VARCHAR(32) NOT NULL,
+    event VARCHAR(32) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(512) NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMPTZ NULL DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status_next ON webhook_deliveries (status, next_attempt_at);
+COMMENT ON TABLE webhook_deliveries IS 'webhook 投递记录表';
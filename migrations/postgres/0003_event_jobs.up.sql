@@ -0,0 +1,18 @@
+-- 飞书消息事件的异步处理队列
+CREATE TABLE IF NOT EXISTS event_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '',
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    chat_id VARCHAR(64) NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(512) NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uk_tenant_event UNIQUE (tenant_key, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_event_jobs_tenant_status_next ON event_jobs (tenant_key, status, next_attempt_at);
+COMMENT ON TABLE event_jobs IS '飞书事件处理队列表';
@@ -0,0 +1,9 @@
+DROP INDEX uk_articles_tenant_source_message;
+
+UPDATE articles SET source_message_id = '' WHERE source_message_id IS NULL;
+
+ALTER TABLE articles ALTER COLUMN source_message_id SET DEFAULT '';
+
+ALTER TABLE articles ALTER COLUMN source_message_id SET NOT NULL;
+
+CREATE INDEX idx_articles_tenant_source_message ON articles (tenant_key, source_message_id);
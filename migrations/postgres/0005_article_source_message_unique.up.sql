@@ -0,0 +1,15 @@
+-- 来源消息ID改为唯一索引，避免并发导入同一条消息时重复建文章；未知的来源消息ID保存为 NULL，不参与唯一约束
+ALTER TABLE articles ALTER COLUMN source_message_id DROP NOT NULL;
+
+ALTER TABLE articles ALTER COLUMN source_message_id DROP DEFAULT;
+
+UPDATE articles SET source_message_id = NULL WHERE source_message_id = '';
+
+-- 已重复导入的文章只保留最早一篇的来源消息ID
+UPDATE articles SET source_message_id = NULL
+WHERE source_message_id IS NOT NULL
+  AND id > (SELECT MIN(b.id) FROM articles b WHERE b.tenant_key = articles.tenant_key AND b.source_message_id = articles.source_message_id);
+
+DROP INDEX idx_articles_tenant_source_message;
+
+CREATE UNIQUE INDEX uk_articles_tenant_source_message ON articles (tenant_key, source_message_id);
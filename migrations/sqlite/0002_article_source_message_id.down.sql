@@ -0,0 +1,3 @@
+DROP INDEX idx_articles_tenant_source_message;
+
+ALTER TABLE articles DROP COLUMN source_message_id;
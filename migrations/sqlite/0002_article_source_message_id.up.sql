@@ -0,0 +1,4 @@
+-- 记录文章对应的来源消息ID，导入历史消息时据此去重
+ALTER TABLE articles ADD COLUMN source_message_id VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_articles_tenant_source_message ON articles (tenant_key, source_message_id);
@@ -0,0 +1,17 @@
+-- 飞书消息事件的异步处理队列
+CREATE TABLE IF NOT EXISTS event_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '',
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    chat_id VARCHAR(64) NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(512) NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_key, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_event_jobs_tenant_status_next ON event_jobs (tenant_key, status, next_attempt_at);
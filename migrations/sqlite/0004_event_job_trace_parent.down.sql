@@ -0,0 +1 @@
+ALTER TABLE event_jobs DROP COLUMN trace_parent;
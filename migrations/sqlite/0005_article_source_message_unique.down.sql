@@ -0,0 +1,40 @@
+-- SQLite 不支持修改列的 NOT NULL 约束，需要重建 articles 表
+CREATE TABLE articles_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant_key VARCHAR(64) NOT NULL DEFAULT '',
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    author_id VARCHAR(64) NOT NULL DEFAULT '',
+    author_name VARCHAR(64) NOT NULL DEFAULT '匿名用户',
+    curator_id VARCHAR(64) NOT NULL DEFAULT '',
+    is_anonymous BOOLEAN NOT NULL DEFAULT 0,
+    author_id_encrypted VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(32) NOT NULL DEFAULT 'feishu',
+    status VARCHAR(16) NOT NULL DEFAULT 'published',
+    raw_content TEXT NULL,
+    simhash BIGINT NOT NULL DEFAULT 0,
+    doc_token VARCHAR(64) NOT NULL DEFAULT '',
+    forward_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    source_message_id VARCHAR(64) NOT NULL DEFAULT ''
+);
+
+DROP INDEX uk_articles_tenant_source_message;
+
+UPDATE articles SET source_message_id = '' WHERE source_message_id IS NULL;
+
+INSERT INTO articles_new SELECT * FROM articles;
+
+-- 保留自增序列，避免复用已删除文章的ID
+UPDATE sqlite_sequence SET seq = (SELECT seq FROM sqlite_sequence WHERE name = 'articles') WHERE name = 'articles_new';
+
+DROP TABLE articles;
+
+ALTER TABLE articles_new RENAME TO articles;
+
+CREATE INDEX idx_articles_tenant ON articles (tenant_key);
+CREATE INDEX idx_articles_author ON articles (author_id);
+CREATE INDEX idx_articles_status ON articles (status);
+CREATE INDEX idx_articles_created ON articles (created_at);
+CREATE INDEX idx_articles_tenant_source_message ON articles (tenant_key, source_message_id);
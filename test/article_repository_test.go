@@ -6,11 +6,12 @@ import (
 	"MikoNews/internal/model"
 	"MikoNews/internal/pkg/logger"
 	"MikoNews/internal/repository"
-	"MikoNews/internal/repository/impl/mysql"
+	repositoryImpl "MikoNews/internal/repository/impl"
 	"context"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ func TestMain(m *testing.M) {
 var (
 	dbConfig *config.DatabaseConfig
 	db       *database.DB
+	dbDir    string
 	repo     repository.ArticleRepository
 	testCtx  context.Context
 )
@@ -45,32 +47,36 @@ func setupTestEnv() {
 	}
 	logger.InitLogger(testLogPath, testLogLevel)
 
-	// 2. 设置数据库配置
+	// 2. 设置数据库配置，使用临时目录下的 SQLite 数据库，各驱动的完整测试见 internal/repository/impl
+	var err error
+	dbDir, err = os.MkdirTemp("", "miko-news-test")
+	if err != nil {
+		log.Fatalf("创建临时目录失败: %v", err)
+	}
 	dbConfig = &config.DatabaseConfig{
-		Host:         "58.87.95.28",
-		Port:         3306,
-		User:         "miko_news",
-		Password:     "mJTpfLp8s4382TWt",
-		DBName:       "miko_news",
+		Driver:       config.DatabaseDriverSQLite,
+		Path:         filepath.Join(dbDir, "miko_news.db"),
 		MaxOpenConns: 5,
 		MaxIdleConns: 3,
 	}
 
 	// 3. 连接数据库 (现在 logger 已初始化)
-	var err error
 	db, err = database.New(dbConfig)
 	if err != nil {
 		log.Fatalf("连接测试数据库失败: %v", err)
 	}
 
-	// // 4. 自动迁移
-	// err = db.DB.AutoMigrate(&model.Article{})
-	// if err != nil {
-	// 	log.Fatalf("自动迁移失败: %v", err)
-	// }
+	// 4. 执行迁移建表
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("加载迁移脚本失败: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		log.Fatalf("执行迁移失败: %v", err)
+	}
 
 	// 5. 创建仓库实例
-	repo = mysql.NewArticleRepository(db.DB)
+	repo = repositoryImpl.NewArticleRepository(db, "")
 
 	// 6. 创建上下文
 	testCtx = context.Background()
@@ -87,6 +93,7 @@ func teardownTestEnv() {
 			log.Printf("关闭测试数据库连接时出错: %v", err)
 		}
 	}
+	_ = os.RemoveAll(dbDir)
 }
 
 // cleanTestData 清理测试数据